@@ -8,6 +8,7 @@ import (
 	"solemate/api-gateway/internal/config"
 	"solemate/api-gateway/internal/handler"
 	"solemate/pkg/auth"
+	"solemate/pkg/cache"
 )
 
 func main() {
@@ -22,17 +23,34 @@ func main() {
 	// Initialize JWT manager
 	jwtManager := auth.NewJWTManager()
 
+	// Session revocations written by user-service, so that access tokens of
+	// deactivated, deleted or re-roled users stop working right away
+	var revocations *auth.SessionRevocations
+	if redisClient, err := cache.NewRedisClient(cache.GetConfigFromEnv()); err != nil {
+		log.Printf("Redis unavailable, access tokens of revoked sessions stay valid until they expire: %v", err)
+	} else {
+		revocations = auth.NewSessionRevocations(redisClient)
+	}
+
 	// Initialize proxy handler
+	internalTokens, err := auth.NewInternalTokenManager(auth.ServiceGateway)
+	if err != nil {
+		log.Fatalf("Failed to initialize internal tokens: %v", err)
+	}
 	proxyHandler := handler.NewProxyHandler(
 		cfg.Services.UserServiceURL,
 		cfg.Services.ProductServiceURL,
 		cfg.Services.CartServiceURL,
 		cfg.Services.OrderServiceURL,
 		cfg.Services.PaymentServiceURL,
+		internalTokens,
 	)
 
 	// Setup routes
-	router := handler.SetupRoutes(proxyHandler, jwtManager)
+	router := handler.SetupRoutes(proxyHandler, jwtManager, revocations)
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	// Start server
 	serverAddr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
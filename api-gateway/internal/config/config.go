@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 )
 
 type Config struct {
@@ -14,6 +15,11 @@ type ServerConfig struct {
 	Port string
 	Host string
 	ENV  string
+
+	// TrustedProxies are the load balancers in front of the gateway whose
+	// X-Forwarded-For header is believed. Without any, the client IP is the
+	// address the request came from.
+	TrustedProxies []string
 }
 
 type ServicesConfig struct {
@@ -35,6 +41,8 @@ func Load() *Config {
 			Port: getEnv("PORT", "8000"),
 			Host: getEnv("HOST", "0.0.0.0"),
 			ENV:  getEnv("ENV", "development"),
+
+			TrustedProxies: getEnvList("TRUSTED_PROXIES"),
 		},
 		Services: ServicesConfig{
 			UserServiceURL:    getEnv("USER_SERVICE_URL", "http://localhost:8080"),
@@ -56,3 +64,14 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvList reads a comma separated list
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"solemate/pkg/auth"
+	"solemate/pkg/authz"
 	"solemate/pkg/utils"
 )
 
@@ -17,39 +19,41 @@ type ProxyHandler struct {
 	cartServiceURL    string
 	orderServiceURL   string
 	paymentServiceURL string
+	internalTokens    *auth.InternalTokenManager
 }
 
-func NewProxyHandler(userURL, productURL, cartURL, orderURL, paymentURL string) *ProxyHandler {
+func NewProxyHandler(userURL, productURL, cartURL, orderURL, paymentURL string, internalTokens *auth.InternalTokenManager) *ProxyHandler {
 	return &ProxyHandler{
 		userServiceURL:    userURL,
 		productServiceURL: productURL,
 		cartServiceURL:    cartURL,
 		orderServiceURL:   orderURL,
 		paymentServiceURL: paymentURL,
+		internalTokens:    internalTokens,
 	}
 }
 
 func (p *ProxyHandler) ProxyToUserService(c *gin.Context) {
-	p.proxyRequest(c, p.userServiceURL)
+	p.proxyRequest(c, p.userServiceURL, auth.ServiceUser)
 }
 
 func (p *ProxyHandler) ProxyToProductService(c *gin.Context) {
-	p.proxyRequest(c, p.productServiceURL)
+	p.proxyRequest(c, p.productServiceURL, auth.ServiceProduct)
 }
 
 func (p *ProxyHandler) ProxyToCartService(c *gin.Context) {
-	p.proxyRequest(c, p.cartServiceURL)
+	p.proxyRequest(c, p.cartServiceURL, auth.ServiceCart)
 }
 
 func (p *ProxyHandler) ProxyToOrderService(c *gin.Context) {
-	p.proxyRequest(c, p.orderServiceURL)
+	p.proxyRequest(c, p.orderServiceURL, auth.ServiceOrder)
 }
 
 func (p *ProxyHandler) ProxyToPaymentService(c *gin.Context) {
-	p.proxyRequest(c, p.paymentServiceURL)
+	p.proxyRequest(c, p.paymentServiceURL, auth.ServicePayment)
 }
 
-func (p *ProxyHandler) proxyRequest(c *gin.Context, targetURL string) {
+func (p *ProxyHandler) proxyRequest(c *gin.Context, targetURL, audience string) {
 	// Parse target URL
 	target, err := url.Parse(targetURL)
 	if err != nil {
@@ -77,9 +81,9 @@ func (p *ProxyHandler) proxyRequest(c *gin.Context, targetURL string) {
 		return
 	}
 
-	// Copy headers, excluding hop-by-hop headers
+	// Copy headers, excluding hop-by-hop headers and any client-supplied identity
 	for key, values := range c.Request.Header {
-		if isHopByHopHeader(key) {
+		if isHopByHopHeader(key) || isIdentityHeader(key) {
 			continue
 		}
 		for _, value := range values {
@@ -87,15 +91,23 @@ func (p *ProxyHandler) proxyRequest(c *gin.Context, targetURL string) {
 		}
 	}
 
-	// Add user context headers if available
+	// Replace any client-supplied forwarding headers with the address the
+	// gateway sees, which services use to tell anonymous shoppers apart
+	req.Header.Set("X-Forwarded-For", c.ClientIP())
+
+	// Pass the authenticated user context as a signed internal token
 	if userID := c.GetString("user_id"); userID != "" {
-		req.Header.Set("X-User-ID", userID)
-	}
-	if email := c.GetString("email"); email != "" {
-		req.Header.Set("X-User-Email", email)
-	}
-	if role := c.GetString("role"); role != "" {
-		req.Header.Set("X-User-Role", role)
+		token, err := p.internalTokens.MintForUser(audience, &auth.Claims{
+			UserID:      userID,
+			Email:       c.GetString("email"),
+			Role:        c.GetString("role"),
+			Permissions: authz.Permissions(c),
+		})
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to sign internal token", err.Error())
+			return
+		}
+		req.Header.Set(auth.InternalTokenHeader, token)
 	}
 
 	// Make the request
@@ -124,6 +136,14 @@ func (p *ProxyHandler) proxyRequest(c *gin.Context, targetURL string) {
 	io.Copy(c.Writer, resp.Body)
 }
 
+// isIdentityHeader checks if a header carries caller identity that only the
+// gateway may set
+func isIdentityHeader(header string) bool {
+	header = strings.ToLower(header)
+	return header == strings.ToLower(auth.InternalTokenHeader) || strings.HasPrefix(header, "x-user-") ||
+		header == "x-forwarded-for" || header == "x-real-ip"
+}
+
 // isHopByHopHeader checks if a header is hop-by-hop
 func isHopByHopHeader(header string) bool {
 	hopByHopHeaders := []string{
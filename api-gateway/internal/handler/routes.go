@@ -4,9 +4,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"solemate/api-gateway/internal/middleware"
 	"solemate/pkg/auth"
+	"solemate/pkg/authz"
 )
 
-func SetupRoutes(proxyHandler *ProxyHandler, jwtManager *auth.JWTManager) *gin.Engine {
+func SetupRoutes(proxyHandler *ProxyHandler, jwtManager *auth.JWTManager, revocations *auth.SessionRevocations) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 
@@ -24,6 +25,9 @@ func SetupRoutes(proxyHandler *ProxyHandler, jwtManager *auth.JWTManager) *gin.E
 		})
 	})
 
+	// Uploaded product and review images
+	r.GET("/media/*filepath", proxyHandler.ProxyToProductService)
+
 	// API v1 routes
 	v1 := r.Group("/api/v1")
 	{
@@ -40,8 +44,11 @@ func SetupRoutes(proxyHandler *ProxyHandler, jwtManager *auth.JWTManager) *gin.E
 		{
 			products.GET("", proxyHandler.ProxyToProductService)
 			products.GET("/search", proxyHandler.ProxyToProductService)
+			products.GET("/suggest", proxyHandler.ProxyToProductService)
+			products.GET("/trending", proxyHandler.ProxyToProductService)
 			products.GET("/:id", proxyHandler.ProxyToProductService)
 			products.GET("/:id/related", proxyHandler.ProxyToProductService)
+			products.GET("/:id/recommendations", proxyHandler.ProxyToProductService)
 			products.GET("/:id/reviews", proxyHandler.ProxyToProductService)
 			products.POST("/:id/reviews", proxyHandler.ProxyToProductService)
 		}
@@ -51,6 +58,7 @@ func SetupRoutes(proxyHandler *ProxyHandler, jwtManager *auth.JWTManager) *gin.E
 		{
 			categories.GET("", proxyHandler.ProxyToProductService)
 			categories.GET("/:id", proxyHandler.ProxyToProductService)
+			categories.GET("/:id/attributes", proxyHandler.ProxyToProductService)
 		}
 
 		// Public brands routes
@@ -60,14 +68,49 @@ func SetupRoutes(proxyHandler *ProxyHandler, jwtManager *auth.JWTManager) *gin.E
 			brands.GET("/:id", proxyHandler.ProxyToProductService)
 		}
 
+		// Product view tracking, from signed-in or anonymous shoppers
+		v1.POST("/events/product-view", middleware.OptionalAuthMiddleware(jwtManager, revocations), proxyHandler.ProxyToProductService)
+
+		// Shared wishlists (no auth required)
+		v1.GET("/wishlists/shared/:slug", proxyHandler.ProxyToUserService)
+
 		// Protected routes (authentication required)
 		protected := v1.Group("/")
-		protected.Use(middleware.AuthMiddleware(jwtManager))
+		protected.Use(middleware.AuthMiddleware(jwtManager, revocations))
 		{
 			// User profile routes
 			protected.GET("/profile", proxyHandler.ProxyToUserService)
 			protected.PUT("/profile", proxyHandler.ProxyToUserService)
 
+			// Personal data export and account erasure
+			protected.DELETE("/profile", proxyHandler.ProxyToUserService)
+			protected.GET("/profile/export", proxyHandler.ProxyToUserService)
+			protected.GET("/profile/export/:id", proxyHandler.ProxyToUserService)
+			protected.GET("/profile/export/:id/download", proxyHandler.ProxyToUserService)
+			protected.GET("/profile/privacy-requests", proxyHandler.ProxyToUserService)
+
+			// Browsing history
+			protected.GET("/profile/recently-viewed", proxyHandler.ProxyToProductService)
+
+			// Address book routes
+			addresses := protected.Group("/profile/addresses")
+			{
+				addresses.GET("", proxyHandler.ProxyToUserService)
+				addresses.POST("", proxyHandler.ProxyToUserService)
+				addresses.GET("/:id", proxyHandler.ProxyToUserService)
+				addresses.PUT("/:id", proxyHandler.ProxyToUserService)
+				addresses.DELETE("/:id", proxyHandler.ProxyToUserService)
+				addresses.PUT("/:id/default", proxyHandler.ProxyToUserService)
+			}
+
+			// Review photos
+			protected.POST("/reviews/:id/images", proxyHandler.ProxyToProductService)
+
+			// Review votes and reports
+			protected.POST("/reviews/:id/helpful", proxyHandler.ProxyToProductService)
+			protected.POST("/reviews/:id/not-helpful", proxyHandler.ProxyToProductService)
+			protected.POST("/reviews/:id/report", proxyHandler.ProxyToProductService)
+
 			// Cart routes
 			cart := protected.Group("/cart")
 			{
@@ -95,15 +138,15 @@ func SetupRoutes(proxyHandler *ProxyHandler, jwtManager *auth.JWTManager) *gin.E
 
 				// Admin order routes
 				adminOrders := orders.Group("/admin")
-				adminOrders.Use(middleware.AdminMiddleware())
 				{
-					adminOrders.POST("/search", proxyHandler.ProxyToOrderService)
-					adminOrders.GET("/statistics", proxyHandler.ProxyToOrderService)
-					adminOrders.GET("/top-products", proxyHandler.ProxyToOrderService)
-					adminOrders.GET("/sales-metrics", proxyHandler.ProxyToOrderService)
-					adminOrders.PATCH("/:order_id/status", proxyHandler.ProxyToOrderService)
-					adminOrders.POST("/:order_id/ship", proxyHandler.ProxyToOrderService)
-					adminOrders.PATCH("/:order_id/payment-status", proxyHandler.ProxyToOrderService)
+					adminOrders.POST("/search", authz.RequirePermission(authz.OrdersRead), proxyHandler.ProxyToOrderService)
+					adminOrders.GET("/statistics", authz.RequirePermission(authz.AnalyticsRead), proxyHandler.ProxyToOrderService)
+					adminOrders.GET("/top-products", authz.RequirePermission(authz.AnalyticsRead), proxyHandler.ProxyToOrderService)
+					adminOrders.GET("/co-purchases", authz.RequirePermission(authz.AnalyticsRead), proxyHandler.ProxyToOrderService)
+					adminOrders.GET("/sales-metrics", authz.RequirePermission(authz.AnalyticsRead), proxyHandler.ProxyToOrderService)
+					adminOrders.PATCH("/:order_id/status", authz.RequirePermission(authz.OrdersUpdateStatus), proxyHandler.ProxyToOrderService)
+					adminOrders.POST("/:order_id/ship", authz.RequirePermission(authz.OrdersShip), proxyHandler.ProxyToOrderService)
+					adminOrders.PATCH("/:order_id/payment-status", authz.RequirePermission(authz.OrdersUpdatePayment), proxyHandler.ProxyToOrderService)
 				}
 			}
 
@@ -112,7 +155,7 @@ func SetupRoutes(proxyHandler *ProxyHandler, jwtManager *auth.JWTManager) *gin.E
 			{
 				payments.POST("", proxyHandler.ProxyToPaymentService)
 				payments.GET("/:id", proxyHandler.ProxyToPaymentService)
-				payments.POST("/:id/refund", proxyHandler.ProxyToPaymentService)
+				payments.POST("/:id/refund", authz.RequirePermission(authz.RefundsCreate), proxyHandler.ProxyToPaymentService)
 			}
 
 			// Wishlist routes
@@ -125,39 +168,125 @@ func SetupRoutes(proxyHandler *ProxyHandler, jwtManager *auth.JWTManager) *gin.E
 				wishlist.POST("/move-to-cart", proxyHandler.ProxyToUserService)
 			}
 
+			// Named wishlist routes
+			wishlists := protected.Group("/wishlists")
+			{
+				wishlists.GET("", proxyHandler.ProxyToUserService)
+				wishlists.POST("", proxyHandler.ProxyToUserService)
+				wishlists.GET("/:id", proxyHandler.ProxyToUserService)
+				wishlists.PUT("/:id", proxyHandler.ProxyToUserService)
+				wishlists.DELETE("/:id", proxyHandler.ProxyToUserService)
+				wishlists.POST("/:id/items", proxyHandler.ProxyToUserService)
+				wishlists.DELETE("/:id/items/:product_id", proxyHandler.ProxyToUserService)
+				wishlists.POST("/shared/:slug/copy", proxyHandler.ProxyToUserService)
+			}
+
 			// Admin routes
 			admin := protected.Group("/admin")
-			admin.Use(middleware.AdminMiddleware())
 			{
 				// User management
 				adminUsers := admin.Group("/users")
 				{
-					adminUsers.GET("", proxyHandler.ProxyToUserService)
-					adminUsers.GET("/:id", proxyHandler.ProxyToUserService)
-					adminUsers.DELETE("/:id", proxyHandler.ProxyToUserService)
+					adminUsers.GET("", authz.RequirePermission(authz.UsersRead), proxyHandler.ProxyToUserService)
+					adminUsers.GET("/:id", authz.RequirePermission(authz.UsersRead), proxyHandler.ProxyToUserService)
+					adminUsers.DELETE("/:id", authz.RequirePermission(authz.UsersWrite), proxyHandler.ProxyToUserService)
+					adminUsers.POST("/:id/restore", authz.RequirePermission(authz.UsersWrite), proxyHandler.ProxyToUserService)
+					adminUsers.POST("/:id/activate", authz.RequirePermission(authz.UsersWrite), proxyHandler.ProxyToUserService)
+					adminUsers.POST("/:id/deactivate", authz.RequirePermission(authz.UsersWrite), proxyHandler.ProxyToUserService)
+					adminUsers.PUT("/:id/role", authz.RequirePermission(authz.RolesManage), proxyHandler.ProxyToUserService)
+					adminUsers.GET("/:id/privacy-requests", authz.RequirePermission(authz.UsersRead), proxyHandler.ProxyToUserService)
 				}
 
+				// Privacy request administration
+				admin.POST("/privacy-requests/:id/retry", authz.RequirePermission(authz.UsersWrite), proxyHandler.ProxyToUserService)
+
+				// Role and permission management
+				adminRoles := admin.Group("/roles")
+				adminRoles.Use(authz.RequirePermission(authz.RolesManage))
+				{
+					adminRoles.GET("", proxyHandler.ProxyToUserService)
+					adminRoles.PUT("/:role/permissions", proxyHandler.ProxyToUserService)
+				}
+
+				// Search analytics
+				admin.GET("/search/zero-results", authz.RequirePermission(authz.AnalyticsRead), proxyHandler.ProxyToProductService)
+
 				// Product management
 				adminProducts := admin.Group("/products")
+				adminProducts.Use(authz.RequirePermission(authz.ProductsWrite))
 				{
 					adminProducts.POST("", proxyHandler.ProxyToProductService)
+					adminProducts.GET("/export", proxyHandler.ProxyToProductService)
+					adminProducts.POST("/imports", proxyHandler.ProxyToProductService)
+					adminProducts.GET("/imports/:id", proxyHandler.ProxyToProductService)
+					adminProducts.POST("/imports/:id/confirm", proxyHandler.ProxyToProductService)
 					adminProducts.PUT("/:id", proxyHandler.ProxyToProductService)
 					adminProducts.DELETE("/:id", proxyHandler.ProxyToProductService)
+					adminProducts.PUT("/:id/attributes", proxyHandler.ProxyToProductService)
+
+					adminProducts.GET("/:id/variants", proxyHandler.ProxyToProductService)
+					adminProducts.POST("/:id/variants", proxyHandler.ProxyToProductService)
+					adminProducts.PUT("/:id/variants/order", proxyHandler.ProxyToProductService)
+					adminProducts.PUT("/:id/variants/:variant_id", proxyHandler.ProxyToProductService)
+					adminProducts.PUT("/:id/variants/:variant_id/stock", proxyHandler.ProxyToProductService)
+					adminProducts.PUT("/:id/variants/:variant_id/attributes", proxyHandler.ProxyToProductService)
+					adminProducts.POST("/:id/variants/:variant_id/activate", proxyHandler.ProxyToProductService)
+					adminProducts.POST("/:id/variants/:variant_id/deactivate", proxyHandler.ProxyToProductService)
+					adminProducts.POST("/:id/variants/:variant_id/images", proxyHandler.ProxyToProductService)
+
+					adminProducts.GET("/:id/images", proxyHandler.ProxyToProductService)
+					adminProducts.POST("/:id/images", proxyHandler.ProxyToProductService)
+					adminProducts.POST("/:id/images/upload", proxyHandler.ProxyToProductService)
+					adminProducts.PUT("/:id/images/order", proxyHandler.ProxyToProductService)
+					adminProducts.PUT("/:id/images/:image_id", proxyHandler.ProxyToProductService)
+					adminProducts.PUT("/:id/images/:image_id/primary", proxyHandler.ProxyToProductService)
+					adminProducts.DELETE("/:id/images/:image_id", proxyHandler.ProxyToProductService)
+
+					// Pricing
+					adminProducts.GET("/:id/price-schedules", proxyHandler.ProxyToProductService)
+					adminProducts.POST("/:id/price-schedules", proxyHandler.ProxyToProductService)
+					adminProducts.DELETE("/:id/price-schedules/:schedule_id", proxyHandler.ProxyToProductService)
+					adminProducts.GET("/:id/price-history", proxyHandler.ProxyToProductService)
+				}
+
+				// Review moderation
+				adminReviews := admin.Group("/reviews")
+				adminReviews.Use(authz.RequirePermission(authz.ReviewsModerate))
+				{
+					adminReviews.GET("", proxyHandler.ProxyToProductService)
+					adminReviews.POST("/:id/approve", proxyHandler.ProxyToProductService)
+					adminReviews.POST("/:id/reject", proxyHandler.ProxyToProductService)
+				}
+
+				// Category management
+				adminCategories := admin.Group("/categories")
+				adminCategories.Use(authz.RequirePermission(authz.CategoriesWrite))
+				{
+					adminCategories.POST("", proxyHandler.ProxyToProductService)
+					adminCategories.PUT("/order", proxyHandler.ProxyToProductService)
+					adminCategories.PUT("/:id", proxyHandler.ProxyToProductService)
+					adminCategories.DELETE("/:id", proxyHandler.ProxyToProductService)
+					adminCategories.POST("/:id/move", proxyHandler.ProxyToProductService)
+					adminCategories.POST("/:id/attributes", proxyHandler.ProxyToProductService)
+					adminCategories.PUT("/:id/attributes/:attribute_id", proxyHandler.ProxyToProductService)
+					adminCategories.DELETE("/:id/attributes/:attribute_id", proxyHandler.ProxyToProductService)
 				}
 
 				// Order management
 				adminOrders := admin.Group("/orders")
 				{
-					adminOrders.GET("", proxyHandler.ProxyToOrderService)
-					adminOrders.PUT("/:id/status", proxyHandler.ProxyToOrderService)
+					adminOrders.GET("", authz.RequirePermission(authz.OrdersRead), proxyHandler.ProxyToOrderService)
+					adminOrders.PUT("/:id/status", authz.RequirePermission(authz.OrdersUpdateStatus), proxyHandler.ProxyToOrderService)
 				}
 
 				// Analytics routes
 				analytics := admin.Group("/analytics")
+				analytics.Use(authz.RequirePermission(authz.AnalyticsRead))
 				{
 					analytics.GET("/dashboard", proxyHandler.ProxyToOrderService)
 					analytics.GET("/sales", proxyHandler.ProxyToOrderService)
 					analytics.GET("/users", proxyHandler.ProxyToUserService)
+					analytics.GET("/wishlist-adds", proxyHandler.ProxyToUserService)
 					analytics.GET("/products", proxyHandler.ProxyToProductService)
 				}
 			}
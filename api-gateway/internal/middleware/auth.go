@@ -1,14 +1,20 @@
 package middleware
 
 import (
+	"log"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"solemate/pkg/auth"
+	"solemate/pkg/authz"
 	"solemate/pkg/utils"
 )
 
-func AuthMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
+// AuthMiddleware requires a valid access token. Tokens issued before the
+// user's sessions were revoked are refused; revocations may be nil, and a
+// revocation that cannot be looked up is let through rather than locking
+// every user out while Redis is down.
+func AuthMiddleware(jwtManager *auth.JWTManager, revocations *auth.SessionRevocations) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -32,25 +38,35 @@ func AuthMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
 			return
 		}
 
+		revoked, err := revocations.IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			log.Printf("failed to check session revocation of user %s: %v", claims.UserID, err)
+		}
+		if revoked {
+			utils.UnauthorizedResponse(c, "Session has been revoked")
+			c.Abort()
+			return
+		}
+
 		// Set user information in context
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Set("role", claims.Role)
+		c.Set(authz.ContextKey, claims.Permissions)
 
 		c.Next()
 	}
 }
 
-func AdminMiddleware() gin.HandlerFunc {
+// OptionalAuthMiddleware identifies the user when a valid access token is
+// sent and lets requests without an Authorization header through anonymously
+func OptionalAuthMiddleware(jwtManager *auth.JWTManager, revocations *auth.SessionRevocations) gin.HandlerFunc {
+	authenticate := AuthMiddleware(jwtManager, revocations)
 	return func(c *gin.Context) {
-		role := c.GetString("role")
-		if role != "admin" && role != "manager" {
-			utils.ForbiddenResponse(c, "Admin access required")
-			c.Abort()
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
 			return
 		}
-
-		c.Next()
+		authenticate(c)
 	}
 }
-
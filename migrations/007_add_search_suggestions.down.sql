@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS search_queries;
+DROP INDEX IF EXISTS idx_categories_name_trgm;
+DROP INDEX IF EXISTS idx_categories_name_prefix;
+DROP INDEX IF EXISTS idx_brands_name_trgm;
+DROP INDEX IF EXISTS idx_brands_name_prefix;
+DROP INDEX IF EXISTS idx_products_name_prefix;
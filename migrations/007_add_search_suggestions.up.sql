@@ -0,0 +1,17 @@
+-- Search autocomplete: prefix and trigram lookups on names
+CREATE INDEX IF NOT EXISTS idx_products_name_prefix ON products (LOWER(name) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_brands_name_prefix ON brands (LOWER(name) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_brands_name_trgm ON brands USING GIN(name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_categories_name_prefix ON categories (LOWER(name) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING GIN(name gin_trgm_ops);
+
+-- Search query log, used to report searches that find nothing
+CREATE TABLE IF NOT EXISTS search_queries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    query VARCHAR(255) NOT NULL,
+    result_count BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_queries_query ON search_queries(query);
+CREATE INDEX IF NOT EXISTS idx_search_queries_created_at ON search_queries(created_at);
@@ -0,0 +1,21 @@
+-- Bulk catalog imports, see POST /api/v1/admin/products/imports
+CREATE TABLE IF NOT EXISTS product_import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    requested_by UUID NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    dry_run BOOLEAN DEFAULT false,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    total INTEGER DEFAULT 0,
+    processed INTEGER DEFAULT 0,
+    created INTEGER DEFAULT 0,
+    updated INTEGER DEFAULT 0,
+    failed INTEGER DEFAULT 0,
+    errors JSONB,
+    error TEXT,
+    data BYTEA,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_import_jobs_status ON product_import_jobs(status);
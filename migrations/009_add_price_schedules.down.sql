@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS price_history_immutable ON price_history;
+DROP FUNCTION IF EXISTS price_history_immutable();
+DROP TABLE IF EXISTS price_history;
+DROP TABLE IF EXISTS price_schedules;
@@ -0,0 +1,57 @@
+-- Scheduled prices, e.g. weekend sales
+CREATE TABLE IF NOT EXISTS price_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id UUID REFERENCES product_variants(id) ON DELETE CASCADE,
+    price DECIMAL(10,2) NOT NULL,
+    compare_price DECIMAL(10,2),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    previous_price DECIMAL(10,2),
+    previous_compare_price DECIMAL(10,2),
+    applied_at TIMESTAMP,
+    ended_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_schedules_product_id ON price_schedules(product_id);
+CREATE INDEX IF NOT EXISTS idx_price_schedules_variant_id ON price_schedules(variant_id);
+CREATE INDEX IF NOT EXISTS idx_price_schedules_status ON price_schedules(status);
+CREATE INDEX IF NOT EXISTS idx_price_schedules_starts_at ON price_schedules(starts_at);
+CREATE INDEX IF NOT EXISTS idx_price_schedules_ends_at ON price_schedules(ends_at);
+
+-- Every price a product or variant has had. Rows outlive their product so
+-- the history stays complete, and cannot be changed once written.
+CREATE TABLE IF NOT EXISTS price_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    variant_id UUID,
+    price DECIMAL(10,2),
+    compare_price DECIMAL(10,2),
+    source VARCHAR(20) NOT NULL,
+    schedule_id UUID,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_history_target ON price_history(product_id, variant_id, created_at);
+
+CREATE OR REPLACE FUNCTION price_history_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'price_history rows cannot be changed or deleted';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS price_history_immutable ON price_history;
+CREATE TRIGGER price_history_immutable
+    BEFORE UPDATE OR DELETE ON price_history
+    FOR EACH ROW EXECUTE FUNCTION price_history_immutable();
+
+-- Start the history from the current prices
+INSERT INTO price_history (product_id, price, compare_price, source)
+SELECT id, price, compare_price, 'backfill' FROM products;
+
+INSERT INTO price_history (product_id, variant_id, price, source)
+SELECT product_id, id, price, 'backfill' FROM product_variants WHERE price IS NOT NULL;
@@ -0,0 +1,21 @@
+DROP TRIGGER IF EXISTS attribute_definitions_search_vector_trigger ON attribute_definitions;
+DROP TRIGGER IF EXISTS product_attribute_values_search_vector_trigger ON product_attribute_values;
+DROP FUNCTION IF EXISTS attribute_definitions_search_vector_refresh();
+DROP FUNCTION IF EXISTS product_attribute_values_search_vector_refresh();
+DROP TABLE IF EXISTS product_attribute_values;
+DROP TABLE IF EXISTS attribute_definitions;
+
+-- Restore the search document of 006_add_product_search
+CREATE OR REPLACE FUNCTION products_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('english', coalesce(NEW.name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce((SELECT name FROM brands WHERE id = NEW.brand_id), '')), 'B') ||
+        setweight(to_tsvector('english', coalesce((SELECT name FROM categories WHERE id = NEW.category_id), '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(array_to_string(NEW.tags, ' '), '')), 'C') ||
+        setweight(to_tsvector('english', coalesce(NEW.description, '')), 'D');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+UPDATE products SET name = name;
@@ -0,0 +1,91 @@
+-- Typed product attributes (width, drop, closure, ...) defined per category
+-- and inherited by its descendants
+CREATE TABLE IF NOT EXISTS attribute_definitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('enum', 'number', 'boolean')),
+    unit VARCHAR(20),
+    allowed_values TEXT[],
+    filterable BOOLEAN DEFAULT true,
+    sort_order INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attribute_definitions_category_code ON attribute_definitions(category_id, code);
+CREATE INDEX IF NOT EXISTS idx_attribute_definitions_code ON attribute_definitions(code);
+
+-- Values of a product, or of a variant when variant_id is set. value holds
+-- the canonical text and number_value the number for comparisons.
+CREATE TABLE IF NOT EXISTS product_attribute_values (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id UUID REFERENCES product_variants(id) ON DELETE CASCADE,
+    attribute_id UUID NOT NULL REFERENCES attribute_definitions(id) ON DELETE CASCADE,
+    value TEXT NOT NULL,
+    number_value NUMERIC,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_attribute_values_product_id ON product_attribute_values(product_id);
+CREATE INDEX IF NOT EXISTS idx_product_attribute_values_variant_id ON product_attribute_values(variant_id);
+CREATE INDEX IF NOT EXISTS idx_product_attribute_values_attribute_id ON product_attribute_values(attribute_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_attribute_values_product_unique
+    ON product_attribute_values(product_id, attribute_id) WHERE variant_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_attribute_values_variant_unique
+    ON product_attribute_values(variant_id, attribute_id) WHERE variant_id IS NOT NULL;
+
+-- Make attributes searchable: enum values ("wide", "leather") and the names
+-- of true boolean attributes ("Waterproof") join the tags at weight C
+CREATE OR REPLACE FUNCTION products_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('english', coalesce(NEW.name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce((SELECT name FROM brands WHERE id = NEW.brand_id), '')), 'B') ||
+        setweight(to_tsvector('english', coalesce((SELECT name FROM categories WHERE id = NEW.category_id), '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(array_to_string(NEW.tags, ' '), '')), 'C') ||
+        setweight(to_tsvector('english', coalesce((
+            SELECT string_agg(CASE d.type
+                                  WHEN 'enum' THEN v.value
+                                  WHEN 'boolean' THEN CASE WHEN v.value = 'true' THEN d.name END
+                              END, ' ')
+            FROM product_attribute_values v
+            JOIN attribute_definitions d ON d.id = v.attribute_id
+            WHERE v.product_id = NEW.id), '')), 'C') ||
+        setweight(to_tsvector('english', coalesce(NEW.description, '')), 'D');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+-- Changing a product's values, or renaming an attribute, re-indexes the
+-- products concerned
+CREATE OR REPLACE FUNCTION product_attribute_values_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE products SET name = name WHERE id = OLD.product_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE products SET name = name WHERE id = NEW.product_id;
+    END IF;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER product_attribute_values_search_vector_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON product_attribute_values
+    FOR EACH ROW EXECUTE FUNCTION product_attribute_values_search_vector_refresh();
+
+CREATE OR REPLACE FUNCTION attribute_definitions_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    UPDATE products SET name = name
+    WHERE id IN (SELECT product_id FROM product_attribute_values WHERE attribute_id = NEW.id);
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER attribute_definitions_search_vector_trigger
+    AFTER UPDATE OF name ON attribute_definitions
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION attribute_definitions_search_vector_refresh();
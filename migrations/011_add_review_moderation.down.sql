@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_reviews_content_hash;
+DROP INDEX IF EXISTS idx_reviews_status_created_at;
+ALTER TABLE reviews
+    DROP COLUMN IF EXISTS moderated_at,
+    DROP COLUMN IF EXISTS moderated_by,
+    DROP COLUMN IF EXISTS moderation_reason,
+    DROP COLUMN IF EXISTS content_hash,
+    DROP COLUMN IF EXISTS screening_flags;
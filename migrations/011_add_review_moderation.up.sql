@@ -0,0 +1,10 @@
+-- Review moderation: screening results and the moderator's decision
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS screening_flags TEXT[],
+    ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS moderation_reason TEXT,
+    ADD COLUMN IF NOT EXISTS moderated_by UUID,
+    ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_reviews_status_created_at ON reviews(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_reviews_content_hash ON reviews(content_hash);
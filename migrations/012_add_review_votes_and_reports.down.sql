@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS review_reports;
+DROP TABLE IF EXISTS review_votes;
+ALTER TABLE reviews DROP COLUMN IF EXISTS not_helpful_count;
@@ -0,0 +1,29 @@
+-- Helpfulness votes and abuse reports on reviews. Each shopper has one vote
+-- and one report per review. user_id has no foreign key so erased users'
+-- rows can be re-owned by a pseudonym, as their reviews are.
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS not_helpful_count INTEGER DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS review_votes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    helpful BOOLEAN NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_review_votes_review_user ON review_votes(review_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_review_votes_user_id ON review_votes(user_id);
+
+CREATE TABLE IF NOT EXISTS review_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_review_report_reason CHECK (reason IN ('spam', 'offensive', 'off_topic', 'fake', 'other'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_review_reports_review_user ON review_reports(review_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_review_reports_user_id ON review_reports(user_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_products_rating;
+ALTER TABLE products
+    DROP COLUMN IF EXISTS rating_histogram,
+    DROP COLUMN IF EXISTS rating_count,
+    DROP COLUMN IF EXISTS rating_average;
@@ -0,0 +1,9 @@
+-- Rating aggregates of each product's published reviews, kept up to date by
+-- the product service when reviews change. Fill them for existing reviews
+-- with `make backfill-ratings`.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS rating_average DECIMAL(3,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rating_histogram INTEGER[] NOT NULL DEFAULT '{0,0,0,0,0}';
+
+CREATE INDEX IF NOT EXISTS idx_products_rating ON products(rating_average DESC, rating_count DESC);
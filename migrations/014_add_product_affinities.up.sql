@@ -0,0 +1,14 @@
+-- Related products learned from orders and browsing, recomputed wholesale by
+-- the product service's recommendation job. There are no foreign keys so the
+-- job can replace a source's rows in bulk; readers skip inactive products.
+CREATE TABLE IF NOT EXISTS product_affinities (
+    product_id UUID NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    related_product_id UUID NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    support INTEGER NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, source, related_product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_affinities_ranking ON product_affinities(product_id, source, score DESC, support DESC);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_products_recent_views;
+ALTER TABLE products DROP COLUMN IF EXISTS recent_views;
+DROP TABLE IF EXISTS product_co_views;
+DROP TABLE IF EXISTS product_view_stats;
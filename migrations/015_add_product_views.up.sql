@@ -0,0 +1,26 @@
+-- Daily product view counts, copied from Redis by the product service's view
+-- compaction job. Co-views count views of two products in the same browsing
+-- session and feed the "similar" recommendations.
+CREATE TABLE IF NOT EXISTS product_view_stats (
+    product_id UUID NOT NULL,
+    date DATE NOT NULL,
+    views INTEGER NOT NULL,
+    PRIMARY KEY (product_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_view_stats_date ON product_view_stats(date);
+
+CREATE TABLE IF NOT EXISTS product_co_views (
+    product_id UUID NOT NULL,
+    related_product_id UUID NOT NULL,
+    date DATE NOT NULL,
+    views INTEGER NOT NULL,
+    PRIMARY KEY (product_id, related_product_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_co_views_date ON product_co_views(date);
+
+-- Views in the last 30 days, for the popularity sort
+ALTER TABLE products ADD COLUMN IF NOT EXISTS recent_views INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_products_recent_views ON products(recent_views DESC);
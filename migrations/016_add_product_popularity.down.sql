@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_products_units_sold;
+DROP INDEX IF EXISTS idx_products_popularity_score;
+ALTER TABLE products DROP COLUMN IF EXISTS popularity_score;
+ALTER TABLE products DROP COLUMN IF EXISTS units_sold;
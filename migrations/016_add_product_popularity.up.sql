@@ -0,0 +1,7 @@
+-- Popularity scores and 30-day units sold, recomputed by the product
+-- service's popularity job from sales, wishlist adds, views and ratings
+ALTER TABLE products ADD COLUMN IF NOT EXISTS units_sold INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS popularity_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_products_popularity_score ON products(popularity_score DESC);
+CREATE INDEX IF NOT EXISTS idx_products_units_sold ON products(units_sold DESC, popularity_score DESC);
@@ -0,0 +1,6 @@
+-- Cursor pagination continues a listing from the sort key and ID of the last
+-- row seen, so the default sorts of product and order listings are indexed
+-- together with the ID
+CREATE INDEX IF NOT EXISTS idx_products_created_at_id ON products(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_products_price_id ON products(price, id);
+CREATE INDEX IF NOT EXISTS idx_orders_created_at_id ON orders(created_at DESC, id DESC);
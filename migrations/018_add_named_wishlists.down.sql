@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_wishlist_items_wishlist_product;
+DROP INDEX IF EXISTS idx_wishlist_items_wishlist_id;
+
+-- Keep the earliest save of a product saved to several lists
+DELETE FROM wishlist_items a
+USING wishlist_items b
+WHERE a.user_id = b.user_id
+  AND a.product_id = b.product_id
+  AND (a.added_at, a.id) > (b.added_at, b.id);
+
+ALTER TABLE wishlist_items DROP COLUMN IF EXISTS wishlist_id;
+ALTER TABLE wishlist_items ADD CONSTRAINT wishlist_items_user_id_product_id_key UNIQUE (user_id, product_id);
+
+DROP TABLE IF EXISTS wishlists;
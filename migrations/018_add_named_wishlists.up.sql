@@ -0,0 +1,24 @@
+-- Named wishlists. Every user has at most one default list, which backs the
+-- single-list /wishlist endpoints and is created on first use.
+CREATE TABLE IF NOT EXISTS wishlists (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    visibility VARCHAR(20) NOT NULL DEFAULT 'private',
+    share_slug VARCHAR(32) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_wishlists_user_id ON wishlists(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlists_share_slug ON wishlists(share_slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlists_user_default ON wishlists(user_id) WHERE is_default;
+
+-- Items saved before named wishlists have no wishlist_id until the user's
+-- default list is created, which moves them into it. A product can now be in
+-- several of a user's lists, but only once per list.
+ALTER TABLE wishlist_items ADD COLUMN IF NOT EXISTS wishlist_id UUID REFERENCES wishlists(id) ON DELETE CASCADE;
+ALTER TABLE wishlist_items DROP CONSTRAINT IF EXISTS wishlist_items_user_id_product_id_key;
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_wishlist_id ON wishlist_items(wishlist_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlist_items_wishlist_product ON wishlist_items(wishlist_id, product_id);
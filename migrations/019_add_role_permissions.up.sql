@@ -0,0 +1,9 @@
+-- Permissions granted to each role. user-service seeds the defaults from
+-- authz.DefaultRolePermissions for roles that have no rows yet, and admins
+-- edit them through the roles API.
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(50) NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (role, permission)
+);
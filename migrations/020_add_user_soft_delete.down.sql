@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_users_deleted_at;
+
+-- Soft deleted users would otherwise be able to sign in again
+UPDATE users SET is_active = false WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE users DROP COLUMN IF EXISTS sessions_revoked_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,6 @@
+-- Admins soft delete users, who can be restored until the account is erased.
+-- Refresh tokens issued before sessions_revoked_at are refused.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS sessions_revoked_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
@@ -0,0 +1,20 @@
+-- GDPR export and erasure requests. Rows are never deleted and serve as the
+-- audit trail; results holds the outcome per service and archive the finished
+-- export until it expires.
+CREATE TABLE IF NOT EXISTS privacy_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    requested_by UUID NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('export', 'erasure')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'completed', 'failed')),
+    results JSONB,
+    error TEXT,
+    archive BYTEA,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_user_id ON privacy_requests(user_id);
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_active ON privacy_requests(user_id, type) WHERE status IN ('pending', 'processing');
@@ -0,0 +1,12 @@
+-- Last price-drop and restock alert sent to a user per wishlisted product,
+-- so repeated events within the cooldown do not notify the user again
+CREATE TABLE IF NOT EXISTS wishlist_alerts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    type TEXT NOT NULL,
+    price DECIMAL(10,2),
+    notified_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlist_alert ON wishlist_alerts(user_id, product_id, type);
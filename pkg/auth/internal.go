@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"solemate/pkg/authz"
+)
+
+// InternalTokenHeader carries the signed token used for service-to-service calls.
+const InternalTokenHeader = "X-Internal-Token"
+
+// Service identities
+const (
+	ServiceGateway      = "api-gateway"
+	ServiceUser         = "user-service"
+	ServiceProduct      = "product-service"
+	ServiceCart         = "cart-service"
+	ServiceOrder        = "order-service"
+	ServicePayment      = "payment-service"
+	ServiceInventory    = "inventory-service"
+	ServiceNotification = "notification-service"
+)
+
+// InternalClaims identifies the calling service and, when the call is made on a
+// user's behalf, the user context the gateway authenticated.
+type InternalClaims struct {
+	Service     string   `json:"svc"`
+	UserID      string   `json:"user_id,omitempty"`
+	Email       string   `json:"email,omitempty"`
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// InternalTokenManager mints and verifies short-lived tokens scoped to a single
+// target service. All services share INTERNAL_TOKEN_SECRET; the audience claim
+// prevents a token minted for one service from being replayed against another.
+type InternalTokenManager struct {
+	service string
+	secret  string
+	ttl     time.Duration
+}
+
+// developmentInternalTokenSecret is only used when ENV is development and
+// INTERNAL_TOKEN_SECRET is unset.
+const developmentInternalTokenSecret = "default-internal-secret"
+
+// ErrInternalTokenSecretUnset is returned outside development when
+// INTERNAL_TOKEN_SECRET is unset or still the well-known development secret,
+// which would let anyone mint internal tokens.
+var ErrInternalTokenSecretUnset = errors.New("INTERNAL_TOKEN_SECRET must be set to a private value outside development")
+
+func NewInternalTokenManager(service string) (*InternalTokenManager, error) {
+	secret := os.Getenv("INTERNAL_TOKEN_SECRET")
+	if getEnv("ENV", "development") != "development" {
+		if secret == "" || secret == developmentInternalTokenSecret {
+			return nil, ErrInternalTokenSecretUnset
+		}
+	} else if secret == "" {
+		secret = developmentInternalTokenSecret
+	}
+
+	return &InternalTokenManager{
+		service: service,
+		secret:  secret,
+		ttl:     time.Minute,
+	}, nil
+}
+
+// MintForUser signs a token carrying the given user context for a call to audience.
+func (m *InternalTokenManager) MintForUser(audience string, user *Claims) (string, error) {
+	return m.mint(audience, &InternalClaims{
+		UserID:      user.UserID,
+		Email:       user.Email,
+		Role:        user.Role,
+		Permissions: user.Permissions,
+	})
+}
+
+// MintForService signs a token carrying only this service's own identity and the
+// permissions granted to it in authz.ServicePermissions.
+func (m *InternalTokenManager) MintForService(audience string) (string, error) {
+	return m.mint(audience, &InternalClaims{
+		Permissions: authz.Strings(authz.ServicePermissions[m.service]),
+	})
+}
+
+func (m *InternalTokenManager) mint(audience string, claims *InternalClaims) (string, error) {
+	now := time.Now()
+	claims.Service = m.service
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    m.service,
+		Audience:  jwt.ClaimStrings{audience},
+		ExpiresAt: jwt.NewNumericDate(now.Add(m.ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ID:        uuid.New().String(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(m.secret))
+}
+
+// Validate verifies a token and checks that it was minted for this service.
+func (m *InternalTokenManager) Validate(tokenString string) (*InternalClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &InternalClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(m.secret), nil
+	}, jwt.WithAudience(m.service))
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*InternalClaims)
+	if !ok || !token.Valid || claims.Service == "" {
+		return nil, errors.New("invalid internal token")
+	}
+
+	return claims, nil
+}
+
+// ServiceAuthMiddleware authenticates requests arriving at a backend service.
+// Calls forwarded by the gateway or made by another service must carry a valid
+// X-Internal-Token; callers reaching the service directly may instead present a
+// user access token. Plain X-User-* headers are never trusted.
+//
+// Access tokens of revoked sessions are refused like at the gateway. Unlike the
+// gateway, a revocation that cannot be checked refuses the token, and with nil
+// revocations access tokens are not accepted at all: direct access is only a
+// convenience, and the gateway stays available to every caller.
+func ServiceAuthMiddleware(internalTokens *InternalTokenManager, jwtManager *JWTManager, revocations *SessionRevocations) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if internalToken := c.GetHeader(InternalTokenHeader); internalToken != "" {
+			claims, err := internalTokens.Validate(internalToken)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired internal token"})
+				c.Abort()
+				return
+			}
+
+			c.Set("caller_service", claims.Service)
+			c.Set(authz.ContextKey, claims.Permissions)
+
+			if claims.UserID != "" {
+				userID, err := uuid.Parse(claims.UserID)
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID in token"})
+					c.Abort()
+					return
+				}
+				c.Set("user_id", userID)
+				c.Set("user_email", claims.Email)
+				c.Set("user_role", claims.Role)
+			}
+
+			c.Next()
+			return
+		}
+
+		authHeader := c.GetHeader("Authorization")
+		tokenParts := strings.Split(authHeader, " ")
+		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.Abort()
+			return
+		}
+
+		claims, err := jwtManager.ValidateAccessToken(tokenParts[1])
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			c.Abort()
+			return
+		}
+
+		if revocations == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Access tokens are only accepted through the API gateway"})
+			c.Abort()
+			return
+		}
+		revoked, err := revocations.IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			log.Printf("failed to check session revocation of user %s: %v", claims.UserID, err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify session"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked"})
+			c.Abort()
+			return
+		}
+
+		userID, err := uuid.Parse(claims.UserID)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID in token"})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", userID)
+		c.Set("user_email", claims.Email)
+		c.Set("user_role", claims.Role)
+		c.Set(authz.ContextKey, claims.Permissions)
+
+		c.Next()
+	}
+}
+
+// OptionalServiceAuthMiddleware authenticates requests that carry an internal
+// token or access token like ServiceAuthMiddleware, and lets requests without
+// either through anonymously.
+func OptionalServiceAuthMiddleware(internalTokens *InternalTokenManager, jwtManager *JWTManager, revocations *SessionRevocations) gin.HandlerFunc {
+	authenticate := ServiceAuthMiddleware(internalTokens, jwtManager, revocations)
+	return func(c *gin.Context) {
+		if c.GetHeader(InternalTokenHeader) == "" && c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		authenticate(c)
+	}
+}
+
+// CurrentUserID returns the authenticated user's ID set by the auth middleware.
+func CurrentUserID(c *gin.Context) (uuid.UUID, bool) {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return uuid.Nil, false
+	}
+	userID, ok := value.(uuid.UUID)
+	return userID, ok
+}
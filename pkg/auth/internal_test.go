@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"solemate/pkg/authz"
+	"solemate/pkg/cache"
+)
+
+func newInternalTokenManager(t *testing.T, service string) *InternalTokenManager {
+	t.Helper()
+	m, err := NewInternalTokenManager(service)
+	require.NoError(t, err)
+	return m
+}
+
+func TestNewInternalTokenManagerSecret(t *testing.T) {
+	tests := map[string]struct {
+		env     string
+		secret  string
+		wantErr bool
+	}{
+		"development falls back to the default secret": {env: "development"},
+		"unset environment is development":             {},
+		"production requires a secret":                 {env: "production", wantErr: true},
+		"production rejects the default secret":        {env: "production", secret: "default-internal-secret", wantErr: true},
+		"production with a secret":                     {env: "production", secret: "s3cret"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("ENV", tt.env)
+			t.Setenv("INTERNAL_TOKEN_SECRET", tt.secret)
+
+			m, err := NewInternalTokenManager(ServiceOrder)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInternalTokenSecretUnset)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, m.secret)
+		})
+	}
+}
+
+func TestInternalTokenAudience(t *testing.T) {
+	gateway := newInternalTokenManager(t, ServiceGateway)
+	orders := newInternalTokenManager(t, ServiceOrder)
+	products := newInternalTokenManager(t, ServiceProduct)
+
+	token, err := gateway.MintForUser(ServiceOrder, &Claims{UserID: uuid.New().String(), Role: "customer"})
+	require.NoError(t, err)
+
+	claims, err := orders.Validate(token)
+	require.NoError(t, err)
+	assert.Equal(t, ServiceGateway, claims.Service)
+
+	_, err = products.Validate(token)
+	assert.Error(t, err)
+}
+
+func TestServiceAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	orders := newInternalTokenManager(t, ServiceOrder)
+	payments := newInternalTokenManager(t, ServicePayment)
+	serviceToken, err := payments.MintForService(ServiceOrder)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected int
+	}{
+		{"no credentials", nil, http.StatusUnauthorized},
+		{"spoofed user header", map[string]string{"X-User-ID": uuid.New().String()}, http.StatusUnauthorized},
+		{"forged internal token", map[string]string{InternalTokenHeader: "not-a-token"}, http.StatusUnauthorized},
+		{"service token", map[string]string{InternalTokenHeader: serviceToken}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/", ServiceAuthMiddleware(orders, NewJWTManager(), nil), func(c *gin.Context) {
+				assert.Equal(t, ServicePayment, c.GetString("caller_service"))
+				assert.True(t, authz.Allowed(c, authz.OrdersUpdatePayment))
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestServiceAuthMiddlewareAccessTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	server := miniredis.RunT(t)
+	redisClient, err := cache.NewRedisClient(cache.Config{Host: server.Host(), Port: server.Port()})
+	require.NoError(t, err)
+	revocations := NewSessionRevocations(redisClient)
+
+	jwtManager := NewJWTManager()
+	orders := newInternalTokenManager(t, ServiceOrder)
+	activeUser, revokedUser := uuid.New(), uuid.New()
+	activeToken, _, err := jwtManager.GenerateTokenPair(activeUser.String(), "active@example.com", "customer", nil)
+	require.NoError(t, err)
+	revokedToken, _, err := jwtManager.GenerateTokenPair(revokedUser.String(), "revoked@example.com", "customer", nil)
+	require.NoError(t, err)
+	require.NoError(t, revocations.Revoke(context.Background(), revokedUser.String(), time.Now().Add(time.Second)))
+
+	tests := map[string]struct {
+		token       string
+		revocations *SessionRevocations
+		redisDown   bool
+		expected    int
+	}{
+		"active session":                     {token: activeToken, revocations: revocations, expected: http.StatusOK},
+		"revoked session":                    {token: revokedToken, revocations: revocations, expected: http.StatusUnauthorized},
+		"revocations cannot be checked":      {token: activeToken, revocations: revocations, redisDown: true, expected: http.StatusServiceUnavailable},
+		"without revocations tokens refused": {token: activeToken, expected: http.StatusUnauthorized},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tt.redisDown {
+				server.SetError("LOADING")
+				defer server.SetError("")
+			}
+
+			r := gin.New()
+			r.GET("/", ServiceAuthMiddleware(orders, jwtManager, tt.revocations), func(c *gin.Context) {
+				id, ok := CurrentUserID(c)
+				assert.True(t, ok)
+				assert.Equal(t, activeUser, id)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestOptionalServiceAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	products := newInternalTokenManager(t, ServiceProduct)
+	gateway := newInternalTokenManager(t, ServiceGateway)
+	userID := uuid.New()
+	userToken, err := gateway.MintForUser(ServiceProduct, &Claims{UserID: userID.String()})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected int
+		user     bool
+	}{
+		{"anonymous", nil, http.StatusOK, false},
+		{"spoofed user header", map[string]string{"X-User-ID": userID.String()}, http.StatusOK, false},
+		{"forged internal token", map[string]string{InternalTokenHeader: "not-a-token"}, http.StatusUnauthorized, false},
+		{"invalid access token", map[string]string{"Authorization": "Bearer not-a-token"}, http.StatusUnauthorized, false},
+		{"user token", map[string]string{InternalTokenHeader: userToken}, http.StatusOK, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/", OptionalServiceAuthMiddleware(products, NewJWTManager(), nil), func(c *gin.Context) {
+				id, ok := CurrentUserID(c)
+				assert.Equal(t, tt.user, ok)
+				if tt.user {
+					assert.Equal(t, userID, id)
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
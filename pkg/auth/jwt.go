@@ -10,15 +10,20 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"solemate/pkg/authz"
 )
 
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID      string   `json:"user_id"`
+	Email       string   `json:"email"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// AccessTokenTTL is how long an access token is valid
+const AccessTokenTTL = 15 * time.Minute
+
 type JWTManager struct {
 	accessSecret  string
 	refreshSecret string
@@ -30,17 +35,18 @@ func NewJWTManager() *JWTManager {
 	return &JWTManager{
 		accessSecret:  getEnv("JWT_ACCESS_SECRET", "default-access-secret"),
 		refreshSecret: getEnv("JWT_REFRESH_SECRET", "default-refresh-secret"),
-		accessTTL:     15 * time.Minute,
+		accessTTL:     AccessTokenTTL,
 		refreshTTL:    7 * 24 * time.Hour, // 7 days
 	}
 }
 
-func (j *JWTManager) GenerateTokenPair(userID, email, role string) (accessToken, refreshToken string, err error) {
+func (j *JWTManager) GenerateTokenPair(userID, email, role string, permissions []string) (accessToken, refreshToken string, err error) {
 	// Generate access token
 	accessClaims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:      userID,
+		Email:       email,
+		Role:        role,
+		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.accessTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -57,9 +63,10 @@ func (j *JWTManager) GenerateTokenPair(userID, email, role string) (accessToken,
 
 	// Generate refresh token
 	refreshClaims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:      userID,
+		Email:       email,
+		Role:        role,
+		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.refreshTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -111,10 +118,16 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func JWTMiddleware(secret string) gin.HandlerFunc {
-	jwtManager := &JWTManager{
+// NewAccessTokenValidator returns a JWTManager that only validates access tokens
+// signed with secret. Backend services use it with their configured JWT secret.
+func NewAccessTokenValidator(secret string) *JWTManager {
+	return &JWTManager{
 		accessSecret: secret,
 	}
+}
+
+func JWTMiddleware(secret string) gin.HandlerFunc {
+	jwtManager := NewAccessTokenValidator(secret)
 
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -152,6 +165,7 @@ func JWTMiddleware(secret string) gin.HandlerFunc {
 		c.Set("user_id", userID)
 		c.Set("user_email", claims.Email)
 		c.Set("user_role", claims.Role)
+		c.Set(authz.ContextKey, claims.Permissions)
 
 		c.Next()
 	}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"solemate/pkg/cache"
+)
+
+// SessionRevocations shares the time each user's sessions were last revoked
+// between user-service, which revokes them, and the gateway, which refuses
+// access tokens issued before. An entry is kept for AccessTokenTTL: by then
+// every access token it covers has expired on its own. A nil
+// *SessionRevocations revokes nothing.
+type SessionRevocations struct {
+	redis *cache.RedisClient
+}
+
+func NewSessionRevocations(redis *cache.RedisClient) *SessionRevocations {
+	return &SessionRevocations{redis: redis}
+}
+
+// Revoke refuses the user's access tokens issued before at
+func (r *SessionRevocations) Revoke(ctx context.Context, userID string, at time.Time) error {
+	if r == nil {
+		return nil
+	}
+	return r.redis.Set(ctx, revokedSessionsKey(userID), at.Unix(), AccessTokenTTL)
+}
+
+// IsRevoked reports whether the access token was issued before the user's
+// sessions were revoked
+func (r *SessionRevocations) IsRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	if r == nil {
+		return false, nil
+	}
+
+	value, err := r.redis.Get(ctx, revokedSessionsKey(claims.UserID))
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	revokedAt, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	return claims.IssuedBefore(time.Unix(revokedAt, 0)), nil
+}
+
+// IssuedBefore reports whether the token was issued before t. Token issue
+// times only have second precision, so a token issued in the same second
+// counts as issued after.
+func (c *Claims) IssuedBefore(t time.Time) bool {
+	return c.IssuedAt != nil && c.IssuedAt.Before(t.Truncate(time.Second))
+}
+
+func revokedSessionsKey(userID string) string {
+	return "auth:sessions:revoked:" + userID
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimsIssuedBefore(t *testing.T) {
+	revokedAt := time.Date(2026, 1, 1, 12, 0, 0, 500_000_000, time.UTC)
+
+	tests := map[string]struct {
+		issuedAt *jwt.NumericDate
+		want     bool
+	}{
+		"earlier second":    {issuedAt: jwt.NewNumericDate(revokedAt.Add(-time.Second)), want: true},
+		"same second":       {issuedAt: jwt.NewNumericDate(revokedAt.Truncate(time.Second)), want: false},
+		"after revocation":  {issuedAt: jwt.NewNumericDate(revokedAt.Add(time.Minute)), want: false},
+		"no issue time set": {issuedAt: nil, want: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			claims := &Claims{RegisteredClaims: jwt.RegisteredClaims{IssuedAt: tt.issuedAt}}
+			assert.Equal(t, tt.want, claims.IssuedBefore(revokedAt))
+		})
+	}
+}
+
+func TestNilSessionRevocations(t *testing.T) {
+	var revocations *SessionRevocations
+
+	assert.NoError(t, revocations.Revoke(context.Background(), "user", time.Now()))
+
+	revoked, err := revocations.IsRevoked(context.Background(), &Claims{UserID: "user"})
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
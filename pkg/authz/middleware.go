@@ -0,0 +1,47 @@
+package authz
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContextKey is the gin context key holding the caller's granted permissions.
+const ContextKey = "permissions"
+
+// Permissions returns the permissions stored in the request context by the
+// authentication middleware.
+func Permissions(c *gin.Context) []string {
+	value, exists := c.Get(ContextKey)
+	if !exists {
+		return nil
+	}
+	permissions, _ := value.([]string)
+	return permissions
+}
+
+// Allowed reports whether the caller holds permission p.
+func Allowed(c *gin.Context, p Permission) bool {
+	return Has(Permissions(c), p)
+}
+
+// RequirePermission aborts with 403 unless the caller holds every listed permission.
+// It must run after the authentication middleware.
+func RequirePermission(permissions ...Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted := Permissions(c)
+		for _, p := range permissions {
+			if !Has(granted, p) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"success": false,
+					"message": "Missing permission: " + string(p),
+					"error":   "forbidden",
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
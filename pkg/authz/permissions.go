@@ -0,0 +1,137 @@
+package authz
+
+// Permission is a named capability that can be granted to a role, e.g. "orders:ship".
+type Permission string
+
+const (
+	// User management
+	UsersRead   Permission = "users:read"
+	UsersWrite  Permission = "users:write"
+	RolesManage Permission = "roles:manage"
+
+	// Catalog management
+	ProductsWrite   Permission = "products:write"
+	CategoriesWrite Permission = "categories:write"
+	BrandsWrite     Permission = "brands:write"
+	ReviewsModerate Permission = "reviews:moderate"
+
+	// Order management
+	OrdersRead          Permission = "orders:read"
+	OrdersUpdateStatus  Permission = "orders:update_status"
+	OrdersShip          Permission = "orders:ship"
+	OrdersUpdatePayment Permission = "orders:update_payment"
+
+	// Payments
+	PaymentsRead  Permission = "payments:read"
+	RefundsCreate Permission = "refunds:create"
+
+	// Inventory
+	InventoryRead   Permission = "inventory:read"
+	InventoryAdjust Permission = "inventory:adjust"
+
+	// Notifications
+	NotificationsSend   Permission = "notifications:send"
+	NotificationsManage Permission = "notifications:manage"
+	TemplatesWrite      Permission = "templates:write"
+
+	// Reporting
+	AnalyticsRead Permission = "analytics:read"
+
+	// Cross-service export and erasure of a user's personal data
+	PrivacyManage Permission = "privacy:manage"
+)
+
+const (
+	RoleCustomer = "customer"
+	RoleManager  = "manager"
+	RoleAdmin    = "admin"
+)
+
+// AllPermissions lists every permission known to the platform.
+var AllPermissions = []Permission{
+	UsersRead,
+	UsersWrite,
+	RolesManage,
+	ProductsWrite,
+	CategoriesWrite,
+	BrandsWrite,
+	ReviewsModerate,
+	OrdersRead,
+	OrdersUpdateStatus,
+	OrdersShip,
+	OrdersUpdatePayment,
+	PaymentsRead,
+	RefundsCreate,
+	InventoryRead,
+	InventoryAdjust,
+	NotificationsSend,
+	NotificationsManage,
+	TemplatesWrite,
+	AnalyticsRead,
+	PrivacyManage,
+}
+
+// DefaultRolePermissions is the role→permission mapping user-service seeds on
+// first start. After that the mapping lives in the role_permissions table.
+var DefaultRolePermissions = map[string][]Permission{
+	RoleCustomer: {},
+	RoleManager: {
+		UsersRead,
+		ProductsWrite,
+		CategoriesWrite,
+		BrandsWrite,
+		ReviewsModerate,
+		OrdersRead,
+		OrdersUpdateStatus,
+		OrdersShip,
+		PaymentsRead,
+		InventoryRead,
+		InventoryAdjust,
+		NotificationsSend,
+		AnalyticsRead,
+	},
+	RoleAdmin: AllPermissions,
+}
+
+// ServicePermissions grants permissions to service identities for calls a
+// service makes on its own behalf rather than on behalf of a user.
+var ServicePermissions = map[string][]Permission{
+	"payment-service": {OrdersRead, OrdersUpdatePayment},
+	"user-service":    {PrivacyManage, NotificationsSend},
+	"product-service": {NotificationsSend, AnalyticsRead},
+}
+
+// IsValidRole reports whether role is one of the built-in roles.
+func IsValidRole(role string) bool {
+	_, ok := DefaultRolePermissions[role]
+	return ok
+}
+
+// IsValidPermission reports whether p is a known permission.
+func IsValidPermission(p string) bool {
+	for _, permission := range AllPermissions {
+		if string(permission) == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Has reports whether granted contains p.
+func Has(granted []string, p Permission) bool {
+	for _, g := range granted {
+		if g == string(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Strings converts a permission list to the string form embedded in tokens.
+func Strings(permissions []Permission) []string {
+	result := make([]string, len(permissions))
+	for i, p := range permissions {
+		result[i] = string(p)
+	}
+	return result
+}
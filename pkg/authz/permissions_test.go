@@ -0,0 +1,59 @@
+package authz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultRolePermissions(t *testing.T) {
+	assert.Empty(t, DefaultRolePermissions[RoleCustomer])
+	assert.ElementsMatch(t, AllPermissions, DefaultRolePermissions[RoleAdmin])
+
+	manager := Strings(DefaultRolePermissions[RoleManager])
+	assert.True(t, Has(manager, OrdersShip))
+	assert.False(t, Has(manager, RolesManage))
+	assert.False(t, Has(manager, RefundsCreate))
+}
+
+func TestIsValidRoleAndPermission(t *testing.T) {
+	assert.True(t, IsValidRole("manager"))
+	assert.False(t, IsValidRole("superuser"))
+	assert.True(t, IsValidPermission("inventory:adjust"))
+	assert.False(t, IsValidPermission("inventory:delete"))
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		granted  []string
+		expected int
+	}{
+		{"no permissions", nil, http.StatusForbidden},
+		{"missing one", []string{string(OrdersRead)}, http.StatusForbidden},
+		{"has all", []string{string(OrdersRead), string(OrdersShip)}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/", func(c *gin.Context) {
+				if tt.granted != nil {
+					c.Set(ContextKey, tt.granted)
+				}
+				c.Next()
+			}, RequirePermission(OrdersRead, OrdersShip), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
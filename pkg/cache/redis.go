@@ -70,6 +70,52 @@ func (r *RedisClient) GetJSON(ctx context.Context, key string, dest interface{})
 	return nil
 }
 
+func (r *RedisClient) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return r.client.Expire(ctx, key, expiration).Err()
+}
+
+// Pipelined sends the commands queued by fn in one round trip
+func (r *RedisClient) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) error {
+	_, err := r.client.Pipelined(ctx, fn)
+	return err
+}
+
+// ZRevRange returns the members of a sorted set from start to stop, highest
+// score first
+func (r *RedisClient) ZRevRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return r.client.ZRevRange(ctx, key, start, stop).Result()
+}
+
+// ZRevRangeByScore returns up to count members scored between min and max,
+// highest score first
+func (r *RedisClient) ZRevRangeByScore(ctx context.Context, key, min, max string, count int64) ([]string, error) {
+	return r.client.ZRevRangeByScore(ctx, key, &redis.ZRangeBy{Min: min, Max: max, Count: count}).Result()
+}
+
+// ZScores returns every member of a sorted set with its score
+func (r *RedisClient) ZScores(ctx context.Context, key string) (map[string]float64, error) {
+	members, err := r.client.ZRangeWithScores(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[string]float64, len(members))
+	for _, member := range members {
+		scores[member.Member.(string)] = member.Score
+	}
+	return scores, nil
+}
+
+// ZUnionStore stores the weighted sum of sorted sets at dest, which expires
+// after expiration
+func (r *RedisClient) ZUnionStore(ctx context.Context, dest string, keys []string, weights []float64, expiration time.Duration) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZUnionStore(ctx, dest, &redis.ZStore{Keys: keys, Weights: weights})
+		pipe.Expire(ctx, dest, expiration)
+		return nil
+	})
+	return err
+}
+
 func (r *RedisClient) Close() error {
 	return r.client.Close()
 }
@@ -0,0 +1,220 @@
+// Package imaging validates uploaded images and produces the resized
+// renditions the storefront displays, each as a JPEG and a smaller WebP.
+// Every rendition is decoded and re-encoded from pixels, so EXIF and other
+// metadata never reach storage.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+
+	"github.com/gen2brain/webp"
+)
+
+const (
+	// MaxUploadSize is the largest image file accepted, in bytes.
+	MaxUploadSize = 10 << 20
+
+	// MaxPixels guards against small files that decode to huge images.
+	MaxPixels = 25_000_000
+
+	jpegQuality = 85
+	webpQuality = 80
+)
+
+var (
+	ErrTooLarge        = fmt.Errorf("image must be at most %d MB", MaxUploadSize>>20)
+	ErrUnsupportedType = errors.New("image must be a JPEG, PNG or GIF")
+	ErrTooManyPixels   = fmt.Errorf("image must be at most %d megapixels", MaxPixels/1_000_000)
+)
+
+// allowedTypes are the sniffed MIME types accepted for upload.
+var allowedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Rendition is a named output size. The image is scaled so its longest edge
+// is at most MaxEdge pixels; smaller images are never enlarged.
+type Rendition struct {
+	Name    string
+	MaxEdge int
+}
+
+const (
+	Thumbnail = "thumbnail"
+	Card      = "card"
+	Zoom      = "zoom"
+)
+
+// Renditions are generated for every upload.
+var Renditions = []Rendition{
+	{Name: Thumbnail, MaxEdge: 150},
+	{Name: Card, MaxEdge: 600},
+	{Name: Zoom, MaxEdge: 1600},
+}
+
+// Content types renditions are encoded in.
+const (
+	JPEG = "image/jpeg"
+	WebP = "image/webp"
+)
+
+// Formats are encoded for every rendition. WebP files are smaller; the JPEG
+// is kept for clients that cannot display WebP.
+var Formats = []string{JPEG, WebP}
+
+var extensions = map[string]string{
+	JPEG: ".jpg",
+	WebP: ".webp",
+}
+
+// Output is one encoded rendition.
+type Output struct {
+	Name        string
+	ContentType string
+	Data        []byte
+	Width       int
+	Height      int
+}
+
+// Filename returns the file name a rendition is stored under in the given
+// format.
+func Filename(rendition, contentType string) string {
+	return rendition + extensions[contentType]
+}
+
+// DetectType returns the MIME type sniffed from the file contents, ignoring
+// whatever the client claimed.
+func DetectType(data []byte) string {
+	return http.DetectContentType(data)
+}
+
+// Process validates an uploaded image and returns each of Renditions in each
+// of Formats.
+func Process(data []byte) ([]Output, error) {
+	if len(data) > MaxUploadSize {
+		return nil, ErrTooLarge
+	}
+	if !allowedTypes[DetectType(data)] {
+		return nil, ErrUnsupportedType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+
+	src := orient(flatten(decoded), jpegOrientation(data))
+
+	outputs := make([]Output, 0, len(Renditions)*len(Formats))
+	for _, rendition := range Renditions {
+		width, height := fit(src.Bounds().Dx(), src.Bounds().Dy(), rendition.MaxEdge)
+		resized := resize(src, width, height)
+
+		for _, contentType := range Formats {
+			var buf bytes.Buffer
+			if err := encode(&buf, resized, contentType); err != nil {
+				return nil, fmt.Errorf("failed to encode %s rendition as %s: %w", rendition.Name, contentType, err)
+			}
+
+			outputs = append(outputs, Output{
+				Name:        rendition.Name,
+				ContentType: contentType,
+				Data:        buf.Bytes(),
+				Width:       width,
+				Height:      height,
+			})
+		}
+	}
+
+	return outputs, nil
+}
+
+func encode(w io.Writer, img image.Image, contentType string) error {
+	if contentType == WebP {
+		return webp.Encode(w, img, webp.Options{Quality: webpQuality})
+	}
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+}
+
+// flatten copies img onto a white RGBA canvas, so transparent PNG and GIF
+// areas don't turn black in the JPEG output. The WebP output is flattened the
+// same way so both formats look alike.
+func flatten(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Over)
+	return dst
+}
+
+// fit scales width x height down so the longest edge is at most maxEdge.
+func fit(width, height, maxEdge int) (int, int) {
+	if width <= maxEdge && height <= maxEdge {
+		return width, height
+	}
+	if width >= height {
+		return maxEdge, max(1, height*maxEdge/width)
+	}
+	return max(1, width*maxEdge/height), maxEdge
+}
+
+// resize downscales src to width x height by averaging the source pixels
+// covered by each destination pixel.
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	if srcWidth == width && srcHeight == height {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for dy := 0; dy < height; dy++ {
+		y0 := dy * srcHeight / height
+		y1 := max(y0+1, (dy+1)*srcHeight/height)
+
+		for dx := 0; dx < width; dx++ {
+			x0 := dx * srcWidth / width
+			x1 := max(x0+1, (dx+1)*srcWidth/width)
+
+			var r, g, b, a, n uint32
+			for y := y0; y < y1; y++ {
+				row := src.Pix[y*src.Stride:]
+				for x := x0; x < x1; x++ {
+					p := row[x*4 : x*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					b += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+
+			i := dy*dst.Stride + dx*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
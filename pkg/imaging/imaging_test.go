@@ -0,0 +1,138 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// jpegWithOrientation encodes img as a JPEG carrying a big-endian EXIF block
+// with the given orientation tag.
+func jpegWithOrientation(t *testing.T, img image.Image, orientation byte) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	encoded := buf.Bytes()
+
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08, // header, IFD at offset 8
+		0x00, 0x01, // one entry
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, orientation, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, // no next IFD
+	}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(payload) + 2
+
+	app1 := append([]byte{0xFF, 0xE1, byte(length >> 8), byte(length)}, payload...)
+
+	out := append([]byte{}, encoded[:2]...)
+	out = append(out, app1...)
+	return append(out, encoded[2:]...)
+}
+
+func TestProcess_GeneratesRenditions(t *testing.T) {
+	outputs, err := Process(encodePNG(t, testImage(2000, 1000)))
+	require.NoError(t, err)
+	require.Len(t, outputs, len(Renditions)*len(Formats))
+
+	expected := map[string][2]int{
+		Thumbnail: {150, 75},
+		Card:      {600, 300},
+		Zoom:      {1600, 800},
+	}
+	formats := map[string]string{JPEG: "jpeg", WebP: "webp"}
+	seen := map[string]int{}
+	for _, output := range outputs {
+		assert.Equal(t, expected[output.Name], [2]int{output.Width, output.Height}, output.Name)
+		seen[output.ContentType]++
+
+		decoded, format, err := image.Decode(bytes.NewReader(output.Data))
+		require.NoError(t, err)
+		assert.Equal(t, formats[output.ContentType], format, output.Name)
+		assert.Equal(t, output.Width, decoded.Bounds().Dx())
+		assert.Equal(t, output.Height, decoded.Bounds().Dy())
+	}
+	assert.Equal(t, map[string]int{JPEG: len(Renditions), WebP: len(Renditions)}, seen)
+}
+
+func TestFilename(t *testing.T) {
+	assert.Equal(t, "card.jpg", Filename(Card, JPEG))
+	assert.Equal(t, "card.webp", Filename(Card, WebP))
+}
+
+func TestProcess_DoesNotUpscale(t *testing.T) {
+	outputs, err := Process(encodePNG(t, testImage(100, 200)))
+	require.NoError(t, err)
+
+	for _, output := range outputs {
+		if output.Name == Thumbnail {
+			assert.Equal(t, [2]int{75, 150}, [2]int{output.Width, output.Height})
+			continue
+		}
+		assert.Equal(t, [2]int{100, 200}, [2]int{output.Width, output.Height}, output.Name)
+	}
+}
+
+func TestProcess_AppliesOrientationAndStripsExif(t *testing.T) {
+	data := jpegWithOrientation(t, testImage(40, 20), 6)
+	assert.Equal(t, 6, jpegOrientation(data))
+
+	outputs, err := Process(data)
+	require.NoError(t, err)
+
+	for _, output := range outputs {
+		assert.Equal(t, [2]int{20, 40}, [2]int{output.Width, output.Height}, output.Name)
+		assert.False(t, bytes.Contains(output.Data, []byte("Exif")), output.Name)
+	}
+}
+
+func TestProcess_RejectsInvalidUploads(t *testing.T) {
+	_, err := Process([]byte("<html><body>not an image</body></html>"))
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+
+	_, err = Process(make([]byte, MaxUploadSize+1))
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	truncated := encodePNG(t, testImage(50, 50))[:40]
+	_, err = Process(truncated)
+	assert.Error(t, err)
+}
+
+func TestOrient(t *testing.T) {
+	src := testImage(3, 2)
+
+	for orientation := 1; orientation <= 8; orientation++ {
+		dst := orient(src, orientation)
+		if orientation >= 5 {
+			assert.Equal(t, image.Rect(0, 0, 2, 3), dst.Bounds(), "orientation %d", orientation)
+		} else {
+			assert.Equal(t, image.Rect(0, 0, 3, 2), dst.Bounds(), "orientation %d", orientation)
+		}
+	}
+
+	// A 90 degree clockwise turn moves the top-left pixel to the top-right.
+	rotated := orient(src, 6)
+	assert.Equal(t, src.RGBAAt(0, 0), rotated.RGBAAt(1, 0))
+	assert.Equal(t, src.RGBAAt(0, 1), rotated.RGBAAt(0, 0))
+}
@@ -0,0 +1,148 @@
+// Package pagination implements keyset pagination with opaque cursors, which
+// listings offer alongside page and offset pagination. A cursor holds the
+// sort key and ID of the row a page starts after, so pages stay consistent
+// while rows are added or removed and deep pages cost no more than the first.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidCursor is returned for a cursor that cannot be decoded or was
+// issued for a different sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Query asks for a page of Limit rows: the rows next to Cursor when it is
+// set, and the rows at Offset otherwise. A negative limit and offset disable
+// pagination. SkipCount leaves out the total, which costs a full count of the
+// matching rows.
+type Query struct {
+	Limit     int
+	Offset    int
+	Cursor    string
+	SkipCount bool
+}
+
+// Page describes a page of a listing. Total is nil when it was not counted;
+// NextCursor and PrevCursor are empty at the ends of the listing.
+type Page struct {
+	Total      *int64
+	NextCursor string
+	PrevCursor string
+}
+
+// Cursor is a position in a listing: the sort key of a row, ending with its
+// ID. It points at the rows after the row, or before it when Before is set.
+// Sort names the ordering the cursor was issued for, so that a cursor is not
+// used with another sort.
+type Cursor struct {
+	Sort   string
+	Key    []interface{}
+	Before bool
+}
+
+type encodedCursor struct {
+	Sort   string            `json:"s"`
+	Key    []json.RawMessage `json:"k"`
+	Before bool              `json:"b,omitempty"`
+}
+
+// Encode returns the cursor as an opaque, URL-safe string
+func (c *Cursor) Encode() string {
+	encoded := encodedCursor{Sort: c.Sort, Before: c.Before}
+	for _, value := range c.Key {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			raw = []byte("null")
+		}
+		encoded.Key = append(encoded.Key, raw)
+	}
+
+	data, _ := json.Marshal(encoded)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodes a cursor issued for sort. The values of its key are
+// decoded into key, which holds a pointer per column of the sort key.
+func DecodeCursor(value, sort string, key []interface{}) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var encoded encodedCursor
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if encoded.Sort != sort || len(encoded.Key) != len(key) {
+		return nil, ErrInvalidCursor
+	}
+	for i, raw := range encoded.Key {
+		if err := json.Unmarshal(raw, key[i]); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return &Cursor{Sort: sort, Key: key, Before: encoded.Before}, nil
+}
+
+// Condition returns a WHERE condition matching the rows past the cursor in a
+// listing ordered by columns, which must all be sorted the same way and end
+// with a unique column
+func (c *Cursor) Condition(columns []string, descending bool) (string, []interface{}) {
+	operator := ">"
+	if descending != c.Before {
+		operator = "<"
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), operator, placeholders), c.Key
+}
+
+// OrderDescending tells whether rows should be fetched in descending order:
+// rows before a cursor are fetched walking back from it
+func (c *Cursor) OrderDescending(descending bool) bool {
+	if c != nil && c.Before {
+		return !descending
+	}
+	return descending
+}
+
+// Paginate trims rows, fetched with a limit of limit+1 to tell whether the
+// listing goes on, to a page and returns the cursors of the pages on either
+// side. Rows fetched before cursor are put back in listing order. offset is
+// the offset of the page when it was not asked for by cursor. key returns a
+// row's sort key, in the order DecodeCursor expects.
+func Paginate[T any](rows []T, limit int, cursor *Cursor, sort string, offset int, key func(T) []interface{}) ([]T, Page) {
+	var page Page
+	if limit <= 0 {
+		return rows, page
+	}
+
+	more := len(rows) > limit
+	if more {
+		rows = rows[:limit]
+	}
+
+	hasNext, hasPrev := more, cursor != nil || offset > 0
+	if cursor != nil && cursor.Before {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+		hasNext, hasPrev = true, more
+	}
+
+	if len(rows) == 0 {
+		return rows, page
+	}
+	if hasNext {
+		page.NextCursor = (&Cursor{Sort: sort, Key: key(rows[len(rows)-1])}).Encode()
+	}
+	if hasPrev {
+		page.PrevCursor = (&Cursor{Sort: sort, Key: key(rows[0]), Before: true}).Encode()
+	}
+	return rows, page
+}
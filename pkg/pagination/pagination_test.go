@@ -0,0 +1,139 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type row struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+func rowKey(r *row) []interface{} {
+	return []interface{}{&r.CreatedAt, &r.ID}
+}
+
+func testRows(n int) []*row {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 123456000, time.UTC)
+	rows := make([]*row, n)
+	for i := range rows {
+		rows[i] = &row{CreatedAt: start.Add(time.Duration(i) * time.Hour), ID: uuid.New()}
+	}
+	return rows
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	original := testRows(1)[0]
+	encoded := (&Cursor{Sort: "created_at:desc", Key: rowKey(original), Before: true}).Encode()
+
+	var decoded row
+	cursor, err := DecodeCursor(encoded, "created_at:desc", rowKey(&decoded))
+	require.NoError(t, err)
+	assert.True(t, cursor.Before)
+	assert.True(t, original.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, original.ID, decoded.ID)
+}
+
+func TestDecodeCursorRejectsInvalidCursors(t *testing.T) {
+	valid := (&Cursor{Sort: "created_at:desc", Key: rowKey(testRows(1)[0])}).Encode()
+	otherKey := (&Cursor{Sort: "created_at:desc", Key: []interface{}{"not a time", uuid.New()}}).Encode()
+
+	tests := map[string]struct {
+		value string
+		sort  string
+	}{
+		"not base64":     {value: "not a cursor!", sort: "created_at:desc"},
+		"not json":       {value: "bm90IGpzb24", sort: "created_at:desc"},
+		"other sort":     {value: valid, sort: "price:asc"},
+		"wrong key type": {value: otherKey, sort: "created_at:desc"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var decoded row
+			_, err := DecodeCursor(tt.value, tt.sort, rowKey(&decoded))
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+
+	var decoded row
+	_, err := DecodeCursor(valid, "created_at:desc", []interface{}{&decoded.ID})
+	assert.ErrorIs(t, err, ErrInvalidCursor, "key length must match the sort")
+}
+
+func TestCursorCondition(t *testing.T) {
+	columns := []string{"created_at", "id"}
+	key := []interface{}{1, 2}
+
+	condition, args := (&Cursor{Key: key}).Condition(columns, true)
+	assert.Equal(t, "(created_at, id) < (?, ?)", condition)
+	assert.Equal(t, key, args)
+
+	condition, _ = (&Cursor{Key: key}).Condition(columns, false)
+	assert.Equal(t, "(created_at, id) > (?, ?)", condition)
+
+	condition, _ = (&Cursor{Key: key, Before: true}).Condition(columns, true)
+	assert.Equal(t, "(created_at, id) > (?, ?)", condition)
+}
+
+func TestPaginate(t *testing.T) {
+	rows := testRows(4)
+
+	t.Run("first page", func(t *testing.T) {
+		page, result := Paginate(append([]*row{}, rows[:3]...), 2, nil, "s", 0, rowKey)
+		assert.Equal(t, rows[:2], page)
+		assert.NotEmpty(t, result.NextCursor)
+		assert.Empty(t, result.PrevCursor)
+
+		var next row
+		cursor, err := DecodeCursor(result.NextCursor, "s", rowKey(&next))
+		require.NoError(t, err)
+		assert.False(t, cursor.Before)
+		assert.Equal(t, rows[1].ID, next.ID)
+	})
+
+	t.Run("last page after a cursor", func(t *testing.T) {
+		page, result := Paginate(append([]*row{}, rows[2:]...), 2, &Cursor{}, "s", 0, rowKey)
+		assert.Equal(t, rows[2:], page)
+		assert.Empty(t, result.NextCursor)
+		assert.NotEmpty(t, result.PrevCursor)
+
+		var prev row
+		cursor, err := DecodeCursor(result.PrevCursor, "s", rowKey(&prev))
+		require.NoError(t, err)
+		assert.True(t, cursor.Before)
+		assert.Equal(t, rows[2].ID, prev.ID)
+	})
+
+	t.Run("page before a cursor", func(t *testing.T) {
+		// Fetched walking back from rows[3]: nearest first
+		fetched := []*row{rows[2], rows[1], rows[0]}
+		page, result := Paginate(fetched, 2, &Cursor{Before: true}, "s", 0, rowKey)
+		assert.Equal(t, []*row{rows[1], rows[2]}, page)
+		assert.NotEmpty(t, result.NextCursor)
+		assert.NotEmpty(t, result.PrevCursor)
+	})
+
+	t.Run("first page before a cursor", func(t *testing.T) {
+		page, result := Paginate([]*row{rows[1], rows[0]}, 2, &Cursor{Before: true}, "s", 0, rowKey)
+		assert.Equal(t, []*row{rows[0], rows[1]}, page)
+		assert.NotEmpty(t, result.NextCursor)
+		assert.Empty(t, result.PrevCursor)
+	})
+
+	t.Run("offset page", func(t *testing.T) {
+		_, result := Paginate(append([]*row{}, rows[2:]...), 2, nil, "s", 2, rowKey)
+		assert.Empty(t, result.NextCursor)
+		assert.NotEmpty(t, result.PrevCursor)
+	})
+
+	t.Run("empty page", func(t *testing.T) {
+		page, result := Paginate([]*row{}, 2, &Cursor{}, "s", 0, rowKey)
+		assert.Empty(t, page)
+		assert.Equal(t, Page{}, result)
+	})
+}
@@ -0,0 +1,89 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/pkg/auth"
+)
+
+// Client calls the privacy endpoints of one downstream service.
+type Client struct {
+	service        string
+	baseURL        string
+	httpClient     *http.Client
+	internalTokens *auth.InternalTokenManager
+}
+
+func NewClient(service, baseURL string, internalTokens *auth.InternalTokenManager) *Client {
+	return &Client{
+		service:        service,
+		baseURL:        baseURL,
+		internalTokens: internalTokens,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Service returns the name of the service this client talks to.
+func (c *Client) Service() string {
+	return c.service
+}
+
+// Export fetches the service's snapshot of the user's data.
+func (c *Client) Export(ctx context.Context, userID uuid.UUID) (json.RawMessage, error) {
+	var data json.RawMessage
+	if err := c.do(ctx, http.MethodGet, userID, "/export", &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Erase asks the service to delete or anonymise the user's data.
+func (c *Client) Erase(ctx context.Context, userID uuid.UUID) (*ErasureReport, error) {
+	report := NewErasureReport()
+	if err := c.do(ctx, http.MethodDelete, userID, "", report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, userID uuid.UUID, suffix string, out interface{}) error {
+	url := fmt.Sprintf("%s/api/v1%s%s%s", c.baseURL, userPath, userID.String(), suffix)
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := c.internalTokens.MintForService(c.service)
+	if err != nil {
+		return fmt.Errorf("failed to mint internal token: %w", err)
+	}
+	req.Header.Set(auth.InternalTokenHeader, token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status code %d", c.service, resp.StatusCode)
+	}
+
+	response := struct {
+		Data interface{} `json:"data"`
+	}{Data: out}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
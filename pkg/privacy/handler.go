@@ -0,0 +1,49 @@
+package privacy
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"solemate/pkg/authz"
+	"solemate/pkg/utils"
+)
+
+const userPath = "/internal/privacy/users/"
+
+// RegisterRoutes exposes provider on the internal privacy endpoints. Only
+// callers holding authz.PrivacyManage (i.e. user-service) may use them.
+func RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, provider Provider) {
+	privacy := router.Group(userPath + ":user_id")
+	privacy.Use(authMiddleware, authz.RequirePermission(authz.PrivacyManage))
+
+	privacy.GET("/export", func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid user ID", err.Error())
+			return
+		}
+
+		data, err := provider.ExportUserData(c.Request.Context(), userID)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to export user data", err.Error())
+			return
+		}
+
+		utils.SuccessResponse(c, "User data exported", data)
+	})
+
+	privacy.DELETE("", func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid user ID", err.Error())
+			return
+		}
+
+		report, err := provider.EraseUserData(c.Request.Context(), userID)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to erase user data", err.Error())
+			return
+		}
+
+		utils.SuccessResponse(c, "User data erased", report)
+	})
+}
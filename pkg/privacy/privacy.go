@@ -0,0 +1,46 @@
+// Package privacy defines the internal endpoints each service exposes so
+// user-service can export and erase a user's personal data across the platform.
+package privacy
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Provider is implemented by a service's domain layer to export or erase
+// everything it stores about a user.
+type Provider interface {
+	// ExportUserData returns a JSON-serialisable snapshot of the user's data.
+	ExportUserData(ctx context.Context, userID uuid.UUID) (interface{}, error)
+	// EraseUserData deletes or anonymises the user's data. Records that must be
+	// kept (e.g. for accounting) are anonymised rather than deleted.
+	EraseUserData(ctx context.Context, userID uuid.UUID) (*ErasureReport, error)
+}
+
+// ErasureReport counts the records a service removed or anonymised, keyed by
+// record kind, e.g. {"orders": 3}.
+type ErasureReport struct {
+	Deleted    map[string]int64 `json:"deleted,omitempty"`
+	Anonymized map[string]int64 `json:"anonymized,omitempty"`
+}
+
+func NewErasureReport() *ErasureReport {
+	return &ErasureReport{
+		Deleted:    map[string]int64{},
+		Anonymized: map[string]int64{},
+	}
+}
+
+func (r *ErasureReport) AddDeleted(kind string, count int64) {
+	r.Deleted[kind] += count
+}
+
+func (r *ErasureReport) AddAnonymized(kind string, count int64) {
+	r.Anonymized[kind] += count
+}
+
+// ErasedEmail is the placeholder address stored in place of an erased user's email.
+func ErasedEmail(userID uuid.UUID) string {
+	return "erased-" + userID.String() + "@erased.invalid"
+}
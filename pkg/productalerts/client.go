@@ -0,0 +1,63 @@
+package productalerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"solemate/pkg/auth"
+)
+
+// Client posts product events to user-service.
+type Client struct {
+	baseURL        string
+	httpClient     *http.Client
+	internalTokens *auth.InternalTokenManager
+}
+
+func NewClient(baseURL string, internalTokens *auth.InternalTokenManager) *Client {
+	return &Client{
+		baseURL:        baseURL,
+		internalTokens: internalTokens,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Notify hands the event to user-service, which alerts the users who wishlisted the product.
+func (c *Client) Notify(ctx context.Context, event *Event) error {
+	url := fmt.Sprintf("%s/api/v1%s", c.baseURL, Path)
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	token, err := c.internalTokens.MintForService(auth.ServiceUser)
+	if err != nil {
+		return fmt.Errorf("failed to mint internal token: %w", err)
+	}
+	req.Header.Set(auth.InternalTokenHeader, token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// user-service accepts the event and alerts the users in the background
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("%s returned status code %d", auth.ServiceUser, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package productalerts
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// ErrQueueFull is returned by Dispatcher.Notify when events are coming in
+// faster than they can be delivered
+var ErrQueueFull = errors.New("product alert queue is full")
+
+// dispatchTimeout bounds the delivery of a single event
+const dispatchTimeout = 30 * time.Second
+
+// Dispatcher delivers events in the background, so that the admin request or
+// import that changed a product doesn't wait on user-service. Events are
+// delivered one at a time by Run; delivery failures are logged.
+type Dispatcher struct {
+	notifier Notifier
+	events   chan *Event
+}
+
+func NewDispatcher(notifier Notifier, queueSize int) *Dispatcher {
+	return &Dispatcher{
+		notifier: notifier,
+		events:   make(chan *Event, queueSize),
+	}
+}
+
+// Notify queues the event for delivery. It does not block: when the queue is
+// full the event is dropped and ErrQueueFull returned.
+func (d *Dispatcher) Notify(_ context.Context, event *Event) error {
+	select {
+	case d.events <- event:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Run delivers queued events until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-d.events:
+			d.deliver(ctx, event)
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, event *Event) {
+	ctx, cancel := context.WithTimeout(ctx, dispatchTimeout)
+	defer cancel()
+
+	if err := d.notifier.Notify(ctx, event); err != nil {
+		log.Printf("product alert %s for product %s: %v", event.Type, event.ProductID, err)
+	}
+}
@@ -0,0 +1,52 @@
+package productalerts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	delivered chan *Event
+	err       error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, event *Event) error {
+	n.delivered <- event
+	return n.err
+}
+
+func TestDispatcherDeliversInBackground(t *testing.T) {
+	notifier := &recordingNotifier{delivered: make(chan *Event, 2), err: errors.New("user-service unavailable")}
+	dispatcher := NewDispatcher(notifier, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go dispatcher.Run(ctx)
+
+	first := BackInStock(uuid.New(), nil)
+	second := PriceDrop(uuid.New(), "Air Max", 150, 120)
+	require.NoError(t, dispatcher.Notify(context.Background(), first))
+	require.NoError(t, dispatcher.Notify(context.Background(), second))
+
+	for _, want := range []*Event{first, second} {
+		select {
+		case got := <-notifier.delivered:
+			assert.Equal(t, want, got, "a failed delivery does not hold up the next one")
+		case <-time.After(time.Second):
+			t.Fatal("event was not delivered")
+		}
+	}
+}
+
+func TestDispatcherDropsEventsWhenFull(t *testing.T) {
+	dispatcher := NewDispatcher(&recordingNotifier{delivered: make(chan *Event, 1)}, 1)
+
+	assert.NoError(t, dispatcher.Notify(context.Background(), BackInStock(uuid.New(), nil)))
+	assert.ErrorIs(t, dispatcher.Notify(context.Background(), BackInStock(uuid.New(), nil)), ErrQueueFull)
+}
@@ -0,0 +1,55 @@
+// Package productalerts carries catalog changes that wishlist owners care
+// about (price drops, products coming back in stock) from the services that
+// detect them to user-service, which knows who wishlisted what.
+package productalerts
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Path is the user-service endpoint that receives product events.
+const Path = "/internal/product-alerts"
+
+const (
+	TypePriceDrop   = "price_drop"
+	TypeBackInStock = "back_in_stock"
+)
+
+// Event describes a change to a product. OldPrice and NewPrice are set for
+// price drops; VariantID is set when a single variant came back in stock.
+type Event struct {
+	Type        string     `json:"type" binding:"required,oneof=price_drop back_in_stock"`
+	ProductID   uuid.UUID  `json:"product_id" binding:"required"`
+	VariantID   *uuid.UUID `json:"variant_id,omitempty"`
+	ProductName string     `json:"product_name,omitempty"`
+	OldPrice    *float64   `json:"old_price,omitempty"`
+	NewPrice    *float64   `json:"new_price,omitempty"`
+}
+
+// PriceDrop returns the event for a product whose price went from oldPrice down to newPrice.
+func PriceDrop(productID uuid.UUID, productName string, oldPrice, newPrice float64) *Event {
+	return &Event{
+		Type:        TypePriceDrop,
+		ProductID:   productID,
+		ProductName: productName,
+		OldPrice:    &oldPrice,
+		NewPrice:    &newPrice,
+	}
+}
+
+// BackInStock returns the event for a product or variant whose stock went from zero to positive.
+func BackInStock(productID uuid.UUID, variantID *uuid.UUID) *Event {
+	return &Event{
+		Type:      TypeBackInStock,
+		ProductID: productID,
+		VariantID: variantID,
+	}
+}
+
+// Notifier publishes product events. Services use a Dispatcher wrapping a
+// Client, whose Notify only queues the event.
+type Notifier interface {
+	Notify(ctx context.Context, event *Event) error
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"solemate/pkg/pagination"
 )
 
 type APIResponse struct {
@@ -20,11 +21,17 @@ type PaginatedResponse struct {
 	Pagination Pagination  `json:"pagination"`
 }
 
+// Pagination describes a page of a listing. Page is left out for pages asked
+// for by cursor, and Total and TotalPages when the total was not counted.
+// NextCursor and PrevCursor fetch the neighbouring pages of listings that
+// support cursors.
 type Pagination struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int   `json:"total_pages"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      *int64 `json:"total,omitempty"`
+	TotalPages *int   `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 func SuccessResponse(c *gin.Context, message string, data interface{}) {
@@ -93,7 +100,31 @@ func CalculatePagination(page, limit int, total int64) Pagination {
 	return Pagination{
 		Page:       page,
 		Limit:      limit,
-		Total:      total,
-		TotalPages: totalPages,
+		Total:      &total,
+		TotalPages: &totalPages,
 	}
 }
+
+// CalculateCursorPagination describes a page of a listing that supports both
+// cursor and offset pagination. page is ignored when the page was asked for
+// by cursor.
+func CalculateCursorPagination(page, limit int, cursor string, result pagination.Page) Pagination {
+	if cursor != "" {
+		page = 0
+	}
+
+	var p Pagination
+	if result.Total != nil {
+		p = CalculatePagination(page, limit, *result.Total)
+		p.Page = page
+	} else {
+		if limit <= 0 {
+			limit = 10
+		}
+		p = Pagination{Page: page, Limit: limit}
+	}
+
+	p.NextCursor = result.NextCursor
+	p.PrevCursor = result.PrevCursor
+	return p
+}
@@ -3,6 +3,8 @@ package utils
 import (
 	"regexp"
 	"strings"
+
+	"solemate/pkg/authz"
 )
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
@@ -29,16 +31,45 @@ func IsValidPhoneNumber(phone string) bool {
 	return phoneRegex.MatchString(cleaned)
 }
 
+var countryCodeRegex = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// postalCodePatterns holds postal code formats for countries we ship to most.
+// Countries not listed fall back to genericPostalCodeRegex.
+var postalCodePatterns = map[string]*regexp.Regexp{
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"ES": regexp.MustCompile(`^\d{5}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"NL": regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`),
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"IN": regexp.MustCompile(`^\d{6}$`),
+	"JP": regexp.MustCompile(`^\d{3}-?\d{4}$`),
+	"BR": regexp.MustCompile(`^\d{5}-?\d{3}$`),
+}
+
+var genericPostalCodeRegex = regexp.MustCompile(`^[A-Z\d][A-Z\d -]{1,8}[A-Z\d]$`)
+
+// IsValidCountryCode reports whether code is an upper-case ISO 3166-1 alpha-2 code.
+func IsValidCountryCode(code string) bool {
+	return countryCodeRegex.MatchString(code)
+}
+
+// IsValidPostalCode validates a postal code against the format used in country.
+func IsValidPostalCode(country, postalCode string) bool {
+	code := strings.ToUpper(strings.TrimSpace(postalCode))
+	if pattern, ok := postalCodePatterns[strings.ToUpper(country)]; ok {
+		return pattern.MatchString(code)
+	}
+	return genericPostalCodeRegex.MatchString(code)
+}
+
 func SanitizeString(input string) string {
 	return strings.TrimSpace(input)
 }
 
 func IsValidRole(role string) bool {
-	validRoles := []string{"customer", "admin", "manager"}
-	for _, validRole := range validRoles {
-		if role == validRole {
-			return true
-		}
-	}
-	return false
+	return authz.IsValidRole(role)
 }
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidPostalCode(t *testing.T) {
+	tests := []struct {
+		country    string
+		postalCode string
+		expected   bool
+	}{
+		{"US", "94105", true},
+		{"US", "94105-1234", true},
+		{"US", "9410", false},
+		{"CA", "K1A 0B1", true},
+		{"CA", "k1a0b1", true},
+		{"CA", "12345", false},
+		{"GB", "SW1A 1AA", true},
+		{"GB", "94105", false},
+		{"NL", "1012 AB", true},
+		{"IN", "110001", true},
+		{"IN", "11001", false},
+		{"NZ", "6011", true},
+		{"NZ", "!", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.country+" "+tt.postalCode, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsValidPostalCode(tt.country, tt.postalCode))
+		})
+	}
+}
+
+func TestIsValidCountryCode(t *testing.T) {
+	assert.True(t, IsValidCountryCode("US"))
+	assert.False(t, IsValidCountryCode("us"))
+	assert.False(t, IsValidCountryCode("USA"))
+}
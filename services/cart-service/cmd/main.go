@@ -9,6 +9,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"solemate/pkg/auth"
 	"solemate/pkg/cache"
+	"solemate/pkg/privacy"
 	"solemate/services/cart-service/internal/config"
 	cartHttp "solemate/services/cart-service/internal/handler/http"
 	cartCache "solemate/services/cart-service/internal/infrastructure/cache"
@@ -49,7 +50,25 @@ func main() {
 	cartService := service.NewCartService(cartRepo, productRepo)
 
 	// Initialize JWT middleware
-	jwtMiddleware := auth.JWTMiddleware(cfg.JWT.AccessSecret)
+	internalTokens, err := auth.NewInternalTokenManager(auth.ServiceCart)
+	if err != nil {
+		log.Fatalf("Failed to initialize internal tokens: %v", err)
+	}
+
+	// Session revocations written by user-service; without Redis, access
+	// tokens are only accepted through the gateway
+	var revocations *auth.SessionRevocations
+	if redisClient, err := cache.NewRedisClient(cache.GetConfigFromEnv()); err != nil {
+		log.Printf("Redis unavailable, access tokens are only accepted through the gateway: %v", err)
+	} else {
+		revocations = auth.NewSessionRevocations(redisClient)
+	}
+
+	jwtMiddleware := auth.ServiceAuthMiddleware(
+		internalTokens,
+		auth.NewAccessTokenValidator(cfg.JWT.AccessSecret),
+		revocations,
+	)
 
 	// Initialize handlers
 	cartHandler := cartHttp.NewCartHandler(cartService)
@@ -72,6 +91,7 @@ func main() {
 	// API routes
 	v1 := router.Group("/api/v1")
 	cartHandler.RegisterRoutes(v1, jwtMiddleware)
+	privacy.RegisterRoutes(v1, jwtMiddleware, cartService)
 
 	// Start server
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
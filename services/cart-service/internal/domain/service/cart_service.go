@@ -2,14 +2,21 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"solemate/pkg/privacy"
 	"solemate/services/cart-service/internal/domain/entity"
 	"solemate/services/cart-service/internal/domain/repository"
 )
 
+var (
+	ErrProductUnavailable = errors.New("product is not available")
+	ErrInsufficientStock  = errors.New("insufficient stock for requested quantity")
+)
+
 type CartService interface {
 	GetCart(ctx context.Context, userID uuid.UUID) (*entity.Cart, error)
 	AddItem(ctx context.Context, userID uuid.UUID, item *entity.CartItem) error
@@ -21,6 +28,10 @@ type CartService interface {
 	ValidateAndAddItem(ctx context.Context, userID uuid.UUID, productID uuid.UUID, variantID *uuid.UUID, quantity int) error
 	ApplyDiscount(ctx context.Context, userID uuid.UUID, itemID uuid.UUID, discount float64) error
 	GetItemCount(ctx context.Context, userID uuid.UUID) (int, error)
+
+	// Personal data export and erasure (privacy.Provider)
+	ExportUserData(ctx context.Context, userID uuid.UUID) (interface{}, error)
+	EraseUserData(ctx context.Context, userID uuid.UUID) (*privacy.ErasureReport, error)
 }
 
 type cartService struct {
@@ -55,7 +66,7 @@ func (s *cartService) AddItem(ctx context.Context, userID uuid.UUID, item *entit
 			return fmt.Errorf("failed to check product availability: %w", err)
 		}
 		if !available {
-			return fmt.Errorf("product is not available")
+			return ErrProductUnavailable
 		}
 
 		hasStock, err := s.productRepo.ValidateStock(ctx, item.ProductID, item.VariantID, item.Quantity)
@@ -63,7 +74,7 @@ func (s *cartService) AddItem(ctx context.Context, userID uuid.UUID, item *entit
 			return fmt.Errorf("failed to validate stock: %w", err)
 		}
 		if !hasStock {
-			return fmt.Errorf("insufficient stock for requested quantity")
+			return ErrInsufficientStock
 		}
 	}
 
@@ -103,6 +114,14 @@ func (s *cartService) ValidateAndAddItem(ctx context.Context, userID uuid.UUID,
 		return fmt.Errorf("failed to get product: %w", err)
 	}
 
+	available, err := s.productRepo.CheckProductAvailability(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to check product availability: %w", err)
+	}
+	if !available {
+		return ErrProductUnavailable
+	}
+
 	var variant *entity.ProductVariantInfo
 	if variantID != nil {
 		variant, err = s.productRepo.GetProductVariant(ctx, *variantID)
@@ -117,7 +136,7 @@ func (s *cartService) ValidateAndAddItem(ctx context.Context, userID uuid.UUID,
 		return fmt.Errorf("failed to validate stock: %w", err)
 	}
 	if !hasStock {
-		return fmt.Errorf("insufficient stock for requested quantity")
+		return ErrInsufficientStock
 	}
 
 	// Create cart item
@@ -177,7 +196,7 @@ func (s *cartService) UpdateItemQuantity(ctx context.Context, userID uuid.UUID,
 					return fmt.Errorf("failed to validate stock: %w", err)
 				}
 				if !hasStock {
-					return fmt.Errorf("insufficient stock for requested quantity")
+					return ErrInsufficientStock
 				}
 				break
 			}
@@ -237,4 +256,30 @@ func (s *cartService) GetItemCount(ctx context.Context, userID uuid.UUID) (int,
 		return 0, err
 	}
 	return len(cart.Items), nil
-}
\ No newline at end of file
+}
+
+func (s *cartService) ExportUserData(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	cart, err := s.cartRepo.GetCart(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cart: %w", err)
+	}
+
+	return map[string]interface{}{
+		"cart": cart,
+	}, nil
+}
+
+func (s *cartService) EraseUserData(ctx context.Context, userID uuid.UUID) (*privacy.ErasureReport, error) {
+	cart, err := s.cartRepo.GetCart(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cart: %w", err)
+	}
+
+	if err := s.cartRepo.DeleteCart(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to delete cart: %w", err)
+	}
+
+	report := privacy.NewErasureReport()
+	report.AddDeleted("cart_items", int64(len(cart.Items)))
+	return report, nil
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -81,7 +82,14 @@ func (h *CartHandler) AddItem(c *gin.Context) {
 
 	err := h.cartService.ValidateAndAddItem(c.Request.Context(), userUUID, req.ProductID, req.VariantID, req.Quantity)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to add item to cart", err.Error())
+		switch {
+		case errors.Is(err, service.ErrInsufficientStock):
+			utils.ErrorResponse(c, http.StatusConflict, "Failed to add item to cart", "out_of_stock")
+		case errors.Is(err, service.ErrProductUnavailable):
+			utils.ErrorResponse(c, http.StatusConflict, "Failed to add item to cart", "product_unavailable")
+		default:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Failed to add item to cart", err.Error())
+		}
 		return
 	}
 
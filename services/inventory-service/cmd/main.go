@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"solemate/pkg/auth"
+	"solemate/pkg/cache"
 	"solemate/pkg/database"
 	"solemate/services/inventory-service/internal/config"
 	inventoryHttp "solemate/services/inventory-service/internal/handler/http"
@@ -63,18 +64,27 @@ func main() {
 		orderRepo,
 	)
 
-	// Initialize middleware
-	jwtMiddleware := auth.JWTMiddleware(cfg.JWT.AccessSecret)
-	adminMiddleware := func(c *gin.Context) {
-		userRole, exists := c.Get("user_role")
-		if !exists || userRole != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
-			c.Abort()
-			return
-		}
-		c.Next()
+	internalTokens, err := auth.NewInternalTokenManager(auth.ServiceInventory)
+	if err != nil {
+		log.Fatalf("Failed to initialize internal tokens: %v", err)
+	}
+
+	// Session revocations written by user-service; without Redis, access
+	// tokens are only accepted through the gateway
+	var revocations *auth.SessionRevocations
+	if redisClient, err := cache.NewRedisClient(cache.GetConfigFromEnv()); err != nil {
+		log.Printf("Redis unavailable, access tokens are only accepted through the gateway: %v", err)
+	} else {
+		revocations = auth.NewSessionRevocations(redisClient)
 	}
 
+	// Initialize middleware
+	jwtMiddleware := auth.ServiceAuthMiddleware(
+		internalTokens,
+		auth.NewAccessTokenValidator(cfg.JWT.AccessSecret),
+		revocations,
+	)
+
 	// Initialize handlers
 	inventoryHandler := inventoryHttp.NewInventoryHandler(inventoryService)
 
@@ -95,7 +105,7 @@ func main() {
 
 	// API routes
 	v1 := router.Group("/api/v1")
-	inventoryHandler.RegisterRoutes(v1, jwtMiddleware, adminMiddleware)
+	inventoryHandler.RegisterRoutes(v1, jwtMiddleware)
 
 	// Start server
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
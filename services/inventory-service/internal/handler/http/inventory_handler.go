@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"solemate/pkg/authz"
 	"solemate/services/inventory-service/internal/domain/service"
 )
 
@@ -19,15 +20,18 @@ func NewInventoryHandler(inventoryService service.InventoryService) *InventoryHa
 	}
 }
 
-func (h *InventoryHandler) RegisterRoutes(router *gin.RouterGroup, jwtMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
+func (h *InventoryHandler) RegisterRoutes(router *gin.RouterGroup, jwtMiddleware gin.HandlerFunc) {
+	canRead := authz.RequirePermission(authz.InventoryRead)
+	canAdjust := authz.RequirePermission(authz.InventoryAdjust)
+
 	inventory := router.Group("/inventory")
 	inventory.Use(jwtMiddleware)
 	{
 		// Inventory item operations
-		inventory.POST("/items", h.CreateInventoryItem)
+		inventory.POST("/items", canAdjust, h.CreateInventoryItem)
 		inventory.GET("/items/:id", h.GetInventoryItem)
-		inventory.PUT("/items/:id", h.UpdateInventoryItem)
-		inventory.DELETE("/items/:id", h.DeleteInventoryItem)
+		inventory.PUT("/items/:id", canAdjust, h.UpdateInventoryItem)
+		inventory.DELETE("/items/:id", canAdjust, h.DeleteInventoryItem)
 		inventory.GET("/items", h.SearchInventoryItems)
 
 		// Stock operations
@@ -35,30 +39,29 @@ func (h *InventoryHandler) RegisterRoutes(router *gin.RouterGroup, jwtMiddleware
 		inventory.POST("/reserve", h.ReserveStock)
 		inventory.DELETE("/reservations/:id", h.ReleaseStockReservation)
 		inventory.POST("/reservations/:id/fulfill", h.FulfillStockReservation)
-		inventory.POST("/adjust", h.AdjustStock)
-		inventory.POST("/transfer", h.TransferStock)
+		inventory.POST("/adjust", canAdjust, h.AdjustStock)
+		inventory.POST("/transfer", canAdjust, h.TransferStock)
 
 		// Warehouse operations
-		inventory.POST("/warehouses", h.CreateWarehouse)
+		inventory.POST("/warehouses", canAdjust, h.CreateWarehouse)
 		inventory.GET("/warehouses/:id", h.GetWarehouse)
-		inventory.PUT("/warehouses/:id", h.UpdateWarehouse)
+		inventory.PUT("/warehouses/:id", canAdjust, h.UpdateWarehouse)
 		inventory.GET("/warehouses", h.GetAllWarehouses)
-		inventory.GET("/warehouses/:id/summary", h.GetWarehouseInventorySummary)
+		inventory.GET("/warehouses/:id/summary", canRead, h.GetWarehouseInventorySummary)
 
 		// Stock movements
-		inventory.GET("/movements", h.GetStockMovements)
+		inventory.GET("/movements", canRead, h.GetStockMovements)
 
 		// Admin operations
 		admin := inventory.Group("/admin")
-		admin.Use(adminMiddleware)
 		{
-			admin.POST("/bulk-update", h.BulkStockUpdate)
-			admin.POST("/bulk-reserve", h.BulkReserveStock)
-			admin.GET("/analytics", h.GetInventoryAnalytics)
-			admin.GET("/alerts", h.GetStockAlerts)
-			admin.POST("/alerts/generate", h.GenerateStockAlerts)
-			admin.POST("/alerts/:id/read", h.MarkAlertAsRead)
-			admin.POST("/alerts/:id/resolve", h.MarkAlertAsResolved)
+			admin.POST("/bulk-update", canAdjust, h.BulkStockUpdate)
+			admin.POST("/bulk-reserve", canAdjust, h.BulkReserveStock)
+			admin.GET("/analytics", canRead, h.GetInventoryAnalytics)
+			admin.GET("/alerts", canRead, h.GetStockAlerts)
+			admin.POST("/alerts/generate", canAdjust, h.GenerateStockAlerts)
+			admin.POST("/alerts/:id/read", canAdjust, h.MarkAlertAsRead)
+			admin.POST("/alerts/:id/resolve", canAdjust, h.MarkAlertAsResolved)
 		}
 	}
 }
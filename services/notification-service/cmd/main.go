@@ -7,7 +7,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"solemate/pkg/auth"
+	"solemate/pkg/cache"
 	"solemate/pkg/database"
+	"solemate/pkg/privacy"
 	"solemate/services/notification-service/internal/config"
 	"solemate/services/notification-service/internal/domain/entity"
 	"solemate/services/notification-service/internal/domain/service"
@@ -62,17 +64,26 @@ func main() {
 	templateService := service.NewTemplateService(templateRepo)
 	preferenceService := service.NewPreferenceService(preferenceRepo, userRepo)
 
-	jwtMiddleware := auth.JWTMiddleware(cfg.JWT.AccessSecret)
-	adminMiddleware := func(c *gin.Context) {
-		userRole, exists := c.Get("user_role")
-		if !exists || userRole != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
-			c.Abort()
-			return
-		}
-		c.Next()
+	internalTokens, err := auth.NewInternalTokenManager(auth.ServiceNotification)
+	if err != nil {
+		log.Fatalf("Failed to initialize internal tokens: %v", err)
+	}
+
+	// Session revocations written by user-service; without Redis, access
+	// tokens are only accepted through the gateway
+	var revocations *auth.SessionRevocations
+	if redisClient, err := cache.NewRedisClient(cache.GetConfigFromEnv()); err != nil {
+		log.Printf("Redis unavailable, access tokens are only accepted through the gateway: %v", err)
+	} else {
+		revocations = auth.NewSessionRevocations(redisClient)
 	}
 
+	jwtMiddleware := auth.ServiceAuthMiddleware(
+		internalTokens,
+		auth.NewAccessTokenValidator(cfg.JWT.AccessSecret),
+		revocations,
+	)
+
 	notificationHandler := notificationHttp.NewNotificationHandler(
 		notificationService,
 		templateService,
@@ -93,7 +104,8 @@ func main() {
 	})
 
 	v1 := router.Group("/api/v1")
-	notificationHandler.RegisterRoutes(v1, jwtMiddleware, adminMiddleware)
+	notificationHandler.RegisterRoutes(v1, jwtMiddleware)
+	privacy.RegisterRoutes(v1, jwtMiddleware, notificationService)
 
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
 	log.Printf("Notification service starting on %s", addr)
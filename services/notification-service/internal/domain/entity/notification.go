@@ -20,6 +20,9 @@ const (
 	NotificationTypePaymentSuccessful NotificationType = "payment_successful"
 	NotificationTypePaymentFailed     NotificationType = "payment_failed"
 	NotificationTypeStockAlert        NotificationType = "stock_alert"
+	NotificationTypePriceDrop         NotificationType = "price_drop"
+	NotificationTypeReviewApproved    NotificationType = "review_approved"
+	NotificationTypeReviewRejected    NotificationType = "review_rejected"
 	NotificationTypeWelcome           NotificationType = "welcome"
 	NotificationTypePasswordReset     NotificationType = "password_reset"
 	NotificationTypePromotion         NotificationType = "promotion"
@@ -111,6 +114,7 @@ type NotificationPreference struct {
 	Newsletter            bool                 `json:"newsletter" gorm:"default:false"`
 	SecurityAlerts        bool                 `json:"security_alerts" gorm:"default:true"`
 	StockAlerts           bool                 `json:"stock_alerts" gorm:"default:false"`
+	PriceAlerts           bool                 `json:"price_alerts" gorm:"default:false"`
 	PreferredChannel      NotificationChannel  `json:"preferred_channel" gorm:"type:varchar(20);default:'email'"`
 	TimeZone              string               `json:"time_zone" gorm:"type:varchar(50);default:'UTC'"`
 	QuietHoursStart       *string              `json:"quiet_hours_start" gorm:"type:varchar(5)"`
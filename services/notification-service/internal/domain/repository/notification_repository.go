@@ -5,13 +5,17 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"solemate/pkg/pagination"
 	"solemate/services/notification-service/internal/domain/entity"
 )
 
 type NotificationRepository interface {
 	Create(ctx context.Context, notification *entity.Notification) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error)
-	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.Notification, error)
+	// GetByUserID returns a page of the user's notifications, newest first,
+	// ordered by created_at and then ID so that cursors can continue from any
+	// notification
+	GetByUserID(ctx context.Context, userID uuid.UUID, query pagination.Query) ([]*entity.Notification, *pagination.Page, error)
 	GetPendingNotifications(ctx context.Context, limit int) ([]*entity.Notification, error)
 	GetScheduledNotifications(ctx context.Context, before time.Time, limit int) ([]*entity.Notification, error)
 	GetByStatus(ctx context.Context, status entity.NotificationStatus, limit, offset int) ([]*entity.Notification, error)
@@ -26,6 +30,7 @@ type NotificationRepository interface {
 	IncrementRetryCount(ctx context.Context, id uuid.UUID) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteOldNotifications(ctx context.Context, olderThan time.Time) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 	GetStatistics(ctx context.Context, from, to time.Time) (*NotificationStatistics, error)
 	GetDeliveryReport(ctx context.Context, from, to time.Time, groupBy string) ([]*DeliveryReport, error)
 }
@@ -2,12 +2,13 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"solemate/pkg/pagination"
+	"solemate/pkg/privacy"
 	"solemate/services/notification-service/internal/domain/entity"
 	"solemate/services/notification-service/internal/domain/repository"
 )
@@ -17,7 +18,7 @@ type NotificationService interface {
 	SendBulkNotification(ctx context.Context, request *SendBulkNotificationRequest) ([]*NotificationResponse, error)
 	SendTemplateNotification(ctx context.Context, request *SendTemplateNotificationRequest) (*NotificationResponse, error)
 	GetNotification(ctx context.Context, id uuid.UUID) (*NotificationResponse, error)
-	GetUserNotifications(ctx context.Context, userID uuid.UUID, limit, offset int) (*NotificationListResponse, error)
+	GetUserNotifications(ctx context.Context, userID uuid.UUID, query pagination.Query) (*NotificationListResponse, error)
 	GetNotificationsByStatus(ctx context.Context, status entity.NotificationStatus, limit, offset int) (*NotificationListResponse, error)
 	RetryFailedNotifications(ctx context.Context, request *RetryFailedRequest) (*RetryFailedResponse, error)
 	CancelNotification(ctx context.Context, id uuid.UUID) error
@@ -26,6 +27,10 @@ type NotificationService interface {
 	GetStatistics(ctx context.Context, from, to time.Time) (*StatisticsResponse, error)
 	GetDeliveryReport(ctx context.Context, from, to time.Time, groupBy string) (*DeliveryReportResponse, error)
 	ProcessEvent(ctx context.Context, request *EventProcessingRequest) (*EventProcessingResponse, error)
+
+	// Personal data export and erasure (privacy.Provider)
+	ExportUserData(ctx context.Context, userID uuid.UUID) (interface{}, error)
+	EraseUserData(ctx context.Context, userID uuid.UUID) (*privacy.ErasureReport, error)
 }
 
 type TemplateService interface {
@@ -45,6 +50,10 @@ type PreferenceService interface {
 	CreateDefaultPreferences(ctx context.Context, userID uuid.UUID) (*PreferenceResponse, error)
 }
 
+// defaultNotificationLimit is the page size of a user's notification list
+// when none is given
+const defaultNotificationLimit = 10
+
 type notificationService struct {
 	notificationRepo repository.NotificationRepository
 	templateRepo     repository.TemplateRepository
@@ -275,8 +284,18 @@ func (s *notificationService) GetNotification(ctx context.Context, id uuid.UUID)
 	return s.toNotificationResponse(notification), nil
 }
 
-func (s *notificationService) GetUserNotifications(ctx context.Context, userID uuid.UUID, limit, offset int) (*NotificationListResponse, error) {
-	notifications, err := s.notificationRepo.GetByUserID(ctx, userID, limit, offset)
+// GetUserNotifications returns a page of the user's notifications. Unlike
+// the repository, it never lists them all; a limit that is not positive
+// falls back to the default.
+func (s *notificationService) GetUserNotifications(ctx context.Context, userID uuid.UUID, query pagination.Query) (*NotificationListResponse, error) {
+	if query.Limit <= 0 {
+		query.Limit = defaultNotificationLimit
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	notifications, page, err := s.notificationRepo.GetByUserID(ctx, userID, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user notifications: %w", err)
 	}
@@ -288,9 +307,11 @@ func (s *notificationService) GetUserNotifications(ctx context.Context, userID u
 
 	return &NotificationListResponse{
 		Notifications: responses,
-		Total:         int64(len(responses)),
-		Limit:         limit,
-		Offset:        offset,
+		Total:         page.Total,
+		Limit:         query.Limit,
+		Offset:        query.Offset,
+		NextCursor:    page.NextCursor,
+		PrevCursor:    page.PrevCursor,
 	}, nil
 }
 
@@ -305,9 +326,10 @@ func (s *notificationService) GetNotificationsByStatus(ctx context.Context, stat
 		responses[i] = s.toNotificationResponse(notification)
 	}
 
+	total := int64(len(responses))
 	return &NotificationListResponse{
 		Notifications: responses,
-		Total:         int64(len(responses)),
+		Total:         &total,
 		Limit:         limit,
 		Offset:        offset,
 	}, nil
@@ -522,6 +544,23 @@ func (s *notificationService) ProcessEvent(ctx context.Context, request *EventPr
 				notificationsCreated++
 			}
 		}
+	case "wishlist.price_drop", "wishlist.back_in_stock":
+		if request.UserID != nil {
+			notificationType := s.mapWishlistEventToNotificationType(request.EventType)
+			if err := s.createProductAlertNotification(ctx, *request.UserID, notificationType, request.EntityID, request.Payload); err == nil {
+				notificationsCreated++
+			}
+		}
+	case "review.approved", "review.rejected":
+		if request.UserID != nil {
+			notificationType := entity.NotificationTypeReviewApproved
+			if request.EventType == "review.rejected" {
+				notificationType = entity.NotificationTypeReviewRejected
+			}
+			if err := s.createReviewDecisionNotification(ctx, *request.UserID, notificationType, request.EntityID, request.Payload); err == nil {
+				notificationsCreated++
+			}
+		}
 	}
 
 	if err := s.eventRepo.MarkAsProcessed(ctx, event.ID); err != nil {
@@ -567,6 +606,8 @@ func (s *notificationService) checkChannelConsent(preference *entity.Notificatio
 		return preference.Newsletter
 	case entity.NotificationTypeStockAlert:
 		return preference.StockAlerts
+	case entity.NotificationTypePriceDrop:
+		return preference.PriceAlerts
 	default:
 		return true
 	}
@@ -614,6 +655,13 @@ func (s *notificationService) mapPaymentEventToNotificationType(eventType string
 	}
 }
 
+func (s *notificationService) mapWishlistEventToNotificationType(eventType string) entity.NotificationType {
+	if eventType == "wishlist.price_drop" {
+		return entity.NotificationTypePriceDrop
+	}
+	return entity.NotificationTypeStockAlert
+}
+
 func (s *notificationService) createOrderNotification(ctx context.Context, userID uuid.UUID, notificationType entity.NotificationType, payload map[string]interface{}) error {
 	orderID, _ := payload["order_id"].(string)
 	orderTotal, _ := payload["total"].(float64)
@@ -662,6 +710,109 @@ func (s *notificationService) createPaymentNotification(ctx context.Context, use
 	return err
 }
 
+// createProductAlertNotification tells a user that a wishlisted product got
+// cheaper or came back in stock, on the channel they prefer. SendNotification
+// drops it if the user has not opted in to that kind of alert.
+func (s *notificationService) createProductAlertNotification(ctx context.Context, userID uuid.UUID, notificationType entity.NotificationType, productID uuid.UUID, payload map[string]interface{}) error {
+	productName, _ := payload["product_name"].(string)
+	if productName == "" {
+		productName = "An item on your wishlist"
+	}
+
+	channel := entity.ChannelEmail
+	if preference, err := s.preferenceRepo.GetByUserID(ctx, userID); err == nil && preference.PreferredChannel != "" {
+		channel = preference.PreferredChannel
+	}
+
+	metadata := map[string]interface{}{
+		"product_id": productID.String(),
+	}
+	if variantID, ok := payload["variant_id"].(string); ok {
+		metadata["variant_id"] = variantID
+	}
+
+	var subject, content string
+	if notificationType == entity.NotificationTypePriceDrop {
+		oldPrice, _ := payload["old_price"].(float64)
+		newPrice, _ := payload["new_price"].(float64)
+		metadata["old_price"] = oldPrice
+		metadata["new_price"] = newPrice
+
+		subject = fmt.Sprintf("Price drop - %s", productName)
+		content = fmt.Sprintf("%s is now %.2f (was %.2f).", productName, newPrice, oldPrice)
+	} else {
+		subject = fmt.Sprintf("Back in stock - %s", productName)
+		content = fmt.Sprintf("%s is back in stock.", productName)
+	}
+
+	entityType := "product"
+	request := &SendNotificationRequest{
+		UserID:            userID,
+		Type:              notificationType,
+		Channel:           channel,
+		Priority:          entity.PriorityLow,
+		Subject:           subject,
+		Content:           content,
+		Metadata:          metadata,
+		RelatedEntityID:   &productID,
+		RelatedEntityType: &entityType,
+	}
+
+	_, err := s.SendNotification(ctx, request)
+	return err
+}
+
+func (s *notificationService) createReviewDecisionNotification(ctx context.Context, userID uuid.UUID, notificationType entity.NotificationType, reviewID uuid.UUID, payload map[string]interface{}) error {
+	productName, _ := payload["product_name"].(string)
+	if productName == "" {
+		productName = "a product"
+	}
+	reason, _ := payload["reason"].(string)
+
+	channel := entity.ChannelEmail
+	if preference, err := s.preferenceRepo.GetByUserID(ctx, userID); err == nil && preference.PreferredChannel != "" {
+		channel = preference.PreferredChannel
+	}
+
+	metadata := map[string]interface{}{
+		"review_id": reviewID.String(),
+	}
+	if productID, ok := payload["product_id"].(string); ok {
+		metadata["product_id"] = productID
+	}
+	if reason != "" {
+		metadata["reason"] = reason
+	}
+
+	var subject, content string
+	if notificationType == entity.NotificationTypeReviewApproved {
+		subject = fmt.Sprintf("Your review of %s is live", productName)
+		content = fmt.Sprintf("Your review of %s has been approved and is now visible to other shoppers.", productName)
+	} else {
+		subject = fmt.Sprintf("Your review of %s was not published", productName)
+		content = fmt.Sprintf("Your review of %s did not meet our review guidelines and was not published.", productName)
+	}
+	if reason != "" {
+		content = fmt.Sprintf("%s Reason: %s", content, reason)
+	}
+
+	entityType := "review"
+	request := &SendNotificationRequest{
+		UserID:            userID,
+		Type:              notificationType,
+		Channel:           channel,
+		Priority:          entity.PriorityLow,
+		Subject:           subject,
+		Content:           content,
+		Metadata:          metadata,
+		RelatedEntityID:   &reviewID,
+		RelatedEntityType: &entityType,
+	}
+
+	_, err := s.SendNotification(ctx, request)
+	return err
+}
+
 func (s *notificationService) convertChannelStats(stats map[entity.NotificationChannel]repository.ChannelStats) map[entity.NotificationChannel]ChannelStats {
 	result := make(map[entity.NotificationChannel]ChannelStats)
 	for channel, stat := range stats {
@@ -717,4 +868,41 @@ type RenderedTemplate struct {
 	Subject     string  `json:"subject"`
 	Content     string  `json:"content"`
 	HTMLContent *string `json:"html_content"`
-}
\ No newline at end of file
+}
+
+func (s *notificationService) ExportUserData(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	// A negative limit and offset disable pagination
+	notifications, _, err := s.notificationRepo.GetByUserID(ctx, userID, pagination.Query{Limit: -1, Offset: -1, SkipCount: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user notifications: %w", err)
+	}
+
+	// Users who never changed their preferences have no stored row
+	preferences, _ := s.preferenceRepo.GetByUserID(ctx, userID)
+
+	return map[string]interface{}{
+		"notifications": notifications,
+		"preferences":   preferences,
+	}, nil
+}
+
+// EraseUserData deletes the user's notification history, which contains the
+// rendered messages and recipient addresses, and their preferences.
+func (s *notificationService) EraseUserData(ctx context.Context, userID uuid.UUID) (*privacy.ErasureReport, error) {
+	report := privacy.NewErasureReport()
+
+	count, err := s.notificationRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete notifications: %w", err)
+	}
+	report.AddDeleted("notifications", count)
+
+	if preferences, err := s.preferenceRepo.GetByUserID(ctx, userID); err == nil && preferences != nil {
+		if err := s.preferenceRepo.Delete(ctx, userID); err != nil {
+			return nil, fmt.Errorf("failed to delete preferences: %w", err)
+		}
+		report.AddDeleted("preferences", 1)
+	}
+
+	return report, nil
+}
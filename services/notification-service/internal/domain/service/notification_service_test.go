@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/pkg/pagination"
+	"solemate/services/notification-service/internal/domain/entity"
+	"solemate/services/notification-service/internal/domain/repository"
+)
+
+// MockNotificationRepository is a mock implementation of repository.NotificationRepository
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(ctx context.Context, notification *entity.Notification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID, query pagination.Query) ([]*entity.Notification, *pagination.Page, error) {
+	args := m.Called(ctx, userID, query)
+	if args.Get(1) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*entity.Notification), args.Get(1).(*pagination.Page), args.Error(2)
+}
+
+func (m *MockNotificationRepository) GetPendingNotifications(ctx context.Context, limit int) ([]*entity.Notification, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetScheduledNotifications(ctx context.Context, before time.Time, limit int) ([]*entity.Notification, error) {
+	args := m.Called(ctx, before, limit)
+	return args.Get(0).([]*entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetByStatus(ctx context.Context, status entity.NotificationStatus, limit, offset int) ([]*entity.Notification, error) {
+	args := m.Called(ctx, status, limit, offset)
+	return args.Get(0).([]*entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetByType(ctx context.Context, notificationType entity.NotificationType, limit, offset int) ([]*entity.Notification, error) {
+	args := m.Called(ctx, notificationType, limit, offset)
+	return args.Get(0).([]*entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetByChannel(ctx context.Context, channel entity.NotificationChannel, limit, offset int) ([]*entity.Notification, error) {
+	args := m.Called(ctx, channel, limit, offset)
+	return args.Get(0).([]*entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetByRelatedEntity(ctx context.Context, entityID uuid.UUID, entityType string) ([]*entity.Notification, error) {
+	args := m.Called(ctx, entityID, entityType)
+	return args.Get(0).([]*entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) Update(ctx context.Context, notification *entity.Notification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entity.NotificationStatus) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) MarkAsSent(ctx context.Context, id uuid.UUID, sentAt time.Time, externalID *string) error {
+	args := m.Called(ctx, id, sentAt, externalID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) MarkAsDelivered(ctx context.Context, id uuid.UUID, deliveredAt time.Time) error {
+	args := m.Called(ctx, id, deliveredAt)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) MarkAsFailed(ctx context.Context, id uuid.UUID, failedAt time.Time, errorMessage string) error {
+	args := m.Called(ctx, id, failedAt, errorMessage)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) IncrementRetryCount(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) DeleteOldNotifications(ctx context.Context, olderThan time.Time) error {
+	args := m.Called(ctx, olderThan)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetStatistics(ctx context.Context, from, to time.Time) (*repository.NotificationStatistics, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).(*repository.NotificationStatistics), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetDeliveryReport(ctx context.Context, from, to time.Time, groupBy string) ([]*repository.DeliveryReport, error) {
+	args := m.Called(ctx, from, to, groupBy)
+	return args.Get(0).([]*repository.DeliveryReport), args.Error(1)
+}
+
+// MockPreferenceRepository is a mock implementation of repository.PreferenceRepository
+type MockPreferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockPreferenceRepository) Create(ctx context.Context, preference *entity.NotificationPreference) error {
+	args := m.Called(ctx, preference)
+	return args.Error(0)
+}
+
+func (m *MockPreferenceRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*entity.NotificationPreference, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.NotificationPreference), args.Error(1)
+}
+
+func (m *MockPreferenceRepository) Update(ctx context.Context, preference *entity.NotificationPreference) error {
+	args := m.Called(ctx, preference)
+	return args.Error(0)
+}
+
+func (m *MockPreferenceRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockPreferenceRepository) GetUsersWithPreference(ctx context.Context, channel entity.NotificationChannel, notificationType entity.NotificationType) ([]uuid.UUID, error) {
+	args := m.Called(ctx, channel, notificationType)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockPreferenceRepository) BulkGetPreferences(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]*entity.NotificationPreference, error) {
+	args := m.Called(ctx, userIDs)
+	return args.Get(0).(map[uuid.UUID]*entity.NotificationPreference), args.Error(1)
+}
+
+func TestNotificationService_GetUserNotifications(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	notifications := []*entity.Notification{
+		{ID: uuid.New(), UserID: userID, Subject: "Your order shipped"},
+		{ID: uuid.New(), UserID: userID, Subject: "Price drop"},
+	}
+
+	t.Run("cursor page carries the cursors and total", func(t *testing.T) {
+		notificationRepo := new(MockNotificationRepository)
+		service := NewNotificationService(notificationRepo, nil, nil, nil, nil, nil, nil)
+		total := int64(12)
+		query := pagination.Query{Limit: 2, Cursor: "abc"}
+		notificationRepo.On("GetByUserID", ctx, userID, query).
+			Return(notifications, &pagination.Page{Total: &total, NextCursor: "next", PrevCursor: "prev"}, nil)
+
+		response, err := service.GetUserNotifications(ctx, userID, query)
+
+		require.NoError(t, err)
+		require.Len(t, response.Notifications, 2)
+		assert.Equal(t, "Your order shipped", response.Notifications[0].Subject)
+		assert.Equal(t, &total, response.Total)
+		assert.Equal(t, 2, response.Limit)
+		assert.Equal(t, "next", response.NextCursor)
+		assert.Equal(t, "prev", response.PrevCursor)
+	})
+
+	t.Run("uncounted page leaves out the total", func(t *testing.T) {
+		notificationRepo := new(MockNotificationRepository)
+		service := NewNotificationService(notificationRepo, nil, nil, nil, nil, nil, nil)
+		query := pagination.Query{Limit: 2, SkipCount: true}
+		notificationRepo.On("GetByUserID", ctx, userID, query).
+			Return(notifications, &pagination.Page{NextCursor: "next"}, nil)
+
+		response, err := service.GetUserNotifications(ctx, userID, query)
+
+		require.NoError(t, err)
+		assert.Nil(t, response.Total)
+		assert.Empty(t, response.PrevCursor)
+	})
+
+	t.Run("a limit that is not positive cannot list everything", func(t *testing.T) {
+		notificationRepo := new(MockNotificationRepository)
+		service := NewNotificationService(notificationRepo, nil, nil, nil, nil, nil, nil)
+		notificationRepo.On("GetByUserID", ctx, userID, pagination.Query{Limit: defaultNotificationLimit}).
+			Return([]*entity.Notification{}, &pagination.Page{}, nil)
+
+		response, err := service.GetUserNotifications(ctx, userID, pagination.Query{Limit: -1, Offset: -1})
+
+		require.NoError(t, err)
+		assert.Equal(t, defaultNotificationLimit, response.Limit)
+		assert.Equal(t, 0, response.Offset)
+		notificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		notificationRepo := new(MockNotificationRepository)
+		service := NewNotificationService(notificationRepo, nil, nil, nil, nil, nil, nil)
+		query := pagination.Query{Limit: 10, Cursor: "not-a-cursor"}
+		notificationRepo.On("GetByUserID", ctx, userID, query).Return(nil, nil, pagination.ErrInvalidCursor)
+
+		_, err := service.GetUserNotifications(ctx, userID, query)
+
+		assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+	})
+}
+
+func TestNotificationService_ExportUserData(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("exports every notification without counting", func(t *testing.T) {
+		notificationRepo := new(MockNotificationRepository)
+		preferenceRepo := new(MockPreferenceRepository)
+		service := NewNotificationService(notificationRepo, nil, preferenceRepo, nil, nil, nil, nil)
+		notifications := []*entity.Notification{{ID: uuid.New(), UserID: userID}}
+		notificationRepo.On("GetByUserID", ctx, userID, pagination.Query{Limit: -1, Offset: -1, SkipCount: true}).
+			Return(notifications, &pagination.Page{}, nil)
+		preferenceRepo.On("GetByUserID", ctx, userID).Return(nil, errors.New("preference not found"))
+
+		data, err := service.ExportUserData(ctx, userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, notifications, data.(map[string]interface{})["notifications"])
+	})
+}
@@ -72,6 +72,7 @@ func (s *preferenceService) UpdateUserPreferences(ctx context.Context, userID uu
 			Newsletter:         false,
 			SecurityAlerts:     true,
 			StockAlerts:        false,
+			PriceAlerts:        false,
 			PreferredChannel:   entity.ChannelEmail,
 			TimeZone:           "UTC",
 		}
@@ -111,6 +112,9 @@ func (s *preferenceService) UpdateUserPreferences(ctx context.Context, userID uu
 	if request.StockAlerts != nil {
 		preference.StockAlerts = *request.StockAlerts
 	}
+	if request.PriceAlerts != nil {
+		preference.PriceAlerts = *request.PriceAlerts
+	}
 	if request.PreferredChannel != nil {
 		preference.PreferredChannel = *request.PreferredChannel
 	}
@@ -159,6 +163,7 @@ func (s *preferenceService) CreateDefaultPreferences(ctx context.Context, userID
 		Newsletter:         false,
 		SecurityAlerts:     true,
 		StockAlerts:        false,
+		PriceAlerts:        false,
 		PreferredChannel:   entity.ChannelEmail,
 		TimeZone:           "UTC",
 	}
@@ -198,6 +203,8 @@ func (s *preferenceService) checkTypeConsent(preference *entity.NotificationPref
 		return preference.Newsletter
 	case entity.NotificationTypeStockAlert:
 		return preference.StockAlerts
+	case entity.NotificationTypePriceDrop:
+		return preference.PriceAlerts
 	case entity.NotificationTypeWelcome, entity.NotificationTypePasswordReset:
 		return preference.SecurityAlerts
 	default:
@@ -219,6 +226,7 @@ func (s *preferenceService) toPreferenceResponse(preference *entity.Notification
 		Newsletter:            preference.Newsletter,
 		SecurityAlerts:        preference.SecurityAlerts,
 		StockAlerts:           preference.StockAlerts,
+		PriceAlerts:           preference.PriceAlerts,
 		PreferredChannel:      preference.PreferredChannel,
 		TimeZone:              preference.TimeZone,
 		QuietHoursStart:       preference.QuietHoursStart,
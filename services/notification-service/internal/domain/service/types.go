@@ -130,6 +130,7 @@ type UpdatePreferenceRequest struct {
 	Newsletter            *bool                          `json:"newsletter"`
 	SecurityAlerts        *bool                          `json:"security_alerts"`
 	StockAlerts           *bool                          `json:"stock_alerts"`
+	PriceAlerts           *bool                          `json:"price_alerts"`
 	PreferredChannel      *entity.NotificationChannel    `json:"preferred_channel"`
 	TimeZone              *string                        `json:"time_zone"`
 	QuietHoursStart       *string                        `json:"quiet_hours_start"`
@@ -149,6 +150,7 @@ type PreferenceResponse struct {
 	Newsletter            bool                           `json:"newsletter"`
 	SecurityAlerts        bool                           `json:"security_alerts"`
 	StockAlerts           bool                           `json:"stock_alerts"`
+	PriceAlerts           bool                           `json:"price_alerts"`
 	PreferredChannel      entity.NotificationChannel     `json:"preferred_channel"`
 	TimeZone              string                         `json:"time_zone"`
 	QuietHoursStart       *string                        `json:"quiet_hours_start"`
@@ -157,11 +159,15 @@ type PreferenceResponse struct {
 	UpdatedAt             time.Time                      `json:"updated_at"`
 }
 
+// NotificationListResponse is a page of notifications. Total is left out
+// when it was not counted, and the cursors at the ends of the list.
 type NotificationListResponse struct {
 	Notifications []*NotificationResponse            `json:"notifications"`
-	Total         int64                              `json:"total"`
+	Total         *int64                             `json:"total,omitempty"`
 	Limit         int                                `json:"limit"`
 	Offset        int                                `json:"offset"`
+	NextCursor    string                             `json:"next_cursor,omitempty"`
+	PrevCursor    string                             `json:"prev_cursor,omitempty"`
 }
 
 type TemplateListResponse struct {
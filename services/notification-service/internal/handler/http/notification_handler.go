@@ -1,12 +1,15 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"solemate/pkg/authz"
+	"solemate/pkg/pagination"
 	"solemate/services/notification-service/internal/domain/entity"
 	"solemate/services/notification-service/internal/domain/service"
 )
@@ -29,34 +32,38 @@ func NewNotificationHandler(
 	}
 }
 
-func (h *NotificationHandler) RegisterRoutes(router *gin.RouterGroup, jwtMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
+func (h *NotificationHandler) RegisterRoutes(router *gin.RouterGroup, jwtMiddleware gin.HandlerFunc) {
+	canSend := authz.RequirePermission(authz.NotificationsSend)
+	canManage := authz.RequirePermission(authz.NotificationsManage)
+	canWriteTemplates := authz.RequirePermission(authz.TemplatesWrite)
+
 	notifications := router.Group("/notifications")
 	notifications.Use(jwtMiddleware)
 	{
 		notifications.POST("/send", h.SendNotification)
-		notifications.POST("/send-bulk", adminMiddleware, h.SendBulkNotification)
+		notifications.POST("/send-bulk", canSend, h.SendBulkNotification)
 		notifications.POST("/send-template", h.SendTemplateNotification)
 		notifications.GET("/:id", h.GetNotification)
 		notifications.GET("/user/:userId", h.GetUserNotifications)
 		notifications.POST("/process-event", h.ProcessEvent)
-		notifications.POST("/retry-failed", adminMiddleware, h.RetryFailedNotifications)
+		notifications.POST("/retry-failed", canManage, h.RetryFailedNotifications)
 		notifications.PATCH("/:id/cancel", h.CancelNotification)
 
-		notifications.GET("/admin/by-status/:status", adminMiddleware, h.GetNotificationsByStatus)
-		notifications.GET("/admin/statistics", adminMiddleware, h.GetStatistics)
-		notifications.GET("/admin/delivery-report", adminMiddleware, h.GetDeliveryReport)
-		notifications.POST("/admin/process-queue", adminMiddleware, h.ProcessNotificationQueue)
-		notifications.POST("/admin/process-scheduled", adminMiddleware, h.ProcessScheduledNotifications)
+		notifications.GET("/admin/by-status/:status", canManage, h.GetNotificationsByStatus)
+		notifications.GET("/admin/statistics", canManage, h.GetStatistics)
+		notifications.GET("/admin/delivery-report", canManage, h.GetDeliveryReport)
+		notifications.POST("/admin/process-queue", canManage, h.ProcessNotificationQueue)
+		notifications.POST("/admin/process-scheduled", canManage, h.ProcessScheduledNotifications)
 	}
 
 	templates := router.Group("/notification-templates")
 	templates.Use(jwtMiddleware)
 	{
-		templates.POST("/", adminMiddleware, h.CreateTemplate)
+		templates.POST("/", canWriteTemplates, h.CreateTemplate)
 		templates.GET("/:id", h.GetTemplate)
 		templates.GET("/name/:name", h.GetTemplateByName)
-		templates.PUT("/:id", adminMiddleware, h.UpdateTemplate)
-		templates.DELETE("/:id", adminMiddleware, h.DeleteTemplate)
+		templates.PUT("/:id", canWriteTemplates, h.UpdateTemplate)
+		templates.DELETE("/:id", canWriteTemplates, h.DeleteTemplate)
 		templates.GET("/", h.ListTemplates)
 		templates.POST("/:id/render", h.RenderTemplate)
 	}
@@ -160,11 +167,18 @@ func (h *NotificationHandler) GetUserNotifications(c *gin.Context) {
 		return
 	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	var query pagination.Query
+	query.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "10"))
+	query.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	query.Cursor = c.Query("cursor") // next_cursor or prev_cursor of a previous page
+	query.SkipCount, _ = strconv.ParseBool(c.Query("skip_count"))
 
-	response, err := h.notificationService.GetUserNotifications(c.Request.Context(), userID, limit, offset)
+	response, err := h.notificationService.GetUserNotifications(c.Request.Context(), userID, query)
 	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -7,10 +7,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"solemate/pkg/auth"
+	"solemate/pkg/cache"
 	"solemate/pkg/database"
+	"solemate/pkg/privacy"
 	"solemate/services/order-service/internal/config"
 	orderHttp "solemate/services/order-service/internal/handler/http"
 	orderDatabase "solemate/services/order-service/internal/infrastructure/database"
+	orderInfra "solemate/services/order-service/internal/infrastructure/http"
 	"solemate/services/order-service/internal/domain/service"
 	"solemate/services/order-service/internal/domain/entity"
 	"solemate/services/order-service/internal/domain/repository"
@@ -47,20 +50,31 @@ func main() {
 	var productRepo repository.ProductRepository = nil
 	var notificationRepo repository.NotificationRepository = nil
 
+	// Address book lookups go to user-service on the customer's behalf
+	internalTokens, err := auth.NewInternalTokenManager(auth.ServiceOrder)
+	if err != nil {
+		log.Fatalf("Failed to initialize internal tokens: %v", err)
+	}
+	addressRepo := orderInfra.NewAddressRepository(cfg.External.UserServiceURL, internalTokens)
+
 	// Initialize services
-	orderService := service.NewOrderService(orderRepo, cartRepo, productRepo, notificationRepo)
+	orderService := service.NewOrderService(orderRepo, cartRepo, productRepo, addressRepo, notificationRepo)
+
+	// Session revocations written by user-service; without Redis, access
+	// tokens are only accepted through the gateway
+	var revocations *auth.SessionRevocations
+	if redisClient, err := cache.NewRedisClient(cache.GetConfigFromEnv()); err != nil {
+		log.Printf("Redis unavailable, access tokens are only accepted through the gateway: %v", err)
+	} else {
+		revocations = auth.NewSessionRevocations(redisClient)
+	}
 
 	// Initialize middleware
-	jwtMiddleware := auth.JWTMiddleware(cfg.JWT.AccessSecret)
-	adminMiddleware := func(c *gin.Context) {
-		userRole, exists := c.Get("user_role")
-		if !exists || userRole != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
-			c.Abort()
-			return
-		}
-		c.Next()
-	}
+	jwtMiddleware := auth.ServiceAuthMiddleware(
+		internalTokens,
+		auth.NewAccessTokenValidator(cfg.JWT.AccessSecret),
+		revocations,
+	)
 
 	// Initialize handlers
 	orderHandler := orderHttp.NewOrderHandler(orderService)
@@ -82,7 +96,8 @@ func main() {
 
 	// API routes
 	v1 := router.Group("/api/v1")
-	orderHandler.RegisterRoutes(v1, jwtMiddleware, adminMiddleware)
+	orderHandler.RegisterRoutes(v1, jwtMiddleware)
+	privacy.RegisterRoutes(v1, jwtMiddleware, orderService)
 
 	// Start server
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
@@ -37,6 +37,7 @@ type JWTConfig struct {
 }
 
 type ExternalConfig struct {
+	UserServiceURL    string
 	CartServiceURL    string
 	ProductServiceURL string
 	PaymentServiceURL string
@@ -66,6 +67,7 @@ func Load() *Config {
 			RefreshSecret: getEnv("JWT_REFRESH_SECRET", "your-refresh-secret-key"),
 		},
 		External: ExternalConfig{
+			UserServiceURL:    getEnv("USER_SERVICE_URL", "http://localhost:8080"),
 			CartServiceURL:    getEnv("CART_SERVICE_URL", "http://localhost:8083"),
 			ProductServiceURL: getEnv("PRODUCT_SERVICE_URL", "http://localhost:8081"),
 			PaymentServiceURL: getEnv("PAYMENT_SERVICE_URL", "http://localhost:8085"),
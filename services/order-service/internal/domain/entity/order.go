@@ -220,4 +220,5 @@ var (
 	ErrOrderNotCancellable    = OrderError{Message: "order cannot be cancelled"}
 	ErrOrderNotRefundable     = OrderError{Message: "order is not refundable"}
 	ErrInvalidOrderData       = OrderError{Message: "invalid order data"}
+	ErrAddressNotFound        = OrderError{Message: "address not found"}
 )
\ No newline at end of file
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"solemate/pkg/pagination"
 	"solemate/services/order-service/internal/domain/entity"
 )
 
@@ -21,6 +22,7 @@ type OrderRepository interface {
 	GetOrdersByStatus(ctx context.Context, status entity.OrderStatus, limit, offset int) ([]*entity.Order, int64, error)
 	GetOrdersByDateRange(ctx context.Context, startDate, endDate time.Time, limit, offset int) ([]*entity.Order, int64, error)
 	GetOrdersByPaymentStatus(ctx context.Context, paymentStatus entity.PaymentStatus, limit, offset int) ([]*entity.Order, int64, error)
+	GetUserOrdersContainingProduct(ctx context.Context, userID, productID uuid.UUID, statuses []entity.OrderStatus) ([]*entity.Order, error)
 
 	// Order summaries
 	GetOrderSummariesByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.OrderSummary, int64, error)
@@ -34,10 +36,14 @@ type OrderRepository interface {
 	// Order analytics
 	GetOrderStatistics(ctx context.Context, startDate, endDate time.Time) (*OrderStatistics, error)
 	GetTopProducts(ctx context.Context, startDate, endDate time.Time, limit int) ([]*ProductSalesInfo, error)
+	GetCoPurchasedProducts(ctx context.Context, startDate, endDate time.Time, minOrders, limit int) ([]*ProductPairInfo, error)
 	GetSalesMetrics(ctx context.Context, startDate, endDate time.Time) (*SalesMetrics, error)
 
 	// Order search and filtering
-	SearchOrders(ctx context.Context, filters *OrderFilters) ([]*entity.Order, int64, error)
+	SearchOrders(ctx context.Context, filters *OrderFilters) ([]*entity.Order, *pagination.Page, error)
+
+	// Personal data erasure
+	AnonymizeOrdersByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 
 type CartRepository interface {
@@ -55,6 +61,11 @@ type ProductRepository interface {
 	ReleaseStock(ctx context.Context, items []StockReservation) error
 }
 
+type AddressRepository interface {
+	// Integration with the user-service address book
+	GetUserAddress(ctx context.Context, userID, addressID uuid.UUID) (*AddressData, error)
+}
+
 type NotificationRepository interface {
 	// Order notifications
 	SendOrderConfirmation(ctx context.Context, order *entity.Order) error
@@ -77,6 +88,8 @@ type OrderFilters struct {
 	SortOrder        string // "asc", "desc"
 	Limit            int
 	Offset           int
+	Cursor           string // continues from a page's cursor instead of Offset
+	SkipCount        bool   // leaves the total out of the page
 }
 
 type OrderStatistics struct {
@@ -103,6 +116,16 @@ type ProductSalesInfo struct {
 	OrderCount    int       `json:"order_count"`
 }
 
+// ProductPairInfo counts the orders that contain both products. ProductOrders
+// is the number of orders containing ProductID, so OrderCount/ProductOrders is
+// the share of its buyers who also bought RelatedProductID.
+type ProductPairInfo struct {
+	ProductID        uuid.UUID `json:"product_id"`
+	RelatedProductID uuid.UUID `json:"related_product_id"`
+	OrderCount       int       `json:"order_count"`
+	ProductOrders    int       `json:"product_orders"`
+}
+
 type SalesMetrics struct {
 	TotalRevenue      float64 `json:"total_revenue"`
 	TotalOrders       int64   `json:"total_orders"`
@@ -154,6 +177,20 @@ type ProductVariantInfo struct {
 	ImageURL  string    `json:"image_url"`
 }
 
+type AddressData struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Type       string    `json:"type"`
+	Name       string    `json:"name"`
+	Street1    string    `json:"street_1"`
+	Street2    string    `json:"street_2"`
+	City       string    `json:"city"`
+	State      string    `json:"state"`
+	PostalCode string    `json:"postal_code"`
+	Country    string    `json:"country"`
+	Phone      string    `json:"phone"`
+}
+
 type StockReservation struct {
 	ProductID uuid.UUID  `json:"product_id"`
 	VariantID *uuid.UUID `json:"variant_id"`
@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"solemate/pkg/pagination"
+	"solemate/pkg/privacy"
 	"solemate/services/order-service/internal/domain/entity"
 	"solemate/services/order-service/internal/domain/repository"
 )
@@ -13,10 +16,12 @@ import (
 type OrderService interface {
 	// Order creation and management
 	CreateOrderFromCart(ctx context.Context, userID uuid.UUID, shippingAddress, billingAddress entity.Address, shippingMethod string, notes string) (*entity.Order, error)
+	ResolveUserAddress(ctx context.Context, userID, addressID uuid.UUID) (entity.Address, error)
 	GetOrderByID(ctx context.Context, orderID uuid.UUID) (*entity.Order, error)
 	GetOrderByNumber(ctx context.Context, orderNumber string) (*entity.Order, error)
 	GetUserOrders(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entity.Order, int64, error)
 	GetUserOrderSummaries(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entity.OrderSummary, int64, error)
+	GetUserPurchasesOfProduct(ctx context.Context, userID, productID uuid.UUID) ([]*entity.Order, error)
 
 	// Order status management
 	ConfirmOrder(ctx context.Context, orderID uuid.UUID) error
@@ -39,18 +44,24 @@ type OrderService interface {
 	// Administrative functions
 	GetOrdersByStatus(ctx context.Context, status entity.OrderStatus, page, limit int) ([]*entity.Order, int64, error)
 	GetOrdersByPaymentStatus(ctx context.Context, paymentStatus entity.PaymentStatus, page, limit int) ([]*entity.Order, int64, error)
-	SearchOrders(ctx context.Context, filters *repository.OrderFilters) ([]*entity.Order, int64, error)
+	SearchOrders(ctx context.Context, filters *repository.OrderFilters) ([]*entity.Order, *pagination.Page, error)
 
 	// Analytics and reporting
 	GetOrderStatistics(ctx context.Context, startDate, endDate time.Time) (*repository.OrderStatistics, error)
 	GetTopProducts(ctx context.Context, startDate, endDate time.Time, limit int) ([]*repository.ProductSalesInfo, error)
+	GetCoPurchasedProducts(ctx context.Context, startDate, endDate time.Time, minOrders, limit int) ([]*repository.ProductPairInfo, error)
 	GetSalesMetrics(ctx context.Context, startDate, endDate time.Time) (*repository.SalesMetrics, error)
+
+	// Personal data export and erasure (privacy.Provider)
+	ExportUserData(ctx context.Context, userID uuid.UUID) (interface{}, error)
+	EraseUserData(ctx context.Context, userID uuid.UUID) (*privacy.ErasureReport, error)
 }
 
 type orderService struct {
 	orderRepo        repository.OrderRepository
 	cartRepo         repository.CartRepository
 	productRepo      repository.ProductRepository
+	addressRepo      repository.AddressRepository
 	notificationRepo repository.NotificationRepository
 }
 
@@ -58,12 +69,14 @@ func NewOrderService(
 	orderRepo repository.OrderRepository,
 	cartRepo repository.CartRepository,
 	productRepo repository.ProductRepository,
+	addressRepo repository.AddressRepository,
 	notificationRepo repository.NotificationRepository,
 ) OrderService {
 	return &orderService{
 		orderRepo:        orderRepo,
 		cartRepo:         cartRepo,
 		productRepo:      productRepo,
+		addressRepo:      addressRepo,
 		notificationRepo: notificationRepo,
 	}
 }
@@ -169,6 +182,39 @@ func (s *orderService) CreateOrderFromCart(ctx context.Context, userID uuid.UUID
 	return order, nil
 }
 
+// ResolveUserAddress loads a saved address from the user's address book and
+// converts it into the address snapshot stored on the order.
+func (s *orderService) ResolveUserAddress(ctx context.Context, userID, addressID uuid.UUID) (entity.Address, error) {
+	if s.addressRepo == nil {
+		return entity.Address{}, fmt.Errorf("address book is not available")
+	}
+
+	data, err := s.addressRepo.GetUserAddress(ctx, userID, addressID)
+	if err != nil {
+		return entity.Address{}, err
+	}
+	if data.UserID != userID {
+		return entity.Address{}, entity.ErrAddressNotFound
+	}
+
+	firstName, lastName := data.Name, ""
+	if i := strings.LastIndex(data.Name, " "); i > 0 {
+		firstName, lastName = data.Name[:i], data.Name[i+1:]
+	}
+
+	return entity.Address{
+		FirstName:     firstName,
+		LastName:      lastName,
+		AddressLine1:  data.Street1,
+		AddressLine2:  data.Street2,
+		City:          data.City,
+		StateProvince: data.State,
+		PostalCode:    data.PostalCode,
+		Country:       data.Country,
+		Phone:         data.Phone,
+	}, nil
+}
+
 func (s *orderService) GetOrderByID(ctx context.Context, orderID uuid.UUID) (*entity.Order, error) {
 	return s.orderRepo.GetOrderByID(ctx, orderID)
 }
@@ -187,6 +233,13 @@ func (s *orderService) GetUserOrderSummaries(ctx context.Context, userID uuid.UU
 	return s.orderRepo.GetOrderSummariesByUserID(ctx, userID, limit, offset)
 }
 
+// GetUserPurchasesOfProduct returns the user's orders that contain the
+// product and have reached the customer, so the purchase can be vouched for
+func (s *orderService) GetUserPurchasesOfProduct(ctx context.Context, userID, productID uuid.UUID) ([]*entity.Order, error) {
+	return s.orderRepo.GetUserOrdersContainingProduct(ctx, userID, productID,
+		[]entity.OrderStatus{entity.OrderStatusDelivered, entity.OrderStatusCompleted})
+}
+
 func (s *orderService) ConfirmOrder(ctx context.Context, orderID uuid.UUID) error {
 	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
 	if err != nil {
@@ -471,7 +524,7 @@ func (s *orderService) GetOrdersByPaymentStatus(ctx context.Context, paymentStat
 	return s.orderRepo.GetOrdersByPaymentStatus(ctx, paymentStatus, limit, offset)
 }
 
-func (s *orderService) SearchOrders(ctx context.Context, filters *repository.OrderFilters) ([]*entity.Order, int64, error) {
+func (s *orderService) SearchOrders(ctx context.Context, filters *repository.OrderFilters) ([]*entity.Order, *pagination.Page, error) {
 	return s.orderRepo.SearchOrders(ctx, filters)
 }
 
@@ -483,6 +536,10 @@ func (s *orderService) GetTopProducts(ctx context.Context, startDate, endDate ti
 	return s.orderRepo.GetTopProducts(ctx, startDate, endDate, limit)
 }
 
+func (s *orderService) GetCoPurchasedProducts(ctx context.Context, startDate, endDate time.Time, minOrders, limit int) ([]*repository.ProductPairInfo, error) {
+	return s.orderRepo.GetCoPurchasedProducts(ctx, startDate, endDate, minOrders, limit)
+}
+
 func (s *orderService) GetSalesMetrics(ctx context.Context, startDate, endDate time.Time) (*repository.SalesMetrics, error) {
 	return s.orderRepo.GetSalesMetrics(ctx, startDate, endDate)
 }
@@ -513,4 +570,29 @@ func (s *orderService) calculateTax(items []entity.OrderItem, address entity.Add
 
 	// Assume 8.5% tax rate for simplicity
 	return subtotal * 0.085
-}
\ No newline at end of file
+}
+
+func (s *orderService) ExportUserData(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	// A negative limit and offset disable pagination
+	orders, _, err := s.orderRepo.GetOrdersByUserID(ctx, userID, -1, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load orders: %w", err)
+	}
+
+	return map[string]interface{}{
+		"orders": orders,
+	}, nil
+}
+
+// EraseUserData anonymises the user's orders. Orders are kept because they are
+// needed for accounting.
+func (s *orderService) EraseUserData(ctx context.Context, userID uuid.UUID) (*privacy.ErasureReport, error) {
+	count, err := s.orderRepo.AnonymizeOrdersByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to anonymize orders: %w", err)
+	}
+
+	report := privacy.NewErasureReport()
+	report.AddAnonymized("orders", count)
+	return report, nil
+}
@@ -1,12 +1,16 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"solemate/pkg/auth"
+	"solemate/pkg/authz"
+	"solemate/pkg/pagination"
 	"solemate/pkg/utils"
 	"solemate/services/order-service/internal/domain/entity"
 	"solemate/services/order-service/internal/domain/repository"
@@ -24,11 +28,15 @@ func NewOrderHandler(orderService service.OrderService) *OrderHandler {
 }
 
 // Request DTOs
+// CreateOrderRequest takes each address either inline or as the ID of an
+// address saved in the customer's address book.
 type CreateOrderRequest struct {
-	ShippingAddress entity.Address `json:"shipping_address" binding:"required"`
-	BillingAddress  entity.Address `json:"billing_address" binding:"required"`
-	ShippingMethod  string         `json:"shipping_method" binding:"required"`
-	Notes           string         `json:"notes"`
+	ShippingAddress   *entity.Address `json:"shipping_address"`
+	ShippingAddressID *uuid.UUID      `json:"shipping_address_id"`
+	BillingAddress    *entity.Address `json:"billing_address"`
+	BillingAddressID  *uuid.UUID      `json:"billing_address_id"`
+	ShippingMethod    string          `json:"shipping_method" binding:"required"`
+	Notes             string          `json:"notes"`
 }
 
 type UpdateOrderStatusRequest struct {
@@ -83,11 +91,20 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	shippingAddress, ok := h.resolveAddress(c, userUUID, "shipping", req.ShippingAddress, req.ShippingAddressID)
+	if !ok {
+		return
+	}
+	billingAddress, ok := h.resolveAddress(c, userUUID, "billing", req.BillingAddress, req.BillingAddressID)
+	if !ok {
+		return
+	}
+
 	order, err := h.orderService.CreateOrderFromCart(
 		c.Request.Context(),
 		userUUID,
-		req.ShippingAddress,
-		req.BillingAddress,
+		shippingAddress,
+		billingAddress,
 		req.ShippingMethod,
 		req.Notes,
 	)
@@ -99,6 +116,31 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	utils.CreatedResponse(c, "Order created successfully", order)
 }
 
+// resolveAddress returns the inline address or looks up addressID in the
+// user's address book. Exactly one of the two must be given.
+func (h *OrderHandler) resolveAddress(c *gin.Context, userID uuid.UUID, kind string, address *entity.Address, addressID *uuid.UUID) (entity.Address, bool) {
+	if (address == nil) == (addressID == nil) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", "provide either "+kind+"_address or "+kind+"_address_id")
+		return entity.Address{}, false
+	}
+
+	if address != nil {
+		return *address, true
+	}
+
+	resolved, err := h.orderService.ResolveUserAddress(c.Request.Context(), userID, *addressID)
+	if err != nil {
+		if errors.Is(err, entity.ErrAddressNotFound) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Address not found", kind+"_address_id does not match a saved address")
+			return entity.Address{}, false
+		}
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to load saved address", err.Error())
+		return entity.Address{}, false
+	}
+
+	return resolved, true
+}
+
 func (h *OrderHandler) GetOrder(c *gin.Context) {
 	orderIDStr := c.Param("order_id")
 	orderID, err := uuid.Parse(orderIDStr)
@@ -113,18 +155,11 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		return
 	}
 
-	// Check if user can access this order
-	userID, exists := c.Get("user_id")
-	if exists {
-		userUUID, ok := userID.(uuid.UUID)
-		if ok && order.UserID != userUUID {
-			// Check if user has admin role
-			userRole, roleExists := c.Get("user_role")
-			if !roleExists || userRole != "admin" {
-				utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "insufficient_permissions")
-				return
-			}
-		}
+	// Check if caller can access this order
+	userID, _ := auth.CurrentUserID(c)
+	if order.UserID != userID && !authz.Allowed(c, authz.OrdersRead) {
+		utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "insufficient_permissions")
+		return
 	}
 
 	utils.SuccessResponse(c, "Order retrieved successfully", order)
@@ -139,18 +174,11 @@ func (h *OrderHandler) GetOrderByNumber(c *gin.Context) {
 		return
 	}
 
-	// Check if user can access this order
-	userID, exists := c.Get("user_id")
-	if exists {
-		userUUID, ok := userID.(uuid.UUID)
-		if ok && order.UserID != userUUID {
-			// Check if user has admin role
-			userRole, roleExists := c.Get("user_role")
-			if !roleExists || userRole != "admin" {
-				utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "insufficient_permissions")
-				return
-			}
-		}
+	// Check if caller can access this order
+	userID, _ := auth.CurrentUserID(c)
+	if order.UserID != userID && !authz.Allowed(c, authz.OrdersRead) {
+		utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "insufficient_permissions")
+		return
 	}
 
 	utils.SuccessResponse(c, "Order retrieved successfully", order)
@@ -222,6 +250,36 @@ func (h *OrderHandler) GetUserOrderSummaries(c *gin.Context) {
 	utils.PaginatedSuccessResponse(c, "Order summaries retrieved successfully", summaries, pagination)
 }
 
+// GetUserPurchases lists the user's delivered or completed orders that
+// contain a product. Product-service calls it to verify reviewers' purchases.
+func (h *OrderHandler) GetUserPurchases(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", "invalid_user_id")
+		return
+	}
+
+	productID, err := uuid.Parse(c.Query("product_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID format", "invalid_product_id")
+		return
+	}
+
+	orders, err := h.orderService.GetUserPurchasesOfProduct(c.Request.Context(), userUUID, productID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get purchases", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Purchases retrieved successfully", orders)
+}
+
 // Order status management
 func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 	orderIDStr := c.Param("order_id")
@@ -374,13 +432,6 @@ func (h *OrderHandler) UpdateBillingAddress(c *gin.Context) {
 
 // Administrative functions
 func (h *OrderHandler) SearchOrders(c *gin.Context) {
-	// Check admin permissions
-	userRole, exists := c.Get("user_role")
-	if !exists || userRole != "admin" {
-		utils.ErrorResponse(c, http.StatusForbidden, "Admin access required", "insufficient_permissions")
-		return
-	}
-
 	var req OrderSearchRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err.Error())
@@ -389,6 +440,8 @@ func (h *OrderHandler) SearchOrders(c *gin.Context) {
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	cursor := c.Query("cursor") // next_cursor or prev_cursor of a previous page
+	skipCount, _ := strconv.ParseBool(c.Query("skip_count"))
 
 	if page < 1 {
 		page = 1
@@ -410,26 +463,24 @@ func (h *OrderHandler) SearchOrders(c *gin.Context) {
 		SortOrder:     req.SortOrder,
 		Limit:         limit,
 		Offset:        (page - 1) * limit,
+		Cursor:        cursor,
+		SkipCount:     skipCount,
 	}
 
-	orders, total, err := h.orderService.SearchOrders(c.Request.Context(), filters)
+	orders, result, err := h.orderService.SearchOrders(c.Request.Context(), filters)
 	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid cursor", err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to search orders", err.Error())
 		return
 	}
 
-	pagination := utils.CalculatePagination(page, limit, total)
-	utils.PaginatedSuccessResponse(c, "Orders retrieved successfully", orders, pagination)
+	utils.PaginatedSuccessResponse(c, "Orders retrieved successfully", orders, utils.CalculateCursorPagination(page, limit, cursor, *result))
 }
 
 func (h *OrderHandler) GetOrderStatistics(c *gin.Context) {
-	// Check admin permissions
-	userRole, exists := c.Get("user_role")
-	if !exists || userRole != "admin" {
-		utils.ErrorResponse(c, http.StatusForbidden, "Admin access required", "insufficient_permissions")
-		return
-	}
-
 	startDateStr := c.Query("start_date")
 	endDateStr := c.Query("end_date")
 
@@ -465,20 +516,16 @@ func (h *OrderHandler) GetOrderStatistics(c *gin.Context) {
 	utils.SuccessResponse(c, "Order statistics retrieved successfully", statistics)
 }
 
+// GetTopProducts lists products by units sold, best selling first.
+// Product-service reads it for its whole catalog to rank products by
+// popularity, hence the high limit.
 func (h *OrderHandler) GetTopProducts(c *gin.Context) {
-	// Check admin permissions
-	userRole, exists := c.Get("user_role")
-	if !exists || userRole != "admin" {
-		utils.ErrorResponse(c, http.StatusForbidden, "Admin access required", "insufficient_permissions")
-		return
-	}
-
 	startDateStr := c.Query("start_date")
 	endDateStr := c.Query("end_date")
 	limitStr := c.DefaultQuery("limit", "10")
 
 	limit, _ := strconv.Atoi(limitStr)
-	if limit < 1 || limit > 50 {
+	if limit < 1 || limit > 10000 {
 		limit = 10
 	}
 
@@ -514,14 +561,54 @@ func (h *OrderHandler) GetTopProducts(c *gin.Context) {
 	utils.SuccessResponse(c, "Top products retrieved successfully", products)
 }
 
-func (h *OrderHandler) GetSalesMetrics(c *gin.Context) {
-	// Check admin permissions
-	userRole, exists := c.Get("user_role")
-	if !exists || userRole != "admin" {
-		utils.ErrorResponse(c, http.StatusForbidden, "Admin access required", "insufficient_permissions")
+// GetCoPurchasedProducts lists pairs of products bought in the same orders,
+// most frequent first. Product-service builds its recommendations from it.
+func (h *OrderHandler) GetCoPurchasedProducts(c *gin.Context) {
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+
+	minOrders, _ := strconv.Atoi(c.DefaultQuery("min_orders", "2"))
+	if minOrders < 1 {
+		minOrders = 2
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "1000"))
+	if limit < 1 || limit > 10000 {
+		limit = 1000
+	}
+
+	var startDate, endDate time.Time
+	var err error
+
+	if startDateStr != "" {
+		startDate, err = time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid start date format", "invalid_date")
+			return
+		}
+	} else {
+		startDate = time.Now().AddDate(0, -6, 0) // Default to the last six months
+	}
+
+	if endDateStr != "" {
+		endDate, err = time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid end date format", "invalid_date")
+			return
+		}
+	} else {
+		endDate = time.Now()
+	}
+
+	pairs, err := h.orderService.GetCoPurchasedProducts(c.Request.Context(), startDate, endDate, minOrders, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get co-purchased products", err.Error())
 		return
 	}
 
+	utils.SuccessResponse(c, "Co-purchased products retrieved successfully", pairs)
+}
+
+func (h *OrderHandler) GetSalesMetrics(c *gin.Context) {
 	startDateStr := c.Query("start_date")
 	endDateStr := c.Query("end_date")
 
@@ -558,7 +645,7 @@ func (h *OrderHandler) GetSalesMetrics(c *gin.Context) {
 }
 
 // Route registration
-func (h *OrderHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+func (h *OrderHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
 	orders := router.Group("/orders")
 	orders.Use(authMiddleware)
 
@@ -566,6 +653,7 @@ func (h *OrderHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware, a
 	orders.POST("", h.CreateOrder)
 	orders.GET("/me", h.GetUserOrders)
 	orders.GET("/me/summaries", h.GetUserOrderSummaries)
+	orders.GET("/me/purchases", h.GetUserPurchases)
 	orders.GET("/number/:order_number", h.GetOrderByNumber) // Must be before /:order_id
 	orders.GET("/:order_id", h.GetOrder)
 
@@ -575,14 +663,14 @@ func (h *OrderHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware, a
 
 	// Admin routes
 	admin := orders.Group("/admin")
-	admin.Use(adminMiddleware)
 	{
-		admin.POST("/search", h.SearchOrders)
-		admin.GET("/statistics", h.GetOrderStatistics)
-		admin.GET("/top-products", h.GetTopProducts)
-		admin.GET("/sales-metrics", h.GetSalesMetrics)
-		admin.PATCH("/:order_id/status", h.UpdateOrderStatus)
-		admin.POST("/:order_id/ship", h.ShipOrder)
-		admin.PATCH("/:order_id/payment-status", h.UpdatePaymentStatus)
+		admin.POST("/search", authz.RequirePermission(authz.OrdersRead), h.SearchOrders)
+		admin.GET("/statistics", authz.RequirePermission(authz.AnalyticsRead), h.GetOrderStatistics)
+		admin.GET("/top-products", authz.RequirePermission(authz.AnalyticsRead), h.GetTopProducts)
+		admin.GET("/co-purchases", authz.RequirePermission(authz.AnalyticsRead), h.GetCoPurchasedProducts)
+		admin.GET("/sales-metrics", authz.RequirePermission(authz.AnalyticsRead), h.GetSalesMetrics)
+		admin.PATCH("/:order_id/status", authz.RequirePermission(authz.OrdersUpdateStatus), h.UpdateOrderStatus)
+		admin.POST("/:order_id/ship", authz.RequirePermission(authz.OrdersShip), h.ShipOrder)
+		admin.PATCH("/:order_id/payment-status", authz.RequirePermission(authz.OrdersUpdatePayment), h.UpdatePaymentStatus)
 	}
 }
\ No newline at end of file
@@ -3,10 +3,12 @@ package database
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"solemate/pkg/pagination"
 	"solemate/services/order-service/internal/domain/entity"
 	"solemate/services/order-service/internal/domain/repository"
 )
@@ -87,6 +89,18 @@ func (r *orderRepositoryImpl) GetOrdersByUserID(ctx context.Context, userID uuid
 	return orders, total, err
 }
 
+// GetUserOrdersContainingProduct returns the user's orders in one of the
+// given statuses that include the product, most recent first
+func (r *orderRepositoryImpl) GetUserOrdersContainingProduct(ctx context.Context, userID, productID uuid.UUID, statuses []entity.OrderStatus) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status IN ?", userID, statuses).
+		Where("EXISTS (SELECT 1 FROM order_items WHERE order_items.order_id = orders.id AND order_items.product_id = ?)", productID).
+		Order("created_at DESC").
+		Find(&orders).Error
+	return orders, err
+}
+
 func (r *orderRepositoryImpl) GetOrdersByStatus(ctx context.Context, status entity.OrderStatus, limit, offset int) ([]*entity.Order, int64, error) {
 	var orders []*entity.Order
 	var total int64
@@ -364,6 +378,45 @@ func (r *orderRepositoryImpl) GetTopProducts(ctx context.Context, startDate, end
 	return products, err
 }
 
+// GetCoPurchasedProducts counts, for each ordered pair of products, the
+// orders containing both. Cancelled and refunded orders don't count, nor do
+// several variants of one product in the same order. Pairs bought together in
+// fewer than minOrders orders are left out.
+func (r *orderRepositoryImpl) GetCoPurchasedProducts(ctx context.Context, startDate, endDate time.Time, minOrders, limit int) ([]*repository.ProductPairInfo, error) {
+	var pairs []*repository.ProductPairInfo
+
+	err := r.db.WithContext(ctx).Raw(`
+		WITH purchases AS (
+			SELECT DISTINCT oi.order_id, oi.product_id
+			FROM order_items oi
+			JOIN orders o ON oi.order_id = o.id
+			WHERE o.created_at BETWEEN ? AND ? AND o.status NOT IN ?
+		),
+		product_orders AS (
+			SELECT product_id, COUNT(*) AS order_count
+			FROM purchases
+			GROUP BY product_id
+		)
+		SELECT
+			a.product_id,
+			b.product_id AS related_product_id,
+			COUNT(*) AS order_count,
+			po.order_count AS product_orders
+		FROM purchases a
+		JOIN purchases b ON a.order_id = b.order_id AND a.product_id <> b.product_id
+		JOIN product_orders po ON po.product_id = a.product_id
+		GROUP BY a.product_id, b.product_id, po.order_count
+		HAVING COUNT(*) >= ?
+		ORDER BY order_count DESC
+		LIMIT ?
+	`, startDate, endDate,
+		[]entity.OrderStatus{entity.OrderStatusCancelled, entity.OrderStatusRefunded},
+		minOrders, limit).
+		Scan(&pairs).Error
+
+	return pairs, err
+}
+
 func (r *orderRepositoryImpl) GetSalesMetrics(ctx context.Context, startDate, endDate time.Time) (*repository.SalesMetrics, error) {
 	var metrics repository.SalesMetrics
 
@@ -406,9 +459,20 @@ func (r *orderRepositoryImpl) GetSalesMetrics(ctx context.Context, startDate, en
 	return &metrics, nil
 }
 
-func (r *orderRepositoryImpl) SearchOrders(ctx context.Context, filters *repository.OrderFilters) ([]*entity.Order, int64, error) {
+// SearchOrders returns a page of matching orders, by cursor when filters has
+// one and by offset otherwise. A row more than the limit is fetched to tell
+// whether there is a next page.
+func (r *orderRepositoryImpl) SearchOrders(ctx context.Context, filters *repository.OrderFilters) ([]*entity.Order, *pagination.Page, error) {
 	var orders []*entity.Order
-	var total int64
+
+	sort := orderSortFor(filters)
+	var cursor *pagination.Cursor
+	if filters.Cursor != "" {
+		var err error
+		if cursor, err = pagination.DecodeCursor(filters.Cursor, sort.name, sort.key(&entity.Order{})); err != nil {
+			return nil, nil, err
+		}
+	}
 
 	query := r.db.WithContext(ctx).Model(&entity.Order{})
 
@@ -453,33 +517,121 @@ func (r *orderRepositoryImpl) SearchOrders(ctx context.Context, filters *reposit
 	}
 
 	// Count total
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	var total *int64
+	if !filters.SkipCount {
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return nil, nil, err
+		}
+		total = &count
 	}
 
-	// Apply sorting
-	orderBy := "created_at DESC"
-	if filters.SortBy != "" {
-		direction := "DESC"
-		if filters.SortOrder == "asc" {
-			direction = "ASC"
-		}
-		orderBy = fmt.Sprintf("%s %s", filters.SortBy, direction)
+	// Apply sorting, from the cursor onwards if there is one
+	if cursor != nil {
+		condition, args := cursor.Condition(sort.columns, sort.descending)
+		query = query.Where(condition, args...)
 	}
+	query = sort.apply(query, cursor)
 
 	// Get results with pagination
+	offset := filters.Offset
+	if cursor != nil {
+		offset = 0
+	}
 	err := query.
 		Preload("Items").
-		Order(orderBy).
-		Limit(filters.Limit).
-		Offset(filters.Offset).
+		Limit(filters.Limit + 1).
+		Offset(offset).
 		Find(&orders).Error
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return orders, total, err
+	orders, page := pagination.Paginate(orders, filters.Limit, cursor, sort.name, offset, sort.key)
+	page.Total = total
+	return orders, &page, nil
+}
+
+// orderSort is an ordering of orders. Its columns end with the ID so that
+// orders with equal values keep the same order from page to page.
+type orderSort struct {
+	name       string // tells cursors of different sorts apart
+	columns    []string
+	descending bool
+	key        func(order *entity.Order) []interface{}
+}
+
+// orderSortFor sorts by the creation time, newest first, unless filters asks
+// for another of the sorts OrderFilters lists
+func orderSortFor(filters *repository.OrderFilters) orderSort {
+	sortBy := "created_at"
+	switch filters.SortBy {
+	case "created_at", "total_price", "status":
+		sortBy = filters.SortBy
+	}
+
+	sort := orderSort{name: sortBy + ":desc", descending: true}
+	if filters.SortBy != "" && filters.SortOrder == "asc" {
+		sort.name, sort.descending = sortBy+":asc", false
+	}
+
+	switch sortBy {
+	case "total_price":
+		sort.columns = []string{"total_price", "id"}
+		sort.key = func(o *entity.Order) []interface{} {
+			return []interface{}{&o.TotalPrice, &o.ID}
+		}
+	case "status":
+		sort.columns = []string{"status", "id"}
+		sort.key = func(o *entity.Order) []interface{} {
+			return []interface{}{&o.Status, &o.ID}
+		}
+	default:
+		sort.columns = []string{"created_at", "id"}
+		sort.key = func(o *entity.Order) []interface{} {
+			return []interface{}{&o.CreatedAt, &o.ID}
+		}
+	}
+	return sort
+}
+
+// apply orders query by the sort, reversed to walk back from a cursor to the
+// previous page
+func (s orderSort) apply(query *gorm.DB, cursor *pagination.Cursor) *gorm.DB {
+	direction := " ASC"
+	if cursor.OrderDescending(s.descending) {
+		direction = " DESC"
+	}
+	return query.Order(strings.Join(s.columns, direction+", ") + direction)
 }
 
 // Helper function to generate order numbers
 func (r *orderRepositoryImpl) generateOrderNumber() string {
 	timestamp := time.Now().Unix()
 	return fmt.Sprintf("ORD-%d", timestamp)
-}
\ No newline at end of file
+}
+
+// AnonymizeOrdersByUserID strips names, street addresses, phone numbers and
+// free-text notes from the user's orders. Amounts, items, country, region and
+// postal code are kept for accounting and tax reporting.
+func (r *orderRepositoryImpl) AnonymizeOrdersByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Order{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"shipping_first_name":    "",
+			"shipping_last_name":     "",
+			"shipping_company":       "",
+			"shipping_address_line1": "",
+			"shipping_address_line2": "",
+			"shipping_phone":         "",
+			"billing_first_name":     "",
+			"billing_last_name":      "",
+			"billing_company":        "",
+			"billing_address_line1":  "",
+			"billing_address_line2":  "",
+			"billing_phone":          "",
+			"notes":                  "",
+			"cancel_reason":          "",
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/pkg/auth"
+	"solemate/services/order-service/internal/domain/entity"
+	"solemate/services/order-service/internal/domain/repository"
+)
+
+type addressRepositoryImpl struct {
+	baseURL        string
+	httpClient     *http.Client
+	internalTokens *auth.InternalTokenManager
+}
+
+func NewAddressRepository(baseURL string, internalTokens *auth.InternalTokenManager) repository.AddressRepository {
+	return &addressRepositoryImpl{
+		baseURL:        baseURL,
+		internalTokens: internalTokens,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetUserAddress fetches the address from user-service on behalf of userID, so
+// user-service enforces that the address belongs to that user.
+func (r *addressRepositoryImpl) GetUserAddress(ctx context.Context, userID, addressID uuid.UUID) (*repository.AddressData, error) {
+	url := fmt.Sprintf("%s/api/v1/profile/addresses/%s", r.baseURL, addressID.String())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := r.internalTokens.MintForUser(auth.ServiceUser, &auth.Claims{UserID: userID.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint internal token: %w", err)
+	}
+	req.Header.Set(auth.InternalTokenHeader, token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, entity.ErrAddressNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Data *repository.AddressData `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if response.Data == nil {
+		return nil, entity.ErrAddressNotFound
+	}
+
+	return response.Data, nil
+}
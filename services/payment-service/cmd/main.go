@@ -7,7 +7,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"solemate/pkg/auth"
+	"solemate/pkg/cache"
 	"solemate/pkg/database"
+	"solemate/pkg/privacy"
 	"solemate/services/payment-service/internal/config"
 	paymentHandlers "solemate/services/payment-service/internal/handler/http"
 	paymentDatabase "solemate/services/payment-service/internal/infrastructure/database"
@@ -52,7 +54,11 @@ func main() {
 	stripeRepo := stripe.NewStripeRepository(cfg.Stripe.APIKey, cfg.Stripe.WebhookSecret)
 
 	// Initialize order repository (HTTP client to order service)
-	orderRepo := paymentHttp.NewOrderRepository("http://localhost:8082")
+	internalTokens, err := auth.NewInternalTokenManager(auth.ServicePayment)
+	if err != nil {
+		log.Fatalf("Failed to initialize internal tokens: %v", err)
+	}
+	orderRepo := paymentHttp.NewOrderRepository(cfg.External.OrderServiceURL, internalTokens)
 
 	// Initialize services
 	paymentService := service.NewPaymentService(
@@ -64,18 +70,22 @@ func main() {
 		orderRepo,
 	)
 
-	// Initialize middleware
-	jwtMiddleware := auth.JWTMiddleware(cfg.JWT.AccessSecret)
-	adminMiddleware := func(c *gin.Context) {
-		userRole, exists := c.Get("user_role")
-		if !exists || userRole != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
-			c.Abort()
-			return
-		}
-		c.Next()
+	// Session revocations written by user-service; without Redis, access
+	// tokens are only accepted through the gateway
+	var revocations *auth.SessionRevocations
+	if redisClient, err := cache.NewRedisClient(cache.GetConfigFromEnv()); err != nil {
+		log.Printf("Redis unavailable, access tokens are only accepted through the gateway: %v", err)
+	} else {
+		revocations = auth.NewSessionRevocations(redisClient)
 	}
 
+	// Initialize middleware
+	jwtMiddleware := auth.ServiceAuthMiddleware(
+		internalTokens,
+		auth.NewAccessTokenValidator(cfg.JWT.AccessSecret),
+		revocations,
+	)
+
 	// Initialize handlers
 	paymentHandler := paymentHandlers.NewPaymentHandler(paymentService)
 
@@ -96,7 +106,8 @@ func main() {
 
 	// API routes
 	v1 := router.Group("/api/v1")
-	paymentHandler.RegisterRoutes(v1, jwtMiddleware, adminMiddleware)
+	paymentHandler.RegisterRoutes(v1, jwtMiddleware)
+	privacy.RegisterRoutes(v1, jwtMiddleware, paymentService)
 
 	// Start server
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
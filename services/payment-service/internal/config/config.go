@@ -14,6 +14,7 @@ type Config struct {
 	JWT      JWTConfig
 	Stripe   StripeConfig
 	Redis    RedisConfig
+	External ExternalConfig
 }
 
 type ServerConfig struct {
@@ -42,6 +43,10 @@ type StripeConfig struct {
 	PublishableKey string
 }
 
+type ExternalConfig struct {
+	OrderServiceURL string
+}
+
 type RedisConfig struct {
 	Host     string
 	Port     string
@@ -84,6 +89,9 @@ func Load() *Config {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
+		External: ExternalConfig{
+			OrderServiceURL: getEnv("ORDER_SERVICE_URL", "http://localhost:8084"),
+		},
 	}
 
 	// Validate required configuration
@@ -27,6 +27,9 @@ type PaymentRepository interface {
 	GetPaymentStatistics(ctx context.Context, startDate, endDate time.Time) (*PaymentStatistics, error)
 	GetPaymentMethodStats(ctx context.Context, startDate, endDate time.Time) ([]*PaymentMethodStats, error)
 	GetRevenueMetrics(ctx context.Context, startDate, endDate time.Time) (*RevenueMetrics, error)
+
+	// Personal data erasure
+	AnonymizePaymentsByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 
 type PaymentMethodRepository interface {
@@ -57,6 +60,9 @@ type RefundRepository interface {
 	GetRefundsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.Refund, int64, error)
 	GetRefundsByStatus(ctx context.Context, status entity.RefundStatus, limit, offset int) ([]*entity.Refund, int64, error)
 	GetRefundsByDateRange(ctx context.Context, startDate, endDate time.Time, limit, offset int) ([]*entity.Refund, int64, error)
+
+	// Personal data erasure
+	AnonymizeRefundsByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 
 type WebhookRepository interface {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"solemate/pkg/privacy"
 	"solemate/services/payment-service/internal/domain/entity"
 	"solemate/services/payment-service/internal/domain/repository"
 )
@@ -37,6 +38,10 @@ type PaymentService interface {
 	// Analytics and reporting
 	GetPaymentStatistics(ctx context.Context, startDate, endDate time.Time) (*repository.PaymentStatistics, error)
 	GetRevenueMetrics(ctx context.Context, startDate, endDate time.Time) (*repository.RevenueMetrics, error)
+
+	// Personal data export and erasure (privacy.Provider)
+	ExportUserData(ctx context.Context, userID uuid.UUID) (interface{}, error)
+	EraseUserData(ctx context.Context, userID uuid.UUID) (*privacy.ErasureReport, error)
 }
 
 type paymentService struct {
@@ -607,4 +612,61 @@ func (s *paymentService) mapRefundToResponse(refund *entity.Refund) *RefundRespo
 		UpdatedAt:      refund.UpdatedAt,
 		ProcessedAt:    refund.ProcessedAt,
 	}
-}
\ No newline at end of file
+}
+
+func (s *paymentService) ExportUserData(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	// A negative limit and offset disable pagination
+	payments, _, err := s.paymentRepo.GetPaymentsByUserID(ctx, userID, -1, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payments: %w", err)
+	}
+
+	paymentMethods, err := s.paymentMethodRepo.GetPaymentMethodsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payment methods: %w", err)
+	}
+
+	for _, payment := range payments {
+		payment.ClientSecret = ""
+	}
+
+	return map[string]interface{}{
+		"payments":        payments,
+		"payment_methods": paymentMethods,
+	}, nil
+}
+
+// EraseUserData deletes stored payment methods and anonymises payments and
+// refunds, which are kept for accounting.
+func (s *paymentService) EraseUserData(ctx context.Context, userID uuid.UUID) (*privacy.ErasureReport, error) {
+	report := privacy.NewErasureReport()
+
+	count, err := s.paymentRepo.AnonymizePaymentsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to anonymize payments: %w", err)
+	}
+	report.AddAnonymized("payments", count)
+
+	count, err = s.refundRepo.AnonymizeRefundsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to anonymize refunds: %w", err)
+	}
+	report.AddAnonymized("refunds", count)
+
+	paymentMethods, err := s.paymentMethodRepo.GetPaymentMethodsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payment methods: %w", err)
+	}
+	for _, paymentMethod := range paymentMethods {
+		// The card stays with Stripe if detaching fails; the local copy is removed regardless
+		if _, err := s.stripeRepo.DetachPaymentMethod(ctx, paymentMethod.StripePaymentMethodID); err != nil {
+			fmt.Printf("Warning: failed to detach payment method %s from Stripe: %v\n", paymentMethod.ID, err)
+		}
+		if err := s.paymentMethodRepo.DeletePaymentMethod(ctx, paymentMethod.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete payment method: %w", err)
+		}
+		report.AddDeleted("payment_methods", 1)
+	}
+
+	return report, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"solemate/pkg/authz"
 	"solemate/services/payment-service/internal/domain/service"
 )
 
@@ -20,7 +21,7 @@ func NewPaymentHandler(paymentService service.PaymentService) *PaymentHandler {
 	}
 }
 
-func (h *PaymentHandler) RegisterRoutes(router *gin.RouterGroup, jwtMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
+func (h *PaymentHandler) RegisterRoutes(router *gin.RouterGroup, jwtMiddleware gin.HandlerFunc) {
 	payments := router.Group("/payments")
 	payments.Use(jwtMiddleware)
 	{
@@ -39,13 +40,13 @@ func (h *PaymentHandler) RegisterRoutes(router *gin.RouterGroup, jwtMiddleware g
 		payments.DELETE("/methods/:id", h.DeletePaymentMethod)
 
 		// Refund operations
-		payments.POST("/:id/refunds", h.CreateRefund)
+		payments.POST("/:id/refunds", authz.RequirePermission(authz.RefundsCreate), h.CreateRefund)
 		payments.GET("/:id/refunds", h.GetRefundsByPaymentID)
 		payments.GET("/refunds/:id", h.GetRefund)
 
 		// Analytics (admin only)
 		admin := payments.Group("/analytics")
-		admin.Use(adminMiddleware)
+		admin.Use(authz.RequirePermission(authz.AnalyticsRead))
 		{
 			admin.GET("/statistics", h.GetPaymentStatistics)
 			admin.GET("/revenue", h.GetRevenueMetrics)
@@ -96,8 +97,7 @@ func (h *PaymentHandler) GetPayment(c *gin.Context) {
 
 	// Check if user owns this payment or is admin
 	userID := c.GetString("user_id")
-	userRole := c.GetString("user_role")
-	if userRole != "admin" && payment.UserID.String() != userID {
+	if !authz.Allowed(c, authz.PaymentsRead) && payment.UserID.String() != userID {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
@@ -159,8 +159,7 @@ func (h *PaymentHandler) GetPaymentByOrderID(c *gin.Context) {
 
 	// Check if user owns this payment or is admin
 	userID := c.GetString("user_id")
-	userRole := c.GetString("user_role")
-	if userRole != "admin" && payment.UserID.String() != userID {
+	if !authz.Allowed(c, authz.PaymentsRead) && payment.UserID.String() != userID {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
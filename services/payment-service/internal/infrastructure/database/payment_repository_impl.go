@@ -338,4 +338,17 @@ func (r *paymentRepositoryImpl) GetRevenueMetrics(ctx context.Context, startDate
 	}
 
 	return &metrics, nil
-}
\ No newline at end of file
+}
+
+// AnonymizePaymentsByUserID clears free-text fields and the link to stored
+// payment methods. Amounts and Stripe references are kept for accounting.
+func (r *paymentRepositoryImpl) AnonymizePaymentsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Payment{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"payment_method_id": nil,
+			"client_secret":     "",
+			"description":       "",
+		})
+	return result.RowsAffected, result.Error
+}
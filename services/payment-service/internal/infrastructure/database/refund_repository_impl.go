@@ -122,4 +122,12 @@ func (r *refundRepositoryImpl) GetRefundsByDateRange(ctx context.Context, startD
 		Find(&refunds).Error
 
 	return refunds, count, err
-}
\ No newline at end of file
+}
+
+// AnonymizeRefundsByUserID clears free-text fields on the user's refunds.
+func (r *refundRepositoryImpl) AnonymizeRefundsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Refund{}).
+		Where("user_id = ?", userID).
+		Update("description", "")
+	return result.RowsAffected, result.Error
+}
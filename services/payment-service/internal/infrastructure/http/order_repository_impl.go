@@ -9,17 +9,20 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"solemate/pkg/auth"
 	"solemate/services/payment-service/internal/domain/repository"
 )
 
 type orderRepositoryImpl struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL        string
+	httpClient     *http.Client
+	internalTokens *auth.InternalTokenManager
 }
 
-func NewOrderRepository(baseURL string) repository.OrderRepository {
+func NewOrderRepository(baseURL string, internalTokens *auth.InternalTokenManager) repository.OrderRepository {
 	return &orderRepositoryImpl{
-		baseURL: baseURL,
+		baseURL:        baseURL,
+		internalTokens: internalTokens,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -34,6 +37,10 @@ func (r *orderRepositoryImpl) GetOrderByID(ctx context.Context, orderID uuid.UUI
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	if err := r.authorize(req); err != nil {
+		return nil, err
+	}
+
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
@@ -60,7 +67,7 @@ func (r *orderRepositoryImpl) GetOrderByID(ctx context.Context, orderID uuid.UUI
 }
 
 func (r *orderRepositoryImpl) UpdateOrderPaymentStatus(ctx context.Context, orderID uuid.UUID, status string, transactionID string) error {
-	url := fmt.Sprintf("%s/api/v1/orders/%s/payment-status", r.baseURL, orderID.String())
+	url := fmt.Sprintf("%s/api/v1/orders/admin/%s/payment-status", r.baseURL, orderID.String())
 
 	payload := map[string]interface{}{
 		"payment_status":  status,
@@ -78,6 +85,9 @@ func (r *orderRepositoryImpl) UpdateOrderPaymentStatus(ctx context.Context, orde
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if err := r.authorize(req); err != nil {
+		return err
+	}
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
@@ -90,4 +100,14 @@ func (r *orderRepositoryImpl) UpdateOrderPaymentStatus(ctx context.Context, orde
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// authorize attaches a token identifying payment-service to order-service.
+func (r *orderRepositoryImpl) authorize(req *http.Request) error {
+	token, err := r.internalTokens.MintForService(auth.ServiceOrder)
+	if err != nil {
+		return fmt.Errorf("failed to mint internal token: %w", err)
+	}
+	req.Header.Set(auth.InternalTokenHeader, token)
+	return nil
+}
@@ -0,0 +1,41 @@
+// Command backfill-ratings recomputes every product's rating average, count
+// and histogram from its published reviews. Run it once after migrating to
+// 013_add_product_ratings, or whenever the aggregates are suspected stale.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/joho/godotenv"
+	"solemate/pkg/database"
+	"solemate/services/product-service/internal/config"
+	dbImpl "solemate/services/product-service/internal/infrastructure/database"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	cfg := config.Load()
+
+	db, err := database.NewPostgresConnection(database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.DBName,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	count, err := dbImpl.NewReviewRepository(db).RefreshProductRatings(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to backfill product ratings: %v", err)
+	}
+
+	log.Printf("Backfilled ratings for %d products", count)
+}
@@ -1,17 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/joho/godotenv"
 	"solemate/pkg/auth"
+	"solemate/pkg/cache"
 	"solemate/pkg/database"
+	"solemate/pkg/productalerts"
 	"solemate/services/product-service/internal/config"
 	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
 	"solemate/services/product-service/internal/domain/service"
 	httpHandler "solemate/services/product-service/internal/handler/http"
+	cacheImpl "solemate/services/product-service/internal/infrastructure/cache"
 	dbImpl "solemate/services/product-service/internal/infrastructure/database"
+	httpImpl "solemate/services/product-service/internal/infrastructure/http"
+	"solemate/services/product-service/internal/infrastructure/storage"
 )
 
 func main() {
@@ -46,6 +53,17 @@ func main() {
 		&entity.ProductVariant{},
 		&entity.ProductImage{},
 		&entity.Review{},
+		&entity.ReviewVote{},
+		&entity.ReviewReport{},
+		&entity.SearchQuery{},
+		&entity.ProductImportJob{},
+		&entity.PriceSchedule{},
+		&entity.PriceHistory{},
+		&entity.AttributeDefinition{},
+		&entity.ProductAttributeValue{},
+		&entity.ProductAffinity{},
+		&entity.ProductViewStat{},
+		&entity.ProductCoView{},
 	); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
@@ -55,24 +73,114 @@ func main() {
 	categoryRepo := dbImpl.NewCategoryRepository(db)
 	brandRepo := dbImpl.NewBrandRepository(db)
 	reviewRepo := dbImpl.NewReviewRepository(db)
+	variantRepo := dbImpl.NewProductVariantRepository(db)
+	imageRepo := dbImpl.NewProductImageRepository(db)
+	searchQueryRepo := dbImpl.NewSearchQueryRepository(db)
+	importRepo := dbImpl.NewProductImportRepository(db)
+	priceScheduleRepo := dbImpl.NewPriceScheduleRepository(db)
+	priceHistoryRepo := dbImpl.NewPriceHistoryRepository(db)
+	attributeRepo := dbImpl.NewAttributeRepository(db)
+	recommendationRepo := dbImpl.NewRecommendationRepository(db)
+	productViewRepo := dbImpl.NewProductViewRepository(db)
 
-	// Initialize JWT manager
+	// Cache search suggestions and recommendations, track product views and
+	// check session revocations in Redis. Without Redis every request goes to
+	// the database, views are not tracked and access tokens are only accepted
+	// through the gateway.
+	var suggestionCache repository.SuggestionCache
+	var recommendationCache repository.RecommendationCache
+	var viewTracker repository.ViewTracker
+	var revocations *auth.SessionRevocations
+	if redisClient, err := cache.NewRedisClient(cache.GetConfigFromEnv()); err != nil {
+		log.Printf("Redis unavailable, search suggestions and recommendations will not be cached and views will not be tracked: %v", err)
+	} else {
+		suggestionCache = cacheImpl.NewSuggestionCache(redisClient)
+		recommendationCache = cacheImpl.NewRecommendationCache(redisClient)
+		viewTracker = cacheImpl.NewViewTracker(redisClient)
+		revocations = auth.NewSessionRevocations(redisClient)
+	}
+
+	// Initialize image storage
+	var imageStorage repository.ImageStorage
+	switch cfg.Storage.Backend {
+	case "s3":
+		imageStorage = storage.NewS3Storage(storage.S3Config{
+			Endpoint:  cfg.Storage.S3.Endpoint,
+			Region:    cfg.Storage.S3.Region,
+			Bucket:    cfg.Storage.S3.Bucket,
+			AccessKey: cfg.Storage.S3.AccessKey,
+			SecretKey: cfg.Storage.S3.SecretKey,
+			PublicURL: cfg.Storage.S3.PublicURL,
+		})
+	case "local":
+		imageStorage = storage.NewLocalStorage(cfg.Storage.LocalDir, cfg.Storage.PublicURL)
+	default:
+		log.Fatalf("Unknown storage backend %q", cfg.Storage.Backend)
+	}
+	imageUploader := service.NewImageUploader(imageStorage)
+
+	// Initialize JWT manager and internal token verifier
 	jwtManager := auth.NewJWTManager()
+	internalTokens, err := auth.NewInternalTokenManager(auth.ServiceProduct)
+	if err != nil {
+		log.Fatalf("Failed to initialize internal tokens: %v", err)
+	}
 
 	// Initialize services
-	productService := service.NewProductService(productRepo, categoryRepo, brandRepo, nil, nil)
-	categoryService := service.NewCategoryService(categoryRepo)
+	// Price-drop and restock alerts are delivered to user-service in the
+	// background, so product updates and imports don't wait on it
+	productAlerts := productalerts.NewDispatcher(productalerts.NewClient(cfg.External.UserServiceURL, internalTokens), 1000)
+	go productAlerts.Run(context.Background())
+	productService := service.NewProductService(productRepo, categoryRepo, brandRepo, variantRepo, imageRepo, productAlerts, imageUploader, searchQueryRepo, suggestionCache, importRepo, priceScheduleRepo, priceHistoryRepo, attributeRepo)
+	categoryService := service.NewCategoryService(categoryRepo, attributeRepo)
 	brandService := service.NewBrandService(brandRepo)
-	reviewService := service.NewReviewService(reviewRepo, productRepo)
+	reviewScreener := service.NewReviewScreener(cfg.Reviews.ModerationMode, cfg.Reviews.BannedWords, cfg.Reviews.ReportThreshold)
+	notificationRepo := httpImpl.NewNotificationRepository(cfg.External.NotificationServiceURL, internalTokens)
+	orderRepo := httpImpl.NewOrderRepository(cfg.External.OrderServiceURL, internalTokens)
+	reviewService := service.NewReviewService(reviewRepo, productRepo, imageUploader, reviewScreener, notificationRepo, orderRepo)
+	recommendationService := service.NewRecommendationService(productRepo, recommendationRepo, orderRepo, recommendationCache, service.RecommendationSettings{
+		Window:     cfg.Recommendations.Window,
+		MinOrders:  cfg.Recommendations.MinOrders,
+		MinCoViews: cfg.Recommendations.MinCoViews,
+	})
+	productViewService := service.NewProductViewService(productRepo, categoryRepo, productViewRepo, viewTracker)
+	wishlistRepo := httpImpl.NewWishlistRepository(cfg.External.UserServiceURL, internalTokens)
+	popularityService := service.NewPopularityService(productRepo, orderRepo, wishlistRepo)
+
+	// Restart or fail the catalog imports that were running at shutdown
+	if err := productService.ResumeImports(context.Background()); err != nil {
+		log.Printf("Failed to resume catalog imports: %v", err)
+	}
+
+	// Apply and revert scheduled prices in the background
+	go productService.RunPriceScheduler(context.Background(), cfg.Pricing.SchedulerInterval)
+
+	// Recompute frequently-bought-together affinities in the background
+	go recommendationService.RunRecommendationJob(context.Background(), cfg.Recommendations.RefreshInterval)
+
+	// Copy daily view counts from Redis to Postgres in the background
+	if viewTracker != nil {
+		go productViewService.RunViewCompaction(context.Background(), cfg.Views.CompactionInterval)
+	}
+
+	// Recompute popularity scores and units sold in the background
+	go popularityService.RunPopularityRefresh(context.Background(), cfg.Popularity.RefreshInterval)
 
 	// Initialize handlers
 	productHandler := httpHandler.NewProductHandler(productService)
 	categoryHandler := httpHandler.NewCategoryHandler(categoryService)
 	brandHandler := httpHandler.NewBrandHandler(brandService)
 	reviewHandler := httpHandler.NewReviewHandler(reviewService)
+	recommendationHandler := httpHandler.NewRecommendationHandler(recommendationService)
+	productViewHandler := httpHandler.NewProductViewHandler(productViewService)
 
 	// Setup routes
-	router := httpHandler.SetupRoutes(productHandler, categoryHandler, brandHandler, reviewHandler, jwtManager)
+	router := httpHandler.SetupRoutes(productHandler, categoryHandler, brandHandler, reviewHandler, recommendationHandler, productViewHandler, reviewService, jwtManager, internalTokens, revocations)
+
+	// Serve locally stored uploads
+	if cfg.Storage.Backend == "local" {
+		router.Static(storage.LocalMediaPath, cfg.Storage.LocalDir)
+	}
 
 	// Start server
 	serverAddr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
@@ -3,12 +3,21 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Server        ServerConfig
-	Database      DatabaseConfig
-	Elasticsearch ElasticsearchConfig
+	Server          ServerConfig
+	Database        DatabaseConfig
+	Elasticsearch   ElasticsearchConfig
+	External        ExternalConfig
+	Storage         StorageConfig
+	Pricing         PricingConfig
+	Reviews         ReviewConfig
+	Recommendations RecommendationConfig
+	Views           ViewConfig
+	Popularity      PopularityConfig
 }
 
 type ServerConfig struct {
@@ -33,6 +42,70 @@ type ElasticsearchConfig struct {
 	Index    string
 }
 
+type ExternalConfig struct {
+	UserServiceURL         string
+	NotificationServiceURL string
+	OrderServiceURL        string
+}
+
+// StorageConfig selects where uploaded images are kept: "local" writes them
+// to LocalDir and serves them from PublicURL, "s3" puts them in an
+// S3-compatible bucket
+type StorageConfig struct {
+	Backend   string
+	LocalDir  string
+	PublicURL string
+	S3        S3StorageConfig
+}
+
+type S3StorageConfig struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+}
+
+// PricingConfig controls how often due price schedules are applied and reverted
+type PricingConfig struct {
+	SchedulerInterval time.Duration
+}
+
+// ReviewConfig controls review moderation. In "auto" mode reviews are
+// published unless screening flags them; in "manual" mode every review waits
+// for a moderator. BannedWords are matched as whole words, case-insensitively.
+// A published review goes back to the queue once ReportThreshold shoppers
+// report it.
+type ReviewConfig struct {
+	ModerationMode  string
+	BannedWords     []string
+	ReportThreshold int
+}
+
+// RecommendationConfig controls the job that recomputes product affinities:
+// every RefreshInterval it counts products bought or viewed together within
+// Window, ignoring pairs with fewer than MinOrders orders or MinCoViews views
+// in common
+type RecommendationConfig struct {
+	RefreshInterval time.Duration
+	Window          time.Duration
+	MinOrders       int
+	MinCoViews      int
+}
+
+// ViewConfig controls how often the view counts kept in Redis are copied to
+// Postgres
+type ViewConfig struct {
+	CompactionInterval time.Duration
+}
+
+// PopularityConfig controls how often the products' popularity scores and
+// units sold are recomputed
+type PopularityConfig struct {
+	RefreshInterval time.Duration
+}
+
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
@@ -54,6 +127,44 @@ func Load() *Config {
 			Password: getEnv("ELASTICSEARCH_PASSWORD", ""),
 			Index:    getEnv("ELASTICSEARCH_INDEX", "products"),
 		},
+		External: ExternalConfig{
+			UserServiceURL:         getEnv("USER_SERVICE_URL", "http://localhost:8080"),
+			NotificationServiceURL: getEnv("NOTIFICATION_SERVICE_URL", "http://localhost:8086"),
+			OrderServiceURL:        getEnv("ORDER_SERVICE_URL", "http://localhost:8084"),
+		},
+		Storage: StorageConfig{
+			Backend:   getEnv("STORAGE_BACKEND", "local"),
+			LocalDir:  getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+			PublicURL: getEnv("STORAGE_PUBLIC_URL", "http://localhost:8081/media"),
+			S3: S3StorageConfig{
+				Endpoint:  getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+				Region:    getEnv("S3_REGION", "us-east-1"),
+				Bucket:    getEnv("S3_BUCKET", ""),
+				AccessKey: getEnv("S3_ACCESS_KEY_ID", ""),
+				SecretKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+				PublicURL: getEnv("S3_PUBLIC_URL", ""),
+			},
+		},
+		Pricing: PricingConfig{
+			SchedulerInterval: time.Duration(getEnvAsInt("PRICE_SCHEDULER_INTERVAL_SECONDS", 60)) * time.Second,
+		},
+		Reviews: ReviewConfig{
+			ModerationMode:  getEnv("REVIEW_MODERATION_MODE", "auto"),
+			BannedWords:     getEnvAsList("REVIEW_BANNED_WORDS"),
+			ReportThreshold: getEnvAsInt("REVIEW_REPORT_THRESHOLD", 3),
+		},
+		Recommendations: RecommendationConfig{
+			RefreshInterval: time.Duration(getEnvAsInt("RECOMMENDATION_REFRESH_MINUTES", 60)) * time.Minute,
+			Window:          time.Duration(getEnvAsInt("RECOMMENDATION_WINDOW_DAYS", 180)) * 24 * time.Hour,
+			MinOrders:       getEnvAsInt("RECOMMENDATION_MIN_ORDERS", 2),
+			MinCoViews:      getEnvAsInt("RECOMMENDATION_MIN_CO_VIEWS", 3),
+		},
+		Views: ViewConfig{
+			CompactionInterval: time.Duration(getEnvAsInt("VIEW_COMPACTION_INTERVAL_MINUTES", 15)) * time.Minute,
+		},
+		Popularity: PopularityConfig{
+			RefreshInterval: time.Duration(getEnvAsInt("POPULARITY_REFRESH_MINUTES", 60)) * time.Minute,
+		},
 	}
 }
 
@@ -71,4 +182,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 		}
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getEnvAsList reads a comma-separated list, dropping empty entries
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
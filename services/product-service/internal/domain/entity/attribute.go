@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	AttributeTypeEnum    = "enum"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+)
+
+// AttributeDefinition is a typed product spec, such as width or heel-to-toe
+// drop, for the products of a category and its descendants. A category can
+// redefine a code it inherits.
+type AttributeDefinition struct {
+	ID            uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CategoryID    uuid.UUID      `json:"category_id" gorm:"type:uuid;not null;uniqueIndex:idx_attribute_definitions_category_code"`
+	Code          string         `json:"code" gorm:"size:50;not null;uniqueIndex:idx_attribute_definitions_category_code;index"`
+	Name          string         `json:"name" gorm:"not null"`
+	Type          string         `json:"type" gorm:"size:20;not null"`
+	Unit          string         `json:"unit,omitempty" gorm:"size:20"`               // e.g. mm for drop
+	AllowedValues pq.StringArray `json:"allowed_values,omitempty" gorm:"type:text[]"` // enum only
+	Filterable    bool           `json:"filterable" gorm:"default:true"`
+	SortOrder     int            `json:"sort_order" gorm:"default:0"`
+	CreatedAt     time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// ProductAttributeValue is a product's value for an attribute, or a
+// variant's when VariantID is set. Value is the canonical text form (the
+// allowed enum value, the number, or true/false); numbers are also kept in
+// NumberValue so they can be compared.
+type ProductAttributeValue struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProductID   uuid.UUID  `json:"product_id" gorm:"type:uuid;not null;index"`
+	VariantID   *uuid.UUID `json:"variant_id,omitempty" gorm:"type:uuid;index"`
+	AttributeID uuid.UUID  `json:"attribute_id" gorm:"type:uuid;not null;index"`
+	Value       string     `json:"value" gorm:"not null"`
+	NumberValue *float64   `json:"-" gorm:"type:numeric"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relationships
+	Attribute *AttributeDefinition `json:"attribute,omitempty" gorm:"foreignKey:AttributeID"`
+}
+
+func (AttributeDefinition) TableName() string {
+	return "attribute_definitions"
+}
+
+func (ProductAttributeValue) TableName() string {
+	return "product_attribute_values"
+}
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PriceScheduleScheduled = "scheduled"
+	PriceScheduleActive    = "active"
+	PriceScheduleEnded     = "ended"
+	PriceScheduleCancelled = "cancelled"
+)
+
+const (
+	PriceSourceManual   = "manual"
+	PriceSourceSchedule = "schedule"
+	PriceSourceImport   = "import"
+	PriceSourceBackfill = "backfill" // prices that predate the history
+)
+
+// PriceSchedule sets a product's or variant's price for a time window. When
+// it starts the current prices are kept in PreviousPrice and
+// PreviousComparePrice, and they are restored when it ends. A schedule
+// without an end is a planned permanent price change.
+type PriceSchedule struct {
+	ID                   uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProductID            uuid.UUID  `json:"product_id" gorm:"type:uuid;not null;index"`
+	VariantID            *uuid.UUID `json:"variant_id,omitempty" gorm:"type:uuid;index"`
+	Price                float64    `json:"price" gorm:"type:decimal(10,2);not null"`
+	ComparePrice         *float64   `json:"compare_price" gorm:"type:decimal(10,2)"`
+	StartsAt             time.Time  `json:"starts_at" gorm:"not null;index"`
+	EndsAt               *time.Time `json:"ends_at,omitempty" gorm:"index"`
+	Status               string     `json:"status" gorm:"size:20;not null;default:scheduled;index"`
+	PreviousPrice        *float64   `json:"previous_price,omitempty" gorm:"type:decimal(10,2)"`
+	PreviousComparePrice *float64   `json:"previous_compare_price,omitempty" gorm:"type:decimal(10,2)"`
+	AppliedAt            *time.Time `json:"applied_at,omitempty"`
+	EndedAt              *time.Time `json:"ended_at,omitempty"`
+	CreatedAt            time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt            time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// PriceHistory records every price a product or variant has had. Rows are
+// only ever inserted (see migrations/009_add_price_schedules). A variant's
+// Price is nil while it uses the product's price.
+type PriceHistory struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProductID    uuid.UUID  `json:"product_id" gorm:"type:uuid;not null;index:idx_price_history_target"`
+	VariantID    *uuid.UUID `json:"variant_id,omitempty" gorm:"type:uuid;index:idx_price_history_target"`
+	Price        *float64   `json:"price" gorm:"type:decimal(10,2)"`
+	ComparePrice *float64   `json:"compare_price" gorm:"type:decimal(10,2)"`
+	Source       string     `json:"source" gorm:"size:20;not null"`
+	ScheduleID   *uuid.UUID `json:"schedule_id,omitempty" gorm:"type:uuid"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime;index:idx_price_history_target"`
+}
+
+func (PriceSchedule) TableName() string {
+	return "price_schedules"
+}
+
+func (PriceHistory) TableName() string {
+	return "price_history"
+}
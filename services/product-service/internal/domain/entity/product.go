@@ -24,6 +24,12 @@ type Product struct {
 	MetaTitle       string         `json:"meta_title"`
 	MetaDescription string         `json:"meta_description" gorm:"type:text"`
 	StockQuantity   int            `json:"stock_quantity" gorm:"-"` // Add this line, gorm:"-" to ignore this field in DB operations
+	RatingAverage   float64        `json:"rating_average" gorm:"type:decimal(3,2);not null;default:0"`
+	RatingCount     int            `json:"rating_count" gorm:"not null;default:0"`
+	RatingHistogram pq.Int64Array  `json:"rating_histogram" gorm:"type:integer[];not null;default:'{0,0,0,0,0}'"` // counts of 1 to 5 star ratings
+	RecentViews     int            `json:"recent_views" gorm:"not null;default:0"`                                // views in the last 30 days
+	UnitsSold       int            `json:"units_sold" gorm:"not null;default:0"`                                  // units sold in the last 30 days
+	PopularityScore float64        `json:"popularity_score" gorm:"not null;default:0"`
 	CreatedAt       time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 
@@ -32,6 +38,9 @@ type Product struct {
 	Brand    *Brand           `json:"brand,omitempty" gorm:"foreignKey:BrandID"`
 	Variants []ProductVariant `json:"variants,omitempty" gorm:"foreignKey:ProductID"`
 	Images   []ProductImage   `json:"images,omitempty" gorm:"foreignKey:ProductID"`
+
+	// Product-level attribute values, set on single product responses
+	Attributes []ProductAttributeValue `json:"attributes,omitempty" gorm:"-"`
 }
 
 type Category struct {
@@ -49,6 +58,16 @@ type Category struct {
 	Parent     *Category  `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
 	Children   []Category `json:"children,omitempty" gorm:"foreignKey:ParentID"`
 	Products   []Product  `json:"products,omitempty" gorm:"foreignKey:CategoryID"`
+
+	// Path from the top-level category down to this one, set on single
+	// category and product responses
+	Breadcrumbs []CategoryBreadcrumb `json:"breadcrumbs,omitempty" gorm:"-"`
+}
+
+type CategoryBreadcrumb struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Slug string    `json:"slug"`
 }
 
 type Brand struct {
@@ -75,22 +94,35 @@ type ProductVariant struct {
 	Stock     int            `json:"stock" gorm:"default:0"`
 	Weight    *float64       `json:"weight" gorm:"type:decimal(10,3)"`
 	Images    pq.StringArray `json:"images" gorm:"type:text[]"`
+	SortOrder int            `json:"sort_order" gorm:"default:0"`
 	IsActive  bool           `json:"is_active" gorm:"default:true"`
 	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// Relationships
 	Product *Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+
+	// Variant-level attribute values, set on single product responses
+	Attributes []ProductAttributeValue `json:"attributes,omitempty" gorm:"-"`
 }
 
 type ProductImage struct {
 	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	ProductID uuid.UUID `json:"product_id" gorm:"type:uuid;not null"`
-	URL       string    `json:"url" gorm:"not null"`
+	URL       string    `json:"url" gorm:"not null"` // full size; the zoom rendition for uploaded images
 	AltText   string    `json:"alt_text"`
 	SortOrder int       `json:"sort_order" gorm:"default:0"`
 	IsPrimary bool      `json:"is_primary" gorm:"default:false"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 
+	// Smaller renditions and the WebP encodings of all three, set for
+	// uploaded images only
+	ThumbnailURL     string `json:"thumbnail_url,omitempty"`
+	CardURL          string `json:"card_url,omitempty"`
+	WebPURL          string `json:"webp_url,omitempty"`
+	ThumbnailWebPURL string `json:"thumbnail_webp_url,omitempty"`
+	CardWebPURL      string `json:"card_webp_url,omitempty"`
+
 	// Relationships
 	Product *Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
 }
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CatalogFormatCSV  = "csv"
+	CatalogFormatJSON = "json"
+)
+
+const (
+	ImportStatusPending    = "pending"
+	ImportStatusValidating = "validating"
+	ImportStatusValidated  = "validated" // dry run finished without errors
+	ImportStatusImporting  = "importing"
+	ImportStatusCompleted  = "completed"
+	ImportStatusFailed     = "failed"
+)
+
+// ProductImportJob is a bulk catalog import. Every job validates the whole
+// file first; a dry run stops there so the report can be reviewed, otherwise
+// products are upserted by SKU and the counters report progress.
+type ProductImportJob struct {
+	ID          uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	RequestedBy uuid.UUID        `json:"requested_by" gorm:"type:uuid;not null"`
+	Format      string           `json:"format" gorm:"size:10;not null"`
+	DryRun      bool             `json:"dry_run"`
+	Status      string           `json:"status" gorm:"size:20;not null;default:pending;index"`
+	Total       int              `json:"total"`     // products in the file
+	Processed   int              `json:"processed"` // products imported so far, including failures
+	Created     int              `json:"created"`   // new SKUs; planned creations while validating
+	Updated     int              `json:"updated"`   // existing SKUs; planned updates while validating
+	Failed      int              `json:"failed"`
+	Errors      []ImportRowError `json:"errors,omitempty" gorm:"type:jsonb;serializer:json"`
+	Error       string           `json:"error,omitempty" gorm:"type:text"`
+	Data        []byte           `json:"-" gorm:"type:bytea"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+}
+
+// ImportRowError is a problem with one product in an import file. Row is the
+// CSV line number, or the product's position in a JSON array, counting from 1.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	SKU     string `json:"sku,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (ProductImportJob) TableName() string {
+	return "product_import_jobs"
+}
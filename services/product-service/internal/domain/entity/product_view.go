@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductViewStat is a product's view count for one day. Views are counted
+// in Redis as they happen and copied here by the view compaction job.
+type ProductViewStat struct {
+	ProductID uuid.UUID `json:"product_id" gorm:"type:uuid;primaryKey"`
+	Date      time.Time `json:"date" gorm:"type:date;primaryKey"`
+	Views     int       `json:"views" gorm:"not null"`
+}
+
+func (ProductViewStat) TableName() string {
+	return "product_view_stats"
+}
+
+// ProductCoView counts, for one day, the views of RelatedProductID made in
+// the same browsing session as a view of ProductID
+type ProductCoView struct {
+	ProductID        uuid.UUID `json:"product_id" gorm:"type:uuid;primaryKey"`
+	RelatedProductID uuid.UUID `json:"related_product_id" gorm:"type:uuid;primaryKey"`
+	Date             time.Time `json:"date" gorm:"type:date;primaryKey"`
+	Views            int       `json:"views" gorm:"not null"`
+}
+
+func (ProductCoView) TableName() string {
+	return "product_co_views"
+}
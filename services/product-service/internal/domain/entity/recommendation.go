@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Where a product affinity was learned from
+const (
+	AffinitySourcePurchase = "purchase" // bought in the same orders
+	AffinitySourceView     = "view"     // viewed in the same sessions
+)
+
+// ProductAffinity says how strongly shoppers interested in a product are
+// also interested in a related one. Score is the share of the product's
+// orders or sessions that included the related product, and Support the
+// number of them. Rows are recomputed wholesale by the recommendation job.
+type ProductAffinity struct {
+	ProductID        uuid.UUID `json:"product_id" gorm:"type:uuid;primaryKey"`
+	Source           string    `json:"source" gorm:"size:20;primaryKey"`
+	RelatedProductID uuid.UUID `json:"related_product_id" gorm:"type:uuid;primaryKey"`
+	Score            float64   `json:"score" gorm:"not null"`
+	Support          int       `json:"support" gorm:"not null"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (ProductAffinity) TableName() string {
+	return "product_affinities"
+}
@@ -7,25 +7,86 @@ import (
 	"github.com/lib/pq"
 )
 
+const (
+	ReviewStatusPending  = "PENDING"
+	ReviewStatusApproved = "APPROVED"
+	ReviewStatusRejected = "REJECTED"
+)
+
+// Reasons automated screening holds a review for a moderator
+const (
+	ReviewFlagBannedWords = "banned_words"
+	ReviewFlagLinks       = "links"
+	ReviewFlagDuplicate   = "duplicate"
+	ReviewFlagReported    = "reported" // shoppers reported it past the threshold
+)
+
+// Reasons a shopper can give when reporting a review
+const (
+	ReviewReportSpam      = "spam"
+	ReviewReportOffensive = "offensive"
+	ReviewReportOffTopic  = "off_topic"
+	ReviewReportFake      = "fake"
+	ReviewReportOther     = "other"
+)
+
 type Review struct {
-	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	ProductID    uuid.UUID      `json:"product_id" gorm:"type:uuid;not null"`
-	UserID       uuid.UUID      `json:"user_id" gorm:"type:uuid;not null"`
-	OrderID      *uuid.UUID     `json:"order_id" gorm:"type:uuid"`
-	Rating       int            `json:"rating" gorm:"not null;check:rating >= 1 AND rating <= 5"`
-	Title        string         `json:"title" gorm:"size:255"`
-	Comment      string         `json:"comment" gorm:"type:text"`
-	Images       pq.StringArray `json:"images" gorm:"type:text[]"`
-	IsVerified   bool           `json:"is_verified" gorm:"default:false"`
-	HelpfulCount int            `json:"helpful_count" gorm:"default:0"`
-	Status       string         `json:"status" gorm:"size:20;default:PENDING;check:status IN ('PENDING', 'APPROVED', 'REJECTED')"`
-	CreatedAt    time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt    time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProductID       uuid.UUID      `json:"product_id" gorm:"type:uuid;not null"`
+	UserID          uuid.UUID      `json:"user_id" gorm:"type:uuid;not null"`
+	OrderID         *uuid.UUID     `json:"order_id" gorm:"type:uuid"`
+	Rating          int            `json:"rating" gorm:"not null;check:rating >= 1 AND rating <= 5"`
+	Title           string         `json:"title" gorm:"size:255"`
+	Comment         string         `json:"comment" gorm:"type:text"`
+	Images          pq.StringArray `json:"images" gorm:"type:text[]"`
+	IsVerified      bool           `json:"is_verified" gorm:"default:false"`
+	HelpfulCount    int            `json:"helpful_count" gorm:"default:0"`
+	NotHelpfulCount int            `json:"not_helpful_count" gorm:"default:0"`
+	Status          string         `json:"status" gorm:"size:20;default:PENDING;check:status IN ('PENDING', 'APPROVED', 'REJECTED')"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Moderation
+	ScreeningFlags   pq.StringArray `json:"screening_flags,omitempty" gorm:"type:text[]"`
+	ContentHash      string         `json:"-" gorm:"size:64;index"` // of the normalised comment, to spot copies
+	ModerationReason string         `json:"moderation_reason,omitempty" gorm:"type:text"`
+	ModeratedBy      *uuid.UUID     `json:"moderated_by,omitempty" gorm:"type:uuid"`
+	ModeratedAt      *time.Time     `json:"moderated_at,omitempty"`
 
 	// Relationships
 	Product *Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
 }
 
+// ReviewVote is a shopper's helpful or not-helpful vote on a review. Each
+// shopper has at most one vote per review.
+type ReviewVote struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ReviewID  uuid.UUID `json:"review_id" gorm:"type:uuid;not null;uniqueIndex:idx_review_votes_review_user"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_review_votes_review_user;index"`
+	Helpful   bool      `json:"helpful" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// ReviewReport is a shopper's report that a review breaks the guidelines.
+// Each shopper can report a review once.
+type ReviewReport struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ReviewID  uuid.UUID `json:"review_id" gorm:"type:uuid;not null;uniqueIndex:idx_review_reports_review_user"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_review_reports_review_user;index"`
+	Reason    string    `json:"reason" gorm:"size:20;not null"`
+	Details   string    `json:"details,omitempty" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
 func (Review) TableName() string {
 	return "reviews"
 }
+
+func (ReviewVote) TableName() string {
+	return "review_votes"
+}
+
+func (ReviewReport) TableName() string {
+	return "review_reports"
+}
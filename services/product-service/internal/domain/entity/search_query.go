@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchQuery logs one product search so merchandisers can see what shoppers
+// look for, and which searches find nothing
+type SearchQuery struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Query       string    `json:"query" gorm:"size:255;not null;index"` // lower-cased, whitespace collapsed
+	ResultCount int64     `json:"result_count" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+func (SearchQuery) TableName() string {
+	return "search_queries"
+}
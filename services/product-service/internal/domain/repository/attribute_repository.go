@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+type AttributeRepository interface {
+	CreateDefinition(ctx context.Context, definition *entity.AttributeDefinition) error
+	GetDefinition(ctx context.Context, id uuid.UUID) (*entity.AttributeDefinition, error)
+	UpdateDefinition(ctx context.Context, definition *entity.AttributeDefinition) error
+
+	// DeleteDefinition deletes a definition and every value set for it
+	DeleteDefinition(ctx context.Context, id uuid.UUID) error
+
+	// GetDefinitionsByCategoryIDs returns the definitions of the given
+	// categories in display order
+	GetDefinitionsByCategoryIDs(ctx context.Context, categoryIDs []uuid.UUID) ([]*entity.AttributeDefinition, error)
+
+	// GetValues returns all of a product's values, its variants' included,
+	// with their definitions
+	GetValues(ctx context.Context, productID uuid.UUID) ([]*entity.ProductAttributeValue, error)
+
+	// ReplaceValues replaces the values of a product, or of one of its
+	// variants when variantID is set
+	ReplaceValues(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, values []*entity.ProductAttributeValue) error
+}
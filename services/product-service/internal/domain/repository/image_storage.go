@@ -0,0 +1,14 @@
+package repository
+
+import "context"
+
+// ImageStorage holds uploaded image files and serves them from public URLs
+type ImageStorage interface {
+	// Put stores data under key and returns the URL it is served from
+	Put(ctx context.Context, key string, data []byte, contentType string) (string, error)
+	// Delete removes the file stored under key. Missing files are not an error.
+	Delete(ctx context.Context, key string) error
+	// KeyForURL returns the key of a URL returned by Put, or false for URLs
+	// this storage doesn't own (e.g. images hosted elsewhere)
+	KeyForURL(url string) (string, bool)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+type ProductImportRepository interface {
+	// Create stores a new import job along with its uploaded file
+	Create(ctx context.Context, job *entity.ProductImportJob) error
+
+	// GetByID retrieves an import job including its uploaded file
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.ProductImportJob, error)
+
+	// Update saves status, progress and report changes
+	Update(ctx context.Context, job *entity.ProductImportJob) error
+
+	// ListByStatus retrieves the jobs in any of the given statuses, oldest
+	// first, including their uploaded files
+	ListByStatus(ctx context.Context, statuses ...string) ([]*entity.ProductImportJob, error)
+
+	// SaveProduct writes an imported product along with its new and changed
+	// variants and images in one transaction. The product, variants and
+	// images without an ID are created, the others are updated.
+	SaveProduct(ctx context.Context, product *entity.Product, variants []*entity.ProductVariant, images []*entity.ProductImage) error
+}
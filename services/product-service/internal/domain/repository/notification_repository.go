@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+type NotificationRepository interface {
+	// SendReviewDecision asks notification-service to tell a review's author
+	// that a moderator approved or rejected it
+	SendReviewDecision(ctx context.Context, review *entity.Review) error
+}
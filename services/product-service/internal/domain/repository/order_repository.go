@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PurchasedOrder is one of a user's orders that contains a product and has
+// reached the customer
+type PurchasedOrder struct {
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+}
+
+// ProductSales is the number of units of a product sold in a period
+type ProductSales struct {
+	ProductID     uuid.UUID `json:"product_id"`
+	TotalQuantity int       `json:"total_quantity"`
+}
+
+// ProductPair counts the orders containing both products. ProductOrders is
+// the number of orders containing ProductID.
+type ProductPair struct {
+	ProductID        uuid.UUID `json:"product_id"`
+	RelatedProductID uuid.UUID `json:"related_product_id"`
+	OrderCount       int       `json:"order_count"`
+	ProductOrders    int       `json:"product_orders"`
+}
+
+// OrderRepository looks up orders in order-service
+type OrderRepository interface {
+	// GetPurchases returns the user's delivered or completed orders that
+	// contain the product, most recent first
+	GetPurchases(ctx context.Context, userID, productID uuid.UUID) ([]PurchasedOrder, error)
+
+	// GetCoPurchases returns the pairs of products bought together in at
+	// least minOrders orders placed between since and until
+	GetCoPurchases(ctx context.Context, since, until time.Time, minOrders, limit int) ([]ProductPair, error)
+
+	// GetTopProducts returns the units sold of the best selling products in
+	// orders placed between since and until, best selling first
+	GetTopProducts(ctx context.Context, since, until time.Time, limit int) ([]ProductSales, error)
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+type PriceScheduleRepository interface {
+	Create(ctx context.Context, schedule *entity.PriceSchedule) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.PriceSchedule, error)
+	Update(ctx context.Context, schedule *entity.PriceSchedule) error
+	GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entity.PriceSchedule, error)
+
+	// Transition moves a schedule from one status to another and reports
+	// whether it did. Only one caller wins when several race for a schedule.
+	Transition(ctx context.Context, id uuid.UUID, from, to string) (bool, error)
+
+	// DueToStart returns scheduled schedules whose start has passed and whose
+	// end has not, oldest first
+	DueToStart(ctx context.Context, now time.Time, limit int) ([]*entity.PriceSchedule, error)
+
+	// DueToEnd returns active schedules whose end has passed, oldest first
+	DueToEnd(ctx context.Context, now time.Time, limit int) ([]*entity.PriceSchedule, error)
+
+	// ExpireUnstarted ends scheduled schedules whose whole window passed
+	// before they were started, and returns how many it ended
+	ExpireUnstarted(ctx context.Context, now time.Time) (int64, error)
+
+	// Overlapping returns the scheduled or active schedules for the same
+	// product or variant whose window overlaps [start, end). A nil end is open.
+	Overlapping(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, start time.Time, end *time.Time) ([]*entity.PriceSchedule, error)
+}
+
+type PriceHistoryRepository interface {
+	Create(ctx context.Context, entry *entity.PriceHistory) error
+
+	// GetTimeline returns the price changes of a product, or of one of its
+	// variants, since the given time, oldest first
+	GetTimeline(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, since time.Time) ([]*entity.PriceHistory, error)
+
+	// LowestPrice returns the lowest price in effect at any point since the
+	// given time, including the price that was current at that time
+	LowestPrice(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, since time.Time) (*float64, error)
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
+	"solemate/pkg/pagination"
 	"solemate/services/product-service/internal/domain/entity"
 )
 
@@ -14,11 +16,24 @@ type ProductRepository interface {
 	GetBySlug(ctx context.Context, slug string) (*entity.Product, error)
 	Update(ctx context.Context, product *entity.Product) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, filters ProductFilters) ([]*entity.Product, int64, error)
-	SearchByText(ctx context.Context, query string, filters ProductFilters) ([]*entity.Product, int64, error)
+	List(ctx context.Context, filters ProductFilters) ([]*entity.Product, *pagination.Page, error)
+	SearchByText(ctx context.Context, query string, filters ProductFilters) ([]*entity.Product, *pagination.Page, error)
+	SearchFacets(ctx context.Context, query string, filters ProductFilters) (*ProductFacets, error)
+	Suggest(ctx context.Context, query string, limit int) (*SearchSuggestions, error)
 	GetRelatedProducts(ctx context.Context, productID uuid.UUID, limit int) ([]*entity.Product, error)
+	GetActiveByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Product, error)
+	ListAfterSKU(ctx context.Context, afterSKU string, limit int) ([]*entity.Product, error)
+
+	// UpdatePopularity recomputes every product's popularity score from the
+	// signals gathered from other services, its recent views and its rating.
+	// Products without signals are scored on views and rating alone.
+	UpdatePopularity(ctx context.Context, signals []*PopularitySignals, weights PopularityWeights) (int64, error)
 }
 
+// ErrCategoryCycle is returned when a category would be moved under itself
+// or one of its descendants
+var ErrCategoryCycle = errors.New("a category cannot be moved under itself or one of its descendants")
+
 type CategoryRepository interface {
 	Create(ctx context.Context, category *entity.Category) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Category, error)
@@ -28,6 +43,14 @@ type CategoryRepository interface {
 	List(ctx context.Context, limit, offset int) ([]*entity.Category, int64, error)
 	GetChildren(ctx context.Context, parentID uuid.UUID) ([]*entity.Category, error)
 	GetTree(ctx context.Context) ([]*entity.Category, error)
+	GetAncestors(ctx context.Context, id uuid.UUID) ([]*entity.Category, error)
+	GetSiblings(ctx context.Context, parentID *uuid.UUID) ([]*entity.Category, error)
+
+	// Move re-parents a category and renumbers its new siblings. It fails
+	// with ErrCategoryCycle when parentID is the category or lies in its
+	// subtree.
+	Move(ctx context.Context, id uuid.UUID, parentID *uuid.UUID, siblingIDs []uuid.UUID) error
+	Reorder(ctx context.Context, parentID *uuid.UUID, categoryIDs []uuid.UUID) error
 }
 
 type BrandRepository interface {
@@ -47,6 +70,7 @@ type ProductVariantRepository interface {
 	Update(ctx context.Context, variant *entity.ProductVariant) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	UpdateStock(ctx context.Context, id uuid.UUID, quantity int) error
+	Reorder(ctx context.Context, productID uuid.UUID, variantIDs []uuid.UUID) error
 }
 
 type ProductImageRepository interface {
@@ -56,19 +80,98 @@ type ProductImageRepository interface {
 	Update(ctx context.Context, image *entity.ProductImage) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	SetPrimary(ctx context.Context, productID, imageID uuid.UUID) error
+	Reorder(ctx context.Context, productID uuid.UUID, imageIDs []uuid.UUID) error
 }
 
 // ProductFilters represents filters for product queries
 type ProductFilters struct {
-	CategoryID   *uuid.UUID `json:"category_id"`
-	BrandID      *uuid.UUID `json:"brand_id"`
-	MinPrice     *float64   `json:"min_price"`
-	MaxPrice     *float64   `json:"max_price"`
-	Tags         []string   `json:"tags"`
-	IsActive     *bool      `json:"is_active"`
-	InStock      *bool      `json:"in_stock"`
-	SortBy       string     `json:"sort_by"`       // price, name, created_at, popularity
-	SortOrder    string     `json:"sort_order"`    // asc, desc
-	Limit        int        `json:"limit"`
-	Offset       int        `json:"offset"`
-}
\ No newline at end of file
+	CategoryIDs []uuid.UUID       `json:"category_ids"`
+	BrandIDs    []uuid.UUID       `json:"brand_ids"`
+	Sizes       []string          `json:"sizes"`  // matches active variants
+	Colors      []string          `json:"colors"` // matches active variants, case-insensitive
+	MinPrice    *float64          `json:"min_price"`
+	MaxPrice    *float64          `json:"max_price"`
+	Tags        []string          `json:"tags"`
+	IsActive    *bool             `json:"is_active"`
+	InStock     *bool             `json:"in_stock"`
+	Attributes  []AttributeFilter `json:"attributes"` // all must match
+	SortBy      string            `json:"sort_by"`    // relevance (text search only), price, name, created_at, rating, popularity, best_selling
+	SortOrder   string            `json:"sort_order"` // asc, desc
+	Limit       int               `json:"limit"`
+	Offset      int               `json:"offset"`
+	Cursor      string            `json:"cursor"`     // continues from a page's cursor instead of Offset; not for relevance
+	SkipCount   bool              `json:"skip_count"` // leaves the total out of the page
+}
+
+// AttributeFilter matches products with a value for the attribute code. The
+// = operator matches any of Values, case-insensitively; <, <=, > and >=
+// compare numeric attributes with Number. Variant values count while the
+// variant is active.
+type AttributeFilter struct {
+	Code     string   `json:"code"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values,omitempty"`
+	Number   float64  `json:"number,omitempty"`
+}
+
+// ProductFacets counts the products matching a search per filter value. Each
+// facet is computed under every active filter except its own, so selecting
+// one brand still shows how many products the other brands have.
+type ProductFacets struct {
+	Brands     []FacetCount     `json:"brands"`
+	Categories []FacetCount     `json:"categories"`
+	Sizes      []FacetCount     `json:"sizes"`
+	Colors     []FacetCount     `json:"colors"`
+	Prices     []PriceBucket    `json:"prices"`
+	Attributes []AttributeFacet `json:"attributes"`
+}
+
+// AttributeFacet counts the products per value of a filterable attribute
+type AttributeFacet struct {
+	Code   string       `json:"code"`
+	Name   string       `json:"name"`
+	Type   string       `json:"type"`
+	Unit   string       `json:"unit,omitempty"`
+	Values []FacetCount `json:"values"`
+}
+
+// FacetCount is the number of products with one filter value. Label is the
+// display name for brand and category IDs.
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// PriceBucket counts products priced from Min up to, but excluding, Max. The
+// last bucket has no Max.
+type PriceBucket struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int64    `json:"count"`
+}
+
+// PopularitySignals are a product's popularity signals kept outside the
+// catalog. Sales and WishlistAdds are decayed so recent activity counts more;
+// UnitsSold is the plain count for the best selling sort.
+type PopularitySignals struct {
+	ProductID    uuid.UUID
+	UnitsSold    int
+	Sales        float64
+	WishlistAdds float64
+}
+
+// PopularityWeights sets how much each signal adds to a popularity score.
+// Counts are log-scaled so one viral product cannot drown out the rest.
+// Views decay by half every ViewHalfLifeDays within the last ViewDays days,
+// and the rating is damped by RatingPrior so a single five-star review
+// doesn't outrank a well-reviewed product.
+type PopularityWeights struct {
+	Sales            float64
+	WishlistAdds     float64
+	Views            float64
+	Rating           float64
+	ViewDays         int
+	ViewHalfLifeDays float64
+	RatingPrior      int
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+// ViewTracker counts product views as they happen and keeps each viewer's
+// browsing history
+type ViewTracker interface {
+	// RecordView counts a view of a product, for trending in each of the
+	// given categories, and adds it to the viewer's history. A viewer's
+	// repeated views are counted once. source is the client IP of an
+	// anonymous view, which caps how many anonymous viewers from one address
+	// are counted; it is empty for signed-in viewers.
+	RecordView(ctx context.Context, viewer, source string, productID uuid.UUID, categoryIDs []uuid.UUID, at time.Time) error
+
+	// RecentlyViewed returns the products the viewer looked at, latest first
+	RecentlyViewed(ctx context.Context, viewer string, limit int) ([]uuid.UUID, error)
+
+	// Trending returns the most viewed products of the last day, recent
+	// views weighing more, optionally within a category
+	Trending(ctx context.Context, categoryID *uuid.UUID, limit int) ([]uuid.UUID, error)
+
+	// DailyViews returns each product's views so far on day
+	DailyViews(ctx context.Context, day time.Time) ([]*entity.ProductViewStat, error)
+
+	// DailyCoViews returns the products viewed together so far on day
+	DailyCoViews(ctx context.Context, day time.Time) ([]*entity.ProductCoView, error)
+}
+
+// ProductViewRepository keeps the daily view counts for analytics and the
+// popularity sort
+type ProductViewRepository interface {
+	// SaveDailyViews stores the counts, replacing any saved earlier for the
+	// same days
+	SaveDailyViews(ctx context.Context, stats []*entity.ProductViewStat, coViews []*entity.ProductCoView) error
+
+	// RefreshRecentViews sets each product's recent views to its total since
+	// the given day
+	RefreshRecentViews(ctx context.Context, since time.Time) (int64, error)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+type RecommendationRepository interface {
+	// ReplaceAffinities swaps all affinities from a source for the given ones
+	// in one transaction, so readers never see a half-written set
+	ReplaceAffinities(ctx context.Context, source string, affinities []*entity.ProductAffinity) error
+
+	// GetRelatedProductIDs returns the active products with the strongest
+	// affinity to a product from a source, strongest first
+	GetRelatedProductIDs(ctx context.Context, productID uuid.UUID, source string, limit int) ([]uuid.UUID, error)
+
+	// GetViewAffinities scores the products viewed together since the given
+	// day at least minViews times, keeping the perProduct strongest for each
+	GetViewAffinities(ctx context.Context, since time.Time, minViews, perProduct int) ([]*entity.ProductAffinity, error)
+}
+
+// RecommendationCache holds each product's ranked recommendations, by type
+type RecommendationCache interface {
+	Get(ctx context.Context, productID uuid.UUID, recommendationType string) ([]uuid.UUID, bool)
+	Set(ctx context.Context, productID uuid.UUID, recommendationType string, productIDs []uuid.UUID) error
+}
@@ -14,6 +14,32 @@ type ReviewRepository interface {
 	Update(ctx context.Context, review *entity.Review) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetUserReviewForProduct(ctx context.Context, userID, productID uuid.UUID) (*entity.Review, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Review, error)
+	ReassignUser(ctx context.Context, userID, pseudonymID uuid.UUID) (int64, error)
+
+	// ListByStatus returns reviews in a moderation state, oldest first, so
+	// the queue is worked in the order reviews came in
+	ListByStatus(ctx context.Context, status string, limit, offset int) ([]*entity.Review, int64, error)
+
+	// CountByContentHash counts the other reviews with the same content hash
+	CountByContentHash(ctx context.Context, hash string, excludeID uuid.UUID) (int64, error)
+
+	// Vote records a user's vote, replacing any earlier vote by the same
+	// user, and moves the review's helpful counters in the same transaction
+	Vote(ctx context.Context, vote *entity.ReviewVote) error
+
+	// Report records a user's report and returns how many reports the review
+	// has had since a moderator last looked at it
+	Report(ctx context.Context, report *entity.ReviewReport) (int64, error)
+
+	// HoldForModeration sends a published review back to the moderation
+	// queue with the given flag. It reports whether the review was published.
+	HoldForModeration(ctx context.Context, id uuid.UUID, flag string) (bool, error)
+
+	// RefreshProductRatings recomputes every product's rating average, count
+	// and histogram from its published reviews. Create, Update, Delete and
+	// HoldForModeration keep a single product's up to date.
+	RefreshProductRatings(ctx context.Context) (int64, error)
 }
 
 // ReviewFilters represents filters for review queries
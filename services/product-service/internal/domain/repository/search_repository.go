@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+type SearchQueryRepository interface {
+	Create(ctx context.Context, query *entity.SearchQuery) error
+	ZeroResultQueries(ctx context.Context, since time.Time, limit int) ([]*ZeroResultQuery, error)
+}
+
+// SuggestionCache keeps recent autocomplete responses, keyed by the
+// normalised query and limit
+type SuggestionCache interface {
+	Get(ctx context.Context, query string, limit int) (*SearchSuggestions, bool)
+	Set(ctx context.Context, query string, limit int, suggestions *SearchSuggestions) error
+}
+
+// ZeroResultQuery is a search that found nothing, with how often it was run
+type ZeroResultQuery struct {
+	Query          string    `json:"query"`
+	Searches       int64     `json:"searches"`
+	LastSearchedAt time.Time `json:"last_searched_at"`
+}
+
+// SearchSuggestions are the type-ahead matches for a partial query
+type SearchSuggestions struct {
+	Products   []Suggestion `json:"products"`
+	Brands     []Suggestion `json:"brands"`
+	Categories []Suggestion `json:"categories"`
+}
+
+type Suggestion struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Slug string    `json:"slug"`
+}
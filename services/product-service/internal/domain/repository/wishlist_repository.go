@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductWishlistAdds is the number of shoppers who saved a product to a
+// wishlist in a period
+type ProductWishlistAdds struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Adds      int       `json:"adds"`
+}
+
+// WishlistRepository reads wishlist analytics from user-service
+type WishlistRepository interface {
+	// GetWishlistAdds returns, per product, the shoppers who saved it
+	// between since and until and still have it in a wishlist
+	GetWishlistAdds(ctx context.Context, since, until time.Time) ([]ProductWishlistAdds, error)
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"solemate/pkg/utils"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+var ErrAttributeNotFound = errors.New("attribute not found")
+
+// attributeCodePattern keeps codes usable as filter keys, e.g. width=wide
+var attributeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// attributeFilterPattern splits a filter such as drop<=6 into code,
+// operator and value. Two-character operators come first so <= isn't read
+// as < followed by "=6".
+var attributeFilterPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_]*)(<=|>=|<|>|=)(.+)$`)
+
+type CreateAttributeRequest struct {
+	Code          string   `json:"code" binding:"required"`
+	Name          string   `json:"name" binding:"required"`
+	Type          string   `json:"type" binding:"required,oneof=enum number boolean"`
+	Unit          string   `json:"unit"`
+	AllowedValues []string `json:"allowed_values"`
+	Filterable    *bool    `json:"filterable"`
+	SortOrder     int      `json:"sort_order"`
+}
+
+// UpdateAttributeRequest changes an attribute's presentation. The code and
+// type are fixed once values may have been stored against them.
+type UpdateAttributeRequest struct {
+	Name          *string   `json:"name"`
+	Unit          *string   `json:"unit"`
+	AllowedValues *[]string `json:"allowed_values"`
+	Filterable    *bool     `json:"filterable"`
+	SortOrder     *int      `json:"sort_order"`
+}
+
+// SetAttributesRequest replaces all of a product's or variant's attribute
+// values, keyed by code. Values are JSON strings for enums, numbers for
+// numbers and booleans for booleans; null leaves an attribute unset.
+type SetAttributesRequest struct {
+	Attributes map[string]interface{} `json:"attributes" binding:"required"`
+}
+
+// ListAttributes returns the attributes that apply to a category's
+// products, inherited ones included
+func (s *CategoryService) ListAttributes(ctx context.Context, categoryID uuid.UUID) ([]*entity.AttributeDefinition, error) {
+	return categoryAttributes(ctx, s.categoryRepo, s.attributeRepo, categoryID)
+}
+
+// CreateAttribute defines an attribute for a category and its descendants
+func (s *CategoryService) CreateAttribute(ctx context.Context, categoryID uuid.UUID, req *CreateAttributeRequest) (*entity.AttributeDefinition, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+
+	code := strings.ToLower(strings.TrimSpace(req.Code))
+	if !attributeCodePattern.MatchString(code) {
+		return nil, errors.New("code must start with a letter and contain only lowercase letters, digits and underscores")
+	}
+
+	existing, err := s.attributeRepo.GetDefinitionsByCategoryIDs(ctx, []uuid.UUID{categoryID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attributes: %w", err)
+	}
+	for _, definition := range existing {
+		if definition.Code == code {
+			return nil, errors.New("category already has an attribute with this code")
+		}
+	}
+
+	definition := &entity.AttributeDefinition{
+		CategoryID: categoryID,
+		Code:       code,
+		Name:       utils.SanitizeString(req.Name),
+		Type:       req.Type,
+		Unit:       utils.SanitizeString(req.Unit),
+		Filterable: true,
+		SortOrder:  req.SortOrder,
+	}
+	if req.Filterable != nil {
+		definition.Filterable = *req.Filterable
+	}
+	if err := setAllowedValues(definition, req.AllowedValues); err != nil {
+		return nil, err
+	}
+
+	if err := s.attributeRepo.CreateDefinition(ctx, definition); err != nil {
+		return nil, fmt.Errorf("failed to create attribute: %w", err)
+	}
+
+	return definition, nil
+}
+
+// UpdateAttribute changes one of a category's own attributes. Values already
+// stored are kept even if they are no longer allowed.
+func (s *CategoryService) UpdateAttribute(ctx context.Context, categoryID, attributeID uuid.UUID, req *UpdateAttributeRequest) (*entity.AttributeDefinition, error) {
+	definition, err := s.categoryAttribute(ctx, categoryID, attributeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		definition.Name = utils.SanitizeString(*req.Name)
+	}
+	if req.Unit != nil {
+		definition.Unit = utils.SanitizeString(*req.Unit)
+	}
+	if req.AllowedValues != nil {
+		if err := setAllowedValues(definition, *req.AllowedValues); err != nil {
+			return nil, err
+		}
+	}
+	if req.Filterable != nil {
+		definition.Filterable = *req.Filterable
+	}
+	if req.SortOrder != nil {
+		definition.SortOrder = *req.SortOrder
+	}
+
+	if err := s.attributeRepo.UpdateDefinition(ctx, definition); err != nil {
+		return nil, fmt.Errorf("failed to update attribute: %w", err)
+	}
+
+	return definition, nil
+}
+
+// DeleteAttribute deletes one of a category's own attributes along with
+// every value stored for it
+func (s *CategoryService) DeleteAttribute(ctx context.Context, categoryID, attributeID uuid.UUID) error {
+	if _, err := s.categoryAttribute(ctx, categoryID, attributeID); err != nil {
+		return err
+	}
+	return s.attributeRepo.DeleteDefinition(ctx, attributeID)
+}
+
+func (s *CategoryService) categoryAttribute(ctx context.Context, categoryID, attributeID uuid.UUID) (*entity.AttributeDefinition, error) {
+	definition, err := s.attributeRepo.GetDefinition(ctx, attributeID)
+	if err != nil || definition.CategoryID != categoryID {
+		return nil, ErrAttributeNotFound
+	}
+	return definition, nil
+}
+
+// setAllowedValues checks the allowed values suit the attribute's type.
+// Only enums have them, and they must be distinct ignoring case.
+func setAllowedValues(definition *entity.AttributeDefinition, values []string) error {
+	if definition.Type != entity.AttributeTypeEnum {
+		if len(values) > 0 {
+			return errors.New("only enum attributes have allowed values")
+		}
+		definition.AllowedValues = nil
+		return nil
+	}
+
+	seen := make(map[string]bool, len(values))
+	allowed := make([]string, 0, len(values))
+	for _, value := range values {
+		value = utils.SanitizeString(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if seen[strings.ToLower(value)] {
+			return fmt.Errorf("allowed value %q is listed twice", value)
+		}
+		seen[strings.ToLower(value)] = true
+		allowed = append(allowed, value)
+	}
+	if len(allowed) == 0 {
+		return errors.New("enum attributes need at least one allowed value")
+	}
+
+	definition.AllowedValues = allowed
+	return nil
+}
+
+// SetProductAttributes replaces a product's own attribute values
+func (s *ProductService) SetProductAttributes(ctx context.Context, productID uuid.UUID, req *SetAttributesRequest) ([]*entity.ProductAttributeValue, error) {
+	return s.setAttributes(ctx, productID, nil, req)
+}
+
+// SetVariantAttributes replaces a variant's attribute values, e.g. a width
+// that differs between the variants of one model
+func (s *ProductService) SetVariantAttributes(ctx context.Context, productID, variantID uuid.UUID, req *SetAttributesRequest) ([]*entity.ProductAttributeValue, error) {
+	if _, err := s.productVariant(ctx, productID, variantID); err != nil {
+		return nil, err
+	}
+	return s.setAttributes(ctx, productID, &variantID, req)
+}
+
+func (s *ProductService) setAttributes(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, req *SetAttributesRequest) ([]*entity.ProductAttributeValue, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	var definitions []*entity.AttributeDefinition
+	if product.CategoryID != nil {
+		definitions, err = categoryAttributes(ctx, s.categoryRepo, s.attributes, *product.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	byCode := make(map[string]*entity.AttributeDefinition, len(definitions))
+	for _, definition := range definitions {
+		byCode[definition.Code] = definition
+	}
+
+	var values []*entity.ProductAttributeValue
+	for _, definition := range definitions {
+		raw, ok := req.Attributes[definition.Code]
+		if !ok || raw == nil {
+			continue
+		}
+		value, err := attributeValue(definition, raw)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	for code := range req.Attributes {
+		if byCode[code] == nil {
+			return nil, fmt.Errorf("attribute %q does not apply to this product's category", code)
+		}
+	}
+
+	if err := s.attributes.ReplaceValues(ctx, productID, variantID, values); err != nil {
+		return nil, fmt.Errorf("failed to save attributes: %w", err)
+	}
+
+	stored, err := s.attributes.GetValues(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attributes: %w", err)
+	}
+	var result []*entity.ProductAttributeValue
+	for _, value := range stored {
+		if sameVariant(value.VariantID, variantID) {
+			result = append(result, value)
+		}
+	}
+	return result, nil
+}
+
+// attachAttributes fills in the attribute values of a product and its
+// variants. Like breadcrumbs, they are left out rather than failing the
+// request.
+func (s *ProductService) attachAttributes(ctx context.Context, product *entity.Product) {
+	values, err := s.attributes.GetValues(ctx, product.ID)
+	if err != nil {
+		log.Printf("failed to get attributes for product %s: %v", product.ID, err)
+		return
+	}
+
+	variants := make(map[uuid.UUID]*entity.ProductVariant, len(product.Variants))
+	for i := range product.Variants {
+		variants[product.Variants[i].ID] = &product.Variants[i]
+	}
+	for _, value := range values {
+		if value.VariantID == nil {
+			product.Attributes = append(product.Attributes, *value)
+		} else if variant := variants[*value.VariantID]; variant != nil {
+			variant.Attributes = append(variant.Attributes, *value)
+		}
+	}
+}
+
+// attributeValue checks a JSON value against its definition and converts it
+// to the stored form
+func attributeValue(definition *entity.AttributeDefinition, raw interface{}) (*entity.ProductAttributeValue, error) {
+	value := &entity.ProductAttributeValue{AttributeID: definition.ID}
+
+	switch definition.Type {
+	case entity.AttributeTypeEnum:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", definition.Code)
+		}
+		for _, allowed := range definition.AllowedValues {
+			if strings.EqualFold(strings.TrimSpace(text), allowed) {
+				value.Value = allowed
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("%s must be one of %s", definition.Code, strings.Join(definition.AllowedValues, ", "))
+
+	case entity.AttributeTypeNumber:
+		number, ok := raw.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a number", definition.Code)
+		}
+		value.Value = strconv.FormatFloat(number, 'f', -1, 64)
+		value.NumberValue = &number
+		return value, nil
+
+	case entity.AttributeTypeBoolean:
+		flag, ok := raw.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be true or false", definition.Code)
+		}
+		value.Value = strconv.FormatBool(flag)
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("%s has unknown type %q", definition.Code, definition.Type)
+}
+
+// categoryAttributes returns the attributes for a category's products: its
+// own and its ancestors'. Where codes clash the nearest category wins.
+func categoryAttributes(ctx context.Context, categoryRepo repository.CategoryRepository, attributeRepo repository.AttributeRepository, categoryID uuid.UUID) ([]*entity.AttributeDefinition, error) {
+	ancestors, err := categoryRepo.GetAncestors(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := make(map[uuid.UUID]int, len(ancestors))
+	ids := make([]uuid.UUID, len(ancestors))
+	for i, ancestor := range ancestors {
+		depth[ancestor.ID] = i
+		ids[i] = ancestor.ID
+	}
+
+	definitions, err := attributeRepo.GetDefinitionsByCategoryIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attributes: %w", err)
+	}
+
+	nearest := make(map[string]*entity.AttributeDefinition)
+	for _, definition := range definitions {
+		current := nearest[definition.Code]
+		if current == nil || depth[definition.CategoryID] > depth[current.CategoryID] {
+			nearest[definition.Code] = definition
+		}
+	}
+
+	result := make([]*entity.AttributeDefinition, 0, len(nearest))
+	for _, definition := range definitions {
+		if nearest[definition.Code] == definition {
+			result = append(result, definition)
+		}
+	}
+	return result, nil
+}
+
+// ParseAttributeFilters parses search filters such as width=wide,extra-wide
+// (any of the values) and drop<=6
+func ParseAttributeFilters(raw []string) ([]repository.AttributeFilter, error) {
+	var filters []repository.AttributeFilter
+	for _, expression := range raw {
+		match := attributeFilterPattern.FindStringSubmatch(strings.TrimSpace(expression))
+		if match == nil {
+			return nil, fmt.Errorf("invalid attribute filter %q", expression)
+		}
+
+		filter := repository.AttributeFilter{Code: strings.ToLower(match[1]), Operator: match[2]}
+		if filter.Operator == "=" {
+			for _, value := range strings.Split(match[3], ",") {
+				if value = strings.TrimSpace(value); value != "" {
+					filter.Values = append(filter.Values, value)
+				}
+			}
+			if len(filter.Values) == 0 {
+				return nil, fmt.Errorf("invalid attribute filter %q", expression)
+			}
+		} else {
+			number, err := strconv.ParseFloat(strings.TrimSpace(match[3]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("attribute filter %q needs a number", expression)
+			}
+			filter.Number = number
+		}
+
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+func sameVariant(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
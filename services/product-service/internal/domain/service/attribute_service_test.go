@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+func TestParseAttributeFilters(t *testing.T) {
+	tests := map[string]struct {
+		raw  []string
+		want []repository.AttributeFilter
+	}{
+		"values": {
+			raw:  []string{"width=wide,extra-wide"},
+			want: []repository.AttributeFilter{{Code: "width", Operator: "=", Values: []string{"wide", "extra-wide"}}},
+		},
+		"empty values are dropped": {
+			raw:  []string{"width= wide , ,narrow"},
+			want: []repository.AttributeFilter{{Code: "width", Operator: "=", Values: []string{"wide", "narrow"}}},
+		},
+		"code is lower-cased": {
+			raw:  []string{"Width=Wide"},
+			want: []repository.AttributeFilter{{Code: "width", Operator: "=", Values: []string{"Wide"}}},
+		},
+		"less or equal is not read as less than": {
+			raw:  []string{"drop<=6"},
+			want: []repository.AttributeFilter{{Code: "drop", Operator: "<=", Number: 6}},
+		},
+		"numeric operators": {
+			raw: []string{"drop<6", "drop>4.5", "stack_height>=-1"},
+			want: []repository.AttributeFilter{
+				{Code: "drop", Operator: "<", Number: 6},
+				{Code: "drop", Operator: ">", Number: 4.5},
+				{Code: "stack_height", Operator: ">=", Number: -1},
+			},
+		},
+		"no filters": {
+			raw:  nil,
+			want: nil,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			filters, err := ParseAttributeFilters(tt.raw)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, filters)
+		})
+	}
+}
+
+func TestParseAttributeFiltersRejectsInvalidFilters(t *testing.T) {
+	tests := map[string]string{
+		"no operator":            "width",
+		"no value":               "width=",
+		"only separators":        "width=,,",
+		"unsupported operator":   "drop!=6",
+		"like operator":          "width~wide",
+		"code with sql":          "width;drop table=1",
+		"code starting digit":    "1width=wide",
+		"non-numeric comparison": "drop<=six",
+		"comparison with a list": "drop<4,6",
+		"quoted code":            `"width"=wide`,
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseAttributeFilters([]string{raw})
+			assert.Error(t, err)
+		})
+	}
+}
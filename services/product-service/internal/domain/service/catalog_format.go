@@ -0,0 +1,415 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+// CatalogProduct is one product in a catalog import or export file. Category
+// and brand are referenced by slug so files can move between environments.
+type CatalogProduct struct {
+	SKU             string           `json:"sku"`
+	Name            string           `json:"name"`
+	Slug            string           `json:"slug,omitempty"` // derived from the name when empty
+	Description     string           `json:"description,omitempty"`
+	Category        string           `json:"category,omitempty"`
+	Brand           string           `json:"brand,omitempty"`
+	Price           *float64         `json:"price"`
+	ComparePrice    *float64         `json:"compare_price,omitempty"`
+	Cost            *float64         `json:"cost,omitempty"`
+	Weight          *float64         `json:"weight,omitempty"`
+	Tags            []string         `json:"tags,omitempty"`
+	Images          []CatalogImage   `json:"images,omitempty"`
+	MetaTitle       string           `json:"meta_title,omitempty"`
+	MetaDescription string           `json:"meta_description,omitempty"`
+	IsActive        *bool            `json:"is_active,omitempty"` // defaults to true for new products
+	Variants        []CatalogVariant `json:"variants,omitempty"`
+
+	row    int
+	fields catalogFields
+}
+
+type CatalogImage struct {
+	URL     string `json:"url"`
+	AltText string `json:"alt_text,omitempty"`
+}
+
+type CatalogVariant struct {
+	SKU      string   `json:"sku"`
+	Size     string   `json:"size,omitempty"`
+	Color    string   `json:"color,omitempty"`
+	Price    *float64 `json:"price,omitempty"`
+	Stock    int      `json:"stock"`
+	Weight   *float64 `json:"weight,omitempty"`
+	Images   []string `json:"images,omitempty"`
+	IsActive *bool    `json:"is_active,omitempty"` // defaults to true for new variants
+
+	row    int
+	fields catalogFields
+}
+
+// catalogFields is the set of fields an import file provides, named by their
+// JSON keys. Fields the file leaves out are kept as they are on existing
+// products and variants rather than cleared. A nil set provides every field.
+type catalogFields map[string]bool
+
+func (f catalogFields) has(field string) bool {
+	return f == nil || f[field]
+}
+
+// catalogCSVColumns is the CSV layout: one row per variant, with the product
+// columns repeated on each of a product's rows. A product without variants
+// has a single row with empty variant columns. Lists are separated by
+// catalogCSVListSeparator; CSV images carry no alt text.
+var catalogCSVColumns = []string{
+	"sku", "name", "slug", "description", "category", "brand",
+	"price", "compare_price", "cost", "weight", "tags", "images",
+	"meta_title", "meta_description", "is_active",
+	"variant_sku", "variant_size", "variant_color", "variant_price",
+	"variant_stock", "variant_weight", "variant_images", "variant_is_active",
+}
+
+const catalogCSVListSeparator = "|"
+
+var ErrUnsupportedCatalogFormat = errors.New("format must be csv or json")
+
+// decodeCatalog parses an import file. Problems with individual values are
+// returned as row errors so the whole file can be reported on at once; an
+// error is only returned when the file itself cannot be read.
+func decodeCatalog(format string, data []byte) ([]*CatalogProduct, []entity.ImportRowError, error) {
+	switch format {
+	case entity.CatalogFormatCSV:
+		return decodeCatalogCSV(data)
+	case entity.CatalogFormatJSON:
+		return decodeCatalogJSON(data)
+	default:
+		return nil, nil, ErrUnsupportedCatalogFormat
+	}
+}
+
+func decodeCatalogJSON(data []byte) ([]*CatalogProduct, []entity.ImportRowError, error) {
+	var objects []json.RawMessage
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON, expected an array of products: %w", err)
+	}
+
+	products := make([]*CatalogProduct, len(objects))
+	for i, object := range objects {
+		product := &CatalogProduct{row: i + 1}
+		if err := json.Unmarshal(object, product); err != nil {
+			return nil, nil, fmt.Errorf("invalid JSON for product %d: %w", product.row, err)
+		}
+
+		// Decoded a second time as plain objects to find out which fields
+		// the product and its variants provide
+		var productKeys map[string]json.RawMessage
+		var variantKeys []map[string]json.RawMessage
+		if err := json.Unmarshal(object, &productKeys); err != nil {
+			return nil, nil, fmt.Errorf("invalid JSON for product %d: %w", product.row, err)
+		}
+		if variants, ok := productKeys["variants"]; ok {
+			if err := json.Unmarshal(variants, &variantKeys); err != nil {
+				return nil, nil, fmt.Errorf("invalid JSON for product %d: %w", product.row, err)
+			}
+		}
+
+		product.fields = jsonFields(productKeys)
+		for j := range product.Variants {
+			product.Variants[j].row = product.row
+			if j < len(variantKeys) {
+				product.Variants[j].fields = jsonFields(variantKeys[j])
+			}
+		}
+		products[i] = product
+	}
+	return products, nil, nil
+}
+
+func jsonFields(object map[string]json.RawMessage) catalogFields {
+	fields := make(catalogFields, len(object))
+	for key := range object {
+		fields[strings.ToLower(key)] = true
+	}
+	return fields
+}
+
+func decodeCatalogCSV(data []byte) ([]*CatalogProduct, []entity.ImportRowError, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	productFields, variantFields := catalogFields{}, catalogFields{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		columns[name] = i
+		if field, ok := strings.CutPrefix(name, "variant_"); ok {
+			variantFields[field] = true
+		} else {
+			productFields[name] = true
+		}
+	}
+	for _, required := range []string{"sku", "name", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("CSV header is missing the %q column", required)
+		}
+	}
+
+	var products []*CatalogProduct
+	var rowErrors []entity.ImportRowError
+	bySKU := map[string]*CatalogProduct{}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		row := csvRow{record: record, columns: columns, line: line}
+
+		sku := row.get("sku")
+		product, seen := bySKU[sku]
+		if !seen || sku == "" {
+			// The product columns are read from a product's first row only
+			product = &CatalogProduct{
+				SKU:             sku,
+				Name:            row.get("name"),
+				Slug:            row.get("slug"),
+				Description:     row.get("description"),
+				Category:        row.get("category"),
+				Brand:           row.get("brand"),
+				Price:           row.float("price"),
+				ComparePrice:    row.float("compare_price"),
+				Cost:            row.float("cost"),
+				Weight:          row.float("weight"),
+				Tags:            row.list("tags"),
+				MetaTitle:       row.get("meta_title"),
+				MetaDescription: row.get("meta_description"),
+				IsActive:        row.bool("is_active"),
+				row:             line,
+				fields:          productFields,
+			}
+			for _, url := range row.list("images") {
+				product.Images = append(product.Images, CatalogImage{URL: url})
+			}
+			products = append(products, product)
+			if sku != "" {
+				bySKU[sku] = product
+			}
+		}
+
+		if variantSKU := row.get("variant_sku"); variantSKU != "" {
+			variant := CatalogVariant{
+				SKU:      variantSKU,
+				Size:     row.get("variant_size"),
+				Color:    row.get("variant_color"),
+				Price:    row.float("variant_price"),
+				Weight:   row.float("variant_weight"),
+				Images:   row.list("variant_images"),
+				IsActive: row.bool("variant_is_active"),
+				row:      line,
+				fields:   variantFields,
+			}
+			if stock := row.int("variant_stock"); stock != nil {
+				variant.Stock = *stock
+			}
+			product.Variants = append(product.Variants, variant)
+		}
+
+		rowErrors = append(rowErrors, row.errors...)
+	}
+
+	return products, rowErrors, nil
+}
+
+// csvRow reads typed values from a CSV record, collecting parse errors
+type csvRow struct {
+	record  []string
+	columns map[string]int
+	line    int
+	errors  []entity.ImportRowError
+}
+
+func (r *csvRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r *csvRow) invalid(column, message string) {
+	r.errors = append(r.errors, entity.ImportRowError{
+		Row:     r.line,
+		SKU:     r.get("sku"),
+		Field:   column,
+		Message: message,
+	})
+}
+
+func (r *csvRow) float(column string) *float64 {
+	value := r.get(column)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		r.invalid(column, "must be a number")
+		return nil
+	}
+	return &parsed
+}
+
+func (r *csvRow) int(column string) *int {
+	value := r.get(column)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		r.invalid(column, "must be a whole number")
+		return nil
+	}
+	return &parsed
+}
+
+func (r *csvRow) bool(column string) *bool {
+	value := r.get(column)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		r.invalid(column, "must be true or false")
+		return nil
+	}
+	return &parsed
+}
+
+func (r *csvRow) list(column string) []string {
+	var values []string
+	for _, value := range strings.Split(r.get(column), catalogCSVListSeparator) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// toCatalogProduct converts a product loaded with its category, brand,
+// variants and images into its export representation
+func toCatalogProduct(product *entity.Product) *CatalogProduct {
+	isActive := product.IsActive
+	catalog := &CatalogProduct{
+		SKU:             product.SKU,
+		Name:            product.Name,
+		Slug:            product.Slug,
+		Description:     product.Description,
+		Price:           &product.Price,
+		ComparePrice:    product.ComparePrice,
+		Cost:            product.Cost,
+		Weight:          product.Weight,
+		Tags:            product.Tags,
+		MetaTitle:       product.MetaTitle,
+		MetaDescription: product.MetaDescription,
+		IsActive:        &isActive,
+	}
+	if product.Category != nil {
+		catalog.Category = product.Category.Slug
+	}
+	if product.Brand != nil {
+		catalog.Brand = product.Brand.Slug
+	}
+	for _, image := range product.Images {
+		catalog.Images = append(catalog.Images, CatalogImage{URL: image.URL, AltText: image.AltText})
+	}
+	for _, variant := range product.Variants {
+		isActive := variant.IsActive
+		catalog.Variants = append(catalog.Variants, CatalogVariant{
+			SKU:      variant.SKU,
+			Size:     variant.Size,
+			Color:    variant.Color,
+			Price:    variant.Price,
+			Stock:    variant.Stock,
+			Weight:   variant.Weight,
+			Images:   variant.Images,
+			IsActive: &isActive,
+		})
+	}
+	return catalog
+}
+
+// catalogCSVRecords lays a product out as CSV records in catalogCSVColumns order
+func catalogCSVRecords(product *CatalogProduct) [][]string {
+	images := make([]string, len(product.Images))
+	for i, image := range product.Images {
+		images[i] = image.URL
+	}
+
+	productColumns := []string{
+		product.SKU,
+		product.Name,
+		product.Slug,
+		product.Description,
+		product.Category,
+		product.Brand,
+		formatOptionalFloat(product.Price),
+		formatOptionalFloat(product.ComparePrice),
+		formatOptionalFloat(product.Cost),
+		formatOptionalFloat(product.Weight),
+		strings.Join(product.Tags, catalogCSVListSeparator),
+		strings.Join(images, catalogCSVListSeparator),
+		product.MetaTitle,
+		product.MetaDescription,
+		formatOptionalBool(product.IsActive),
+	}
+
+	if len(product.Variants) == 0 {
+		return [][]string{append(productColumns, make([]string, 8)...)}
+	}
+
+	records := make([][]string, 0, len(product.Variants))
+	for _, variant := range product.Variants {
+		record := append([]string{}, productColumns...)
+		record = append(record,
+			variant.SKU,
+			variant.Size,
+			variant.Color,
+			formatOptionalFloat(variant.Price),
+			strconv.Itoa(variant.Stock),
+			formatOptionalFloat(variant.Weight),
+			strings.Join(variant.Images, catalogCSVListSeparator),
+			formatOptionalBool(variant.IsActive),
+		)
+		records = append(records, record)
+	}
+	return records
+}
+
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+func formatOptionalBool(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*value)
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+func TestDecodeCatalogCSV(t *testing.T) {
+	allProductFields := catalogFields{"sku": true, "name": true, "price": true, "tags": true, "is_active": true}
+	allVariantFields := catalogFields{"sku": true, "size": true, "stock": true}
+
+	tests := map[string]struct {
+		data       string
+		want       []*CatalogProduct
+		wantErrors []entity.ImportRowError
+	}{
+		"rows of the same product become its variants": {
+			data: "sku,name,price,tags,is_active,variant_sku,variant_size,variant_stock\n" +
+				"AM-90,Air Max 90,129.99,running|retro,false,AM-90-9,9,4\n" +
+				"AM-90,ignored,1,,,AM-90-10,10,\n",
+			want: []*CatalogProduct{{
+				SKU:      "AM-90",
+				Name:     "Air Max 90",
+				Price:    floatPtr(129.99),
+				Tags:     []string{"running", "retro"},
+				IsActive: boolPtr(false),
+				Variants: []CatalogVariant{
+					{SKU: "AM-90-9", Size: "9", Stock: 4, row: 2, fields: allVariantFields},
+					{SKU: "AM-90-10", Size: "10", row: 3, fields: allVariantFields},
+				},
+				row:    2,
+				fields: allProductFields,
+			}},
+		},
+		"product without variants": {
+			data: "sku,name,price,tags,is_active,variant_sku,variant_size,variant_stock\n" +
+				"AM-90,Air Max 90,129.99,,,,,\n",
+			want: []*CatalogProduct{{SKU: "AM-90", Name: "Air Max 90", Price: floatPtr(129.99), row: 2, fields: allProductFields}},
+		},
+		"header is case and space insensitive and the byte order mark is skipped": {
+			data: "\ufeff SKU ,Name,PRICE\nAM-90,Air Max 90,10\n",
+			want: []*CatalogProduct{{
+				SKU:    "AM-90",
+				Name:   "Air Max 90",
+				Price:  floatPtr(10),
+				row:    2,
+				fields: catalogFields{"sku": true, "name": true, "price": true},
+			}},
+		},
+		"invalid values are reported per row": {
+			data: "sku,name,price,is_active,variant_sku,variant_stock\n" +
+				"AM-90,Air Max 90,cheap,maybe,AM-90-9,some\n",
+			want: []*CatalogProduct{{
+				SKU:  "AM-90",
+				Name: "Air Max 90",
+				Variants: []CatalogVariant{
+					{SKU: "AM-90-9", row: 2, fields: catalogFields{"sku": true, "stock": true}},
+				},
+				row:    2,
+				fields: catalogFields{"sku": true, "name": true, "price": true, "is_active": true},
+			}},
+			wantErrors: []entity.ImportRowError{
+				{Row: 2, SKU: "AM-90", Field: "price", Message: "must be a number"},
+				{Row: 2, SKU: "AM-90", Field: "is_active", Message: "must be true or false"},
+				{Row: 2, SKU: "AM-90", Field: "variant_stock", Message: "must be a whole number"},
+			},
+		},
+		"products without a sku are never merged": {
+			data: "sku,name,price\n,First,1\n,Second,2\n",
+			want: []*CatalogProduct{
+				{Name: "First", Price: floatPtr(1), row: 2, fields: catalogFields{"sku": true, "name": true, "price": true}},
+				{Name: "Second", Price: floatPtr(2), row: 3, fields: catalogFields{"sku": true, "name": true, "price": true}},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			products, rowErrors, err := decodeCatalog(entity.CatalogFormatCSV, []byte(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, products)
+			assert.Equal(t, tt.wantErrors, rowErrors)
+		})
+	}
+}
+
+func TestDecodeCatalogCSVRejectsInvalidFiles(t *testing.T) {
+	tests := map[string]struct {
+		data    string
+		wantErr string
+	}{
+		"empty file":         {data: "", wantErr: "failed to read CSV header"},
+		"missing sku column": {data: "name,price\nAir Max,1\n", wantErr: `missing the "sku" column`},
+		"missing name column": {
+			data:    "sku,price\nAM-90,1\n",
+			wantErr: `missing the "name" column`,
+		},
+		"missing price column": {
+			data:    "sku,name,variant_price\nAM-90,Air Max,1\n",
+			wantErr: `missing the "price" column`,
+		},
+		"malformed CSV": {data: "sku,name,price\n\"AM-90,Air Max,1\n", wantErr: "invalid CSV"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := decodeCatalog(entity.CatalogFormatCSV, []byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestDecodeCatalogJSON(t *testing.T) {
+	tests := map[string]struct {
+		data string
+		want []*CatalogProduct
+	}{
+		"fields are tracked per product and variant": {
+			data: `[
+				{"sku": "AM-90", "name": "Air Max 90", "price": 129.99, "description": "",
+				 "variants": [{"sku": "AM-90-9", "stock": 4}, {"sku": "AM-90-10", "size": "10"}]},
+				{"sku": "AM-95", "name": "Air Max 95", "price": 149.99}
+			]`,
+			want: []*CatalogProduct{
+				{
+					SKU:   "AM-90",
+					Name:  "Air Max 90",
+					Price: floatPtr(129.99),
+					Variants: []CatalogVariant{
+						{SKU: "AM-90-9", Stock: 4, row: 1, fields: catalogFields{"sku": true, "stock": true}},
+						{SKU: "AM-90-10", Size: "10", row: 1, fields: catalogFields{"sku": true, "size": true}},
+					},
+					row:    1,
+					fields: catalogFields{"sku": true, "name": true, "price": true, "description": true, "variants": true},
+				},
+				{
+					SKU:    "AM-95",
+					Name:   "Air Max 95",
+					Price:  floatPtr(149.99),
+					row:    2,
+					fields: catalogFields{"sku": true, "name": true, "price": true},
+				},
+			},
+		},
+		"field names are case insensitive": {
+			data: `[{"SKU": "AM-90", "Name": "Air Max 90", "Price": 1}]`,
+			want: []*CatalogProduct{{
+				SKU:    "AM-90",
+				Name:   "Air Max 90",
+				Price:  floatPtr(1),
+				row:    1,
+				fields: catalogFields{"sku": true, "name": true, "price": true},
+			}},
+		},
+		"null products are kept so they are reported": {
+			data: `[null]`,
+			want: []*CatalogProduct{{row: 1, fields: catalogFields{}}},
+		},
+		"empty array": {
+			data: `[]`,
+			want: []*CatalogProduct{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			products, rowErrors, err := decodeCatalog(entity.CatalogFormatJSON, []byte(tt.data))
+			require.NoError(t, err)
+			assert.Empty(t, rowErrors)
+			assert.Equal(t, tt.want, products)
+		})
+	}
+}
+
+func TestDecodeCatalogJSONRejectsInvalidFiles(t *testing.T) {
+	tests := map[string]struct {
+		data    string
+		wantErr string
+	}{
+		"not an array":      {data: `{"sku": "AM-90"}`, wantErr: "expected an array of products"},
+		"malformed JSON":    {data: `[{"sku": "AM-90"`, wantErr: "expected an array of products"},
+		"wrong field type":  {data: `[{"sku": "AM-90"}, {"price": "cheap"}]`, wantErr: "invalid JSON for product 2"},
+		"variants not list": {data: `[{"sku": "AM-90", "variants": {}}]`, wantErr: "invalid JSON for product 1"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := decodeCatalog(entity.CatalogFormatJSON, []byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestDecodeCatalogRejectsUnsupportedFormats(t *testing.T) {
+	_, _, err := decodeCatalog("xlsx", []byte("sku,name,price\n"))
+	assert.ErrorIs(t, err, ErrUnsupportedCatalogFormat)
+}
+
+func TestCatalogCSVRoundTrip(t *testing.T) {
+	exported := &CatalogProduct{
+		SKU:          "AM-90",
+		Name:         "Air Max 90",
+		Slug:         "air-max-90",
+		Category:     "running",
+		Price:        floatPtr(129.99),
+		ComparePrice: floatPtr(149.99),
+		Tags:         []string{"running", "retro"},
+		Images:       []CatalogImage{{URL: "https://cdn.example.com/am90.jpg"}},
+		IsActive:     boolPtr(true),
+		Variants: []CatalogVariant{
+			{SKU: "AM-90-9", Size: "9", Stock: 4, Images: []string{"https://cdn.example.com/am90-9.jpg"}, IsActive: boolPtr(true)},
+			{SKU: "AM-90-10", Size: "10", Price: floatPtr(139.99), IsActive: boolPtr(false)},
+		},
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	require.NoError(t, writer.Write(catalogCSVColumns))
+	require.NoError(t, writer.WriteAll(catalogCSVRecords(exported)))
+
+	products, rowErrors, err := decodeCatalog(entity.CatalogFormatCSV, buf.Bytes())
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, products, 1)
+
+	imported := products[0]
+	for _, field := range []string{"sku", "description", "weight", "meta_title", "is_active"} {
+		assert.True(t, imported.fields.has(field), "an export provides %s", field)
+	}
+	imported.row, imported.fields = 0, nil
+	for i := range imported.Variants {
+		assert.True(t, imported.Variants[i].fields.has("stock"))
+		imported.Variants[i].row, imported.Variants[i].fields = 0, nil
+	}
+	assert.Equal(t, exported, imported)
+}
+
+func TestCatalogFields(t *testing.T) {
+	var all catalogFields
+	assert.True(t, all.has("description"), "a nil set provides every field")
+
+	some := catalogFields{"sku": true}
+	assert.True(t, some.has("sku"))
+	assert.False(t, some.has("description"))
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+var ErrCategoryCycle = repository.ErrCategoryCycle
+
+// MoveCategoryRequest re-parents a category. A null or empty parent_id moves
+// it to the top level. Position is its index among the new siblings and
+// defaults to the end.
+type MoveCategoryRequest struct {
+	ParentID *string `json:"parent_id"`
+	Position *int    `json:"position"`
+}
+
+// ReorderCategoriesRequest lists every child of a parent, or every top-level
+// category when parent_id is empty, in the new display order
+type ReorderCategoriesRequest struct {
+	ParentID *string     `json:"parent_id"`
+	IDs      []uuid.UUID `json:"ids" binding:"required,min=1"`
+}
+
+// MoveCategory moves a category and its subtree under a new parent. The
+// repository checks the parent is outside the subtree in the same
+// transaction as the move.
+func (s *CategoryService) MoveCategory(ctx context.Context, id uuid.UUID, req *MoveCategoryRequest) (*entity.Category, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	parentID, err := parseParentID(req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	if parentID != nil && *parentID == id {
+		return nil, ErrCategoryCycle
+	}
+
+	siblings, err := s.categoryRepo.GetSiblings(ctx, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sibling categories: %w", err)
+	}
+
+	order := make([]uuid.UUID, 0, len(siblings)+1)
+	for _, sibling := range siblings {
+		if sibling.ID != id {
+			order = append(order, sibling.ID)
+		}
+	}
+	position := len(order)
+	if req.Position != nil {
+		if *req.Position < 0 || *req.Position > len(order) {
+			return nil, fmt.Errorf("position must be between 0 and %d", len(order))
+		}
+		position = *req.Position
+	}
+	order = append(order[:position], append([]uuid.UUID{id}, order[position:]...)...)
+
+	if err := s.categoryRepo.Move(ctx, id, parentID, order); err != nil {
+		if errors.Is(err, ErrCategoryCycle) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to move category: %w", err)
+	}
+
+	return s.GetCategoryByID(ctx, id)
+}
+
+// ReorderCategories sets the display order of a set of sibling categories
+func (s *CategoryService) ReorderCategories(ctx context.Context, req *ReorderCategoriesRequest) ([]*entity.Category, error) {
+	parentID, err := parseParentID(req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	if parentID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, *parentID); err != nil {
+			return nil, errors.New("parent category not found")
+		}
+	}
+
+	siblings, err := s.categoryRepo.GetSiblings(ctx, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sibling categories: %w", err)
+	}
+
+	current := make([]uuid.UUID, len(siblings))
+	for i, sibling := range siblings {
+		current[i] = sibling.ID
+	}
+	if !samePermutation(current, req.IDs) {
+		return nil, errors.New("ids must list each of the parent's categories exactly once")
+	}
+
+	if err := s.categoryRepo.Reorder(ctx, parentID, req.IDs); err != nil {
+		return nil, fmt.Errorf("failed to reorder categories: %w", err)
+	}
+
+	return s.categoryRepo.GetSiblings(ctx, parentID)
+}
+
+// checkParent makes sure parentID exists and is outside the subtree rooted
+// at id, since a category's ancestors include its new parent's ancestors
+func (s *CategoryService) checkParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == id {
+		return ErrCategoryCycle
+	}
+
+	ancestors, err := s.categoryRepo.GetAncestors(ctx, *parentID)
+	if err != nil {
+		return errors.New("parent category not found")
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.ID == id {
+			return ErrCategoryCycle
+		}
+	}
+	return nil
+}
+
+func parseParentID(value *string) (*uuid.UUID, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*value)
+	if err != nil {
+		return nil, errors.New("invalid parent category ID")
+	}
+	return &id, nil
+}
+
+// categoryBreadcrumbs returns the path from the top level down to and
+// including the category
+func categoryBreadcrumbs(ctx context.Context, categoryRepo repository.CategoryRepository, id uuid.UUID) ([]entity.CategoryBreadcrumb, error) {
+	ancestors, err := categoryRepo.GetAncestors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	breadcrumbs := make([]entity.CategoryBreadcrumb, len(ancestors))
+	for i, ancestor := range ancestors {
+		breadcrumbs[i] = entity.CategoryBreadcrumb{
+			ID:   ancestor.ID,
+			Name: ancestor.Name,
+			Slug: ancestor.Slug,
+		}
+	}
+	return breadcrumbs, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+func TestCategoryService_CheckParent(t *testing.T) {
+	ctx := context.Background()
+	root, shoes, running := uuid.New(), uuid.New(), uuid.New()
+	path := []*entity.Category{{ID: root}, {ID: shoes}, {ID: running}}
+
+	tests := map[string]struct {
+		id       uuid.UUID
+		parentID *uuid.UUID
+		wantErr  error
+	}{
+		"top level":                 {id: shoes},
+		"unrelated parent":          {id: uuid.New(), parentID: &running},
+		"itself":                    {id: shoes, parentID: &shoes, wantErr: ErrCategoryCycle},
+		"its own child":             {id: shoes, parentID: &running, wantErr: ErrCategoryCycle},
+		"a descendant further down": {id: root, parentID: &running, wantErr: ErrCategoryCycle},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			categories := new(MockCategoryRepository)
+			categories.On("GetAncestors", ctx, running).Return(path, nil)
+			service := NewCategoryService(categories, nil)
+
+			err := service.checkParent(ctx, tt.id, tt.parentID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("missing parent", func(t *testing.T) {
+		categories := new(MockCategoryRepository)
+		missing := uuid.New()
+		categories.On("GetAncestors", ctx, missing).Return([]*entity.Category(nil), errors.New("category not found"))
+		service := NewCategoryService(categories, nil)
+
+		err := service.checkParent(ctx, shoes, &missing)
+
+		assert.EqualError(t, err, "parent category not found")
+	})
+}
+
+func TestCategoryService_MoveCategory(t *testing.T) {
+	ctx := context.Background()
+	id, parentID := uuid.New(), uuid.New()
+	first, second := uuid.New(), uuid.New()
+	parent := parentID.String()
+
+	// expectMove sets up a move of id among first and second under parentID
+	expectMove := func(categories *MockCategoryRepository, order []uuid.UUID) {
+		categories.On("GetByID", ctx, id).Return(&entity.Category{ID: id, ParentID: &parentID}, nil)
+		categories.On("GetSiblings", ctx, &parentID).Return([]*entity.Category{{ID: first}, {ID: id}, {ID: second}}, nil)
+		categories.On("Move", ctx, id, &parentID, order).Return(nil)
+		categories.On("GetAncestors", ctx, id).Return([]*entity.Category{{ID: parentID}, {ID: id}}, nil)
+	}
+
+	tests := map[string]struct {
+		position *int
+		order    []uuid.UUID
+	}{
+		"defaults to the end": {order: []uuid.UUID{first, second, id}},
+		"to the front":        {position: intPtr(0), order: []uuid.UUID{id, first, second}},
+		"between siblings":    {position: intPtr(1), order: []uuid.UUID{first, id, second}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			categories := new(MockCategoryRepository)
+			expectMove(categories, tt.order)
+			service := NewCategoryService(categories, nil)
+
+			category, err := service.MoveCategory(ctx, id, &MoveCategoryRequest{ParentID: &parent, Position: tt.position})
+
+			require.NoError(t, err)
+			assert.Len(t, category.Breadcrumbs, 2)
+			categories.AssertExpectations(t)
+		})
+	}
+
+	t.Run("position past the end", func(t *testing.T) {
+		categories := new(MockCategoryRepository)
+		categories.On("GetByID", ctx, id).Return(&entity.Category{ID: id}, nil)
+		categories.On("GetSiblings", ctx, &parentID).Return([]*entity.Category{{ID: first}, {ID: second}}, nil)
+		service := NewCategoryService(categories, nil)
+
+		_, err := service.MoveCategory(ctx, id, &MoveCategoryRequest{ParentID: &parent, Position: intPtr(3)})
+
+		assert.EqualError(t, err, "position must be between 0 and 2")
+		categories.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("under itself", func(t *testing.T) {
+		categories := new(MockCategoryRepository)
+		categories.On("GetByID", ctx, id).Return(&entity.Category{ID: id}, nil)
+		service := NewCategoryService(categories, nil)
+		self := id.String()
+
+		_, err := service.MoveCategory(ctx, id, &MoveCategoryRequest{ParentID: &self})
+
+		assert.ErrorIs(t, err, ErrCategoryCycle)
+		categories.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("cycle found by the move is returned as is", func(t *testing.T) {
+		categories := new(MockCategoryRepository)
+		categories.On("GetByID", ctx, id).Return(&entity.Category{ID: id}, nil)
+		categories.On("GetSiblings", ctx, &parentID).Return([]*entity.Category{}, nil)
+		categories.On("Move", ctx, id, &parentID, []uuid.UUID{id}).Return(ErrCategoryCycle)
+		service := NewCategoryService(categories, nil)
+
+		_, err := service.MoveCategory(ctx, id, &MoveCategoryRequest{ParentID: &parent})
+
+		assert.Equal(t, ErrCategoryCycle, err)
+	})
+}
@@ -12,12 +12,14 @@ import (
 )
 
 type CategoryService struct {
-	categoryRepo repository.CategoryRepository
+	categoryRepo  repository.CategoryRepository
+	attributeRepo repository.AttributeRepository
 }
 
-func NewCategoryService(categoryRepo repository.CategoryRepository) *CategoryService {
+func NewCategoryService(categoryRepo repository.CategoryRepository, attributeRepo repository.AttributeRepository) *CategoryService {
 	return &CategoryService{
-		categoryRepo: categoryRepo,
+		categoryRepo:  categoryRepo,
+		attributeRepo: attributeRepo,
 	}
 }
 
@@ -83,11 +85,28 @@ func (s *CategoryService) CreateCategory(ctx context.Context, req *CreateCategor
 }
 
 func (s *CategoryService) GetCategoryByID(ctx context.Context, id uuid.UUID) (*entity.Category, error) {
-	return s.categoryRepo.GetByID(ctx, id)
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withBreadcrumbs(ctx, category)
 }
 
 func (s *CategoryService) GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error) {
-	return s.categoryRepo.GetBySlug(ctx, slug)
+	category, err := s.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.withBreadcrumbs(ctx, category)
+}
+
+func (s *CategoryService) withBreadcrumbs(ctx context.Context, category *entity.Category) (*entity.Category, error) {
+	breadcrumbs, err := categoryBreadcrumbs(ctx, s.categoryRepo, category.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category breadcrumbs: %w", err)
+	}
+	category.Breadcrumbs = breadcrumbs
+	return category, nil
 }
 
 func (s *CategoryService) UpdateCategory(ctx context.Context, id uuid.UUID, req *UpdateCategoryRequest) (*entity.Category, error) {
@@ -132,12 +151,8 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, id uuid.UUID, req
 			if err != nil {
 				return nil, errors.New("invalid parent category ID")
 			}
-			if parentUUID == id {
-				return nil, errors.New("category cannot be its own parent")
-			}
-			parent, err := s.categoryRepo.GetByID(ctx, parentUUID)
-			if err != nil || parent == nil {
-				return nil, errors.New("parent category not found")
+			if err := s.checkParent(ctx, id, &parentUUID); err != nil {
+				return nil, err
 			}
 			category.ParentID = &parentUUID
 		}
@@ -148,7 +163,7 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, id uuid.UUID, req
 		return nil, fmt.Errorf("failed to update category: %w", err)
 	}
 
-	return s.categoryRepo.GetByID(ctx, category.ID)
+	return s.GetCategoryByID(ctx, category.ID)
 }
 
 func (s *CategoryService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+
+	"github.com/google/uuid"
+	"solemate/pkg/imaging"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// UploadedImage holds the URLs of an uploaded image's renditions, as JPEG
+// and as WebP
+type UploadedImage struct {
+	ThumbnailURL     string `json:"thumbnail_url"`
+	CardURL          string `json:"card_url"`
+	ZoomURL          string `json:"zoom_url"`
+	ThumbnailWebPURL string `json:"thumbnail_webp_url"`
+	CardWebPURL      string `json:"card_webp_url"`
+	ZoomWebPURL      string `json:"zoom_webp_url"`
+}
+
+// ImageUploader turns uploaded files into stored renditions and removes them again
+type ImageUploader struct {
+	storage repository.ImageStorage
+}
+
+func NewImageUploader(storage repository.ImageStorage) *ImageUploader {
+	return &ImageUploader{
+		storage: storage,
+	}
+}
+
+// Upload validates data, strips its metadata and stores each rendition under
+// a new directory below prefix
+func (u *ImageUploader) Upload(ctx context.Context, prefix string, data []byte) (*UploadedImage, error) {
+	renditions, err := imaging.Process(data)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := path.Join(prefix, uuid.New().String())
+	uploaded := &UploadedImage{}
+
+	for _, rendition := range renditions {
+		url, err := u.storage.Put(ctx, path.Join(dir, imaging.Filename(rendition.Name, rendition.ContentType)), rendition.Data, rendition.ContentType)
+		if err != nil {
+			u.removeDir(ctx, dir)
+			return nil, fmt.Errorf("failed to store image: %w", err)
+		}
+
+		webp := rendition.ContentType == imaging.WebP
+		switch {
+		case rendition.Name == imaging.Thumbnail && webp:
+			uploaded.ThumbnailWebPURL = url
+		case rendition.Name == imaging.Thumbnail:
+			uploaded.ThumbnailURL = url
+		case rendition.Name == imaging.Card && webp:
+			uploaded.CardWebPURL = url
+		case rendition.Name == imaging.Card:
+			uploaded.CardURL = url
+		case rendition.Name == imaging.Zoom && webp:
+			uploaded.ZoomWebPURL = url
+		case rendition.Name == imaging.Zoom:
+			uploaded.ZoomURL = url
+		}
+	}
+
+	return uploaded, nil
+}
+
+// Remove deletes every rendition of the uploads the URLs point at. URLs of
+// images hosted elsewhere are ignored. Failures are logged rather than
+// returned, since the record that referenced the image is already gone.
+func (u *ImageUploader) Remove(ctx context.Context, urls ...string) {
+	for _, url := range urls {
+		key, ok := u.storage.KeyForURL(url)
+		if !ok || !isRenditionFile(path.Base(key)) {
+			continue
+		}
+		u.removeDir(ctx, path.Dir(key))
+	}
+}
+
+func isRenditionFile(name string) bool {
+	for _, rendition := range imaging.Renditions {
+		for _, contentType := range imaging.Formats {
+			if name == imaging.Filename(rendition.Name, contentType) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RemoveUnused removes the uploads in before that are no longer in after
+func (u *ImageUploader) RemoveUnused(ctx context.Context, before, after []string) {
+	kept := make(map[string]bool, len(after))
+	for _, url := range after {
+		kept[url] = true
+	}
+
+	for _, url := range before {
+		if !kept[url] {
+			u.Remove(ctx, url)
+		}
+	}
+}
+
+func (u *ImageUploader) removeDir(ctx context.Context, dir string) {
+	for _, rendition := range imaging.Renditions {
+		for _, contentType := range imaging.Formats {
+			key := path.Join(dir, imaging.Filename(rendition.Name, contentType))
+			if err := u.storage.Delete(ctx, key); err != nil {
+				log.Printf("failed to delete stored image %s: %v", key, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+const (
+	// maxPopularityProducts caps how many products' sales are read from
+	// order-service per window
+	maxPopularityProducts = 10000
+
+	// popularityHalfLifeDays is how many days it takes for a sale or wishlist
+	// add to count half as much
+	popularityHalfLifeDays = 14.0
+
+	// unitsSoldDays is the window of the units sold behind the best selling
+	// sort
+	unitsSoldDays = 30
+)
+
+// popularityWindows are the periods, in days ago, that sales and wishlist
+// adds are counted over. Each window is weighed by the decay at its middle,
+// which is close enough to decaying every order and avoids asking other
+// services for per-day counts.
+var popularityWindows = []struct{ from, to int }{
+	{0, 7},
+	{7, 14},
+	{14, 30},
+	{30, 60},
+	{60, 90},
+}
+
+// popularityWeights favours what shoppers buy over what they save or look at
+var popularityWeights = repository.PopularityWeights{
+	Sales:            3,
+	WishlistAdds:     2,
+	Views:            1,
+	Rating:           0.5,
+	ViewDays:         90,
+	ViewHalfLifeDays: popularityHalfLifeDays,
+	RatingPrior:      5,
+}
+
+type PopularityService struct {
+	productRepo repository.ProductRepository
+	orders      repository.OrderRepository
+	wishlists   repository.WishlistRepository
+}
+
+func NewPopularityService(
+	productRepo repository.ProductRepository,
+	orders repository.OrderRepository,
+	wishlists repository.WishlistRepository,
+) *PopularityService {
+	return &PopularityService{
+		productRepo: productRepo,
+		orders:      orders,
+		wishlists:   wishlists,
+	}
+}
+
+// RunPopularityRefresh recomputes the products' popularity scores and units
+// sold every interval until ctx is cancelled
+func (s *PopularityService) RunPopularityRefresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.refreshPopularity(ctx); err != nil {
+			log.Printf("popularity refresh: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshPopularity leaves the scores as they are when sales or wishlist adds
+// cannot be read, rather than ranking every product as if nothing sold
+func (s *PopularityService) refreshPopularity(ctx context.Context) error {
+	tomorrow := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+
+	byProduct := make(map[uuid.UUID]*repository.PopularitySignals)
+	signalsFor := func(productID uuid.UUID) *repository.PopularitySignals {
+		signals, ok := byProduct[productID]
+		if !ok {
+			signals = &repository.PopularitySignals{ProductID: productID}
+			byProduct[productID] = signals
+		}
+		return signals
+	}
+
+	for _, window := range popularityWindows {
+		since := tomorrow.AddDate(0, 0, -window.to)
+		until := tomorrow.AddDate(0, 0, -window.from)
+		decay := math.Pow(0.5, float64(window.from+window.to)/2/popularityHalfLifeDays)
+
+		sales, err := s.orders.GetTopProducts(ctx, since, until, maxPopularityProducts)
+		if err != nil {
+			return fmt.Errorf("failed to get sales: %w", err)
+		}
+		for _, sale := range sales {
+			signals := signalsFor(sale.ProductID)
+			signals.Sales += float64(sale.TotalQuantity) * decay
+			if window.to <= unitsSoldDays {
+				signals.UnitsSold += sale.TotalQuantity
+			}
+		}
+
+		adds, err := s.wishlists.GetWishlistAdds(ctx, since, until)
+		if err != nil {
+			return fmt.Errorf("failed to get wishlist adds: %w", err)
+		}
+		for _, add := range adds {
+			signalsFor(add.ProductID).WishlistAdds += float64(add.Adds) * decay
+		}
+	}
+
+	signals := make([]*repository.PopularitySignals, 0, len(byProduct))
+	for _, productSignals := range byProduct {
+		signals = append(signals, productSignals)
+	}
+
+	updated, err := s.productRepo.UpdatePopularity(ctx, signals, popularityWeights)
+	if err != nil {
+		return fmt.Errorf("failed to update popularity: %w", err)
+	}
+
+	log.Printf("popularity refresh: updated %d products from %d with sales or wishlist adds", updated, len(signals))
+	return nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// MockOrderRepository is a mock implementation of repository.OrderRepository
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepository) GetPurchases(ctx context.Context, userID, productID uuid.UUID) ([]repository.PurchasedOrder, error) {
+	args := m.Called(ctx, userID, productID)
+	return args.Get(0).([]repository.PurchasedOrder), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetCoPurchases(ctx context.Context, since, until time.Time, minOrders, limit int) ([]repository.ProductPair, error) {
+	args := m.Called(ctx, since, until, minOrders, limit)
+	return args.Get(0).([]repository.ProductPair), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetTopProducts(ctx context.Context, since, until time.Time, limit int) ([]repository.ProductSales, error) {
+	args := m.Called(ctx, since, until, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.ProductSales), args.Error(1)
+}
+
+// MockWishlistRepository is a mock implementation of repository.WishlistRepository
+type MockWishlistRepository struct {
+	mock.Mock
+}
+
+func (m *MockWishlistRepository) GetWishlistAdds(ctx context.Context, since, until time.Time) ([]repository.ProductWishlistAdds, error) {
+	args := m.Called(ctx, since, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.ProductWishlistAdds), args.Error(1)
+}
+
+// popularityWindow returns the bounds refreshPopularity asks for the window
+// of days from to to days ago
+func popularityWindow(from, to int) (since, until time.Time) {
+	tomorrow := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	return tomorrow.AddDate(0, 0, -to), tomorrow.AddDate(0, 0, -from)
+}
+
+// expectPopularityWindows makes every window return no sales and no adds
+// unless sales or adds are given for it
+func expectPopularityWindows(orders *MockOrderRepository, wishlists *MockWishlistRepository,
+	sales map[int][]repository.ProductSales, adds map[int][]repository.ProductWishlistAdds) {
+	for _, window := range popularityWindows {
+		since, until := popularityWindow(window.from, window.to)
+		windowSales := sales[window.from]
+		if windowSales == nil {
+			windowSales = []repository.ProductSales{}
+		}
+		windowAdds := adds[window.from]
+		if windowAdds == nil {
+			windowAdds = []repository.ProductWishlistAdds{}
+		}
+		orders.On("GetTopProducts", mock.Anything, since, until, maxPopularityProducts).Return(windowSales, nil)
+		wishlists.On("GetWishlistAdds", mock.Anything, since, until).Return(windowAdds, nil)
+	}
+}
+
+func TestPopularityService_RefreshPopularity(t *testing.T) {
+	ctx := context.Background()
+	products := new(MockProductRepository)
+	orders := new(MockOrderRepository)
+	wishlists := new(MockWishlistRepository)
+	service := NewPopularityService(products, orders, wishlists)
+
+	recent, steady, saved := uuid.New(), uuid.New(), uuid.New()
+	expectPopularityWindows(orders, wishlists,
+		map[int][]repository.ProductSales{
+			0:  {{ProductID: recent, TotalQuantity: 10}},
+			14: {{ProductID: steady, TotalQuantity: 2}},
+			30: {{ProductID: recent, TotalQuantity: 4}},
+		},
+		map[int][]repository.ProductWishlistAdds{
+			7: {{ProductID: saved, Adds: 3}, {ProductID: recent, Adds: 1}},
+		},
+	)
+
+	var signals map[uuid.UUID]*repository.PopularitySignals
+	products.On("UpdatePopularity", ctx, mock.Anything, popularityWeights).Run(func(args mock.Arguments) {
+		signals = make(map[uuid.UUID]*repository.PopularitySignals)
+		for _, s := range args.Get(1).([]*repository.PopularitySignals) {
+			signals[s.ProductID] = s
+		}
+	}).Return(int64(100), nil)
+
+	require.NoError(t, service.refreshPopularity(ctx))
+	require.Len(t, signals, 3)
+
+	// Each window is weighed by the decay at its middle
+	decay := func(from, to int) float64 { return math.Pow(0.5, float64(from+to)/2/popularityHalfLifeDays) }
+
+	assert.InDelta(t, 10*decay(0, 7)+4*decay(30, 60), signals[recent].Sales, 1e-9)
+	assert.InDelta(t, 1*decay(7, 14), signals[recent].WishlistAdds, 1e-9)
+	assert.Equal(t, 10, signals[recent].UnitsSold, "sales older than 30 days are not counted as units sold")
+
+	assert.InDelta(t, 2*decay(14, 30), signals[steady].Sales, 1e-9)
+	assert.Equal(t, 2, signals[steady].UnitsSold)
+
+	assert.Zero(t, signals[saved].Sales)
+	assert.Zero(t, signals[saved].UnitsSold)
+	assert.InDelta(t, 3*decay(7, 14), signals[saved].WishlistAdds, 1e-9)
+
+	orders.AssertExpectations(t)
+	wishlists.AssertExpectations(t)
+}
+
+func TestPopularityService_RefreshPopularityKeepsScoresOnFailure(t *testing.T) {
+	ctx := context.Background()
+	since, until := popularityWindow(popularityWindows[0].from, popularityWindows[0].to)
+
+	t.Run("sales unavailable", func(t *testing.T) {
+		products := new(MockProductRepository)
+		orders := new(MockOrderRepository)
+		service := NewPopularityService(products, orders, new(MockWishlistRepository))
+
+		orders.On("GetTopProducts", ctx, since, until, maxPopularityProducts).Return(nil, errors.New("order-service unavailable"))
+
+		assert.Error(t, service.refreshPopularity(ctx))
+		products.AssertNotCalled(t, "UpdatePopularity", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("wishlist adds unavailable", func(t *testing.T) {
+		products := new(MockProductRepository)
+		orders := new(MockOrderRepository)
+		wishlists := new(MockWishlistRepository)
+		service := NewPopularityService(products, orders, wishlists)
+
+		orders.On("GetTopProducts", ctx, since, until, maxPopularityProducts).Return([]repository.ProductSales{}, nil)
+		wishlists.On("GetWishlistAdds", ctx, since, until).Return(nil, errors.New("user-service unavailable"))
+
+		assert.Error(t, service.refreshPopularity(ctx))
+		products.AssertNotCalled(t, "UpdatePopularity", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/pkg/productalerts"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+const (
+	// priceSchedulerBatchSize is how many due schedules are started or ended per tick
+	priceSchedulerBatchSize = 100
+
+	// defaultPriceHistoryDays is the window for price timelines and the
+	// lowest price, as used for "lowest price in the last 30 days" labels
+	defaultPriceHistoryDays = 30
+)
+
+var ErrPriceScheduleNotFound = errors.New("price schedule not found")
+
+type CreatePriceScheduleRequest struct {
+	VariantID    *uuid.UUID `json:"variant_id"`
+	Price        float64    `json:"price" binding:"min=0"`
+	ComparePrice *float64   `json:"compare_price" binding:"omitempty,min=0"`
+	StartsAt     time.Time  `json:"starts_at" binding:"required"`
+	EndsAt       *time.Time `json:"ends_at"`
+}
+
+// PriceTimeline is the price history of a product or variant over a window
+type PriceTimeline struct {
+	ProductID   uuid.UUID              `json:"product_id"`
+	VariantID   *uuid.UUID             `json:"variant_id,omitempty"`
+	Since       time.Time              `json:"since"`
+	Entries     []*entity.PriceHistory `json:"entries"`
+	LowestPrice *float64               `json:"lowest_price"`
+}
+
+// ListPriceSchedules returns all of a product's price schedules, latest first
+func (s *ProductService) ListPriceSchedules(ctx context.Context, productID uuid.UUID) ([]*entity.PriceSchedule, error) {
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.priceSchedules.GetByProductID(ctx, productID)
+}
+
+// CreatePriceSchedule plans a price for a product or one of its variants.
+// Windows for the same product or variant cannot overlap. A schedule whose
+// start has already passed is applied right away.
+func (s *ProductService) CreatePriceSchedule(ctx context.Context, productID uuid.UUID, req *CreatePriceScheduleRequest) (*entity.PriceSchedule, error) {
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	if req.VariantID != nil {
+		if _, err := s.productVariant(ctx, productID, *req.VariantID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Price < 0 || (req.ComparePrice != nil && *req.ComparePrice < 0) {
+		return nil, errors.New("price must be non-negative")
+	}
+
+	if req.EndsAt != nil {
+		if !req.EndsAt.After(req.StartsAt) {
+			return nil, errors.New("ends_at must be after starts_at")
+		}
+		if !req.EndsAt.After(time.Now()) {
+			return nil, errors.New("ends_at must be in the future")
+		}
+	}
+
+	overlapping, err := s.priceSchedules.Overlapping(ctx, productID, req.VariantID, req.StartsAt, req.EndsAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing schedules: %w", err)
+	}
+	if len(overlapping) > 0 {
+		return nil, fmt.Errorf("overlaps price schedule %s", overlapping[0].ID)
+	}
+
+	schedule := &entity.PriceSchedule{
+		ProductID:    productID,
+		VariantID:    req.VariantID,
+		Price:        req.Price,
+		ComparePrice: req.ComparePrice,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		Status:       entity.PriceScheduleScheduled,
+	}
+
+	if err := s.priceSchedules.Create(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to create price schedule: %w", err)
+	}
+
+	if !schedule.StartsAt.After(time.Now()) {
+		if err := s.startPriceSchedule(ctx, schedule); err != nil {
+			return nil, err
+		}
+	}
+
+	return schedule, nil
+}
+
+// CancelPriceSchedule cancels a schedule. A schedule that is already active
+// is ended first, restoring the previous price.
+func (s *ProductService) CancelPriceSchedule(ctx context.Context, productID, scheduleID uuid.UUID) (*entity.PriceSchedule, error) {
+	schedule, err := s.priceSchedules.GetByID(ctx, scheduleID)
+	if err != nil || schedule.ProductID != productID {
+		return nil, ErrPriceScheduleNotFound
+	}
+
+	switch schedule.Status {
+	case entity.PriceScheduleScheduled:
+		claimed, err := s.priceSchedules.Transition(ctx, schedule.ID, entity.PriceScheduleScheduled, entity.PriceScheduleCancelled)
+		if err != nil {
+			return nil, fmt.Errorf("failed to cancel price schedule: %w", err)
+		}
+		if !claimed {
+			return nil, errors.New("price schedule started while being cancelled, try again")
+		}
+		schedule.Status = entity.PriceScheduleCancelled
+	case entity.PriceScheduleActive:
+		if err := s.endPriceSchedule(ctx, schedule, entity.PriceScheduleCancelled); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("price schedule is already %s", schedule.Status)
+	}
+
+	return schedule, nil
+}
+
+// GetPriceTimeline returns the price changes of a product, or of one of its
+// variants, over the last days along with the lowest price in that window
+func (s *ProductService) GetPriceTimeline(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, days int) (*PriceTimeline, error) {
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	if variantID != nil {
+		if _, err := s.productVariant(ctx, productID, *variantID); err != nil {
+			return nil, err
+		}
+	}
+
+	if days <= 0 {
+		days = defaultPriceHistoryDays
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	entries, err := s.priceHistory.GetTimeline(ctx, productID, variantID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history: %w", err)
+	}
+
+	lowest, err := s.priceHistory.LowestPrice(ctx, productID, variantID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lowest price: %w", err)
+	}
+
+	return &PriceTimeline{
+		ProductID:   productID,
+		VariantID:   variantID,
+		Since:       since,
+		Entries:     entries,
+		LowestPrice: lowest,
+	}, nil
+}
+
+// RunPriceScheduler starts and ends due price schedules every interval until
+// ctx is cancelled. Several instances can run at once; each schedule is only
+// applied by the instance that claims it.
+func (s *ProductService) RunPriceScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.applyDuePriceSchedules(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ProductService) applyDuePriceSchedules(ctx context.Context) {
+	now := time.Now()
+
+	// End sales before starting the next ones, so back-to-back windows for
+	// the same product restore and apply prices in order
+	ending, err := s.priceSchedules.DueToEnd(ctx, now, priceSchedulerBatchSize)
+	if err != nil {
+		log.Printf("price scheduler: failed to get schedules to end: %v", err)
+	}
+	for _, schedule := range ending {
+		if err := s.endPriceSchedule(ctx, schedule, entity.PriceScheduleEnded); err != nil {
+			log.Printf("price schedule %s: %v", schedule.ID, err)
+		}
+	}
+
+	// A window that passed while the scheduler was down is never applied
+	if expired, err := s.priceSchedules.ExpireUnstarted(ctx, now); err != nil {
+		log.Printf("price scheduler: failed to expire schedules: %v", err)
+	} else if expired > 0 {
+		log.Printf("price scheduler: ended %d schedules whose window passed before they started", expired)
+	}
+
+	starting, err := s.priceSchedules.DueToStart(ctx, now, priceSchedulerBatchSize)
+	if err != nil {
+		log.Printf("price scheduler: failed to get schedules to start: %v", err)
+	}
+	for _, schedule := range starting {
+		if err := s.startPriceSchedule(ctx, schedule); err != nil {
+			log.Printf("price schedule %s: %v", schedule.ID, err)
+		}
+	}
+}
+
+// startPriceSchedule applies a schedule's prices, keeping the current ones
+// so they can be restored when it ends
+func (s *ProductService) startPriceSchedule(ctx context.Context, schedule *entity.PriceSchedule) error {
+	claimed, err := s.priceSchedules.Transition(ctx, schedule.ID, entity.PriceScheduleScheduled, entity.PriceScheduleActive)
+	if err != nil {
+		return fmt.Errorf("failed to claim price schedule: %w", err)
+	}
+	if !claimed {
+		return nil
+	}
+	schedule.Status = entity.PriceScheduleActive
+
+	price := schedule.Price
+	previousPrice, previousComparePrice, err := s.setScheduledPrice(ctx, schedule, &price, schedule.ComparePrice)
+	if err != nil {
+		// Let the next run try again
+		if _, revertErr := s.priceSchedules.Transition(ctx, schedule.ID, entity.PriceScheduleActive, entity.PriceScheduleScheduled); revertErr != nil {
+			log.Printf("price schedule %s: failed to release: %v", schedule.ID, revertErr)
+		}
+		schedule.Status = entity.PriceScheduleScheduled
+		return err
+	}
+
+	now := time.Now()
+	schedule.PreviousPrice = previousPrice
+	schedule.PreviousComparePrice = previousComparePrice
+	schedule.AppliedAt = &now
+	if schedule.EndsAt == nil {
+		// A permanent change has nothing to restore
+		schedule.Status = entity.PriceScheduleEnded
+		schedule.EndedAt = &now
+	}
+	if err := s.priceSchedules.Update(ctx, schedule); err != nil {
+		return fmt.Errorf("failed to save price schedule: %w", err)
+	}
+
+	return nil
+}
+
+// endPriceSchedule restores the prices a schedule replaced. If the price
+// was changed by hand during the window, that change is kept.
+func (s *ProductService) endPriceSchedule(ctx context.Context, schedule *entity.PriceSchedule, status string) error {
+	claimed, err := s.priceSchedules.Transition(ctx, schedule.ID, entity.PriceScheduleActive, status)
+	if err != nil {
+		return fmt.Errorf("failed to claim price schedule: %w", err)
+	}
+	if !claimed {
+		return nil
+	}
+	schedule.Status = status
+
+	current, err := s.currentPrice(ctx, schedule)
+	if err == nil && current != nil && *current == schedule.Price {
+		_, _, err = s.setScheduledPrice(ctx, schedule, schedule.PreviousPrice, schedule.PreviousComparePrice)
+	}
+	if err != nil {
+		// Let the next run try again
+		if _, revertErr := s.priceSchedules.Transition(ctx, schedule.ID, status, entity.PriceScheduleActive); revertErr != nil {
+			log.Printf("price schedule %s: failed to release: %v", schedule.ID, revertErr)
+		}
+		schedule.Status = entity.PriceScheduleActive
+		return err
+	}
+
+	now := time.Now()
+	schedule.EndedAt = &now
+	if err := s.priceSchedules.Update(ctx, schedule); err != nil {
+		return fmt.Errorf("failed to save price schedule: %w", err)
+	}
+
+	return nil
+}
+
+// currentPrice returns the price of a schedule's product or variant
+func (s *ProductService) currentPrice(ctx context.Context, schedule *entity.PriceSchedule) (*float64, error) {
+	if schedule.VariantID != nil {
+		variant, err := s.variantRepo.GetByID(ctx, *schedule.VariantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get variant: %w", err)
+		}
+		return variant.Price, nil
+	}
+
+	product, err := s.productRepo.GetByID(ctx, schedule.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	return &product.Price, nil
+}
+
+// setScheduledPrice sets the price of a schedule's product or variant and
+// returns the prices it replaced. Variants have no compare price of their
+// own, so comparePrice only applies to products.
+func (s *ProductService) setScheduledPrice(ctx context.Context, schedule *entity.PriceSchedule, price, comparePrice *float64) (*float64, *float64, error) {
+	if schedule.VariantID != nil {
+		variant, err := s.variantRepo.GetByID(ctx, *schedule.VariantID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get variant: %w", err)
+		}
+
+		previous := variant.Price
+		variant.Price = price
+		if err := s.variantRepo.Update(ctx, variant); err != nil {
+			return nil, nil, fmt.Errorf("failed to update variant price: %w", err)
+		}
+		s.recordVariantPrice(ctx, variant, entity.PriceSourceSchedule, &schedule.ID)
+		return previous, nil, nil
+	}
+
+	product, err := s.productRepo.GetByID(ctx, schedule.ProductID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if price == nil {
+		return nil, nil, errors.New("product price cannot be empty")
+	}
+
+	previous, previousCompare := product.Price, product.ComparePrice
+	product.Price = *price
+	product.ComparePrice = comparePrice
+	product.Category, product.Brand, product.Variants, product.Images = nil, nil, nil, nil
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		return nil, nil, fmt.Errorf("failed to update product price: %w", err)
+	}
+	s.recordProductPrice(ctx, product, entity.PriceSourceSchedule, &schedule.ID)
+
+	if product.Price < previous && s.alerts != nil {
+		event := productalerts.PriceDrop(product.ID, product.Name, previous, product.Price)
+		if err := s.alerts.Notify(ctx, event); err != nil {
+			log.Printf("product %s: failed to send price drop alert: %v", product.ID, err)
+		}
+	}
+
+	return &previous, previousCompare, nil
+}
+
+// recordProductPrice adds a product's current price to its price history.
+// The price has already been saved, so a failure is only logged.
+func (s *ProductService) recordProductPrice(ctx context.Context, product *entity.Product, source string, scheduleID *uuid.UUID) {
+	price := product.Price
+	entry := &entity.PriceHistory{
+		ProductID:    product.ID,
+		Price:        &price,
+		ComparePrice: product.ComparePrice,
+		Source:       source,
+		ScheduleID:   scheduleID,
+	}
+	if err := s.priceHistory.Create(ctx, entry); err != nil {
+		log.Printf("product %s: failed to record price history: %v", product.ID, err)
+	}
+}
+
+// recordVariantPrice adds a variant's current price to its price history
+func (s *ProductService) recordVariantPrice(ctx context.Context, variant *entity.ProductVariant, source string, scheduleID *uuid.UUID) {
+	entry := &entity.PriceHistory{
+		ProductID:  variant.ProductID,
+		VariantID:  &variant.ID,
+		Price:      variant.Price,
+		Source:     source,
+		ScheduleID: scheduleID,
+	}
+	if err := s.priceHistory.Create(ctx, entry); err != nil {
+		log.Printf("variant %s: failed to record price history: %v", variant.ID, err)
+	}
+}
+
+// samePrice reports whether two optional prices are equal
+func samePrice(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
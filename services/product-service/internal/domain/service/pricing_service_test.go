@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+// MockPriceScheduleRepository is a mock implementation of repository.PriceScheduleRepository
+type MockPriceScheduleRepository struct {
+	mock.Mock
+}
+
+func (m *MockPriceScheduleRepository) Create(ctx context.Context, schedule *entity.PriceSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockPriceScheduleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.PriceSchedule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PriceSchedule), args.Error(1)
+}
+
+func (m *MockPriceScheduleRepository) Update(ctx context.Context, schedule *entity.PriceSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockPriceScheduleRepository) GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entity.PriceSchedule, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]*entity.PriceSchedule), args.Error(1)
+}
+
+func (m *MockPriceScheduleRepository) Transition(ctx context.Context, id uuid.UUID, from, to string) (bool, error) {
+	args := m.Called(ctx, id, from, to)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPriceScheduleRepository) DueToStart(ctx context.Context, now time.Time, limit int) ([]*entity.PriceSchedule, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]*entity.PriceSchedule), args.Error(1)
+}
+
+func (m *MockPriceScheduleRepository) DueToEnd(ctx context.Context, now time.Time, limit int) ([]*entity.PriceSchedule, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]*entity.PriceSchedule), args.Error(1)
+}
+
+func (m *MockPriceScheduleRepository) ExpireUnstarted(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPriceScheduleRepository) Overlapping(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, start time.Time, end *time.Time) ([]*entity.PriceSchedule, error) {
+	args := m.Called(ctx, productID, variantID, start, end)
+	return args.Get(0).([]*entity.PriceSchedule), args.Error(1)
+}
+
+// expectDueSchedules sets up one scheduler run that ends and starts the given schedules
+func expectDueSchedules(mocks *productServiceMocks, ending, starting []*entity.PriceSchedule) {
+	anyTime := mock.AnythingOfType("time.Time")
+	mocks.priceSchedules.On("DueToEnd", mock.Anything, anyTime, priceSchedulerBatchSize).Return(ending, nil)
+	mocks.priceSchedules.On("ExpireUnstarted", mock.Anything, anyTime).Return(int64(0), nil)
+	mocks.priceSchedules.On("DueToStart", mock.Anything, anyTime, priceSchedulerBatchSize).Return(starting, nil)
+}
+
+func TestProductService_ApplyDuePriceSchedules(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	endsAt := time.Now().Add(24 * time.Hour)
+
+	t.Run("start applies the sale price and keeps the previous one", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		schedule := &entity.PriceSchedule{ID: uuid.New(), ProductID: productID, Price: 79.99, Status: entity.PriceScheduleScheduled, EndsAt: &endsAt}
+		expectDueSchedules(mocks, nil, []*entity.PriceSchedule{schedule})
+
+		mocks.priceSchedules.On("Transition", ctx, schedule.ID, entity.PriceScheduleScheduled, entity.PriceScheduleActive).Return(true, nil)
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID, Name: "Runner", Price: 99.99, ComparePrice: floatPtr(120)}, nil)
+		mocks.products.On("Update", ctx, mock.MatchedBy(func(p *entity.Product) bool {
+			return p.Price == 79.99 && p.ComparePrice == nil
+		})).Return(nil)
+		mocks.priceHistory.On("Create", ctx, mock.MatchedBy(func(entry *entity.PriceHistory) bool {
+			return *entry.Price == 79.99 && entry.Source == entity.PriceSourceSchedule && *entry.ScheduleID == schedule.ID
+		})).Return(nil)
+		mocks.alerts.On("Notify", ctx, mock.Anything).Return(nil)
+		mocks.priceSchedules.On("Update", ctx, mock.MatchedBy(func(s *entity.PriceSchedule) bool {
+			return s.Status == entity.PriceScheduleActive &&
+				*s.PreviousPrice == 99.99 && *s.PreviousComparePrice == 120 &&
+				s.AppliedAt != nil && s.EndedAt == nil
+		})).Return(nil)
+
+		service.applyDuePriceSchedules(ctx)
+
+		mocks.priceSchedules.AssertExpectations(t)
+		mocks.products.AssertExpectations(t)
+		mocks.alerts.AssertNumberOfCalls(t, "Notify", 1)
+	})
+
+	t.Run("permanent change ends once applied", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		variantID := uuid.New()
+		schedule := &entity.PriceSchedule{ID: uuid.New(), ProductID: productID, VariantID: &variantID, Price: 89.99, Status: entity.PriceScheduleScheduled}
+		expectDueSchedules(mocks, nil, []*entity.PriceSchedule{schedule})
+
+		mocks.priceSchedules.On("Transition", ctx, schedule.ID, entity.PriceScheduleScheduled, entity.PriceScheduleActive).Return(true, nil)
+		mocks.variants.On("GetByID", ctx, variantID).Return(&entity.ProductVariant{ID: variantID, ProductID: productID}, nil)
+		mocks.variants.On("Update", ctx, mock.MatchedBy(func(v *entity.ProductVariant) bool {
+			return v.Price != nil && *v.Price == 89.99
+		})).Return(nil)
+		mocks.priceHistory.On("Create", ctx, mock.Anything).Return(nil)
+		mocks.priceSchedules.On("Update", ctx, mock.MatchedBy(func(s *entity.PriceSchedule) bool {
+			return s.Status == entity.PriceScheduleEnded && s.PreviousPrice == nil && s.EndedAt != nil
+		})).Return(nil)
+
+		service.applyDuePriceSchedules(ctx)
+
+		mocks.priceSchedules.AssertExpectations(t)
+		mocks.variants.AssertExpectations(t)
+	})
+
+	t.Run("failed start releases the schedule for the next run", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		schedule := &entity.PriceSchedule{ID: uuid.New(), ProductID: productID, Price: 79.99, Status: entity.PriceScheduleScheduled, EndsAt: &endsAt}
+		expectDueSchedules(mocks, nil, []*entity.PriceSchedule{schedule})
+
+		mocks.priceSchedules.On("Transition", ctx, schedule.ID, entity.PriceScheduleScheduled, entity.PriceScheduleActive).Return(true, nil)
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID, Price: 99.99}, nil)
+		mocks.products.On("Update", ctx, mock.Anything).Return(errors.New("database unavailable"))
+		mocks.priceSchedules.On("Transition", ctx, schedule.ID, entity.PriceScheduleActive, entity.PriceScheduleScheduled).Return(true, nil)
+
+		service.applyDuePriceSchedules(ctx)
+
+		mocks.priceSchedules.AssertExpectations(t)
+		mocks.priceSchedules.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		assert.Equal(t, entity.PriceScheduleScheduled, schedule.Status)
+	})
+
+	t.Run("schedule claimed by another instance is skipped", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		schedule := &entity.PriceSchedule{ID: uuid.New(), ProductID: productID, Price: 79.99, Status: entity.PriceScheduleScheduled}
+		expectDueSchedules(mocks, nil, []*entity.PriceSchedule{schedule})
+		mocks.priceSchedules.On("Transition", ctx, schedule.ID, entity.PriceScheduleScheduled, entity.PriceScheduleActive).Return(false, nil)
+
+		service.applyDuePriceSchedules(ctx)
+
+		mocks.products.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+		mocks.priceSchedules.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("end restores the previous price", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		schedule := &entity.PriceSchedule{
+			ID: uuid.New(), ProductID: productID, Price: 79.99, Status: entity.PriceScheduleActive, EndsAt: &endsAt,
+			PreviousPrice: floatPtr(99.99), PreviousComparePrice: floatPtr(120),
+		}
+		expectDueSchedules(mocks, []*entity.PriceSchedule{schedule}, nil)
+
+		mocks.priceSchedules.On("Transition", ctx, schedule.ID, entity.PriceScheduleActive, entity.PriceScheduleEnded).Return(true, nil)
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID, Price: 79.99}, nil)
+		mocks.products.On("Update", ctx, mock.MatchedBy(func(p *entity.Product) bool {
+			return p.Price == 99.99 && p.ComparePrice != nil && *p.ComparePrice == 120
+		})).Return(nil)
+		mocks.priceHistory.On("Create", ctx, mock.Anything).Return(nil)
+		mocks.priceSchedules.On("Update", ctx, mock.MatchedBy(func(s *entity.PriceSchedule) bool {
+			return s.Status == entity.PriceScheduleEnded && s.EndedAt != nil
+		})).Return(nil)
+
+		service.applyDuePriceSchedules(ctx)
+
+		mocks.priceSchedules.AssertExpectations(t)
+		mocks.products.AssertExpectations(t)
+		mocks.alerts.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("end keeps a price changed by hand during the sale", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		schedule := &entity.PriceSchedule{
+			ID: uuid.New(), ProductID: productID, Price: 79.99, Status: entity.PriceScheduleActive, EndsAt: &endsAt,
+			PreviousPrice: floatPtr(99.99),
+		}
+		expectDueSchedules(mocks, []*entity.PriceSchedule{schedule}, nil)
+
+		mocks.priceSchedules.On("Transition", ctx, schedule.ID, entity.PriceScheduleActive, entity.PriceScheduleEnded).Return(true, nil)
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID, Price: 84.99}, nil)
+		mocks.priceSchedules.On("Update", ctx, mock.Anything).Return(nil)
+
+		service.applyDuePriceSchedules(ctx)
+
+		mocks.products.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		assert.Equal(t, entity.PriceScheduleEnded, schedule.Status)
+	})
+
+	t.Run("failed end keeps the schedule active", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		schedule := &entity.PriceSchedule{
+			ID: uuid.New(), ProductID: productID, Price: 79.99, Status: entity.PriceScheduleActive, EndsAt: &endsAt,
+			PreviousPrice: floatPtr(99.99),
+		}
+		expectDueSchedules(mocks, []*entity.PriceSchedule{schedule}, nil)
+
+		mocks.priceSchedules.On("Transition", ctx, schedule.ID, entity.PriceScheduleActive, entity.PriceScheduleEnded).Return(true, nil)
+		mocks.products.On("GetByID", ctx, productID).Return(nil, errors.New("database unavailable"))
+		mocks.priceSchedules.On("Transition", ctx, schedule.ID, entity.PriceScheduleEnded, entity.PriceScheduleActive).Return(true, nil)
+
+		service.applyDuePriceSchedules(ctx)
+
+		mocks.priceSchedules.AssertExpectations(t)
+		assert.Equal(t, entity.PriceScheduleActive, schedule.Status)
+	})
+
+	t.Run("expiry failure still starts due schedules", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		anyTime := mock.AnythingOfType("time.Time")
+		mocks.priceSchedules.On("DueToEnd", ctx, anyTime, priceSchedulerBatchSize).Return([]*entity.PriceSchedule(nil), nil)
+		mocks.priceSchedules.On("ExpireUnstarted", ctx, anyTime).Return(int64(0), errors.New("database unavailable"))
+		mocks.priceSchedules.On("DueToStart", ctx, anyTime, priceSchedulerBatchSize).Return([]*entity.PriceSchedule(nil), nil)
+
+		service.applyDuePriceSchedules(ctx)
+
+		mocks.priceSchedules.AssertExpectations(t)
+	})
+}
+
+func TestProductService_CreatePriceSchedule(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	startsAt := time.Now().Add(time.Hour)
+	endsAt := startsAt.Add(48 * time.Hour)
+
+	t.Run("future schedule is stored without being applied", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.priceSchedules.On("Overlapping", ctx, productID, (*uuid.UUID)(nil), startsAt, &endsAt).Return([]*entity.PriceSchedule{}, nil)
+		mocks.priceSchedules.On("Create", ctx, mock.MatchedBy(func(s *entity.PriceSchedule) bool {
+			return s.Price == 79.99 && s.Status == entity.PriceScheduleScheduled
+		})).Return(nil)
+
+		schedule, err := service.CreatePriceSchedule(ctx, productID, &CreatePriceScheduleRequest{Price: 79.99, StartsAt: startsAt, EndsAt: &endsAt})
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.PriceScheduleScheduled, schedule.Status)
+		mocks.priceSchedules.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("overlapping window", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		existing := &entity.PriceSchedule{ID: uuid.New(), ProductID: productID, Status: entity.PriceScheduleActive}
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.priceSchedules.On("Overlapping", ctx, productID, (*uuid.UUID)(nil), startsAt, &endsAt).Return([]*entity.PriceSchedule{existing}, nil)
+
+		_, err := service.CreatePriceSchedule(ctx, productID, &CreatePriceScheduleRequest{Price: 79.99, StartsAt: startsAt, EndsAt: &endsAt})
+
+		assert.EqualError(t, err, "overlaps price schedule "+existing.ID.String())
+		mocks.priceSchedules.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("window that ends before it starts", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		before := startsAt.Add(-time.Minute)
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+
+		_, err := service.CreatePriceSchedule(ctx, productID, &CreatePriceScheduleRequest{Price: 79.99, StartsAt: startsAt, EndsAt: &before})
+
+		assert.EqualError(t, err, "ends_at must be after starts_at")
+		mocks.priceSchedules.AssertNotCalled(t, "Overlapping", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestProductService_GetPriceTimeline(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+
+	t.Run("lowest price over the default window", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		inWindow := mock.MatchedBy(func(since time.Time) bool {
+			return time.Since(since).Round(time.Hour) == defaultPriceHistoryDays*24*time.Hour
+		})
+		entries := []*entity.PriceHistory{{ProductID: productID, Price: floatPtr(99.99)}, {ProductID: productID, Price: floatPtr(79.99)}}
+
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.priceHistory.On("GetTimeline", ctx, productID, (*uuid.UUID)(nil), inWindow).Return(entries, nil)
+		mocks.priceHistory.On("LowestPrice", ctx, productID, (*uuid.UUID)(nil), inWindow).Return(floatPtr(79.99), nil)
+
+		timeline, err := service.GetPriceTimeline(ctx, productID, nil, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, entries, timeline.Entries)
+		require.NotNil(t, timeline.LowestPrice)
+		assert.Equal(t, 79.99, *timeline.LowestPrice)
+	})
+
+	t.Run("variant of another product", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		variantID := uuid.New()
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.variants.On("GetByID", ctx, variantID).Return(&entity.ProductVariant{ID: variantID, ProductID: uuid.New()}, nil)
+
+		_, err := service.GetPriceTimeline(ctx, productID, &variantID, 7)
+
+		assert.ErrorIs(t, err, ErrVariantNotFound)
+		mocks.priceHistory.AssertNotCalled(t, "LowestPrice", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("lowest price failure", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.priceHistory.On("GetTimeline", ctx, productID, (*uuid.UUID)(nil), mock.Anything).Return([]*entity.PriceHistory{}, nil)
+		mocks.priceHistory.On("LowestPrice", ctx, productID, (*uuid.UUID)(nil), mock.Anything).Return(nil, errors.New("database unavailable"))
+
+		_, err := service.GetPriceTimeline(ctx, productID, nil, 7)
+
+		assert.ErrorContains(t, err, "failed to get lowest price")
+	})
+}
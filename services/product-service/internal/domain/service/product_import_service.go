@@ -0,0 +1,686 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/pkg/productalerts"
+	"solemate/pkg/utils"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+const (
+	// MaxImportSize is the largest catalog file accepted for import
+	MaxImportSize = 20 << 20
+
+	// importJobTimeout bounds validating and importing a single file
+	importJobTimeout = 30 * time.Minute
+
+	// importProgressInterval is how many products are imported between
+	// progress updates on the job
+	importProgressInterval = 25
+
+	// maxImportErrors caps the row errors kept on a job
+	maxImportErrors = 500
+
+	// exportBatchSize is how many products are loaded per export query
+	exportBatchSize = 100
+)
+
+var ErrImportJobNotFound = errors.New("import job not found")
+
+type ImportProductsRequest struct {
+	Format string `form:"format"`
+	DryRun bool   `form:"dry_run"`
+}
+
+// importItem is a validated product ready to be upserted
+type importItem struct {
+	product    *CatalogProduct
+	existing   *entity.Product
+	categoryID *uuid.UUID
+	brandID    *uuid.UUID
+}
+
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// StartImport stores an import file and validates, and unless it is a dry
+// run imports, it in the background
+func (s *ProductService) StartImport(ctx context.Context, requestedBy uuid.UUID, data []byte, req *ImportProductsRequest) (*entity.ProductImportJob, error) {
+	format := strings.ToLower(req.Format)
+	if format != entity.CatalogFormatCSV && format != entity.CatalogFormatJSON {
+		return nil, ErrUnsupportedCatalogFormat
+	}
+	if len(data) == 0 {
+		return nil, errors.New("import file is empty")
+	}
+
+	job := &entity.ProductImportJob{
+		RequestedBy: requestedBy,
+		Format:      format,
+		DryRun:      req.DryRun,
+		Status:      entity.ImportStatusPending,
+		Data:        data,
+	}
+	if err := s.importRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	go s.processImport(job.ID)
+
+	return job, nil
+}
+
+// GetImportJob returns an import job's status, progress and report
+func (s *ProductService) GetImportJob(ctx context.Context, id uuid.UUID) (*entity.ProductImportJob, error) {
+	job, err := s.importRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrImportJobNotFound
+	}
+	return job, nil
+}
+
+// ConfirmImport imports the file of a dry run that validated cleanly. The
+// file is validated again first, as the catalog may have changed since.
+func (s *ProductService) ConfirmImport(ctx context.Context, id uuid.UUID) (*entity.ProductImportJob, error) {
+	job, err := s.importRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrImportJobNotFound
+	}
+
+	if !job.DryRun || job.Status != entity.ImportStatusValidated {
+		return nil, fmt.Errorf("only validated dry runs can be imported, import is %s", job.Status)
+	}
+
+	job.DryRun = false
+	job.Status = entity.ImportStatusPending
+	if err := s.importRepo.Update(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to update import job: %w", err)
+	}
+
+	go s.processImport(job.ID)
+
+	return job, nil
+}
+
+// ResumeImports picks up the import jobs a previous run of the service left
+// unfinished. Jobs that were still pending or validating have written
+// nothing and are started again. Jobs that were importing may have been
+// partly applied, so they are failed and have to be uploaded again.
+func (s *ProductService) ResumeImports(ctx context.Context) error {
+	jobs, err := s.importRepo.ListByStatus(ctx, entity.ImportStatusPending, entity.ImportStatusValidating, entity.ImportStatusImporting)
+	if err != nil {
+		return fmt.Errorf("failed to load unfinished import jobs: %w", err)
+	}
+
+	for _, job := range jobs {
+		if job.Status == entity.ImportStatusImporting {
+			s.finishImport(ctx, job, entity.ImportStatusFailed, "import was interrupted by a restart and may be incomplete, upload the file again to finish it")
+			continue
+		}
+		go s.processImport(job.ID)
+	}
+	return nil
+}
+
+// processImport runs an import job outside the HTTP request that created it
+func (s *ProductService) processImport(jobID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), importJobTimeout)
+	defer cancel()
+
+	job, err := s.importRepo.GetByID(ctx, jobID)
+	if err != nil {
+		log.Printf("import job %s: %v", jobID, err)
+		return
+	}
+
+	job.Status = entity.ImportStatusValidating
+	job.Total, job.Processed, job.Created, job.Updated, job.Failed = 0, 0, 0, 0, 0
+	job.Errors = nil
+	job.Error = ""
+	if err := s.importRepo.Update(ctx, job); err != nil {
+		log.Printf("import job %s: failed to mark as validating: %v", jobID, err)
+		return
+	}
+
+	products, rowErrors, err := decodeCatalog(job.Format, job.Data)
+	if err != nil {
+		s.finishImport(ctx, job, entity.ImportStatusFailed, err.Error())
+		return
+	}
+	job.Total = len(products)
+
+	items, validationErrors := s.validateImport(ctx, products)
+	s.addImportErrors(job, append(rowErrors, validationErrors...)...)
+	for _, item := range items {
+		if item.existing != nil {
+			job.Updated++
+		} else {
+			job.Created++
+		}
+	}
+
+	if len(job.Errors) > 0 {
+		s.finishImport(ctx, job, entity.ImportStatusFailed, "validation failed, no products were imported")
+		return
+	}
+	if job.DryRun {
+		s.finishImport(ctx, job, entity.ImportStatusValidated, "")
+		return
+	}
+
+	job.Status = entity.ImportStatusImporting
+	job.Created, job.Updated = 0, 0
+	if err := s.importRepo.Update(ctx, job); err != nil {
+		log.Printf("import job %s: failed to mark as importing: %v", jobID, err)
+		return
+	}
+
+	for i, item := range items {
+		if ctx.Err() != nil {
+			s.finishImport(ctx, job, entity.ImportStatusFailed, "import timed out")
+			return
+		}
+
+		if err := s.importProduct(ctx, item); err != nil {
+			job.Failed++
+			s.addImportErrors(job, entity.ImportRowError{Row: item.product.row, SKU: item.product.SKU, Message: err.Error()})
+		} else if item.existing != nil {
+			job.Updated++
+		} else {
+			job.Created++
+		}
+		job.Processed++
+
+		if (i+1)%importProgressInterval == 0 {
+			if err := s.importRepo.Update(ctx, job); err != nil {
+				log.Printf("import job %s: failed to save progress: %v", jobID, err)
+			}
+		}
+	}
+
+	s.finishImport(ctx, job, entity.ImportStatusCompleted, "")
+}
+
+func (s *ProductService) finishImport(ctx context.Context, job *entity.ProductImportJob, status, message string) {
+	now := time.Now()
+	job.Status = status
+	if message != "" {
+		job.Error = message
+	}
+	job.CompletedAt = &now
+
+	// A validated dry run keeps its file until it is confirmed
+	if status != entity.ImportStatusValidated {
+		job.Data = nil
+	}
+
+	if err := s.importRepo.Update(ctx, job); err != nil {
+		log.Printf("import job %s: failed to save result: %v", job.ID, err)
+	}
+}
+
+func (s *ProductService) addImportErrors(job *entity.ProductImportJob, rowErrors ...entity.ImportRowError) {
+	for _, rowError := range rowErrors {
+		if len(job.Errors) >= maxImportErrors {
+			job.Error = fmt.Sprintf("more than %d errors, only the first %d are listed", maxImportErrors, maxImportErrors)
+			return
+		}
+		job.Errors = append(job.Errors, rowError)
+	}
+}
+
+// validateImport checks every product in a file against the catalog and
+// returns the valid ones along with the problems found in the others
+func (s *ProductService) validateImport(ctx context.Context, products []*CatalogProduct) ([]*importItem, []entity.ImportRowError) {
+	var items []*importItem
+	var rowErrors []entity.ImportRowError
+
+	categories := map[string]*uuid.UUID{}
+	brands := map[string]*uuid.UUID{}
+	productSKUs := map[string]bool{}
+	slugs := map[string]bool{}
+	variantSKUs := map[string]bool{}
+
+	for _, product := range products {
+		var problems []entity.ImportRowError
+		invalid := func(row int, field, message string) {
+			problems = append(problems, entity.ImportRowError{Row: row, SKU: product.SKU, Field: field, Message: message})
+		}
+
+		product.SKU = utils.SanitizeString(product.SKU)
+		product.Name = utils.SanitizeString(product.Name)
+		product.Slug = utils.SanitizeString(product.Slug)
+
+		item := &importItem{product: product}
+
+		switch {
+		case product.SKU == "":
+			invalid(product.row, "sku", "is required")
+		case productSKUs[product.SKU]:
+			invalid(product.row, "sku", "appears more than once in the file")
+		default:
+			productSKUs[product.SKU] = true
+			item.existing, _ = s.productRepo.GetBySKU(ctx, product.SKU)
+		}
+
+		if product.Slug == "" {
+			if item.existing != nil && !product.fields.has("slug") {
+				product.Slug = item.existing.Slug
+			} else {
+				product.Slug = slugify(product.Name)
+			}
+		}
+
+		if product.Name == "" {
+			invalid(product.row, "name", "is required")
+		}
+
+		if product.Slug == "" {
+			invalid(product.row, "slug", "is required")
+		} else if slugs[product.Slug] {
+			invalid(product.row, "slug", "appears more than once in the file")
+		} else {
+			slugs[product.Slug] = true
+			other, _ := s.productRepo.GetBySlug(ctx, product.Slug)
+			if other != nil && other.SKU != product.SKU {
+				invalid(product.row, "slug", fmt.Sprintf("is already used by product %s", other.SKU))
+			}
+		}
+
+		if product.Price == nil {
+			invalid(product.row, "price", "is required")
+		}
+		for _, amount := range []struct {
+			field string
+			value *float64
+		}{
+			{"price", product.Price},
+			{"compare_price", product.ComparePrice},
+			{"cost", product.Cost},
+			{"weight", product.Weight},
+		} {
+			if amount.value != nil && *amount.value < 0 {
+				invalid(product.row, amount.field, "must be non-negative")
+			}
+		}
+
+		if product.Category != "" {
+			id, ok := categories[product.Category]
+			if !ok {
+				if category, err := s.categoryRepo.GetBySlug(ctx, product.Category); err == nil && category != nil {
+					id = &category.ID
+				}
+				categories[product.Category] = id
+			}
+			if id == nil {
+				invalid(product.row, "category", fmt.Sprintf("category %q not found", product.Category))
+			}
+			item.categoryID = id
+		}
+
+		if product.Brand != "" {
+			id, ok := brands[product.Brand]
+			if !ok {
+				if brand, err := s.brandRepo.GetBySlug(ctx, product.Brand); err == nil && brand != nil {
+					id = &brand.ID
+				}
+				brands[product.Brand] = id
+			}
+			if id == nil {
+				invalid(product.row, "brand", fmt.Sprintf("brand %q not found", product.Brand))
+			}
+			item.brandID = id
+		}
+
+		for _, image := range product.Images {
+			if !isHTTPURL(image.URL) {
+				invalid(product.row, "images", fmt.Sprintf("%q is not a valid URL", image.URL))
+			}
+		}
+
+		for i := range product.Variants {
+			variant := &product.Variants[i]
+			variant.SKU = utils.SanitizeString(variant.SKU)
+
+			switch {
+			case variant.SKU == "":
+				invalid(variant.row, "variant_sku", "is required")
+			case variantSKUs[variant.SKU]:
+				invalid(variant.row, "variant_sku", fmt.Sprintf("%s appears more than once in the file", variant.SKU))
+			default:
+				variantSKUs[variant.SKU] = true
+				other, _ := s.variantRepo.GetBySKU(ctx, variant.SKU)
+				if other != nil && (item.existing == nil || other.ProductID != item.existing.ID) {
+					invalid(variant.row, "variant_sku", fmt.Sprintf("%s belongs to another product", variant.SKU))
+				}
+			}
+
+			if variant.Price != nil && *variant.Price < 0 {
+				invalid(variant.row, "variant_price", "must be non-negative")
+			}
+			if variant.Weight != nil && *variant.Weight < 0 {
+				invalid(variant.row, "variant_weight", "must be non-negative")
+			}
+			if variant.Stock < 0 {
+				invalid(variant.row, "variant_stock", "must be non-negative")
+			}
+			for _, image := range variant.Images {
+				if !isHTTPURL(image) {
+					invalid(variant.row, "variant_images", fmt.Sprintf("%q is not a valid URL", image))
+				}
+			}
+		}
+
+		if len(problems) > 0 {
+			rowErrors = append(rowErrors, problems...)
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, rowErrors
+}
+
+// importedVariant is a variant an import creates or changes, with the stock
+// and price it had before
+type importedVariant struct {
+	variant  *entity.ProductVariant
+	created  bool
+	oldStock int
+	oldPrice *float64
+}
+
+// importProduct creates or updates a validated product, then upserts its
+// variants by SKU and adds any images it does not have yet. Variants and
+// images missing from the file are left in place. The product, its variants
+// and its images are saved in one transaction; price history and alerts
+// follow once it commits.
+func (s *ProductService) importProduct(ctx context.Context, item *importItem) error {
+	catalog := item.product
+
+	product := item.existing
+	if product == nil {
+		product = &entity.Product{SKU: catalog.SKU, IsActive: true}
+	}
+	oldPrice, oldComparePrice := product.Price, product.ComparePrice
+	existingVariants := product.Variants
+	existingImages := product.Images
+
+	// Only the fields the file provides are written, so a file with a few
+	// columns updates those and leaves the rest of the product alone
+	fields := catalog.fields
+	product.Name = catalog.Name
+	product.Slug = catalog.Slug
+	product.Price = *catalog.Price
+	if fields.has("description") {
+		product.Description = utils.SanitizeString(catalog.Description)
+	}
+	if fields.has("category") {
+		product.CategoryID = item.categoryID
+	}
+	if fields.has("brand") {
+		product.BrandID = item.brandID
+	}
+	if fields.has("compare_price") {
+		product.ComparePrice = catalog.ComparePrice
+	}
+	if fields.has("cost") {
+		product.Cost = catalog.Cost
+	}
+	if fields.has("weight") {
+		product.Weight = catalog.Weight
+	}
+	if fields.has("tags") {
+		product.Tags = catalog.Tags
+	}
+	if fields.has("meta_title") {
+		product.MetaTitle = utils.SanitizeString(catalog.MetaTitle)
+	}
+	if fields.has("meta_description") {
+		product.MetaDescription = utils.SanitizeString(catalog.MetaDescription)
+	}
+	if catalog.IsActive != nil {
+		product.IsActive = *catalog.IsActive
+	}
+
+	// Saving the preloaded associations would write back their old values
+	product.Category, product.Brand, product.Variants, product.Images = nil, nil, nil, nil
+
+	variants := importVariants(catalog.Variants, existingVariants)
+	images := importImages(catalog.Images, existingImages)
+
+	changedVariants := make([]*entity.ProductVariant, len(variants))
+	for i, imported := range variants {
+		changedVariants[i] = imported.variant
+	}
+	if err := s.importRepo.SaveProduct(ctx, product, changedVariants, images); err != nil {
+		return fmt.Errorf("failed to save product: %w", err)
+	}
+
+	if item.existing == nil {
+		s.recordProductPrice(ctx, product, entity.PriceSourceImport, nil)
+	} else {
+		if product.Price != oldPrice || !samePrice(product.ComparePrice, oldComparePrice) {
+			s.recordProductPrice(ctx, product, entity.PriceSourceImport, nil)
+		}
+
+		if product.Price < oldPrice && s.alerts != nil {
+			event := productalerts.PriceDrop(product.ID, product.Name, oldPrice, product.Price)
+			if err := s.alerts.Notify(ctx, event); err != nil {
+				log.Printf("product %s: failed to send price drop alert: %v", product.ID, err)
+			}
+		}
+	}
+
+	for _, imported := range variants {
+		variant := imported.variant
+		if imported.created {
+			if variant.Price != nil {
+				s.recordVariantPrice(ctx, variant, entity.PriceSourceImport, nil)
+			}
+			continue
+		}
+
+		if !samePrice(variant.Price, imported.oldPrice) {
+			s.recordVariantPrice(ctx, variant, entity.PriceSourceImport, nil)
+		}
+
+		if imported.oldStock <= 0 && variant.Stock > 0 && variant.IsActive && s.alerts != nil {
+			if err := s.alerts.Notify(ctx, productalerts.BackInStock(product.ID, &variant.ID)); err != nil {
+				log.Printf("product %s: failed to send back in stock alert: %v", product.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// importVariants applies the file's variants to the product's existing
+// ones, matched by SKU, and returns the variants to create or update
+func importVariants(variants []CatalogVariant, existing []entity.ProductVariant) []importedVariant {
+	bySKU := make(map[string]*entity.ProductVariant, len(existing))
+	for i := range existing {
+		bySKU[existing[i].SKU] = &existing[i]
+	}
+	sortOrder := len(existing)
+
+	imported := make([]importedVariant, 0, len(variants))
+	for _, catalog := range variants {
+		variant, found := bySKU[catalog.SKU]
+		if !found {
+			variant = &entity.ProductVariant{
+				SKU:       catalog.SKU,
+				SortOrder: sortOrder,
+				IsActive:  true,
+			}
+			sortOrder++
+		}
+		oldStock, oldPrice := variant.Stock, variant.Price
+
+		fields := catalog.fields
+		if fields.has("size") {
+			variant.Size = utils.SanitizeString(catalog.Size)
+		}
+		if fields.has("color") {
+			variant.Color = utils.SanitizeString(catalog.Color)
+		}
+		if fields.has("price") {
+			variant.Price = catalog.Price
+		}
+		if fields.has("stock") {
+			variant.Stock = catalog.Stock
+		}
+		if fields.has("weight") {
+			variant.Weight = catalog.Weight
+		}
+		if fields.has("images") {
+			variant.Images = catalog.Images
+		}
+		if catalog.IsActive != nil {
+			variant.IsActive = *catalog.IsActive
+		}
+
+		imported = append(imported, importedVariant{
+			variant:  variant,
+			created:  !found,
+			oldStock: oldStock,
+			oldPrice: oldPrice,
+		})
+	}
+
+	return imported
+}
+
+// importImages returns the images of the file the product does not have
+// yet, and the existing ones whose alt text the file changes. The first
+// image of a product without any becomes its primary image.
+func importImages(images []CatalogImage, existing []entity.ProductImage) []*entity.ProductImage {
+	byURL := make(map[string]*entity.ProductImage, len(existing))
+	for i := range existing {
+		byURL[existing[i].URL] = &existing[i]
+	}
+	sortOrder := len(existing)
+
+	var changed []*entity.ProductImage
+	for _, catalog := range images {
+		altText := utils.SanitizeString(catalog.AltText)
+
+		if image, found := byURL[catalog.URL]; found {
+			if altText != "" && altText != image.AltText {
+				image.AltText = altText
+				image.Product = nil
+				changed = append(changed, image)
+			}
+			continue
+		}
+
+		image := &entity.ProductImage{
+			URL:       catalog.URL,
+			AltText:   altText,
+			SortOrder: sortOrder,
+			IsPrimary: sortOrder == 0,
+		}
+		sortOrder++
+		changed = append(changed, image)
+		byURL[image.URL] = image
+	}
+
+	return changed
+}
+
+// ExportCatalog streams every product, active or not, to w in the import
+// format. Output is flushed after each batch when w supports it.
+func (s *ProductService) ExportCatalog(ctx context.Context, format string, w io.Writer) error {
+	flush := func() {
+		if flusher, ok := w.(interface{ Flush() }); ok {
+			flusher.Flush()
+		}
+	}
+
+	var csvWriter *csv.Writer
+	switch format {
+	case entity.CatalogFormatCSV:
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(catalogCSVColumns); err != nil {
+			return err
+		}
+	case entity.CatalogFormatJSON:
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+	default:
+		return ErrUnsupportedCatalogFormat
+	}
+
+	afterSKU := ""
+	first := true
+	for {
+		products, err := s.productRepo.ListAfterSKU(ctx, afterSKU, exportBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to load products: %w", err)
+		}
+
+		for _, product := range products {
+			catalog := toCatalogProduct(product)
+
+			if csvWriter != nil {
+				if err := csvWriter.WriteAll(catalogCSVRecords(catalog)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			data, err := json.Marshal(catalog)
+			if err != nil {
+				return err
+			}
+			separator := ",\n"
+			if first {
+				separator = "\n"
+				first = false
+			}
+			if _, err := io.WriteString(w, separator); err != nil {
+				return err
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+		}
+		flush()
+
+		if len(products) < exportBatchSize {
+			break
+		}
+		afterSKU = products[len(products)-1].SKU
+	}
+
+	if csvWriter == nil {
+		if _, err := io.WriteString(w, "\n]\n"); err != nil {
+			return err
+		}
+		flush()
+	}
+	return nil
+}
+
+// slugify derives a URL slug from a product name, e.g. "Air Max 90" becomes
+// "air-max-90"
+func slugify(name string) string {
+	return strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func isHTTPURL(value string) bool {
+	parsed, err := url.ParseRequestURI(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
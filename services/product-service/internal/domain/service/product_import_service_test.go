@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+// MockImportRepository is a mock implementation of repository.ProductImportRepository
+type MockImportRepository struct {
+	mock.Mock
+}
+
+func (m *MockImportRepository) Create(ctx context.Context, job *entity.ProductImportJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockImportRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ProductImportJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ProductImportJob), args.Error(1)
+}
+
+func (m *MockImportRepository) Update(ctx context.Context, job *entity.ProductImportJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockImportRepository) ListByStatus(ctx context.Context, statuses ...string) ([]*entity.ProductImportJob, error) {
+	args := m.Called(ctx, statuses)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ProductImportJob), args.Error(1)
+}
+
+func (m *MockImportRepository) SaveProduct(ctx context.Context, product *entity.Product, variants []*entity.ProductVariant, images []*entity.ProductImage) error {
+	args := m.Called(ctx, product, variants, images)
+	return args.Error(0)
+}
+
+func TestProductService_ImportKeepsFieldsMissingFromTheFile(t *testing.T) {
+	ctx := context.Background()
+	service, mocks := newTestProductService()
+	productID, categoryID, variantID := uuid.New(), uuid.New(), uuid.New()
+
+	existing := &entity.Product{
+		ID:          productID,
+		SKU:         "AM-90",
+		Name:        "Air Max",
+		Slug:        "air-max-classic",
+		Description: "The original",
+		CategoryID:  &categoryID,
+		Price:       129.99,
+		Cost:        floatPtr(60),
+		Tags:        pq.StringArray{"running"},
+		MetaTitle:   "Air Max 90 | SoleMate",
+		IsActive:    true,
+		Variants: []entity.ProductVariant{
+			{ID: variantID, ProductID: productID, SKU: "AM-90-9", Size: "9", Color: "white", Stock: 7, IsActive: true},
+		},
+	}
+
+	data := []byte("sku,name,price,variant_sku,variant_color\nAM-90,Air Max 90,129.99,AM-90-9,black\n")
+	products, rowErrors, err := decodeCatalog(entity.CatalogFormatCSV, data)
+	require.NoError(t, err)
+	require.Empty(t, rowErrors)
+
+	mocks.products.On("GetBySKU", ctx, "AM-90").Return(existing, nil)
+	mocks.products.On("GetBySlug", ctx, "air-max-classic").Return(existing, nil)
+	mocks.variants.On("GetBySKU", ctx, "AM-90-9").Return(&existing.Variants[0], nil)
+
+	items, validationErrors := service.validateImport(ctx, products)
+	require.Empty(t, validationErrors)
+	require.Len(t, items, 1)
+
+	mocks.imports.On("SaveProduct", ctx, mock.MatchedBy(func(p *entity.Product) bool {
+		return p.Name == "Air Max 90" &&
+			p.Slug == "air-max-classic" &&
+			p.Description == "The original" &&
+			p.CategoryID != nil && *p.CategoryID == categoryID &&
+			p.Cost != nil && *p.Cost == 60 &&
+			len(p.Tags) == 1 && p.MetaTitle == "Air Max 90 | SoleMate"
+	}), mock.MatchedBy(func(variants []*entity.ProductVariant) bool {
+		v := variants[0]
+		return len(variants) == 1 && v.ID == variantID && v.Color == "black" && v.Size == "9" && v.Stock == 7
+	}), []*entity.ProductImage(nil)).Return(nil)
+
+	require.NoError(t, service.importProduct(ctx, items[0]))
+	mocks.imports.AssertExpectations(t)
+	mocks.priceHistory.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mocks.alerts.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+}
+
+func TestProductService_ImportSavesProductInOneTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	data := []byte("sku,name,price,images,variant_sku,variant_size,variant_price\n" +
+		"RB-1,Runner,89.99,https://cdn.example.com/rb-1.jpg|https://cdn.example.com/rb-1-side.jpg,RB-1-9,9,89.99\n" +
+		"RB-1,,,,RB-1-10,10,\n")
+	products, rowErrors, err := decodeCatalog(entity.CatalogFormatCSV, data)
+	require.NoError(t, err)
+	require.Empty(t, rowErrors)
+	require.Len(t, products, 1)
+	item := &importItem{product: products[0]}
+
+	t.Run("new product is saved with its variants and images", func(t *testing.T) {
+		service, mocks := newTestProductService()
+
+		mocks.imports.On("SaveProduct", ctx, mock.AnythingOfType("*entity.Product"), mock.MatchedBy(func(variants []*entity.ProductVariant) bool {
+			return len(variants) == 2 &&
+				variants[0].SKU == "RB-1-9" && variants[0].SortOrder == 0 &&
+				variants[1].SKU == "RB-1-10" && variants[1].SortOrder == 1
+		}), mock.MatchedBy(func(images []*entity.ProductImage) bool {
+			return len(images) == 2 &&
+				images[0].IsPrimary && images[0].SortOrder == 0 &&
+				!images[1].IsPrimary && images[1].SortOrder == 1
+		})).Return(nil)
+		mocks.priceHistory.On("Create", ctx, mock.Anything).Return(nil)
+
+		require.NoError(t, service.importProduct(ctx, item))
+
+		mocks.imports.AssertExpectations(t)
+		mocks.products.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mocks.variants.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mocks.images.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		// The product and the priced variant
+		mocks.priceHistory.AssertNumberOfCalls(t, "Create", 2)
+	})
+
+	t.Run("failed save records no price history", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		mocks.imports.On("SaveProduct", ctx, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("variant RB-1-10: duplicate key"))
+
+		err := service.importProduct(ctx, item)
+
+		assert.ErrorContains(t, err, "variant RB-1-10")
+		mocks.priceHistory.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mocks.alerts.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProductService_ResumeImports(t *testing.T) {
+	ctx := context.Background()
+	unfinished := []string{entity.ImportStatusPending, entity.ImportStatusValidating, entity.ImportStatusImporting}
+
+	t.Run("restarts validation and fails interrupted imports", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		pending := &entity.ProductImportJob{ID: uuid.New(), Status: entity.ImportStatusPending, Data: []byte("sku,name,price\n")}
+		importing := &entity.ProductImportJob{ID: uuid.New(), Status: entity.ImportStatusImporting, Data: []byte("sku,name,price\n")}
+
+		mocks.imports.On("ListByStatus", ctx, unfinished).Return([]*entity.ProductImportJob{pending, importing}, nil)
+		mocks.imports.On("Update", ctx, mock.MatchedBy(func(job *entity.ProductImportJob) bool {
+			return job.ID == importing.ID && job.Status == entity.ImportStatusFailed && job.Data == nil && job.CompletedAt != nil
+		})).Return(nil)
+
+		restarted := make(chan struct{})
+		mocks.imports.On("GetByID", mock.Anything, pending.ID).
+			Return(nil, errors.New("import job not found")).
+			Run(func(mock.Arguments) { close(restarted) })
+
+		require.NoError(t, service.ResumeImports(ctx))
+
+		select {
+		case <-restarted:
+		case <-time.After(time.Second):
+			t.Fatal("pending import was not restarted")
+		}
+		mocks.imports.AssertExpectations(t)
+		mocks.imports.AssertNotCalled(t, "GetByID", mock.Anything, importing.ID)
+	})
+
+	t.Run("list failure", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		mocks.imports.On("ListByStatus", ctx, unfinished).Return(nil, errors.New("database unavailable"))
+
+		err := service.ResumeImports(ctx)
+
+		assert.ErrorContains(t, err, "database unavailable")
+	})
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+const (
+	// suggestMinQueryLength avoids suggesting half the catalog for one letter
+	suggestMinQueryLength = 2
+	defaultSuggestLimit   = 5
+	maxSuggestLimit       = 10
+
+	defaultZeroResultDays  = 30
+	defaultZeroResultLimit = 50
+)
+
+// Suggest returns type-ahead matches for a partial search query. Responses
+// are cached, so renamed products can take a few minutes to show up.
+func (s *ProductService) Suggest(ctx context.Context, query string, limit int) (*repository.SearchSuggestions, error) {
+	query = normalizeSearchQuery(query)
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+
+	if len([]rune(query)) < suggestMinQueryLength {
+		return &repository.SearchSuggestions{}, nil
+	}
+
+	if s.suggestions != nil {
+		if cached, ok := s.suggestions.Get(ctx, query, limit); ok {
+			return cached, nil
+		}
+	}
+
+	suggestions, err := s.productRepo.Suggest(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+
+	if s.suggestions != nil {
+		if err := s.suggestions.Set(ctx, query, limit, suggestions); err != nil {
+			log.Printf("failed to cache suggestions for %q: %v", query, err)
+		}
+	}
+
+	return suggestions, nil
+}
+
+// ZeroResultSearches reports the searches of the last days that found
+// nothing, most frequent first
+func (s *ProductService) ZeroResultSearches(ctx context.Context, days, limit int) ([]*repository.ZeroResultQuery, error) {
+	if days <= 0 {
+		days = defaultZeroResultDays
+	}
+	if limit <= 0 {
+		limit = defaultZeroResultLimit
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	return s.searchLog.ZeroResultQueries(ctx, since, limit)
+}
+
+// logSearch records a search and how many products it found. Logging must
+// never fail the search itself.
+func (s *ProductService) logSearch(ctx context.Context, query string, resultCount int64) {
+	if s.searchLog == nil {
+		return
+	}
+
+	entry := &entity.SearchQuery{
+		Query:       normalizeSearchQuery(query),
+		ResultCount: resultCount,
+	}
+	if err := s.searchLog.Create(ctx, entry); err != nil {
+		log.Printf("failed to log search %q: %v", entry.Query, err)
+	}
+}
+
+// normalizeSearchQuery lower-cases a query and collapses its whitespace, so
+// "Air  Max" and "air max" are logged and cached as the same search
+func normalizeSearchQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
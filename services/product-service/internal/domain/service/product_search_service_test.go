@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// MockSuggestionCache is a mock implementation of repository.SuggestionCache
+type MockSuggestionCache struct {
+	mock.Mock
+}
+
+func (m *MockSuggestionCache) Get(ctx context.Context, query string, limit int) (*repository.SearchSuggestions, bool) {
+	args := m.Called(ctx, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1)
+	}
+	return args.Get(0).(*repository.SearchSuggestions), args.Bool(1)
+}
+
+func (m *MockSuggestionCache) Set(ctx context.Context, query string, limit int, suggestions *repository.SearchSuggestions) error {
+	args := m.Called(ctx, query, limit, suggestions)
+	return args.Error(0)
+}
+
+func TestNormalizeSearchQuery(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  string
+	}{
+		"lower case":           {query: "Air MAX", want: "air max"},
+		"runs of whitespace":   {query: "  air \t  max\n", want: "air max"},
+		"punctuation kept":     {query: "Air-Max 90!", want: "air-max 90!"},
+		"only whitespace":      {query: " \t ", want: ""},
+		"letters beyond ASCII": {query: "ÉCLAIR Runner", want: "éclair runner"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeSearchQuery(tt.query))
+		})
+	}
+}
+
+func TestProductService_Suggest(t *testing.T) {
+	ctx := context.Background()
+	found := &repository.SearchSuggestions{Products: []repository.Suggestion{{Name: "Air Max 90"}}}
+
+	// newSuggestService returns a product service that caches suggestions
+	newSuggestService := func() (*ProductService, *productServiceMocks, *MockSuggestionCache) {
+		service, mocks := newTestProductService()
+		cache := new(MockSuggestionCache)
+		service.suggestions = cache
+		return service, mocks, cache
+	}
+
+	t.Run("the normalized query is looked up and cached", func(t *testing.T) {
+		service, mocks, cache := newSuggestService()
+		cache.On("Get", ctx, "air max", defaultSuggestLimit).Return(nil, false)
+		mocks.products.On("Suggest", ctx, "air max", defaultSuggestLimit).Return(found, nil)
+		cache.On("Set", ctx, "air max", defaultSuggestLimit, found).Return(nil)
+
+		suggestions, err := service.Suggest(ctx, "  Air   MAX ", 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, found, suggestions)
+		cache.AssertExpectations(t)
+	})
+
+	t.Run("cached suggestions skip the lookup", func(t *testing.T) {
+		service, mocks, cache := newSuggestService()
+		cache.On("Get", ctx, "air max", maxSuggestLimit).Return(found, true)
+
+		suggestions, err := service.Suggest(ctx, "air max", 50)
+
+		require.NoError(t, err)
+		assert.Equal(t, found, suggestions)
+		mocks.products.AssertNotCalled(t, "Suggest", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a failure to cache still returns the suggestions", func(t *testing.T) {
+		service, mocks, cache := newSuggestService()
+		cache.On("Get", ctx, "air", 3).Return(nil, false)
+		mocks.products.On("Suggest", ctx, "air", 3).Return(found, nil)
+		cache.On("Set", ctx, "air", 3, found).Return(errors.New("redis unavailable"))
+
+		suggestions, err := service.Suggest(ctx, "air", 3)
+
+		require.NoError(t, err)
+		assert.Equal(t, found, suggestions)
+	})
+
+	t.Run("queries that are too short suggest nothing", func(t *testing.T) {
+		service, mocks, cache := newSuggestService()
+
+		suggestions, err := service.Suggest(ctx, " é ", 5)
+
+		require.NoError(t, err)
+		assert.Equal(t, &repository.SearchSuggestions{}, suggestions)
+		cache.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything)
+		mocks.products.AssertNotCalled(t, "Suggest", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("lookup failure is not cached", func(t *testing.T) {
+		service, mocks, cache := newSuggestService()
+		cache.On("Get", ctx, "air", defaultSuggestLimit).Return(nil, false)
+		mocks.products.On("Suggest", ctx, "air", defaultSuggestLimit).Return(nil, errors.New("database unavailable"))
+
+		_, err := service.Suggest(ctx, "air", 0)
+
+		assert.ErrorContains(t, err, "failed to get suggestions")
+		cache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -4,20 +4,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/google/uuid"
+	"solemate/pkg/pagination"
+	"solemate/pkg/productalerts"
 	"solemate/pkg/utils"
 	"solemate/services/product-service/internal/domain/entity"
 	"solemate/services/product-service/internal/domain/repository"
 )
 
 type ProductService struct {
-	productRepo   repository.ProductRepository
-	categoryRepo  repository.CategoryRepository
-	brandRepo     repository.BrandRepository
-	variantRepo   repository.ProductVariantRepository
-	imageRepo     repository.ProductImageRepository
+	productRepo    repository.ProductRepository
+	categoryRepo   repository.CategoryRepository
+	brandRepo      repository.BrandRepository
+	variantRepo    repository.ProductVariantRepository
+	imageRepo      repository.ProductImageRepository
+	alerts         productalerts.Notifier
+	images         *ImageUploader
+	searchLog      repository.SearchQueryRepository
+	suggestions    repository.SuggestionCache
+	importRepo     repository.ProductImportRepository
+	priceSchedules repository.PriceScheduleRepository
+	priceHistory   repository.PriceHistoryRepository
+	attributes     repository.AttributeRepository
 }
 
 func NewProductService(
@@ -26,13 +37,29 @@ func NewProductService(
 	brandRepo repository.BrandRepository,
 	variantRepo repository.ProductVariantRepository,
 	imageRepo repository.ProductImageRepository,
+	alerts productalerts.Notifier,
+	images *ImageUploader,
+	searchLog repository.SearchQueryRepository,
+	suggestions repository.SuggestionCache,
+	importRepo repository.ProductImportRepository,
+	priceSchedules repository.PriceScheduleRepository,
+	priceHistory repository.PriceHistoryRepository,
+	attributes repository.AttributeRepository,
 ) *ProductService {
 	return &ProductService{
-		productRepo:  productRepo,
-		categoryRepo: categoryRepo,
-		brandRepo:    brandRepo,
-		variantRepo:  variantRepo,
-		imageRepo:    imageRepo,
+		productRepo:    productRepo,
+		categoryRepo:   categoryRepo,
+		brandRepo:      brandRepo,
+		variantRepo:    variantRepo,
+		imageRepo:      imageRepo,
+		alerts:         alerts,
+		images:         images,
+		searchLog:      searchLog,
+		suggestions:    suggestions,
+		importRepo:     importRepo,
+		priceSchedules: priceSchedules,
+		priceHistory:   priceHistory,
+		attributes:     attributes,
 	}
 }
 
@@ -71,16 +98,32 @@ type UpdateProductRequest struct {
 
 type ProductSearchRequest struct {
 	Query        string    `json:"query"`
-	CategoryID   *string   `json:"category_id"`
-	BrandID      *string   `json:"brand_id"`
+	CategoryIDs  []string  `json:"category_ids"`
+	BrandIDs     []string  `json:"brand_ids"`
+	Sizes        []string  `json:"sizes"`
+	Colors       []string  `json:"colors"`
 	MinPrice     *float64  `json:"min_price"`
 	MaxPrice     *float64  `json:"max_price"`
 	Tags         []string  `json:"tags"`
 	InStock      *bool     `json:"in_stock"`
+	Attributes   []string  `json:"attributes"` // e.g. width=wide,extra-wide or drop<=6
 	SortBy       string    `json:"sort_by"`
 	SortOrder    string    `json:"sort_order"`
 	Page         int       `json:"page"`
 	Limit        int       `json:"limit"`
+	Cursor       string    `json:"cursor"`     // replaces Page; only without a query
+	SkipCount    bool      `json:"skip_count"` // leaves out the total
+}
+
+// ProductListRequest asks for a page of active products, by Cursor when set
+// and by Page otherwise
+type ProductListRequest struct {
+	SortBy    string `json:"sort_by"`
+	SortOrder string `json:"sort_order"`
+	Page      int    `json:"page"`
+	Limit     int    `json:"limit"`
+	Cursor    string `json:"cursor"`
+	SkipCount bool   `json:"skip_count"`
 }
 
 func (s *ProductService) CreateProduct(ctx context.Context, req *CreateProductRequest) (*entity.Product, error) {
@@ -155,16 +198,43 @@ func (s *ProductService) CreateProduct(ctx context.Context, req *CreateProductRe
 	if err != nil {
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
+	s.recordProductPrice(ctx, product, entity.PriceSourceManual, nil)
 
 	return s.productRepo.GetByID(ctx, product.ID)
 }
 
 func (s *ProductService) GetProductByID(ctx context.Context, id uuid.UUID) (*entity.Product, error) {
-	return s.productRepo.GetByID(ctx, id)
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.attachBreadcrumbs(ctx, product)
+	s.attachAttributes(ctx, product)
+	return product, nil
 }
 
 func (s *ProductService) GetProductBySlug(ctx context.Context, slug string) (*entity.Product, error) {
-	return s.productRepo.GetBySlug(ctx, slug)
+	product, err := s.productRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	s.attachBreadcrumbs(ctx, product)
+	s.attachAttributes(ctx, product)
+	return product, nil
+}
+
+// attachBreadcrumbs fills in the path to the product's category. The product
+// is still worth showing without it, so failures are only logged.
+func (s *ProductService) attachBreadcrumbs(ctx context.Context, product *entity.Product) {
+	if product.Category == nil {
+		return
+	}
+	breadcrumbs, err := categoryBreadcrumbs(ctx, s.categoryRepo, product.Category.ID)
+	if err != nil {
+		log.Printf("failed to get breadcrumbs for category %s: %v", product.Category.ID, err)
+		return
+	}
+	product.Category.Breadcrumbs = breadcrumbs
 }
 
 func (s *ProductService) UpdateProduct(ctx context.Context, id uuid.UUID, req *UpdateProductRequest) (*entity.Product, error) {
@@ -172,6 +242,7 @@ func (s *ProductService) UpdateProduct(ctx context.Context, id uuid.UUID, req *U
 	if err != nil {
 		return nil, err
 	}
+	oldPrice, oldComparePrice := product.Price, product.ComparePrice
 
 	// Update fields if provided
 	if req.Name != nil {
@@ -263,14 +334,47 @@ func (s *ProductService) UpdateProduct(ctx context.Context, id uuid.UUID, req *U
 		return nil, fmt.Errorf("failed to update product: %w", err)
 	}
 
+	if product.Price != oldPrice || !samePrice(product.ComparePrice, oldComparePrice) {
+		s.recordProductPrice(ctx, product, entity.PriceSourceManual, nil)
+	}
+
+	// Let users who wishlisted the product know it got cheaper. The update
+	// has already been saved, so a failed alert is only logged.
+	if product.Price < oldPrice && s.alerts != nil {
+		event := productalerts.PriceDrop(product.ID, product.Name, oldPrice, product.Price)
+		if err := s.alerts.Notify(ctx, event); err != nil {
+			log.Printf("product %s: failed to send price drop alert: %v", product.ID, err)
+		}
+	}
+
 	return s.productRepo.GetByID(ctx, product.ID)
 }
 
+// DeleteProduct deletes a product along with the stored files of its
+// uploaded product and variant images
 func (s *ProductService) DeleteProduct(ctx context.Context, id uuid.UUID) error {
-	return s.productRepo.Delete(ctx, id)
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.productRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	for _, image := range product.Images {
+		s.images.Remove(ctx, image.URL)
+	}
+	for _, variant := range product.Variants {
+		s.images.Remove(ctx, variant.Images...)
+	}
+
+	return nil
 }
 
-func (s *ProductService) SearchProducts(ctx context.Context, req *ProductSearchRequest) ([]*entity.Product, int64, error) {
+// SearchProducts returns a page of matching products along with facet counts
+// for narrowing the search down
+func (s *ProductService) SearchProducts(ctx context.Context, req *ProductSearchRequest) ([]*entity.Product, *pagination.Page, *repository.ProductFacets, error) {
 	// Set defaults
 	if req.Page <= 0 {
 		req.Page = 1
@@ -285,52 +389,85 @@ func (s *ProductService) SearchProducts(ctx context.Context, req *ProductSearchR
 		SortOrder: req.SortOrder,
 		Limit:     req.Limit,
 		Offset:    (req.Page - 1) * req.Limit,
+		Cursor:    req.Cursor,
+		SkipCount: req.SkipCount,
 		IsActive:  boolPtr(true), // Only show active products by default
 		InStock:   req.InStock,
 		MinPrice:  req.MinPrice,
 		MaxPrice:  req.MaxPrice,
 		Tags:      req.Tags,
+		Sizes:     req.Sizes,
+		Colors:    req.Colors,
 	}
 
 	// Parse UUIDs if provided
-	if req.CategoryID != nil && *req.CategoryID != "" {
-		id, err := uuid.Parse(*req.CategoryID)
-		if err != nil {
-			return nil, 0, errors.New("invalid category ID")
-		}
-		filters.CategoryID = &id
+	categoryIDs, err := parseUUIDs(req.CategoryIDs)
+	if err != nil {
+		return nil, nil, nil, errors.New("invalid category ID")
 	}
+	filters.CategoryIDs = categoryIDs
 
-	if req.BrandID != nil && *req.BrandID != "" {
-		id, err := uuid.Parse(*req.BrandID)
-		if err != nil {
-			return nil, 0, errors.New("invalid brand ID")
-		}
-		filters.BrandID = &id
+	brandIDs, err := parseUUIDs(req.BrandIDs)
+	if err != nil {
+		return nil, nil, nil, errors.New("invalid brand ID")
 	}
+	filters.BrandIDs = brandIDs
+
+	attributes, err := ParseAttributeFilters(req.Attributes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	filters.Attributes = attributes
+
+	query := strings.TrimSpace(req.Query)
 
 	// Use text search if query is provided
-	if req.Query != "" && strings.TrimSpace(req.Query) != "" {
-		return s.productRepo.SearchByText(ctx, strings.TrimSpace(req.Query), filters)
+	var products []*entity.Product
+	var page *pagination.Page
+	if query != "" {
+		products, page, err = s.productRepo.SearchByText(ctx, query, filters)
+	} else {
+		products, page, err = s.productRepo.List(ctx, filters)
+	}
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	return s.productRepo.List(ctx, filters)
+	// Log each search once, not again for every page of its results. Searches
+	// that skipped counting their results have no count to log.
+	if query != "" && req.Page == 1 && req.Cursor == "" && page.Total != nil {
+		s.logSearch(ctx, query, *page.Total)
+	}
+
+	facets, err := s.productRepo.SearchFacets(ctx, query, filters)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return products, page, facets, nil
 }
 
-func (s *ProductService) ListProducts(ctx context.Context, page, limit int) ([]*entity.Product, int64, error) {
-	if page <= 0 {
-		page = 1
+// ListProducts lists active products, newest first unless SortBy names
+// another of the sorts ProductFilters supports
+func (s *ProductService) ListProducts(ctx context.Context, req *ProductListRequest) ([]*entity.Product, *pagination.Page, error) {
+	if req.Page <= 0 {
+		req.Page = 1
 	}
-	if limit <= 0 {
-		limit = 20
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.SortBy == "" {
+		req.SortBy = "created_at"
 	}
 
 	filters := repository.ProductFilters{
-		Limit:    limit,
-		Offset:   (page - 1) * limit,
-		IsActive: boolPtr(true),
-		SortBy:   "created_at",
-		SortOrder: "desc",
+		Limit:     req.Limit,
+		Offset:    (req.Page - 1) * req.Limit,
+		Cursor:    req.Cursor,
+		SkipCount: req.SkipCount,
+		IsActive:  boolPtr(true),
+		SortBy:    req.SortBy,
+		SortOrder: req.SortOrder,
 	}
 
 	return s.productRepo.List(ctx, filters)
@@ -346,4 +483,20 @@ func (s *ProductService) GetRelatedProducts(ctx context.Context, productID uuid.
 // Helper function
 func boolPtr(b bool) *bool {
 	return &b
-}
\ No newline at end of file
+}
+
+// parseUUIDs parses a list of IDs, skipping empty entries
+func parseUUIDs(values []string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -2,34 +2,42 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/pkg/pagination"
+	"solemate/pkg/productalerts"
 	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
 )
 
-// MockProductRepository for testing
+// MockProductRepository is a mock implementation of repository.ProductRepository
 type MockProductRepository struct {
 	mock.Mock
 }
 
-func (m *MockProductRepository) CreateProduct(ctx context.Context, product *entity.Product) error {
+func (m *MockProductRepository) Create(ctx context.Context, product *entity.Product) error {
 	args := m.Called(ctx, product)
+	if product.ID == uuid.Nil {
+		product.ID = uuid.New()
+	}
 	return args.Error(0)
 }
 
-func (m *MockProductRepository) GetProductByID(ctx context.Context, productID uuid.UUID) (*entity.Product, error) {
-	args := m.Called(ctx, productID)
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Product, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Product), args.Error(1)
 }
 
-func (m *MockProductRepository) GetProductBySKU(ctx context.Context, sku string) (*entity.Product, error) {
+func (m *MockProductRepository) GetBySKU(ctx context.Context, sku string) (*entity.Product, error) {
 	args := m.Called(ctx, sku)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -37,404 +45,664 @@ func (m *MockProductRepository) GetProductBySKU(ctx context.Context, sku string)
 	return args.Get(0).(*entity.Product), args.Error(1)
 }
 
-func (m *MockProductRepository) UpdateProduct(ctx context.Context, product *entity.Product) error {
+func (m *MockProductRepository) GetBySlug(ctx context.Context, slug string) (*entity.Product, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) Update(ctx context.Context, product *entity.Product) error {
 	args := m.Called(ctx, product)
 	return args.Error(0)
 }
 
-func (m *MockProductRepository) DeleteProduct(ctx context.Context, productID uuid.UUID) error {
-	args := m.Called(ctx, productID)
+func (m *MockProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockProductRepository) SearchProducts(ctx context.Context, filters *ProductFilters) ([]*entity.Product, int64, error) {
+func (m *MockProductRepository) List(ctx context.Context, filters repository.ProductFilters) ([]*entity.Product, *pagination.Page, error) {
 	args := m.Called(ctx, filters)
-	return args.Get(0).([]*entity.Product), args.Get(1).(int64), args.Error(2)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*entity.Product), args.Get(1).(*pagination.Page), args.Error(2)
 }
 
-func (m *MockProductRepository) GetProductsByCategory(ctx context.Context, categoryID uuid.UUID, limit, offset int) ([]*entity.Product, int64, error) {
-	args := m.Called(ctx, categoryID, limit, offset)
-	return args.Get(0).([]*entity.Product), args.Get(1).(int64), args.Error(2)
+func (m *MockProductRepository) SearchByText(ctx context.Context, query string, filters repository.ProductFilters) ([]*entity.Product, *pagination.Page, error) {
+	args := m.Called(ctx, query, filters)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*entity.Product), args.Get(1).(*pagination.Page), args.Error(2)
 }
 
-func (m *MockProductRepository) GetProductsByBrand(ctx context.Context, brandID uuid.UUID, limit, offset int) ([]*entity.Product, int64, error) {
-	args := m.Called(ctx, brandID, limit, offset)
-	return args.Get(0).([]*entity.Product), args.Get(1).(int64), args.Error(2)
+func (m *MockProductRepository) SearchFacets(ctx context.Context, query string, filters repository.ProductFilters) (*repository.ProductFacets, error) {
+	args := m.Called(ctx, query, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.ProductFacets), args.Error(1)
 }
 
-func (m *MockProductRepository) GetFeaturedProducts(ctx context.Context, limit int) ([]*entity.Product, error) {
-	args := m.Called(ctx, limit)
+func (m *MockProductRepository) Suggest(ctx context.Context, query string, limit int) (*repository.SearchSuggestions, error) {
+	args := m.Called(ctx, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.SearchSuggestions), args.Error(1)
+}
+
+func (m *MockProductRepository) GetRelatedProducts(ctx context.Context, productID uuid.UUID, limit int) ([]*entity.Product, error) {
+	args := m.Called(ctx, productID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]*entity.Product), args.Error(1)
 }
 
-func (m *MockProductRepository) UpdateProductStock(ctx context.Context, productID uuid.UUID, stockChange int) error {
-	args := m.Called(ctx, productID, stockChange)
-	return args.Error(0)
+func (m *MockProductRepository) GetActiveByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Product, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Product), args.Error(1)
 }
 
-// MockCategoryRepository for testing
+func (m *MockProductRepository) ListAfterSKU(ctx context.Context, afterSKU string, limit int) ([]*entity.Product, error) {
+	args := m.Called(ctx, afterSKU, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) UpdatePopularity(ctx context.Context, signals []*repository.PopularitySignals, weights repository.PopularityWeights) (int64, error) {
+	args := m.Called(ctx, signals, weights)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockCategoryRepository is a mock implementation of repository.CategoryRepository
 type MockCategoryRepository struct {
 	mock.Mock
 }
 
-func (m *MockCategoryRepository) CreateCategory(ctx context.Context, category *entity.Category) error {
+func (m *MockCategoryRepository) Create(ctx context.Context, category *entity.Category) error {
 	args := m.Called(ctx, category)
 	return args.Error(0)
 }
 
-func (m *MockCategoryRepository) GetCategoryByID(ctx context.Context, categoryID uuid.UUID) (*entity.Category, error) {
-	args := m.Called(ctx, categoryID)
+func (m *MockCategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Category, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Category), args.Error(1)
 }
 
-func (m *MockCategoryRepository) GetAllCategories(ctx context.Context) ([]*entity.Category, error) {
+func (m *MockCategoryRepository) GetBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, category *entity.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) List(ctx context.Context, limit, offset int) ([]*entity.Category, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*entity.Category), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCategoryRepository) GetChildren(ctx context.Context, parentID uuid.UUID) ([]*entity.Category, error) {
+	args := m.Called(ctx, parentID)
+	return args.Get(0).([]*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetTree(ctx context.Context) ([]*entity.Category, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]*entity.Category), args.Error(1)
 }
 
-func (m *MockCategoryRepository) UpdateCategory(ctx context.Context, category *entity.Category) error {
-	args := m.Called(ctx, category)
+func (m *MockCategoryRepository) GetAncestors(ctx context.Context, id uuid.UUID) ([]*entity.Category, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetSiblings(ctx context.Context, parentID *uuid.UUID) ([]*entity.Category, error) {
+	args := m.Called(ctx, parentID)
+	return args.Get(0).([]*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Move(ctx context.Context, id uuid.UUID, parentID *uuid.UUID, siblingIDs []uuid.UUID) error {
+	args := m.Called(ctx, id, parentID, siblingIDs)
 	return args.Error(0)
 }
 
-func (m *MockCategoryRepository) DeleteCategory(ctx context.Context, categoryID uuid.UUID) error {
-	args := m.Called(ctx, categoryID)
+func (m *MockCategoryRepository) Reorder(ctx context.Context, parentID *uuid.UUID, categoryIDs []uuid.UUID) error {
+	args := m.Called(ctx, parentID, categoryIDs)
 	return args.Error(0)
 }
 
-// MockBrandRepository for testing
+// MockBrandRepository is a mock implementation of repository.BrandRepository
 type MockBrandRepository struct {
 	mock.Mock
 }
 
-func (m *MockBrandRepository) CreateBrand(ctx context.Context, brand *entity.Brand) error {
+func (m *MockBrandRepository) Create(ctx context.Context, brand *entity.Brand) error {
 	args := m.Called(ctx, brand)
 	return args.Error(0)
 }
 
-func (m *MockBrandRepository) GetBrandByID(ctx context.Context, brandID uuid.UUID) (*entity.Brand, error) {
-	args := m.Called(ctx, brandID)
+func (m *MockBrandRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Brand, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Brand), args.Error(1)
 }
 
-func (m *MockBrandRepository) GetAllBrands(ctx context.Context) ([]*entity.Brand, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]*entity.Brand), args.Error(1)
+func (m *MockBrandRepository) GetBySlug(ctx context.Context, slug string) (*entity.Brand, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Brand), args.Error(1)
 }
 
-func (m *MockBrandRepository) UpdateBrand(ctx context.Context, brand *entity.Brand) error {
+func (m *MockBrandRepository) Update(ctx context.Context, brand *entity.Brand) error {
 	args := m.Called(ctx, brand)
 	return args.Error(0)
 }
 
-func (m *MockBrandRepository) DeleteBrand(ctx context.Context, brandID uuid.UUID) error {
-	args := m.Called(ctx, brandID)
+func (m *MockBrandRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func TestProductService_CreateProduct(t *testing.T) {
-	mockProductRepo := new(MockProductRepository)
-	mockCategoryRepo := new(MockCategoryRepository)
-	mockBrandRepo := new(MockBrandRepository)
+func (m *MockBrandRepository) List(ctx context.Context, limit, offset int) ([]*entity.Brand, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*entity.Brand), args.Get(1).(int64), args.Error(2)
+}
 
-	productService := NewProductService(mockProductRepo, mockCategoryRepo, mockBrandRepo)
-	ctx := context.Background()
+// MockVariantRepository is a mock implementation of repository.ProductVariantRepository
+type MockVariantRepository struct {
+	mock.Mock
+}
 
-	t.Run("successful product creation", func(t *testing.T) {
-		categoryID := uuid.New()
-		brandID := uuid.New()
+func (m *MockVariantRepository) Create(ctx context.Context, variant *entity.ProductVariant) error {
+	args := m.Called(ctx, variant)
+	if variant.ID == uuid.Nil {
+		variant.ID = uuid.New()
+	}
+	return args.Error(0)
+}
 
-		request := &CreateProductRequest{
-			Name:        "Nike Air Max",
-			Description: "Comfortable running shoes",
-			SKU:         "NIKE-AM-001",
-			Price:       149.99,
-			CategoryID:  categoryID,
-			BrandID:     brandID,
-			Stock:       100,
-			IsActive:    true,
-		}
+func (m *MockVariantRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ProductVariant, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ProductVariant), args.Error(1)
+}
 
-		// Mock category and brand exist
-		category := &entity.Category{ID: categoryID, Name: "Running Shoes"}
-		brand := &entity.Brand{ID: brandID, Name: "Nike"}
+func (m *MockVariantRepository) GetBySKU(ctx context.Context, sku string) (*entity.ProductVariant, error) {
+	args := m.Called(ctx, sku)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ProductVariant), args.Error(1)
+}
 
-		mockCategoryRepo.On("GetCategoryByID", ctx, categoryID).Return(category, nil)
-		mockBrandRepo.On("GetBrandByID", ctx, brandID).Return(brand, nil)
-		mockProductRepo.On("GetProductBySKU", ctx, request.SKU).Return((*entity.Product)(nil), entity.ErrProductNotFound)
-		mockProductRepo.On("CreateProduct", ctx, mock.AnythingOfType("*entity.Product")).Return(nil)
+func (m *MockVariantRepository) GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entity.ProductVariant, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]*entity.ProductVariant), args.Error(1)
+}
 
-		response, err := productService.CreateProduct(ctx, request)
+func (m *MockVariantRepository) Update(ctx context.Context, variant *entity.ProductVariant) error {
+	args := m.Called(ctx, variant)
+	return args.Error(0)
+}
 
-		assert.NoError(t, err)
-		assert.NotNil(t, response)
-		assert.Equal(t, request.Name, response.Name)
-		assert.Equal(t, request.SKU, response.SKU)
-		assert.Equal(t, request.Price, response.Price)
+func (m *MockVariantRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
 
-		mockProductRepo.AssertExpectations(t)
-		mockCategoryRepo.AssertExpectations(t)
-		mockBrandRepo.AssertExpectations(t)
-	})
+func (m *MockVariantRepository) UpdateStock(ctx context.Context, id uuid.UUID, quantity int) error {
+	args := m.Called(ctx, id, quantity)
+	return args.Error(0)
+}
 
-	t.Run("duplicate SKU error", func(t *testing.T) {
-		categoryID := uuid.New()
-		brandID := uuid.New()
+func (m *MockVariantRepository) Reorder(ctx context.Context, productID uuid.UUID, variantIDs []uuid.UUID) error {
+	args := m.Called(ctx, productID, variantIDs)
+	return args.Error(0)
+}
 
-		request := &CreateProductRequest{
-			Name:       "Nike Air Max",
-			SKU:        "EXISTING-SKU",
-			CategoryID: categoryID,
-			BrandID:    brandID,
-		}
+// MockImageRepository is a mock implementation of repository.ProductImageRepository
+type MockImageRepository struct {
+	mock.Mock
+}
 
-		existingProduct := &entity.Product{
-			ID:  uuid.New(),
-			SKU: "EXISTING-SKU",
-		}
+func (m *MockImageRepository) Create(ctx context.Context, image *entity.ProductImage) error {
+	args := m.Called(ctx, image)
+	if image.ID == uuid.Nil {
+		image.ID = uuid.New()
+	}
+	return args.Error(0)
+}
 
-		mockProductRepo.On("GetProductBySKU", ctx, request.SKU).Return(existingProduct, nil)
+func (m *MockImageRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ProductImage, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ProductImage), args.Error(1)
+}
 
-		response, err := productService.CreateProduct(ctx, request)
+func (m *MockImageRepository) GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entity.ProductImage, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]*entity.ProductImage), args.Error(1)
+}
 
-		assert.Error(t, err)
-		assert.Nil(t, response)
-		assert.Contains(t, err.Error(), "product with SKU already exists")
+func (m *MockImageRepository) Update(ctx context.Context, image *entity.ProductImage) error {
+	args := m.Called(ctx, image)
+	return args.Error(0)
+}
 
-		mockProductRepo.AssertExpectations(t)
-	})
+func (m *MockImageRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
 
-	t.Run("invalid category", func(t *testing.T) {
-		categoryID := uuid.New()
-		brandID := uuid.New()
+func (m *MockImageRepository) SetPrimary(ctx context.Context, productID, imageID uuid.UUID) error {
+	args := m.Called(ctx, productID, imageID)
+	return args.Error(0)
+}
 
-		request := &CreateProductRequest{
-			Name:       "Nike Air Max",
-			SKU:        "NIKE-AM-001",
-			CategoryID: categoryID,
-			BrandID:    brandID,
-		}
+func (m *MockImageRepository) Reorder(ctx context.Context, productID uuid.UUID, imageIDs []uuid.UUID) error {
+	args := m.Called(ctx, productID, imageIDs)
+	return args.Error(0)
+}
 
-		mockCategoryRepo.On("GetCategoryByID", ctx, categoryID).Return((*entity.Category)(nil), entity.ErrCategoryNotFound)
+// MockPriceHistoryRepository is a mock implementation of repository.PriceHistoryRepository
+type MockPriceHistoryRepository struct {
+	mock.Mock
+}
 
-		response, err := productService.CreateProduct(ctx, request)
+func (m *MockPriceHistoryRepository) Create(ctx context.Context, entry *entity.PriceHistory) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
 
-		assert.Error(t, err)
-		assert.Nil(t, response)
-		assert.Contains(t, err.Error(), "category not found")
+func (m *MockPriceHistoryRepository) GetTimeline(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, since time.Time) ([]*entity.PriceHistory, error) {
+	args := m.Called(ctx, productID, variantID, since)
+	return args.Get(0).([]*entity.PriceHistory), args.Error(1)
+}
 
-		mockCategoryRepo.AssertExpectations(t)
-	})
+func (m *MockPriceHistoryRepository) LowestPrice(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, since time.Time) (*float64, error) {
+	args := m.Called(ctx, productID, variantID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*float64), args.Error(1)
+}
+
+// MockAttributeRepository is a mock implementation of repository.AttributeRepository
+type MockAttributeRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttributeRepository) CreateDefinition(ctx context.Context, definition *entity.AttributeDefinition) error {
+	args := m.Called(ctx, definition)
+	return args.Error(0)
+}
+
+func (m *MockAttributeRepository) GetDefinition(ctx context.Context, id uuid.UUID) (*entity.AttributeDefinition, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeRepository) UpdateDefinition(ctx context.Context, definition *entity.AttributeDefinition) error {
+	args := m.Called(ctx, definition)
+	return args.Error(0)
+}
+
+func (m *MockAttributeRepository) DeleteDefinition(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAttributeRepository) GetDefinitionsByCategoryIDs(ctx context.Context, categoryIDs []uuid.UUID) ([]*entity.AttributeDefinition, error) {
+	args := m.Called(ctx, categoryIDs)
+	return args.Get(0).([]*entity.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeRepository) GetValues(ctx context.Context, productID uuid.UUID) ([]*entity.ProductAttributeValue, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]*entity.ProductAttributeValue), args.Error(1)
+}
+
+func (m *MockAttributeRepository) ReplaceValues(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, values []*entity.ProductAttributeValue) error {
+	args := m.Called(ctx, productID, variantID, values)
+	return args.Error(0)
+}
+
+// MockNotifier is a mock implementation of productalerts.Notifier
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, event *productalerts.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+// externalImageStorage is an image storage that owns no URLs, as if every
+// image were hosted elsewhere
+type externalImageStorage struct{}
+
+func (externalImageStorage) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	return "", errors.New("not supported")
+}
+
+func (externalImageStorage) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+func (externalImageStorage) KeyForURL(url string) (string, bool) {
+	return "", false
+}
+
+// productServiceMocks holds the mocked dependencies of a ProductService
+type productServiceMocks struct {
+	products       *MockProductRepository
+	categories     *MockCategoryRepository
+	brands         *MockBrandRepository
+	variants       *MockVariantRepository
+	images         *MockImageRepository
+	alerts         *MockNotifier
+	priceHistory   *MockPriceHistoryRepository
+	priceSchedules *MockPriceScheduleRepository
+	attributes     *MockAttributeRepository
+	imports        *MockImportRepository
 }
 
-func TestProductService_GetProduct(t *testing.T) {
-	mockProductRepo := new(MockProductRepository)
-	mockCategoryRepo := new(MockCategoryRepository)
-	mockBrandRepo := new(MockBrandRepository)
+func newTestProductService() (*ProductService, *productServiceMocks) {
+	mocks := &productServiceMocks{
+		products:       new(MockProductRepository),
+		categories:     new(MockCategoryRepository),
+		brands:         new(MockBrandRepository),
+		variants:       new(MockVariantRepository),
+		images:         new(MockImageRepository),
+		alerts:         new(MockNotifier),
+		priceHistory:   new(MockPriceHistoryRepository),
+		priceSchedules: new(MockPriceScheduleRepository),
+		attributes:     new(MockAttributeRepository),
+		imports:        new(MockImportRepository),
+	}
+	service := NewProductService(mocks.products, mocks.categories, mocks.brands, mocks.variants, mocks.images, mocks.alerts,
+		NewImageUploader(externalImageStorage{}), nil, nil, mocks.imports, mocks.priceSchedules, mocks.priceHistory, mocks.attributes)
+	return service, mocks
+}
 
-	productService := NewProductService(mockProductRepo, mockCategoryRepo, mockBrandRepo)
+func TestProductService_CreateProduct(t *testing.T) {
 	ctx := context.Background()
 
-	t.Run("successful product retrieval", func(t *testing.T) {
-		productID := uuid.New()
+	t.Run("successful product creation", func(t *testing.T) {
+		service, mocks := newTestProductService()
 		categoryID := uuid.New()
-		brandID := uuid.New()
+		categoryIDString := categoryID.String()
 
-		product := &entity.Product{
-			ID:          productID,
+		request := &CreateProductRequest{
+			SKU:         "NIKE-AM-001",
 			Name:        "Nike Air Max",
+			Slug:        "nike-air-max",
 			Description: "Comfortable running shoes",
-			SKU:         "NIKE-AM-001",
+			CategoryID:  &categoryIDString,
 			Price:       149.99,
-			CategoryID:  categoryID,
-			BrandID:     brandID,
-			Stock:       100,
-			IsActive:    true,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
 		}
 
-		mockProductRepo.On("GetProductByID", ctx, productID).Return(product, nil)
+		mocks.products.On("GetBySKU", ctx, request.SKU).Return(nil, errors.New("product not found"))
+		mocks.products.On("GetBySlug", ctx, request.Slug).Return(nil, errors.New("product not found"))
+		mocks.categories.On("GetByID", ctx, categoryID).Return(&entity.Category{ID: categoryID, Name: "Running"}, nil)
+		mocks.products.On("Create", ctx, mock.MatchedBy(func(p *entity.Product) bool {
+			return p.SKU == request.SKU && p.Price == request.Price && p.IsActive && *p.CategoryID == categoryID
+		})).Return(nil)
+		mocks.priceHistory.On("Create", ctx, mock.AnythingOfType("*entity.PriceHistory")).Return(nil)
+		mocks.products.On("GetByID", ctx, mock.AnythingOfType("uuid.UUID")).Return(&entity.Product{SKU: request.SKU, Name: request.Name}, nil)
+
+		product, err := service.CreateProduct(ctx, request)
+
+		require.NoError(t, err)
+		assert.Equal(t, request.Name, product.Name)
+		mocks.products.AssertExpectations(t)
+		mocks.categories.AssertExpectations(t)
+		mocks.priceHistory.AssertExpectations(t)
+	})
 
-		response, err := productService.GetProduct(ctx, productID)
+	t.Run("duplicate SKU", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		request := &CreateProductRequest{SKU: "EXISTING-SKU", Name: "Nike Air Max", Slug: "nike-air-max", Price: 100}
 
-		assert.NoError(t, err)
-		assert.NotNil(t, response)
-		assert.Equal(t, product.Name, response.Name)
-		assert.Equal(t, product.SKU, response.SKU)
-		assert.Equal(t, product.Price, response.Price)
+		mocks.products.On("GetBySKU", ctx, request.SKU).Return(&entity.Product{ID: uuid.New(), SKU: request.SKU}, nil)
 
-		mockProductRepo.AssertExpectations(t)
-	})
+		product, err := service.CreateProduct(ctx, request)
 
-	t.Run("product not found", func(t *testing.T) {
-		productID := uuid.New()
+		assert.Nil(t, product)
+		assert.EqualError(t, err, "product with this SKU already exists")
+		mocks.products.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
 
-		mockProductRepo.On("GetProductByID", ctx, productID).Return((*entity.Product)(nil), entity.ErrProductNotFound)
+	t.Run("unknown category", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		categoryID := uuid.New()
+		categoryIDString := categoryID.String()
+		request := &CreateProductRequest{SKU: "NIKE-AM-001", Name: "Nike Air Max", Slug: "nike-air-max", CategoryID: &categoryIDString}
 
-		response, err := productService.GetProduct(ctx, productID)
+		mocks.products.On("GetBySKU", ctx, request.SKU).Return(nil, errors.New("product not found"))
+		mocks.products.On("GetBySlug", ctx, request.Slug).Return(nil, errors.New("product not found"))
+		mocks.categories.On("GetByID", ctx, categoryID).Return(nil, errors.New("category not found"))
 
-		assert.Error(t, err)
-		assert.Nil(t, response)
-		assert.Equal(t, entity.ErrProductNotFound, err)
+		product, err := service.CreateProduct(ctx, request)
 
-		mockProductRepo.AssertExpectations(t)
+		assert.Nil(t, product)
+		assert.EqualError(t, err, "category not found")
+		mocks.products.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 	})
 }
 
-func TestProductService_SearchProducts(t *testing.T) {
-	mockProductRepo := new(MockProductRepository)
-	mockCategoryRepo := new(MockCategoryRepository)
-	mockBrandRepo := new(MockBrandRepository)
-
-	productService := NewProductService(mockProductRepo, mockCategoryRepo, mockBrandRepo)
+func TestProductService_GetProductByID(t *testing.T) {
 	ctx := context.Background()
 
-	t.Run("successful product search", func(t *testing.T) {
-		request := &SearchProductsRequest{
-			Query:    "Nike",
-			MinPrice: floatPtr(50.0),
-			MaxPrice: floatPtr(200.0),
-			Limit:    10,
-			Offset:   0,
-		}
-
-		products := []*entity.Product{
-			{
-				ID:    uuid.New(),
-				Name:  "Nike Air Max",
-				Price: 149.99,
-			},
-			{
-				ID:    uuid.New(),
-				Name:  "Nike Free Run",
-				Price: 89.99,
-			},
-		}
-
-		filters := &ProductFilters{
-			Query:    request.Query,
-			MinPrice: request.MinPrice,
-			MaxPrice: request.MaxPrice,
-			Limit:    request.Limit,
-			Offset:   request.Offset,
-		}
+	t.Run("attaches attributes", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+		variantID := uuid.New()
+		product := &entity.Product{ID: productID, Name: "Nike Air Max", Variants: []entity.ProductVariant{{ID: variantID}}}
+
+		mocks.products.On("GetByID", ctx, productID).Return(product, nil)
+		mocks.attributes.On("GetValues", ctx, productID).Return([]*entity.ProductAttributeValue{
+			{ProductID: productID, Value: "wide"},
+			{ProductID: productID, VariantID: &variantID, Value: "red"},
+		}, nil)
+
+		result, err := service.GetProductByID(ctx, productID)
+
+		require.NoError(t, err)
+		require.Len(t, result.Attributes, 1)
+		assert.Equal(t, "wide", result.Attributes[0].Value)
+		require.Len(t, result.Variants[0].Attributes, 1)
+		assert.Equal(t, "red", result.Variants[0].Attributes[0].Value)
+	})
 
-		mockProductRepo.On("SearchProducts", ctx, mock.MatchedBy(func(f *ProductFilters) bool {
-			return f.Query == filters.Query &&
-				   *f.MinPrice == *filters.MinPrice &&
-				   *f.MaxPrice == *filters.MaxPrice
-		})).Return(products, int64(2), nil)
+	t.Run("product not found", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
 
-		response, err := productService.SearchProducts(ctx, request)
+		mocks.products.On("GetByID", ctx, productID).Return(nil, errors.New("product not found"))
 
-		assert.NoError(t, err)
-		assert.NotNil(t, response)
-		assert.Len(t, response.Products, 2)
-		assert.Equal(t, int64(2), response.Total)
+		result, err := service.GetProductByID(ctx, productID)
 
-		mockProductRepo.AssertExpectations(t)
+		assert.Nil(t, result)
+		assert.Error(t, err)
 	})
 }
 
 func TestProductService_UpdateProduct(t *testing.T) {
-	mockProductRepo := new(MockProductRepository)
-	mockCategoryRepo := new(MockCategoryRepository)
-	mockBrandRepo := new(MockBrandRepository)
-
-	productService := NewProductService(mockProductRepo, mockCategoryRepo, mockBrandRepo)
 	ctx := context.Background()
 
-	t.Run("successful product update", func(t *testing.T) {
+	t.Run("price drop records history and alerts watchers", func(t *testing.T) {
+		service, mocks := newTestProductService()
 		productID := uuid.New()
-		categoryID := uuid.New()
-		brandID := uuid.New()
-
-		existingProduct := &entity.Product{
-			ID:         productID,
-			Name:       "Nike Air Max",
-			Price:      149.99,
-			CategoryID: categoryID,
-			BrandID:    brandID,
-			Stock:      100,
-		}
+		existing := &entity.Product{ID: productID, Name: "Nike Air Max", Price: 150}
+
+		mocks.products.On("GetByID", ctx, productID).Return(existing, nil)
+		mocks.products.On("Update", ctx, mock.MatchedBy(func(p *entity.Product) bool {
+			return p.Name == "Nike Air Max 2026" && p.Price == 120
+		})).Return(nil)
+		mocks.priceHistory.On("Create", ctx, mock.MatchedBy(func(entry *entity.PriceHistory) bool {
+			return entry.ProductID == productID && *entry.Price == 120 && entry.Source == entity.PriceSourceManual
+		})).Return(nil)
+		mocks.alerts.On("Notify", ctx, productalerts.PriceDrop(productID, "Nike Air Max 2026", 150, 120)).Return(nil)
+
+		_, err := service.UpdateProduct(ctx, productID, &UpdateProductRequest{
+			Name:  stringPtr("Nike Air Max 2026"),
+			Price: floatPtr(120),
+		})
+
+		require.NoError(t, err)
+		mocks.products.AssertExpectations(t)
+		mocks.priceHistory.AssertExpectations(t)
+		mocks.alerts.AssertExpectations(t)
+	})
 
-		request := &UpdateProductRequest{
-			Name:  stringPtr("Nike Air Max 2023"),
-			Price: floatPtr(159.99),
-			Stock: intPtr(150),
-		}
+	t.Run("price increase does not alert", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID, Price: 100}, nil)
+		mocks.products.On("Update", ctx, mock.AnythingOfType("*entity.Product")).Return(nil)
+		mocks.priceHistory.On("Create", ctx, mock.AnythingOfType("*entity.PriceHistory")).Return(nil)
 
-		mockProductRepo.On("GetProductByID", ctx, productID).Return(existingProduct, nil)
-		mockProductRepo.On("UpdateProduct", ctx, mock.AnythingOfType("*entity.Product")).Return(nil)
+		_, err := service.UpdateProduct(ctx, productID, &UpdateProductRequest{Price: floatPtr(110)})
+
+		require.NoError(t, err)
+		mocks.alerts.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("negative price", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
 
-		response, err := productService.UpdateProduct(ctx, productID, request)
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID, Price: 100}, nil)
 
-		assert.NoError(t, err)
-		assert.NotNil(t, response)
-		assert.Equal(t, "Nike Air Max 2023", response.Name)
-		assert.Equal(t, 159.99, response.Price)
-		assert.Equal(t, 150, response.Stock)
+		_, err := service.UpdateProduct(ctx, productID, &UpdateProductRequest{Price: floatPtr(-1)})
 
-		mockProductRepo.AssertExpectations(t)
+		assert.EqualError(t, err, "price must be non-negative")
+		mocks.products.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
 	})
 
 	t.Run("product not found", func(t *testing.T) {
+		service, mocks := newTestProductService()
 		productID := uuid.New()
 
-		request := &UpdateProductRequest{
-			Name: stringPtr("Updated Product"),
-		}
-
-		mockProductRepo.On("GetProductByID", ctx, productID).Return((*entity.Product)(nil), entity.ErrProductNotFound)
+		mocks.products.On("GetByID", ctx, productID).Return(nil, errors.New("product not found"))
 
-		response, err := productService.UpdateProduct(ctx, productID, request)
+		result, err := service.UpdateProduct(ctx, productID, &UpdateProductRequest{Name: stringPtr("Updated Product")})
 
+		assert.Nil(t, result)
 		assert.Error(t, err)
-		assert.Nil(t, response)
-		assert.Equal(t, entity.ErrProductNotFound, err)
-
-		mockProductRepo.AssertExpectations(t)
 	})
 }
 
 func TestProductService_DeleteProduct(t *testing.T) {
-	mockProductRepo := new(MockProductRepository)
-	mockCategoryRepo := new(MockCategoryRepository)
-	mockBrandRepo := new(MockBrandRepository)
-
-	productService := NewProductService(mockProductRepo, mockCategoryRepo, mockBrandRepo)
 	ctx := context.Background()
 
 	t.Run("successful product deletion", func(t *testing.T) {
+		service, mocks := newTestProductService()
 		productID := uuid.New()
 
-		mockProductRepo.On("DeleteProduct", ctx, productID).Return(nil)
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.products.On("Delete", ctx, productID).Return(nil)
 
-		err := productService.DeleteProduct(ctx, productID)
-
-		assert.NoError(t, err)
-
-		mockProductRepo.AssertExpectations(t)
+		assert.NoError(t, service.DeleteProduct(ctx, productID))
+		mocks.products.AssertExpectations(t)
 	})
 
 	t.Run("product not found", func(t *testing.T) {
+		service, mocks := newTestProductService()
 		productID := uuid.New()
 
-		mockProductRepo.On("DeleteProduct", ctx, productID).Return(entity.ErrProductNotFound)
+		mocks.products.On("GetByID", ctx, productID).Return(nil, errors.New("product not found"))
+
+		assert.Error(t, service.DeleteProduct(ctx, productID))
+		mocks.products.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProductService_SearchProducts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("passes parsed attribute filters to the repository", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		total := int64(1)
+		products := []*entity.Product{{ID: uuid.New(), Name: "Nike Air Max"}}
+
+		matchesFilters := mock.MatchedBy(func(f repository.ProductFilters) bool {
+			return *f.IsActive && f.Limit == 20 && f.Offset == 0 && len(f.Attributes) == 2 &&
+				f.Attributes[0].Code == "width" && f.Attributes[1].Operator == "<="
+		})
+		mocks.products.On("List", ctx, matchesFilters).Return(products, &pagination.Page{Total: &total}, nil)
+		mocks.products.On("SearchFacets", ctx, "", matchesFilters).Return(&repository.ProductFacets{}, nil)
+
+		result, page, _, err := service.SearchProducts(ctx, &ProductSearchRequest{
+			Attributes: []string{"width=wide", "drop<=6"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, products, result)
+		assert.Equal(t, int64(1), *page.Total)
+		mocks.products.AssertExpectations(t)
+	})
 
-		err := productService.DeleteProduct(ctx, productID)
+	t.Run("text search without a count", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		products := []*entity.Product{{ID: uuid.New(), Name: "Nike Air Max"}}
 
-		assert.Error(t, err)
-		assert.Equal(t, entity.ErrProductNotFound, err)
+		skipsCount := mock.MatchedBy(func(f repository.ProductFilters) bool { return f.SkipCount })
+		mocks.products.On("SearchByText", ctx, "air max", skipsCount).Return(products, &pagination.Page{NextCursor: "next"}, nil)
+		mocks.products.On("SearchFacets", ctx, "air max", skipsCount).Return(&repository.ProductFacets{}, nil)
 
-		mockProductRepo.AssertExpectations(t)
+		result, page, _, err := service.SearchProducts(ctx, &ProductSearchRequest{Query: "air max", SkipCount: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, products, result)
+		assert.Nil(t, page.Total)
+		assert.Equal(t, "next", page.NextCursor)
+	})
+
+	t.Run("invalid attribute filter", func(t *testing.T) {
+		service, mocks := newTestProductService()
+
+		_, _, _, err := service.SearchProducts(ctx, &ProductSearchRequest{Attributes: []string{"drop<=six"}})
+
+		assert.Error(t, err)
+		mocks.products.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
 	})
 }
 
@@ -449,4 +717,4 @@ func floatPtr(f float64) *float64 {
 
 func intPtr(i int) *int {
 	return &i
-}
\ No newline at end of file
+}
@@ -0,0 +1,497 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"solemate/pkg/productalerts"
+	"solemate/pkg/utils"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+var (
+	ErrProductNotFound = errors.New("product not found")
+	ErrVariantNotFound = errors.New("variant not found")
+	ErrImageNotFound   = errors.New("image not found")
+)
+
+// Variant management
+type CreateVariantRequest struct {
+	SKU    string   `json:"sku" binding:"required"`
+	Size   string   `json:"size"`
+	Color  string   `json:"color"`
+	Price  *float64 `json:"price"`
+	Stock  int      `json:"stock" binding:"min=0"`
+	Weight *float64 `json:"weight"`
+	Images []string `json:"images"`
+}
+
+type UpdateVariantRequest struct {
+	SKU    *string   `json:"sku"`
+	Size   *string   `json:"size"`
+	Color  *string   `json:"color"`
+	Price  *float64  `json:"price"`
+	Weight *float64  `json:"weight"`
+	Images *[]string `json:"images"`
+}
+
+type UpdateVariantStockRequest struct {
+	Stock *int `json:"stock" binding:"required,min=0"`
+}
+
+// Image management
+type CreateImageRequest struct {
+	URL       string `json:"url" binding:"required,url"`
+	AltText   string `json:"alt_text"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+// UploadImageRequest carries the form fields sent alongside an uploaded image
+type UploadImageRequest struct {
+	AltText   string `form:"alt_text"`
+	IsPrimary bool   `form:"is_primary"`
+}
+
+type UpdateImageRequest struct {
+	URL     *string `json:"url" binding:"omitempty,url"`
+	AltText *string `json:"alt_text"`
+}
+
+// ReorderRequest lists every variant or image of a product in the new display order
+type ReorderRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required,min=1"`
+}
+
+// ListVariants returns all of a product's variants, including inactive ones
+func (s *ProductService) ListVariants(ctx context.Context, productID uuid.UUID) ([]*entity.ProductVariant, error) {
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.variantRepo.GetByProductID(ctx, productID)
+}
+
+// CreateVariant adds a size/colour variant to a product. Variant SKUs are
+// unique across all products.
+func (s *ProductService) CreateVariant(ctx context.Context, productID uuid.UUID, req *CreateVariantRequest) (*entity.ProductVariant, error) {
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	sku := utils.SanitizeString(req.SKU)
+	if err := s.checkVariantSKU(ctx, sku, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	if req.Price != nil && *req.Price < 0 {
+		return nil, errors.New("price must be non-negative")
+	}
+
+	existing, err := s.variantRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variants: %w", err)
+	}
+
+	variant := &entity.ProductVariant{
+		ProductID: productID,
+		SKU:       sku,
+		Size:      utils.SanitizeString(req.Size),
+		Color:     utils.SanitizeString(req.Color),
+		Price:     req.Price,
+		Stock:     req.Stock,
+		Weight:    req.Weight,
+		Images:    req.Images,
+		SortOrder: len(existing),
+		IsActive:  true,
+	}
+
+	if err := s.variantRepo.Create(ctx, variant); err != nil {
+		return nil, fmt.Errorf("failed to create variant: %w", err)
+	}
+	if variant.Price != nil {
+		s.recordVariantPrice(ctx, variant, entity.PriceSourceManual, nil)
+	}
+
+	return variant, nil
+}
+
+// UpdateVariant edits a variant's details. Stock and activation have their
+// own operations.
+func (s *ProductService) UpdateVariant(ctx context.Context, productID, variantID uuid.UUID, req *UpdateVariantRequest) (*entity.ProductVariant, error) {
+	variant, err := s.productVariant(ctx, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SKU != nil {
+		sku := utils.SanitizeString(*req.SKU)
+		if sku == "" {
+			return nil, errors.New("sku cannot be empty")
+		}
+		if err := s.checkVariantSKU(ctx, sku, variant.ID); err != nil {
+			return nil, err
+		}
+		variant.SKU = sku
+	}
+
+	if req.Size != nil {
+		variant.Size = utils.SanitizeString(*req.Size)
+	}
+
+	if req.Color != nil {
+		variant.Color = utils.SanitizeString(*req.Color)
+	}
+
+	oldPrice := variant.Price
+	if req.Price != nil {
+		if *req.Price < 0 {
+			return nil, errors.New("price must be non-negative")
+		}
+		variant.Price = req.Price
+	}
+
+	if req.Weight != nil {
+		variant.Weight = req.Weight
+	}
+
+	previousImages := variant.Images
+	if req.Images != nil {
+		variant.Images = *req.Images
+	}
+
+	if err := s.variantRepo.Update(ctx, variant); err != nil {
+		return nil, fmt.Errorf("failed to update variant: %w", err)
+	}
+
+	if !samePrice(variant.Price, oldPrice) {
+		s.recordVariantPrice(ctx, variant, entity.PriceSourceManual, nil)
+	}
+
+	if req.Images != nil {
+		s.images.RemoveUnused(ctx, previousImages, variant.Images)
+	}
+
+	return variant, nil
+}
+
+// UploadVariantImage stores an uploaded image and appends its zoom
+// rendition to the variant's images
+func (s *ProductService) UploadVariantImage(ctx context.Context, productID, variantID uuid.UUID, data []byte) (*entity.ProductVariant, *UploadedImage, error) {
+	variant, err := s.productVariant(ctx, productID, variantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	uploaded, err := s.images.Upload(ctx, fmt.Sprintf("products/%s/variants/%s", productID, variantID), data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	variant.Images = append(variant.Images, uploaded.ZoomURL)
+	if err := s.variantRepo.Update(ctx, variant); err != nil {
+		s.images.Remove(ctx, uploaded.ZoomURL)
+		return nil, nil, fmt.Errorf("failed to update variant: %w", err)
+	}
+
+	return variant, uploaded, nil
+}
+
+// SetVariantActive activates or deactivates a variant. Inactive variants are
+// kept so existing carts and orders still resolve, but no longer count
+// towards the product's stock.
+func (s *ProductService) SetVariantActive(ctx context.Context, productID, variantID uuid.UUID, active bool) (*entity.ProductVariant, error) {
+	variant, err := s.productVariant(ctx, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	variant.IsActive = active
+	if err := s.variantRepo.Update(ctx, variant); err != nil {
+		return nil, fmt.Errorf("failed to update variant: %w", err)
+	}
+
+	return variant, nil
+}
+
+// UpdateVariantStock sets a variant's stock level. Users who wishlisted the
+// product are alerted when an active variant comes back in stock.
+func (s *ProductService) UpdateVariantStock(ctx context.Context, productID, variantID uuid.UUID, stock int) (*entity.ProductVariant, error) {
+	if stock < 0 {
+		return nil, errors.New("stock must be non-negative")
+	}
+
+	variant, err := s.productVariant(ctx, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	oldStock := variant.Stock
+
+	if err := s.variantRepo.UpdateStock(ctx, variant.ID, stock); err != nil {
+		return nil, fmt.Errorf("failed to update stock: %w", err)
+	}
+	variant.Stock = stock
+
+	if oldStock <= 0 && stock > 0 && variant.IsActive && s.alerts != nil {
+		if err := s.alerts.Notify(ctx, productalerts.BackInStock(productID, &variant.ID)); err != nil {
+			log.Printf("product %s: failed to send back in stock alert: %v", productID, err)
+		}
+	}
+
+	return variant, nil
+}
+
+// ReorderVariants sets the display order of a product's variants
+func (s *ProductService) ReorderVariants(ctx context.Context, productID uuid.UUID, variantIDs []uuid.UUID) ([]*entity.ProductVariant, error) {
+	variants, err := s.ListVariants(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make([]uuid.UUID, len(variants))
+	for i, variant := range variants {
+		current[i] = variant.ID
+	}
+	if !samePermutation(current, variantIDs) {
+		return nil, errors.New("ids must list each of the product's variants exactly once")
+	}
+
+	if err := s.variantRepo.Reorder(ctx, productID, variantIDs); err != nil {
+		return nil, fmt.Errorf("failed to reorder variants: %w", err)
+	}
+
+	return s.variantRepo.GetByProductID(ctx, productID)
+}
+
+// ListImages returns a product's images in display order
+func (s *ProductService) ListImages(ctx context.Context, productID uuid.UUID) ([]*entity.ProductImage, error) {
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.imageRepo.GetByProductID(ctx, productID)
+}
+
+// AddImage appends an image to a product. The first image becomes primary.
+func (s *ProductService) AddImage(ctx context.Context, productID uuid.UUID, req *CreateImageRequest) (*entity.ProductImage, error) {
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	image := &entity.ProductImage{
+		ProductID: productID,
+		URL:       req.URL,
+		AltText:   utils.SanitizeString(req.AltText),
+	}
+
+	if err := s.appendImage(ctx, image, req.IsPrimary); err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// UploadImage stores an uploaded image's renditions and appends it to the
+// product's images. The first image becomes primary.
+func (s *ProductService) UploadImage(ctx context.Context, productID uuid.UUID, data []byte, req *UploadImageRequest) (*entity.ProductImage, error) {
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	uploaded, err := s.images.Upload(ctx, fmt.Sprintf("products/%s", productID), data)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &entity.ProductImage{
+		ProductID:        productID,
+		URL:              uploaded.ZoomURL,
+		ThumbnailURL:     uploaded.ThumbnailURL,
+		CardURL:          uploaded.CardURL,
+		WebPURL:          uploaded.ZoomWebPURL,
+		ThumbnailWebPURL: uploaded.ThumbnailWebPURL,
+		CardWebPURL:      uploaded.CardWebPURL,
+		AltText:          utils.SanitizeString(req.AltText),
+	}
+
+	if err := s.appendImage(ctx, image, req.IsPrimary); err != nil {
+		s.images.Remove(ctx, uploaded.ZoomURL)
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// UpdateImage edits an image's URL or alt text
+func (s *ProductService) UpdateImage(ctx context.Context, productID, imageID uuid.UUID, req *UpdateImageRequest) (*entity.ProductImage, error) {
+	image, err := s.productImage(ctx, productID, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousURL := image.URL
+	if req.URL != nil && *req.URL != image.URL {
+		image.URL = *req.URL
+		// The renditions belonged to the replaced upload
+		image.ThumbnailURL = ""
+		image.CardURL = ""
+		image.WebPURL = ""
+		image.ThumbnailWebPURL = ""
+		image.CardWebPURL = ""
+	}
+
+	if req.AltText != nil {
+		image.AltText = utils.SanitizeString(*req.AltText)
+	}
+
+	if err := s.imageRepo.Update(ctx, image); err != nil {
+		return nil, fmt.Errorf("failed to update image: %w", err)
+	}
+
+	if image.URL != previousURL {
+		s.images.Remove(ctx, previousURL)
+	}
+
+	return image, nil
+}
+
+// DeleteImage removes an image and its stored files. If it was the primary
+// image, the next image in display order takes its place.
+func (s *ProductService) DeleteImage(ctx context.Context, productID, imageID uuid.UUID) error {
+	image, err := s.productImage(ctx, productID, imageID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.imageRepo.Delete(ctx, image.ID); err != nil {
+		return fmt.Errorf("failed to delete image: %w", err)
+	}
+	s.images.Remove(ctx, image.URL)
+
+	if !image.IsPrimary {
+		return nil
+	}
+
+	remaining, err := s.imageRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to get images: %w", err)
+	}
+	if len(remaining) > 0 {
+		if err := s.imageRepo.SetPrimary(ctx, productID, remaining[0].ID); err != nil {
+			return fmt.Errorf("failed to set primary image: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SetPrimaryImage makes an image the product's primary image
+func (s *ProductService) SetPrimaryImage(ctx context.Context, productID, imageID uuid.UUID) (*entity.ProductImage, error) {
+	image, err := s.productImage(ctx, productID, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.imageRepo.SetPrimary(ctx, productID, image.ID); err != nil {
+		return nil, fmt.Errorf("failed to set primary image: %w", err)
+	}
+	image.IsPrimary = true
+
+	return image, nil
+}
+
+// ReorderImages sets the display order of a product's images
+func (s *ProductService) ReorderImages(ctx context.Context, productID uuid.UUID, imageIDs []uuid.UUID) ([]*entity.ProductImage, error) {
+	images, err := s.ListImages(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make([]uuid.UUID, len(images))
+	for i, image := range images {
+		current[i] = image.ID
+	}
+	if !samePermutation(current, imageIDs) {
+		return nil, errors.New("ids must list each of the product's images exactly once")
+	}
+
+	if err := s.imageRepo.Reorder(ctx, productID, imageIDs); err != nil {
+		return nil, fmt.Errorf("failed to reorder images: %w", err)
+	}
+
+	return s.imageRepo.GetByProductID(ctx, productID)
+}
+
+// appendImage saves image at the end of its product's display order and
+// makes it primary if asked to or if it is the product's first image
+func (s *ProductService) appendImage(ctx context.Context, image *entity.ProductImage, primary bool) error {
+	existing, err := s.imageRepo.GetByProductID(ctx, image.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to get images: %w", err)
+	}
+
+	image.SortOrder = len(existing)
+	if err := s.imageRepo.Create(ctx, image); err != nil {
+		return fmt.Errorf("failed to create image: %w", err)
+	}
+
+	if primary || len(existing) == 0 {
+		if err := s.imageRepo.SetPrimary(ctx, image.ProductID, image.ID); err != nil {
+			return fmt.Errorf("failed to set primary image: %w", err)
+		}
+		image.IsPrimary = true
+	}
+
+	return nil
+}
+
+func (s *ProductService) requireProduct(ctx context.Context, productID uuid.UUID) error {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+func (s *ProductService) productVariant(ctx context.Context, productID, variantID uuid.UUID) (*entity.ProductVariant, error) {
+	variant, err := s.variantRepo.GetByID(ctx, variantID)
+	if err != nil || variant.ProductID != productID {
+		return nil, ErrVariantNotFound
+	}
+	return variant, nil
+}
+
+func (s *ProductService) productImage(ctx context.Context, productID, imageID uuid.UUID) (*entity.ProductImage, error) {
+	image, err := s.imageRepo.GetByID(ctx, imageID)
+	if err != nil || image.ProductID != productID {
+		return nil, ErrImageNotFound
+	}
+	return image, nil
+}
+
+// checkVariantSKU fails if another variant, on any product, already uses sku
+func (s *ProductService) checkVariantSKU(ctx context.Context, sku string, variantID uuid.UUID) error {
+	existing, _ := s.variantRepo.GetBySKU(ctx, sku)
+	if existing != nil && existing.ID != variantID {
+		return errors.New("variant with this SKU already exists")
+	}
+	return nil
+}
+
+// samePermutation reports whether ids contains exactly the IDs in current, each once
+func samePermutation(current, ids []uuid.UUID) bool {
+	if len(current) != len(ids) {
+		return false
+	}
+
+	remaining := make(map[uuid.UUID]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/pkg/productalerts"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+func TestProductService_CreateVariant(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("appends the variant", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+		price := 129.99
+
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.variants.On("GetBySKU", ctx, "AM-10-RED").Return(nil, errors.New("variant not found"))
+		mocks.variants.On("GetByProductID", ctx, productID).Return([]*entity.ProductVariant{{ID: uuid.New()}, {ID: uuid.New()}}, nil)
+		mocks.variants.On("Create", ctx, mock.MatchedBy(func(v *entity.ProductVariant) bool {
+			return v.ProductID == productID && v.SKU == "AM-10-RED" && v.SortOrder == 2 && v.IsActive
+		})).Return(nil)
+		mocks.priceHistory.On("Create", ctx, mock.MatchedBy(func(entry *entity.PriceHistory) bool {
+			return entry.VariantID != nil && *entry.Price == price
+		})).Return(nil)
+
+		variant, err := service.CreateVariant(ctx, productID, &CreateVariantRequest{SKU: "AM-10-RED", Size: "10", Color: "red", Price: &price, Stock: 5})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, variant.SortOrder)
+		mocks.variants.AssertExpectations(t)
+		mocks.priceHistory.AssertExpectations(t)
+	})
+
+	t.Run("duplicate SKU", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		// Variant SKUs are unique across products
+		mocks.variants.On("GetBySKU", ctx, "AM-10-RED").Return(&entity.ProductVariant{ID: uuid.New(), ProductID: uuid.New(), SKU: "AM-10-RED"}, nil)
+
+		variant, err := service.CreateVariant(ctx, productID, &CreateVariantRequest{SKU: "AM-10-RED"})
+
+		assert.Nil(t, variant)
+		assert.EqualError(t, err, "variant with this SKU already exists")
+		mocks.variants.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("product not found", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+
+		mocks.products.On("GetByID", ctx, productID).Return(nil, errors.New("product not found"))
+
+		_, err := service.CreateVariant(ctx, productID, &CreateVariantRequest{SKU: "AM-10-RED"})
+
+		assert.ErrorIs(t, err, ErrProductNotFound)
+	})
+}
+
+func TestProductService_UpdateVariant(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keeping its own SKU", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+		variant := &entity.ProductVariant{ID: uuid.New(), ProductID: productID, SKU: "AM-10-RED"}
+
+		mocks.variants.On("GetByID", ctx, variant.ID).Return(variant, nil)
+		mocks.variants.On("GetBySKU", ctx, "AM-10-RED").Return(variant, nil)
+		mocks.variants.On("Update", ctx, variant).Return(nil)
+
+		updated, err := service.UpdateVariant(ctx, productID, variant.ID, &UpdateVariantRequest{SKU: stringPtr("AM-10-RED"), Color: stringPtr("crimson")})
+
+		require.NoError(t, err)
+		assert.Equal(t, "crimson", updated.Color)
+	})
+
+	t.Run("taking another variant's SKU", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+		variant := &entity.ProductVariant{ID: uuid.New(), ProductID: productID, SKU: "AM-10-RED"}
+
+		mocks.variants.On("GetByID", ctx, variant.ID).Return(variant, nil)
+		mocks.variants.On("GetBySKU", ctx, "AM-11-RED").Return(&entity.ProductVariant{ID: uuid.New(), ProductID: productID, SKU: "AM-11-RED"}, nil)
+
+		_, err := service.UpdateVariant(ctx, productID, variant.ID, &UpdateVariantRequest{SKU: stringPtr("AM-11-RED")})
+
+		assert.EqualError(t, err, "variant with this SKU already exists")
+		mocks.variants.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProductService_VariantOfAnotherProduct(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	variant := &entity.ProductVariant{ID: uuid.New(), ProductID: uuid.New(), SKU: "AM-10-RED", IsActive: true}
+
+	tests := map[string]func(s *ProductService) error{
+		"update": func(s *ProductService) error {
+			_, err := s.UpdateVariant(ctx, productID, variant.ID, &UpdateVariantRequest{Size: stringPtr("11")})
+			return err
+		},
+		"set active": func(s *ProductService) error {
+			_, err := s.SetVariantActive(ctx, productID, variant.ID, false)
+			return err
+		},
+		"update stock": func(s *ProductService) error {
+			_, err := s.UpdateVariantStock(ctx, productID, variant.ID, 5)
+			return err
+		},
+	}
+	for name, call := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, mocks := newTestProductService()
+			mocks.variants.On("GetByID", ctx, variant.ID).Return(variant, nil)
+
+			assert.ErrorIs(t, call(service), ErrVariantNotFound)
+			mocks.variants.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			mocks.variants.AssertNotCalled(t, "UpdateStock", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestProductService_UpdateVariantStock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("back in stock alerts watchers", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+		variant := &entity.ProductVariant{ID: uuid.New(), ProductID: productID, Stock: 0, IsActive: true}
+
+		mocks.variants.On("GetByID", ctx, variant.ID).Return(variant, nil)
+		mocks.variants.On("UpdateStock", ctx, variant.ID, 5).Return(nil)
+		mocks.alerts.On("Notify", ctx, productalerts.BackInStock(productID, &variant.ID)).Return(nil)
+
+		updated, err := service.UpdateVariantStock(ctx, productID, variant.ID, 5)
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, updated.Stock)
+		mocks.alerts.AssertExpectations(t)
+	})
+
+	t.Run("restocking an inactive variant does not alert", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+		variant := &entity.ProductVariant{ID: uuid.New(), ProductID: productID, Stock: 0, IsActive: false}
+
+		mocks.variants.On("GetByID", ctx, variant.ID).Return(variant, nil)
+		mocks.variants.On("UpdateStock", ctx, variant.ID, 5).Return(nil)
+
+		_, err := service.UpdateVariantStock(ctx, productID, variant.ID, 5)
+
+		require.NoError(t, err)
+		mocks.alerts.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("topping up stock does not alert", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+		variant := &entity.ProductVariant{ID: uuid.New(), ProductID: productID, Stock: 3, IsActive: true}
+
+		mocks.variants.On("GetByID", ctx, variant.ID).Return(variant, nil)
+		mocks.variants.On("UpdateStock", ctx, variant.ID, 8).Return(nil)
+
+		_, err := service.UpdateVariantStock(ctx, productID, variant.ID, 8)
+
+		require.NoError(t, err)
+		mocks.alerts.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("negative stock", func(t *testing.T) {
+		service, mocks := newTestProductService()
+
+		_, err := service.UpdateVariantStock(ctx, uuid.New(), uuid.New(), -1)
+
+		assert.EqualError(t, err, "stock must be non-negative")
+		mocks.variants.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed update", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+		variant := &entity.ProductVariant{ID: uuid.New(), ProductID: productID, IsActive: true}
+
+		mocks.variants.On("GetByID", ctx, variant.ID).Return(variant, nil)
+		mocks.variants.On("UpdateStock", ctx, variant.ID, 5).Return(errors.New("variant not found"))
+
+		_, err := service.UpdateVariantStock(ctx, productID, variant.ID, 5)
+
+		assert.Error(t, err)
+		mocks.alerts.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProductService_ReorderVariants(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+	variants := []*entity.ProductVariant{{ID: first}, {ID: second}}
+
+	tests := map[string][]uuid.UUID{
+		"missing a variant":         {second},
+		"listing a variant twice":   {second, second},
+		"another product's variant": {second, uuid.New()},
+	}
+	for name, ids := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, mocks := newTestProductService()
+			mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+			mocks.variants.On("GetByProductID", ctx, productID).Return(variants, nil)
+
+			_, err := service.ReorderVariants(ctx, productID, ids)
+
+			assert.Error(t, err)
+			mocks.variants.AssertNotCalled(t, "Reorder", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestProductService_AddImage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("first image becomes primary", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.images.On("GetByProductID", ctx, productID).Return([]*entity.ProductImage{}, nil)
+		mocks.images.On("Create", ctx, mock.AnythingOfType("*entity.ProductImage")).Return(nil)
+		mocks.images.On("SetPrimary", ctx, productID, mock.AnythingOfType("uuid.UUID")).Return(nil)
+
+		image, err := service.AddImage(ctx, productID, &CreateImageRequest{URL: "https://cdn.example.com/a.jpg"})
+
+		require.NoError(t, err)
+		assert.True(t, image.IsPrimary)
+		assert.Equal(t, 0, image.SortOrder)
+		mocks.images.AssertExpectations(t)
+	})
+
+	t.Run("later images are appended", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.images.On("GetByProductID", ctx, productID).Return([]*entity.ProductImage{{ID: uuid.New(), IsPrimary: true}}, nil)
+		mocks.images.On("Create", ctx, mock.AnythingOfType("*entity.ProductImage")).Return(nil)
+
+		image, err := service.AddImage(ctx, productID, &CreateImageRequest{URL: "https://cdn.example.com/b.jpg"})
+
+		require.NoError(t, err)
+		assert.False(t, image.IsPrimary)
+		assert.Equal(t, 1, image.SortOrder)
+		mocks.images.AssertNotCalled(t, "SetPrimary", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("asking for primary switches it", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.images.On("GetByProductID", ctx, productID).Return([]*entity.ProductImage{{ID: uuid.New(), IsPrimary: true}}, nil)
+		mocks.images.On("Create", ctx, mock.AnythingOfType("*entity.ProductImage")).Return(nil)
+		mocks.images.On("SetPrimary", ctx, productID, mock.AnythingOfType("uuid.UUID")).Return(nil)
+
+		image, err := service.AddImage(ctx, productID, &CreateImageRequest{URL: "https://cdn.example.com/b.jpg", IsPrimary: true})
+
+		require.NoError(t, err)
+		assert.True(t, image.IsPrimary)
+		mocks.images.AssertExpectations(t)
+	})
+}
+
+func TestProductService_SetPrimaryImage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("switches the primary image", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+		image := &entity.ProductImage{ID: uuid.New(), ProductID: productID}
+
+		mocks.images.On("GetByID", ctx, image.ID).Return(image, nil)
+		mocks.images.On("SetPrimary", ctx, productID, image.ID).Return(nil)
+
+		result, err := service.SetPrimaryImage(ctx, productID, image.ID)
+
+		require.NoError(t, err)
+		assert.True(t, result.IsPrimary)
+		mocks.images.AssertExpectations(t)
+	})
+
+	t.Run("image of another product", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		image := &entity.ProductImage{ID: uuid.New(), ProductID: uuid.New()}
+
+		mocks.images.On("GetByID", ctx, image.ID).Return(image, nil)
+
+		_, err := service.SetPrimaryImage(ctx, uuid.New(), image.ID)
+
+		assert.ErrorIs(t, err, ErrImageNotFound)
+		mocks.images.AssertNotCalled(t, "SetPrimary", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestProductService_DeleteImage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deleting the primary image promotes the next one", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+		image := &entity.ProductImage{ID: uuid.New(), ProductID: productID, IsPrimary: true}
+		next := &entity.ProductImage{ID: uuid.New(), ProductID: productID}
+
+		mocks.images.On("GetByID", ctx, image.ID).Return(image, nil)
+		mocks.images.On("Delete", ctx, image.ID).Return(nil)
+		mocks.images.On("GetByProductID", ctx, productID).Return([]*entity.ProductImage{next}, nil)
+		mocks.images.On("SetPrimary", ctx, productID, next.ID).Return(nil)
+
+		require.NoError(t, service.DeleteImage(ctx, productID, image.ID))
+		mocks.images.AssertExpectations(t)
+	})
+
+	t.Run("deleting another image keeps the primary", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		productID := uuid.New()
+		image := &entity.ProductImage{ID: uuid.New(), ProductID: productID}
+
+		mocks.images.On("GetByID", ctx, image.ID).Return(image, nil)
+		mocks.images.On("Delete", ctx, image.ID).Return(nil)
+
+		require.NoError(t, service.DeleteImage(ctx, productID, image.ID))
+		mocks.images.AssertNotCalled(t, "SetPrimary", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("image of another product", func(t *testing.T) {
+		service, mocks := newTestProductService()
+		image := &entity.ProductImage{ID: uuid.New(), ProductID: uuid.New()}
+
+		mocks.images.On("GetByID", ctx, image.ID).Return(image, nil)
+
+		assert.ErrorIs(t, service.DeleteImage(ctx, uuid.New(), image.ID), ErrImageNotFound)
+		mocks.images.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
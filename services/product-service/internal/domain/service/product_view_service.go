@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+const (
+	defaultViewListLimit = 20
+	maxViewListLimit     = 50
+
+	// recentViewsDays is the window of the products' recent views
+	recentViewsDays = 30
+)
+
+// ErrViewTrackingUnavailable is returned when Redis, which holds the live
+// view counts and histories, could not be reached at startup
+var ErrViewTrackingUnavailable = errors.New("view tracking is unavailable")
+
+// RecordViewRequest reports a product page view. Anonymous shoppers may send
+// a VisitorID, stable across their visits, so that the products they browse
+// together feed recommendations even when they share an address with others.
+type RecordViewRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	VisitorID string    `json:"visitor_id" binding:"omitempty,max=64"`
+}
+
+type ProductViewService struct {
+	productRepo  repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	viewRepo     repository.ProductViewRepository
+	tracker      repository.ViewTracker
+}
+
+func NewProductViewService(
+	productRepo repository.ProductRepository,
+	categoryRepo repository.CategoryRepository,
+	viewRepo repository.ProductViewRepository,
+	tracker repository.ViewTracker,
+) *ProductViewService {
+	return &ProductViewService{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		viewRepo:     viewRepo,
+		tracker:      tracker,
+	}
+}
+
+// RecordView counts a view of an active product. userID is nil for
+// anonymous shoppers, who are told apart by clientIP and, when they send
+// one, their visitor ID. The view counts towards trending in the product's
+// category and every category above it.
+func (s *ProductViewService) RecordView(ctx context.Context, userID *uuid.UUID, clientIP string, req *RecordViewRequest) error {
+	if s.tracker == nil {
+		return ErrViewTrackingUnavailable
+	}
+
+	product, err := s.productRepo.GetByID(ctx, req.ProductID)
+	if err != nil || !product.IsActive {
+		return ErrProductNotFound
+	}
+
+	var categoryIDs []uuid.UUID
+	if product.CategoryID != nil {
+		categories, err := s.categoryRepo.GetAncestors(ctx, *product.CategoryID)
+		if err != nil {
+			log.Printf("failed to get categories above %s: %v", *product.CategoryID, err)
+			categoryIDs = append(categoryIDs, *product.CategoryID)
+		}
+		for _, category := range categories {
+			categoryIDs = append(categoryIDs, category.ID)
+		}
+	}
+
+	// Visitor IDs are chosen by the client, so anonymous views are also
+	// capped per address
+	viewer, source := viewerKeys(userID, clientIP, req.VisitorID)
+
+	if err := s.tracker.RecordView(ctx, viewer, source, product.ID, categoryIDs, time.Now()); err != nil {
+		return fmt.Errorf("failed to record view: %w", err)
+	}
+	return nil
+}
+
+// GetRecentlyViewed returns the active products the user looked at, latest
+// first
+func (s *ProductViewService) GetRecentlyViewed(ctx context.Context, userID uuid.UUID, limit int) ([]*entity.Product, error) {
+	if s.tracker == nil {
+		return nil, ErrViewTrackingUnavailable
+	}
+
+	ids, err := s.tracker.RecentlyViewed(ctx, "user:"+userID.String(), viewListLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recently viewed products: %w", err)
+	}
+	return s.productRepo.GetActiveByIDs(ctx, ids)
+}
+
+// GetTrendingProducts returns the most viewed active products of the last
+// day, optionally within a category and its subcategories
+func (s *ProductViewService) GetTrendingProducts(ctx context.Context, categoryID *uuid.UUID, limit int) ([]*entity.Product, error) {
+	if s.tracker == nil {
+		return nil, ErrViewTrackingUnavailable
+	}
+
+	// Ask for more than needed, since products deactivated since they were
+	// viewed are dropped
+	limit = viewListLimit(limit)
+	ids, err := s.tracker.Trending(ctx, categoryID, limit*2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trending products: %w", err)
+	}
+
+	products, err := s.productRepo.GetActiveByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) > limit {
+		products = products[:limit]
+	}
+	return products, nil
+}
+
+// RunViewCompaction copies the daily view counts from Redis to Postgres every
+// interval until ctx is cancelled. Yesterday is copied again so that views
+// counted just before midnight are not lost.
+func (s *ProductViewService) RunViewCompaction(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.compactViews(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ProductViewService) compactViews(ctx context.Context) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		date := day.Format("2006-01-02")
+
+		stats, err := s.tracker.DailyViews(ctx, day)
+		if err != nil {
+			log.Printf("view compaction: failed to get views for %s: %v", date, err)
+			continue
+		}
+		coViews, err := s.tracker.DailyCoViews(ctx, day)
+		if err != nil {
+			log.Printf("view compaction: failed to get co-views for %s: %v", date, err)
+			continue
+		}
+		if err := s.viewRepo.SaveDailyViews(ctx, stats, coViews); err != nil {
+			log.Printf("view compaction: failed to save views for %s: %v", date, err)
+		}
+	}
+
+	if _, err := s.viewRepo.RefreshRecentViews(ctx, today.AddDate(0, 0, -(recentViewsDays-1))); err != nil {
+		log.Printf("view compaction: failed to refresh recent views: %v", err)
+	}
+}
+
+// viewerKeys returns who a view is deduplicated and remembered for, and for
+// anonymous views the address they are capped by
+func viewerKeys(userID *uuid.UUID, clientIP, visitorID string) (viewer, source string) {
+	switch {
+	case userID != nil:
+		return "user:" + userID.String(), ""
+	case visitorID != "":
+		return "visitor:" + visitorID, clientIP
+	default:
+		return "ip:" + clientIP, clientIP
+	}
+}
+
+func viewListLimit(limit int) int {
+	if limit <= 0 {
+		return defaultViewListLimit
+	}
+	if limit > maxViewListLimit {
+		return maxViewListLimit
+	}
+	return limit
+}
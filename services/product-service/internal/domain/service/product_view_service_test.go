@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+// MockViewTracker is a mock implementation of repository.ViewTracker
+type MockViewTracker struct {
+	mock.Mock
+}
+
+func (m *MockViewTracker) RecordView(ctx context.Context, viewer, source string, productID uuid.UUID, categoryIDs []uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, viewer, source, productID, categoryIDs, at)
+	return args.Error(0)
+}
+
+func (m *MockViewTracker) RecentlyViewed(ctx context.Context, viewer string, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, viewer, limit)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockViewTracker) Trending(ctx context.Context, categoryID *uuid.UUID, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, categoryID, limit)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockViewTracker) DailyViews(ctx context.Context, day time.Time) ([]*entity.ProductViewStat, error) {
+	args := m.Called(ctx, day)
+	return args.Get(0).([]*entity.ProductViewStat), args.Error(1)
+}
+
+func (m *MockViewTracker) DailyCoViews(ctx context.Context, day time.Time) ([]*entity.ProductCoView, error) {
+	args := m.Called(ctx, day)
+	return args.Get(0).([]*entity.ProductCoView), args.Error(1)
+}
+
+func TestViewerKeys(t *testing.T) {
+	userID := uuid.New()
+
+	tests := map[string]struct {
+		userID     *uuid.UUID
+		visitorID  string
+		wantViewer string
+		wantSource string
+	}{
+		"signed-in shoppers are not capped by address": {
+			userID:     &userID,
+			visitorID:  "v-123",
+			wantViewer: "user:" + userID.String(),
+		},
+		"visitors are capped by address": {
+			visitorID:  "v-123",
+			wantViewer: "visitor:v-123",
+			wantSource: "203.0.113.7",
+		},
+		"anonymous views without a visitor ID are deduplicated by address": {
+			wantViewer: "ip:203.0.113.7",
+			wantSource: "203.0.113.7",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			viewer, source := viewerKeys(tt.userID, "203.0.113.7", tt.visitorID)
+			assert.Equal(t, tt.wantViewer, viewer)
+			assert.Equal(t, tt.wantSource, source)
+		})
+	}
+}
+
+func TestProductViewService_RecordView(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("anonymous view is recorded for the client address", func(t *testing.T) {
+		products, categories, tracker := new(MockProductRepository), new(MockCategoryRepository), new(MockViewTracker)
+		service := NewProductViewService(products, categories, nil, tracker)
+		productID, categoryID, parentID := uuid.New(), uuid.New(), uuid.New()
+
+		products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID, CategoryID: &categoryID, IsActive: true}, nil)
+		categories.On("GetAncestors", ctx, categoryID).Return([]*entity.Category{{ID: categoryID}, {ID: parentID}}, nil)
+		tracker.On("RecordView", ctx, "ip:198.51.100.4", "198.51.100.4", productID, []uuid.UUID{categoryID, parentID}, mock.AnythingOfType("time.Time")).Return(nil)
+
+		err := service.RecordView(ctx, nil, "198.51.100.4", &RecordViewRequest{ProductID: productID})
+
+		require.NoError(t, err)
+		tracker.AssertExpectations(t)
+	})
+
+	t.Run("inactive product", func(t *testing.T) {
+		products, tracker := new(MockProductRepository), new(MockViewTracker)
+		service := NewProductViewService(products, new(MockCategoryRepository), nil, tracker)
+		productID := uuid.New()
+
+		products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+
+		err := service.RecordView(ctx, nil, "198.51.100.4", &RecordViewRequest{ProductID: productID})
+
+		assert.ErrorIs(t, err, ErrProductNotFound)
+		tracker.AssertNotCalled(t, "RecordView", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// Recommendation types offered on a product page
+const (
+	RecommendationBoughtTogether = "bought_together"
+	RecommendationSimilar        = "similar"
+)
+
+const (
+	defaultRecommendationLimit = 10
+	maxRecommendationLimit     = 50
+
+	// maxAffinitiesPerProduct is how many related products the job keeps for
+	// each product and source
+	maxAffinitiesPerProduct = 20
+
+	// maxCoPurchasePairs caps how many product pairs are read from
+	// order-service per run
+	maxCoPurchasePairs = 10000
+)
+
+var ErrInvalidRecommendationType = errors.New("type must be bought_together or similar")
+
+// recommendationSources maps each recommendation type to the affinities it
+// is ranked by
+var recommendationSources = map[string]string{
+	RecommendationBoughtTogether: entity.AffinitySourcePurchase,
+	RecommendationSimilar:        entity.AffinitySourceView,
+}
+
+// RecommendationSettings controls how affinities are computed: orders and
+// views within Window are counted, and a pair needs at least MinOrders orders
+// or MinCoViews views in common to be recommended
+type RecommendationSettings struct {
+	Window     time.Duration
+	MinOrders  int
+	MinCoViews int
+}
+
+type RecommendationService struct {
+	productRepo        repository.ProductRepository
+	recommendationRepo repository.RecommendationRepository
+	orders             repository.OrderRepository
+	cache              repository.RecommendationCache
+	settings           RecommendationSettings
+}
+
+func NewRecommendationService(
+	productRepo repository.ProductRepository,
+	recommendationRepo repository.RecommendationRepository,
+	orders repository.OrderRepository,
+	cache repository.RecommendationCache,
+	settings RecommendationSettings,
+) *RecommendationService {
+	return &RecommendationService{
+		productRepo:        productRepo,
+		recommendationRepo: recommendationRepo,
+		orders:             orders,
+		cache:              cache,
+		settings:           settings,
+	}
+}
+
+// GetRecommendations returns products to show alongside a product, ranked by
+// affinity. When there are too few affinities, for new or rarely bought
+// products, the list is topped up with the most similar products by
+// category, brand and attributes.
+func (s *RecommendationService) GetRecommendations(ctx context.Context, productID uuid.UUID, recommendationType string, limit int) ([]*entity.Product, error) {
+	if recommendationType == "" {
+		recommendationType = RecommendationBoughtTogether
+	}
+	if _, ok := recommendationSources[recommendationType]; !ok {
+		return nil, ErrInvalidRecommendationType
+	}
+	if limit <= 0 {
+		limit = defaultRecommendationLimit
+	}
+	if limit > maxRecommendationLimit {
+		limit = maxRecommendationLimit
+	}
+
+	ids, err := s.rankedProductIDs(ctx, productID, recommendationType)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	return s.productRepo.GetActiveByIDs(ctx, ids)
+}
+
+// rankedProductIDs returns up to maxRecommendationLimit recommended product
+// IDs, from the cache when it has them
+func (s *RecommendationService) rankedProductIDs(ctx context.Context, productID uuid.UUID, recommendationType string) ([]uuid.UUID, error) {
+	if s.cache != nil {
+		if cached, ok := s.cache.Get(ctx, productID, recommendationType); ok {
+			return cached, nil
+		}
+	}
+
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	ids, err := s.recommendationRepo.GetRelatedProductIDs(ctx, productID, recommendationSources[recommendationType], maxRecommendationLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product affinities: %w", err)
+	}
+
+	if len(ids) < maxRecommendationLimit {
+		similar, err := s.productRepo.GetRelatedProducts(ctx, productID, maxRecommendationLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get similar products: %w", err)
+		}
+
+		seen := make(map[uuid.UUID]bool, len(ids))
+		for _, id := range ids {
+			seen[id] = true
+		}
+		for _, product := range similar {
+			if len(ids) == maxRecommendationLimit {
+				break
+			}
+			if !seen[product.ID] {
+				seen[product.ID] = true
+				ids = append(ids, product.ID)
+			}
+		}
+	}
+
+	if s.cache != nil {
+		if err := s.cache.Set(ctx, productID, recommendationType, ids); err != nil {
+			log.Printf("failed to cache %s recommendations for product %s: %v", recommendationType, productID, err)
+		}
+	}
+
+	return ids, nil
+}
+
+// RunRecommendationJob recomputes product affinities every interval until
+// ctx is cancelled
+func (s *RecommendationService) RunRecommendationJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.refreshPurchaseAffinities(ctx); err != nil {
+			log.Printf("recommendation job: %v", err)
+		}
+		if err := s.refreshViewAffinities(ctx); err != nil {
+			log.Printf("recommendation job: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshPurchaseAffinities scores each pair of products bought together by
+// the share of the first product's orders that also contained the second
+func (s *RecommendationService) refreshPurchaseAffinities(ctx context.Context) error {
+	until := time.Now()
+	pairs, err := s.orders.GetCoPurchases(ctx, until.Add(-s.settings.Window), until, s.settings.MinOrders, maxCoPurchasePairs)
+	if err != nil {
+		return fmt.Errorf("failed to get co-purchases: %w", err)
+	}
+
+	byProduct := make(map[uuid.UUID][]*entity.ProductAffinity)
+	for _, pair := range pairs {
+		if pair.ProductOrders <= 0 || pair.ProductID == pair.RelatedProductID {
+			continue
+		}
+		byProduct[pair.ProductID] = append(byProduct[pair.ProductID], &entity.ProductAffinity{
+			ProductID:        pair.ProductID,
+			RelatedProductID: pair.RelatedProductID,
+			Score:            float64(pair.OrderCount) / float64(pair.ProductOrders),
+			Support:          pair.OrderCount,
+		})
+	}
+
+	affinities := topAffinities(byProduct, maxAffinitiesPerProduct)
+	if err := s.recommendationRepo.ReplaceAffinities(ctx, entity.AffinitySourcePurchase, affinities); err != nil {
+		return fmt.Errorf("failed to save purchase affinities: %w", err)
+	}
+
+	log.Printf("recommendation job: saved %d purchase affinities for %d products", len(affinities), len(byProduct))
+	return nil
+}
+
+// refreshViewAffinities scores each pair of products viewed in the same
+// sessions from the daily counts saved by the view compaction job
+func (s *RecommendationService) refreshViewAffinities(ctx context.Context) error {
+	since := time.Now().Add(-s.settings.Window)
+	affinities, err := s.recommendationRepo.GetViewAffinities(ctx, since, s.settings.MinCoViews, maxAffinitiesPerProduct)
+	if err != nil {
+		return fmt.Errorf("failed to get co-views: %w", err)
+	}
+
+	if err := s.recommendationRepo.ReplaceAffinities(ctx, entity.AffinitySourceView, affinities); err != nil {
+		return fmt.Errorf("failed to save view affinities: %w", err)
+	}
+
+	log.Printf("recommendation job: saved %d view affinities", len(affinities))
+	return nil
+}
+
+// topAffinities keeps the strongest affinities of each product
+func topAffinities(byProduct map[uuid.UUID][]*entity.ProductAffinity, perProduct int) []*entity.ProductAffinity {
+	var affinities []*entity.ProductAffinity
+	for _, related := range byProduct {
+		sort.Slice(related, func(i, j int) bool {
+			if related[i].Score != related[j].Score {
+				return related[i].Score > related[j].Score
+			}
+			return related[i].Support > related[j].Support
+		})
+		if len(related) > perProduct {
+			related = related[:perProduct]
+		}
+		affinities = append(affinities, related...)
+	}
+	return affinities
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// MockRecommendationRepository is a mock implementation of repository.RecommendationRepository
+type MockRecommendationRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecommendationRepository) ReplaceAffinities(ctx context.Context, source string, affinities []*entity.ProductAffinity) error {
+	args := m.Called(ctx, source, affinities)
+	return args.Error(0)
+}
+
+func (m *MockRecommendationRepository) GetRelatedProductIDs(ctx context.Context, productID uuid.UUID, source string, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, productID, source, limit)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRecommendationRepository) GetViewAffinities(ctx context.Context, since time.Time, minViews, perProduct int) ([]*entity.ProductAffinity, error) {
+	args := m.Called(ctx, since, minViews, perProduct)
+	return args.Get(0).([]*entity.ProductAffinity), args.Error(1)
+}
+
+// MockRecommendationCache is a mock implementation of repository.RecommendationCache
+type MockRecommendationCache struct {
+	mock.Mock
+}
+
+func (m *MockRecommendationCache) Get(ctx context.Context, productID uuid.UUID, recommendationType string) ([]uuid.UUID, bool) {
+	args := m.Called(ctx, productID, recommendationType)
+	return args.Get(0).([]uuid.UUID), args.Bool(1)
+}
+
+func (m *MockRecommendationCache) Set(ctx context.Context, productID uuid.UUID, recommendationType string, productIDs []uuid.UUID) error {
+	args := m.Called(ctx, productID, recommendationType, productIDs)
+	return args.Error(0)
+}
+
+// recommendationServiceMocks holds the mocked dependencies of a RecommendationService
+type recommendationServiceMocks struct {
+	products        *MockProductRepository
+	recommendations *MockRecommendationRepository
+	orders          *MockOrderRepository
+	cache           *MockRecommendationCache
+}
+
+// newTestRecommendationService returns a recommendation service that needs
+// two orders or three co-views in common to relate products
+func newTestRecommendationService() (*RecommendationService, *recommendationServiceMocks) {
+	mocks := &recommendationServiceMocks{
+		products:        new(MockProductRepository),
+		recommendations: new(MockRecommendationRepository),
+		orders:          new(MockOrderRepository),
+		cache:           new(MockRecommendationCache),
+	}
+	service := NewRecommendationService(mocks.products, mocks.recommendations, mocks.orders, mocks.cache,
+		RecommendationSettings{Window: 90 * 24 * time.Hour, MinOrders: 2, MinCoViews: 3})
+	return service, mocks
+}
+
+func TestRecommendationService_GetRecommendations(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("affinities come first, topped up with similar products", func(t *testing.T) {
+		service, mocks := newTestRecommendationService()
+		mocks.cache.On("Get", ctx, productID, RecommendationBoughtTogether).Return([]uuid.UUID(nil), false)
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.recommendations.On("GetRelatedProductIDs", ctx, productID, entity.AffinitySourcePurchase, maxRecommendationLimit).
+			Return([]uuid.UUID{second, first}, nil)
+		mocks.products.On("GetRelatedProducts", ctx, productID, maxRecommendationLimit).
+			Return([]*entity.Product{{ID: first}, {ID: third}}, nil)
+		mocks.cache.On("Set", ctx, productID, RecommendationBoughtTogether, []uuid.UUID{second, first, third}).Return(nil)
+		mocks.products.On("GetActiveByIDs", ctx, []uuid.UUID{second, first}).Return([]*entity.Product{{ID: second}, {ID: first}}, nil)
+
+		products, err := service.GetRecommendations(ctx, productID, "", 2)
+
+		require.NoError(t, err)
+		assert.Len(t, products, 2)
+		mocks.cache.AssertExpectations(t)
+		mocks.products.AssertExpectations(t)
+	})
+
+	t.Run("enough affinities need no similar products", func(t *testing.T) {
+		service, mocks := newTestRecommendationService()
+		related := make([]uuid.UUID, maxRecommendationLimit)
+		for i := range related {
+			related[i] = uuid.New()
+		}
+		mocks.cache.On("Get", ctx, productID, RecommendationSimilar).Return([]uuid.UUID(nil), false)
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.recommendations.On("GetRelatedProductIDs", ctx, productID, entity.AffinitySourceView, maxRecommendationLimit).Return(related, nil)
+		mocks.cache.On("Set", ctx, productID, RecommendationSimilar, related).Return(nil)
+		mocks.products.On("GetActiveByIDs", ctx, related[:defaultRecommendationLimit]).Return([]*entity.Product{}, nil)
+
+		_, err := service.GetRecommendations(ctx, productID, RecommendationSimilar, 0)
+
+		require.NoError(t, err)
+		mocks.products.AssertNotCalled(t, "GetRelatedProducts", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("cached recommendations skip the lookup", func(t *testing.T) {
+		service, mocks := newTestRecommendationService()
+		mocks.cache.On("Get", ctx, productID, RecommendationBoughtTogether).Return([]uuid.UUID{third, first}, true)
+		mocks.products.On("GetActiveByIDs", ctx, []uuid.UUID{third, first}).Return([]*entity.Product{{ID: third}, {ID: first}}, nil)
+
+		_, err := service.GetRecommendations(ctx, productID, RecommendationBoughtTogether, 100)
+
+		require.NoError(t, err)
+		mocks.products.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+		mocks.recommendations.AssertNotCalled(t, "GetRelatedProductIDs", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a failure to cache still returns the recommendations", func(t *testing.T) {
+		service, mocks := newTestRecommendationService()
+		mocks.cache.On("Get", ctx, productID, RecommendationBoughtTogether).Return([]uuid.UUID(nil), false)
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.recommendations.On("GetRelatedProductIDs", ctx, productID, entity.AffinitySourcePurchase, maxRecommendationLimit).
+			Return([]uuid.UUID{}, nil)
+		mocks.products.On("GetRelatedProducts", ctx, productID, maxRecommendationLimit).Return([]*entity.Product{{ID: first}}, nil)
+		mocks.cache.On("Set", ctx, productID, RecommendationBoughtTogether, []uuid.UUID{first}).Return(errors.New("redis unavailable"))
+		mocks.products.On("GetActiveByIDs", ctx, []uuid.UUID{first}).Return([]*entity.Product{{ID: first}}, nil)
+
+		products, err := service.GetRecommendations(ctx, productID, RecommendationBoughtTogether, 5)
+
+		require.NoError(t, err)
+		assert.Len(t, products, 1)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		service, _ := newTestRecommendationService()
+
+		_, err := service.GetRecommendations(ctx, productID, "trending", 5)
+
+		assert.Equal(t, ErrInvalidRecommendationType, err)
+	})
+
+	t.Run("missing product", func(t *testing.T) {
+		service, mocks := newTestRecommendationService()
+		mocks.cache.On("Get", ctx, productID, RecommendationBoughtTogether).Return([]uuid.UUID(nil), false)
+		mocks.products.On("GetByID", ctx, productID).Return(nil, errors.New("product not found"))
+
+		_, err := service.GetRecommendations(ctx, productID, RecommendationBoughtTogether, 5)
+
+		assert.Equal(t, ErrProductNotFound, err)
+		mocks.cache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRecommendationService_RefreshPurchaseAffinities(t *testing.T) {
+	ctx := context.Background()
+	shoe, socks, laces, insoles := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("pairs are scored by their share of the product's orders", func(t *testing.T) {
+		service, mocks := newTestRecommendationService()
+		mocks.orders.On("GetCoPurchases", ctx, mock.Anything, mock.Anything, 2, maxCoPurchasePairs).Return([]repository.ProductPair{
+			{ProductID: shoe, RelatedProductID: socks, OrderCount: 4, ProductOrders: 10},
+			{ProductID: shoe, RelatedProductID: laces, OrderCount: 6, ProductOrders: 10},
+			{ProductID: shoe, RelatedProductID: shoe, OrderCount: 10, ProductOrders: 10},
+			{ProductID: insoles, RelatedProductID: shoe, OrderCount: 3, ProductOrders: 0},
+		}, nil)
+		var saved []*entity.ProductAffinity
+		mocks.recommendations.On("ReplaceAffinities", ctx, entity.AffinitySourcePurchase, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			saved = args.Get(2).([]*entity.ProductAffinity)
+		})
+
+		require.NoError(t, service.refreshPurchaseAffinities(ctx))
+
+		require.Len(t, saved, 2, "a product is not related to itself, and pairs without orders are skipped")
+		assert.Equal(t, laces, saved[0].RelatedProductID)
+		assert.InDelta(t, 0.6, saved[0].Score, 1e-9)
+		assert.Equal(t, 6, saved[0].Support)
+		assert.Equal(t, socks, saved[1].RelatedProductID)
+		assert.InDelta(t, 0.4, saved[1].Score, 1e-9)
+	})
+
+	t.Run("order service failure keeps the old affinities", func(t *testing.T) {
+		service, mocks := newTestRecommendationService()
+		mocks.orders.On("GetCoPurchases", ctx, mock.Anything, mock.Anything, 2, maxCoPurchasePairs).
+			Return([]repository.ProductPair(nil), errors.New("order service unavailable"))
+
+		err := service.refreshPurchaseAffinities(ctx)
+
+		assert.ErrorContains(t, err, "failed to get co-purchases")
+		mocks.recommendations.AssertNotCalled(t, "ReplaceAffinities", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTopAffinities(t *testing.T) {
+	productID := uuid.New()
+	affinity := func(score float64, support int) *entity.ProductAffinity {
+		return &entity.ProductAffinity{ProductID: productID, RelatedProductID: uuid.New(), Score: score, Support: support}
+	}
+	strongest, tied, weaker, weakest := affinity(0.8, 4), affinity(0.5, 10), affinity(0.5, 2), affinity(0.1, 50)
+
+	top := topAffinities(map[uuid.UUID][]*entity.ProductAffinity{
+		productID: {weakest, weaker, strongest, tied},
+	}, 3)
+
+	assert.Equal(t, []*entity.ProductAffinity{strongest, tied, weaker}, top,
+		"affinities rank by score, then support, and only the strongest are kept")
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"solemate/pkg/utils"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+// maxReportDetailsLength caps the free text a shopper can add to a report
+const maxReportDetailsLength = 1000
+
+// ReportReviewRequest says why a review breaks the guidelines. Details are
+// required when the reason is "other".
+type ReportReviewRequest struct {
+	Reason  string `json:"reason" binding:"required"`
+	Details string `json:"details"`
+}
+
+// VoteReview records whether a shopper found a published review helpful.
+// Voting again replaces the shopper's earlier vote.
+func (s *ReviewService) VoteReview(ctx context.Context, userID, reviewID uuid.UUID, helpful bool) (*entity.Review, error) {
+	review, err := s.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID == userID {
+		return nil, errors.New("you cannot vote on your own review")
+	}
+
+	vote := &entity.ReviewVote{
+		ReviewID: reviewID,
+		UserID:   userID,
+		Helpful:  helpful,
+	}
+	if err := s.reviewRepo.Vote(ctx, vote); err != nil {
+		return nil, fmt.Errorf("failed to record vote: %w", err)
+	}
+
+	return s.reviewRepo.GetByID(ctx, reviewID)
+}
+
+// ReportReview records a shopper's report on a published review. Once enough
+// shoppers have reported it since it was last moderated, the review is taken
+// down and queued for a moderator.
+func (s *ReviewService) ReportReview(ctx context.Context, userID, reviewID uuid.UUID, req *ReportReviewRequest) (*entity.ReviewReport, error) {
+	review, err := s.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID == userID {
+		return nil, errors.New("you cannot report your own review")
+	}
+
+	reason := strings.ToLower(strings.TrimSpace(req.Reason))
+	switch reason {
+	case entity.ReviewReportSpam, entity.ReviewReportOffensive, entity.ReviewReportOffTopic,
+		entity.ReviewReportFake, entity.ReviewReportOther:
+	default:
+		return nil, errors.New("reason must be spam, offensive, off_topic, fake or other")
+	}
+
+	details := utils.SanitizeString(strings.TrimSpace(req.Details))
+	if reason == entity.ReviewReportOther && details == "" {
+		return nil, errors.New("details are required when the reason is other")
+	}
+	if len([]rune(details)) > maxReportDetailsLength {
+		return nil, fmt.Errorf("details must be at most %d characters", maxReportDetailsLength)
+	}
+
+	report := &entity.ReviewReport{
+		ReviewID: reviewID,
+		UserID:   userID,
+		Reason:   reason,
+		Details:  details,
+	}
+	count, err := s.reviewRepo.Report(ctx, report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to report review: %w", err)
+	}
+
+	if count >= s.screener.reportThreshold {
+		held, err := s.reviewRepo.HoldForModeration(ctx, reviewID, entity.ReviewFlagReported)
+		if err != nil {
+			return nil, fmt.Errorf("failed to queue reported review: %w", err)
+		}
+		if held {
+			log.Printf("review %s: held for moderation after %d reports", reviewID, count)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+func TestReviewService_VoteReview(t *testing.T) {
+	ctx := context.Background()
+	authorID, voterID := uuid.New(), uuid.New()
+
+	t.Run("vote is recorded", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		review := &entity.Review{ID: uuid.New(), UserID: authorID, Status: entity.ReviewStatusApproved}
+		mocks.reviews.On("GetByID", ctx, review.ID).Return(review, nil)
+		mocks.reviews.On("Vote", ctx, &entity.ReviewVote{ReviewID: review.ID, UserID: voterID, Helpful: true}).Return(nil)
+
+		_, err := service.VoteReview(ctx, voterID, review.ID, true)
+
+		require.NoError(t, err)
+		mocks.reviews.AssertExpectations(t)
+	})
+
+	t.Run("authors cannot vote on their own review", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		review := &entity.Review{ID: uuid.New(), UserID: authorID, Status: entity.ReviewStatusApproved}
+		mocks.reviews.On("GetByID", ctx, review.ID).Return(review, nil)
+
+		_, err := service.VoteReview(ctx, authorID, review.ID, true)
+
+		assert.EqualError(t, err, "you cannot vote on your own review")
+		mocks.reviews.AssertNotCalled(t, "Vote", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unpublished review", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		review := &entity.Review{ID: uuid.New(), UserID: authorID, Status: entity.ReviewStatusPending}
+		mocks.reviews.On("GetByID", ctx, review.ID).Return(review, nil)
+
+		_, err := service.VoteReview(ctx, voterID, review.ID, false)
+
+		assert.EqualError(t, err, "review not found")
+		mocks.reviews.AssertNotCalled(t, "Vote", mock.Anything, mock.Anything)
+	})
+}
+
+func TestReviewService_ReportReview(t *testing.T) {
+	ctx := context.Background()
+	authorID, reporterID := uuid.New(), uuid.New()
+
+	// reviewToReport sets up a published review by authorID
+	reviewToReport := func(mocks *reviewServiceMocks) *entity.Review {
+		review := &entity.Review{ID: uuid.New(), UserID: authorID, Status: entity.ReviewStatusApproved}
+		mocks.reviews.On("GetByID", ctx, review.ID).Return(review, nil)
+		return review
+	}
+
+	t.Run("reports below the threshold keep the review published", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		review := reviewToReport(mocks)
+		mocks.reviews.On("Report", ctx, mock.MatchedBy(func(r *entity.ReviewReport) bool {
+			return r.ReviewID == review.ID && r.UserID == reporterID && r.Reason == entity.ReviewReportSpam
+		})).Return(int64(2), nil)
+
+		report, err := service.ReportReview(ctx, reporterID, review.ID, &ReportReviewRequest{Reason: " SPAM "})
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.ReviewReportSpam, report.Reason)
+		mocks.reviews.AssertNotCalled(t, "HoldForModeration", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reaching the threshold holds the review for moderation", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		review := reviewToReport(mocks)
+		mocks.reviews.On("Report", ctx, mock.Anything).Return(int64(3), nil)
+		mocks.reviews.On("HoldForModeration", ctx, review.ID, entity.ReviewFlagReported).Return(true, nil)
+
+		_, err := service.ReportReview(ctx, reporterID, review.ID, &ReportReviewRequest{Reason: entity.ReviewReportFake})
+
+		require.NoError(t, err)
+		mocks.reviews.AssertExpectations(t)
+	})
+
+	t.Run("failure to hold the review", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		review := reviewToReport(mocks)
+		mocks.reviews.On("Report", ctx, mock.Anything).Return(int64(4), nil)
+		mocks.reviews.On("HoldForModeration", ctx, review.ID, entity.ReviewFlagReported).Return(false, errors.New("database unavailable"))
+
+		_, err := service.ReportReview(ctx, reporterID, review.ID, &ReportReviewRequest{Reason: entity.ReviewReportOffensive})
+
+		assert.ErrorContains(t, err, "failed to queue reported review")
+	})
+
+	t.Run("authors cannot report their own review", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		review := reviewToReport(mocks)
+
+		_, err := service.ReportReview(ctx, authorID, review.ID, &ReportReviewRequest{Reason: entity.ReviewReportSpam})
+
+		assert.EqualError(t, err, "you cannot report your own review")
+		mocks.reviews.AssertNotCalled(t, "Report", mock.Anything, mock.Anything)
+	})
+
+	invalid := map[string]struct {
+		req     ReportReviewRequest
+		wantErr string
+	}{
+		"unknown reason": {
+			req:     ReportReviewRequest{Reason: "boring"},
+			wantErr: "reason must be spam, offensive, off_topic, fake or other",
+		},
+		"other without details": {
+			req:     ReportReviewRequest{Reason: entity.ReviewReportOther, Details: "   "},
+			wantErr: "details are required when the reason is other",
+		},
+		"details too long": {
+			req:     ReportReviewRequest{Reason: entity.ReviewReportOffTopic, Details: strings.Repeat("x", maxReportDetailsLength+1)},
+			wantErr: "details must be at most 1000 characters",
+		},
+	}
+	for name, tt := range invalid {
+		t.Run(name, func(t *testing.T) {
+			service, mocks := newTestReviewService()
+			review := reviewToReport(mocks)
+
+			_, err := service.ReportReview(ctx, reporterID, review.ID, &tt.req)
+
+			assert.EqualError(t, err, tt.wantErr)
+			mocks.reviews.AssertNotCalled(t, "Report", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("other with details", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		review := reviewToReport(mocks)
+		mocks.reviews.On("Report", ctx, mock.MatchedBy(func(r *entity.ReviewReport) bool {
+			return r.Reason == entity.ReviewReportOther && r.Details == "Reviews a different shoe"
+		})).Return(int64(1), nil)
+
+		_, err := service.ReportReview(ctx, reporterID, review.ID, &ReportReviewRequest{Reason: "other", Details: " Reviews a different shoe "})
+
+		require.NoError(t, err)
+		mocks.reviews.AssertExpectations(t)
+	})
+}
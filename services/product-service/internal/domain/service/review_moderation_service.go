@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"solemate/pkg/utils"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+const (
+	// ReviewModerationAuto publishes reviews that pass screening and queues
+	// the rest; ReviewModerationManual queues every review
+	ReviewModerationAuto   = "auto"
+	ReviewModerationManual = "manual"
+
+	// minDuplicateLength keeps short comments such as "Great shoes!" from
+	// being flagged as copies of each other
+	minDuplicateLength = 30
+
+	defaultModerationLimit = 20
+)
+
+// reviewLinkPattern finds URLs and bare domains such as cheap-kicks.shop
+var reviewLinkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+|\b[a-z0-9-]+\.(com|net|org|info|biz|io|co|shop|store|xyz|ru|top|link)\b`)
+
+// ReviewScreener decides which reviews need a moderator
+type ReviewScreener struct {
+	mode            string
+	bannedWords     []string
+	reportThreshold int64
+}
+
+// NewReviewScreener returns a screener for the given moderation mode. An
+// unknown mode is treated as manual, the safe choice. A published review is
+// held again after reportThreshold reports, or after the first one if the
+// threshold is not positive.
+func NewReviewScreener(mode string, bannedWords []string, reportThreshold int) *ReviewScreener {
+	if mode != ReviewModerationAuto {
+		mode = ReviewModerationManual
+	}
+	if reportThreshold < 1 {
+		reportThreshold = 1
+	}
+
+	normalized := make([]string, 0, len(bannedWords))
+	for _, word := range bannedWords {
+		if word = normalizeReviewText(word); word != "" {
+			normalized = append(normalized, word)
+		}
+	}
+
+	return &ReviewScreener{mode: mode, bannedWords: normalized, reportThreshold: int64(reportThreshold)}
+}
+
+// flags returns the screening flags raised by a review's text
+func (s *ReviewScreener) flags(title, comment string) []string {
+	var flags []string
+
+	// Padding with spaces makes every word, and every phrase, match whole
+	text := " " + normalizeReviewText(title+" "+comment) + " "
+	for _, word := range s.bannedWords {
+		if strings.Contains(text, " "+word+" ") {
+			flags = append(flags, entity.ReviewFlagBannedWords)
+			break
+		}
+	}
+
+	if reviewLinkPattern.MatchString(title) || reviewLinkPattern.MatchString(comment) {
+		flags = append(flags, entity.ReviewFlagLinks)
+	}
+
+	return flags
+}
+
+// ModerateReviewRequest carries a moderator's reason, which is shown to the
+// review's author. Rejections must give one.
+type ModerateReviewRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ListReviewsForModeration returns the reviews in a moderation state, oldest
+// first. The status defaults to PENDING.
+func (s *ReviewService) ListReviewsForModeration(ctx context.Context, status string, page, limit int) ([]*entity.Review, int64, error) {
+	status = strings.ToUpper(status)
+	switch status {
+	case "":
+		status = entity.ReviewStatusPending
+	case entity.ReviewStatusPending, entity.ReviewStatusApproved, entity.ReviewStatusRejected:
+	default:
+		return nil, 0, errors.New("status must be PENDING, APPROVED or REJECTED")
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = defaultModerationLimit
+	}
+
+	return s.reviewRepo.ListByStatus(ctx, status, limit, (page-1)*limit)
+}
+
+// ApproveReview publishes a review
+func (s *ReviewService) ApproveReview(ctx context.Context, moderatorID, reviewID uuid.UUID, req *ModerateReviewRequest) (*entity.Review, error) {
+	return s.moderate(ctx, moderatorID, reviewID, entity.ReviewStatusApproved, req.Reason)
+}
+
+// RejectReview hides a review from the product page
+func (s *ReviewService) RejectReview(ctx context.Context, moderatorID, reviewID uuid.UUID, req *ModerateReviewRequest) (*entity.Review, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, errors.New("a reason is required to reject a review")
+	}
+	return s.moderate(ctx, moderatorID, reviewID, entity.ReviewStatusRejected, req.Reason)
+}
+
+func (s *ReviewService) moderate(ctx context.Context, moderatorID, reviewID uuid.UUID, status, reason string) (*entity.Review, error) {
+	review, err := s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	// Repeating a decision would notify the author a second time
+	if review.Status == status {
+		return nil, fmt.Errorf("review is already %s", strings.ToLower(status))
+	}
+
+	now := time.Now()
+	review.Status = status
+	review.ModerationReason = utils.SanitizeString(strings.TrimSpace(reason))
+	review.ModeratedBy = &moderatorID
+	review.ModeratedAt = &now
+
+	if err := s.reviewRepo.Update(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to update review: %w", err)
+	}
+
+	review, err = s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The decision stands even if the author can't be told about it
+	if s.notifications != nil {
+		if err := s.notifications.SendReviewDecision(ctx, review); err != nil {
+			log.Printf("review %s: failed to notify author of moderation decision: %v", review.ID, err)
+		}
+	}
+
+	return review, nil
+}
+
+// screen runs automated screening on a new or edited review and sets its
+// status. Any earlier moderation decision no longer applies to the new text.
+func (s *ReviewService) screen(ctx context.Context, review *entity.Review) error {
+	flags := s.screener.flags(review.Title, review.Comment)
+
+	review.ContentHash = reviewContentHash(review.Comment)
+	if review.ContentHash != "" {
+		copies, err := s.reviewRepo.CountByContentHash(ctx, review.ContentHash, review.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check for duplicate reviews: %w", err)
+		}
+		if copies > 0 {
+			flags = append(flags, entity.ReviewFlagDuplicate)
+		}
+	}
+
+	review.ScreeningFlags = flags
+	review.ModerationReason = ""
+	review.ModeratedBy = nil
+	review.ModeratedAt = nil
+
+	if s.screener.mode == ReviewModerationManual || len(flags) > 0 {
+		review.Status = entity.ReviewStatusPending
+	} else {
+		review.Status = entity.ReviewStatusApproved
+	}
+	return nil
+}
+
+// reviewContentHash identifies a comment regardless of case, punctuation and
+// spacing. Comments too short to be meaningful copies get no hash.
+func reviewContentHash(comment string) string {
+	text := normalizeReviewText(comment)
+	if len([]rune(text)) < minDuplicateLength {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeReviewText lower-cases text and reduces it to words separated by
+// single spaces
+func normalizeReviewText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
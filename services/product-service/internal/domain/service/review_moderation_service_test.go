@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+func TestReviewScreener_Flags(t *testing.T) {
+	screener := NewReviewScreener(ReviewModerationAuto, []string{"Scam", "total rip-off", "  "}, 3)
+
+	tests := map[string]struct {
+		title   string
+		comment string
+		want    []string
+	}{
+		"clean review": {
+			title:   "Great fit",
+			comment: "True to size and comfortable from day one.",
+		},
+		"banned word in any case": {
+			comment: "This seller is a SCAM!",
+			want:    []string{entity.ReviewFlagBannedWords},
+		},
+		"banned word in the title": {
+			title: "scam",
+			want:  []string{entity.ReviewFlagBannedWords},
+		},
+		"banned phrase across punctuation": {
+			comment: "A total, rip-off.",
+			want:    []string{entity.ReviewFlagBannedWords},
+		},
+		"banned word inside another word": {
+			comment: "The scampi place next door was closed.",
+		},
+		"url": {
+			comment: "Cheaper at https://example.com/deal",
+			want:    []string{entity.ReviewFlagLinks},
+		},
+		"www link": {
+			comment: "see www.example.org",
+			want:    []string{entity.ReviewFlagLinks},
+		},
+		"bare domain": {
+			comment: "Buy them at cheap-kicks.shop instead",
+			want:    []string{entity.ReviewFlagLinks},
+		},
+		"sentence ending without a space": {
+			comment: "Fits well.Comfortable too.",
+		},
+		"banned word and link": {
+			title:   "Scam",
+			comment: "real ones at kicks.ru",
+			want:    []string{entity.ReviewFlagBannedWords, entity.ReviewFlagLinks},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, screener.flags(tt.title, tt.comment))
+		})
+	}
+}
+
+func TestNormalizeReviewText(t *testing.T) {
+	tests := map[string]struct {
+		text string
+		want string
+	}{
+		"case and punctuation":  {text: "Great SHOES!!!", want: "great shoes"},
+		"runs of whitespace":    {text: "  too \t small\n\n", want: "too small"},
+		"hyphens split words":   {text: "rip-off", want: "rip off"},
+		"letters beyond ASCII":  {text: "Très Confortable", want: "très confortable"},
+		"digits are kept":       {text: "Size 10.5", want: "size 10 5"},
+		"nothing but symbols":   {text: "?!... ---", want: ""},
+		"already normalized":    {text: "fits well", want: "fits well"},
+		"punctuation as spaces": {text: "fits,well", want: "fits well"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeReviewText(tt.text))
+		})
+	}
+}
+
+func TestReviewContentHash(t *testing.T) {
+	long := "These run half a size small, so order up one."
+	short := "Great shoes, love them!"
+	require.Less(t, len(normalizeReviewText(short)), minDuplicateLength)
+	require.GreaterOrEqual(t, len(normalizeReviewText(long)), minDuplicateLength)
+
+	t.Run("short comments get no hash", func(t *testing.T) {
+		assert.Empty(t, reviewContentHash(short))
+		assert.Empty(t, reviewContentHash(""))
+	})
+
+	t.Run("exactly the minimum length is hashed", func(t *testing.T) {
+		assert.NotEmpty(t, reviewContentHash(strings.Repeat("a", minDuplicateLength)))
+		assert.Empty(t, reviewContentHash(strings.Repeat("a", minDuplicateLength-1)))
+	})
+
+	t.Run("copies match regardless of case, punctuation and spacing", func(t *testing.T) {
+		copied := "THESE run half a size small -- so   order up one"
+		assert.Equal(t, reviewContentHash(long), reviewContentHash(copied))
+	})
+
+	t.Run("different comments differ", func(t *testing.T) {
+		assert.NotEqual(t, reviewContentHash(long), reviewContentHash("These run half a size large, so order down one."))
+	})
+
+	t.Run("padding with punctuation does not reach the minimum", func(t *testing.T) {
+		assert.Empty(t, reviewContentHash(short+strings.Repeat("!", minDuplicateLength)))
+	})
+}
+
+func TestReviewService_Moderate(t *testing.T) {
+	ctx := context.Background()
+	moderatorID := uuid.New()
+
+	t.Run("approval notifies the author", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		review := &entity.Review{ID: uuid.New(), Status: entity.ReviewStatusPending}
+		mocks.reviews.On("GetByID", ctx, review.ID).Return(review, nil)
+		mocks.reviews.On("Update", ctx, mock.MatchedBy(func(r *entity.Review) bool {
+			return r.Status == entity.ReviewStatusApproved && *r.ModeratedBy == moderatorID && r.ModeratedAt != nil
+		})).Return(nil)
+		mocks.notifications.On("SendReviewDecision", ctx, review).Return(nil)
+
+		got, err := service.ApproveReview(ctx, moderatorID, review.ID, &ModerateReviewRequest{})
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.ReviewStatusApproved, got.Status)
+		mocks.notifications.AssertExpectations(t)
+	})
+
+	t.Run("rejecting an approved review", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		review := &entity.Review{ID: uuid.New(), Status: entity.ReviewStatusApproved}
+		mocks.reviews.On("GetByID", ctx, review.ID).Return(review, nil)
+		mocks.reviews.On("Update", ctx, mock.Anything).Return(nil)
+		mocks.notifications.On("SendReviewDecision", ctx, review).Return(errors.New("notification service unavailable"))
+
+		got, err := service.RejectReview(ctx, moderatorID, review.ID, &ModerateReviewRequest{Reason: " Off topic "})
+
+		require.NoError(t, err, "the decision stands when the author cannot be told")
+		assert.Equal(t, entity.ReviewStatusRejected, got.Status)
+		assert.Equal(t, "Off topic", got.ModerationReason)
+	})
+
+	t.Run("repeating a decision", func(t *testing.T) {
+		for _, status := range []string{entity.ReviewStatusApproved, entity.ReviewStatusRejected} {
+			service, mocks := newTestReviewService()
+			review := &entity.Review{ID: uuid.New(), Status: status}
+			mocks.reviews.On("GetByID", ctx, review.ID).Return(review, nil)
+
+			var err error
+			if status == entity.ReviewStatusApproved {
+				_, err = service.ApproveReview(ctx, moderatorID, review.ID, &ModerateReviewRequest{})
+			} else {
+				_, err = service.RejectReview(ctx, moderatorID, review.ID, &ModerateReviewRequest{Reason: "spam"})
+			}
+
+			assert.EqualError(t, err, "review is already "+strings.ToLower(status))
+			mocks.reviews.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			mocks.notifications.AssertNotCalled(t, "SendReviewDecision", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("rejection needs a reason", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+
+		_, err := service.RejectReview(ctx, moderatorID, uuid.New(), &ModerateReviewRequest{Reason: "  "})
+
+		assert.EqualError(t, err, "a reason is required to reject a review")
+		mocks.reviews.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+}
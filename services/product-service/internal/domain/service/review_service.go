@@ -4,25 +4,42 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 
 	"github.com/google/uuid"
+	"solemate/pkg/privacy"
 	"solemate/pkg/utils"
 	"solemate/services/product-service/internal/domain/entity"
 	"solemate/services/product-service/internal/domain/repository"
 )
 
+// maxReviewImages caps how many photos a single review can carry
+const maxReviewImages = 5
+
 type ReviewService struct {
-	reviewRepo  repository.ReviewRepository
-	productRepo repository.ProductRepository
+	reviewRepo    repository.ReviewRepository
+	productRepo   repository.ProductRepository
+	images        *ImageUploader
+	screener      *ReviewScreener
+	notifications repository.NotificationRepository
+	orders        repository.OrderRepository
 }
 
 func NewReviewService(
 	reviewRepo repository.ReviewRepository,
 	productRepo repository.ProductRepository,
+	images *ImageUploader,
+	screener *ReviewScreener,
+	notifications repository.NotificationRepository,
+	orders repository.OrderRepository,
 ) *ReviewService {
 	return &ReviewService{
-		reviewRepo:  reviewRepo,
-		productRepo: productRepo,
+		reviewRepo:    reviewRepo,
+		productRepo:   productRepo,
+		images:        images,
+		screener:      screener,
+		notifications: notifications,
+		orders:        orders,
 	}
 }
 
@@ -58,6 +75,10 @@ func (s *ReviewService) CreateReview(ctx context.Context, userID uuid.UUID, req
 		return nil, errors.New("rating must be between 1 and 5")
 	}
 
+	if len(req.Images) > maxReviewImages {
+		return nil, fmt.Errorf("a review can have at most %d images", maxReviewImages)
+	}
+
 	// Parse and validate product ID
 	productID, err := uuid.Parse(req.ProductID)
 	if err != nil {
@@ -79,14 +100,9 @@ func (s *ReviewService) CreateReview(ctx context.Context, userID uuid.UUID, req
 		return nil, errors.New("you have already reviewed this product")
 	}
 
-	// Parse order ID if provided
-	var orderID *uuid.UUID
-	if req.OrderID != "" {
-		id, err := uuid.Parse(req.OrderID)
-		if err != nil {
-			return nil, errors.New("invalid order ID")
-		}
-		orderID = &id
+	orderID, err := s.verifiedOrder(ctx, userID, productID, req.OrderID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create review
@@ -98,9 +114,13 @@ func (s *ReviewService) CreateReview(ctx context.Context, userID uuid.UUID, req
 		Title:        utils.SanitizeString(req.Title),
 		Comment:      utils.SanitizeString(req.Comment),
 		Images:       req.Images,
-		IsVerified:   orderID != nil, // Mark as verified if order ID is provided
+		IsVerified:   orderID != nil, // only set to an order order-service vouched for
 		HelpfulCount: 0,
-		Status:       "APPROVED", // Auto-approve reviews (can be changed to PENDING for moderation)
+	}
+
+	// Screening decides whether the review is published or queued
+	if err := s.screen(ctx, review); err != nil {
+		return nil, err
 	}
 
 	err = s.reviewRepo.Create(ctx, review)
@@ -111,8 +131,54 @@ func (s *ReviewService) CreateReview(ctx context.Context, userID uuid.UUID, req
 	return s.reviewRepo.GetByID(ctx, review.ID)
 }
 
+// verifiedOrder finds the order that makes a review a verified purchase: the
+// order the reviewer named, or else their most recent delivered order of the
+// product. Naming an order that doesn't qualify is an error. If order-service
+// can't be reached the review is published unverified rather than refused.
+func (s *ReviewService) verifiedOrder(ctx context.Context, userID, productID uuid.UUID, requested string) (*uuid.UUID, error) {
+	var orderID *uuid.UUID
+	if requested != "" {
+		id, err := uuid.Parse(requested)
+		if err != nil {
+			return nil, errors.New("invalid order ID")
+		}
+		orderID = &id
+	}
+
+	if s.orders == nil {
+		return nil, nil
+	}
+	purchases, err := s.orders.GetPurchases(ctx, userID, productID)
+	if err != nil {
+		log.Printf("product %s: failed to verify purchase by user %s: %v", productID, userID, err)
+		return nil, nil
+	}
+
+	if orderID == nil {
+		if len(purchases) == 0 {
+			return nil, nil
+		}
+		return &purchases[0].ID, nil
+	}
+	for _, purchase := range purchases {
+		if purchase.ID == *orderID {
+			return orderID, nil
+		}
+	}
+	return nil, errors.New("the order must be a delivered order of yours that contains this product")
+}
+
+// GetReviewByID returns a published review. Pending and rejected reviews
+// are only visible in the moderation queue.
 func (s *ReviewService) GetReviewByID(ctx context.Context, id uuid.UUID) (*entity.Review, error) {
-	return s.reviewRepo.GetByID(ctx, id)
+	review, err := s.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != entity.ReviewStatusApproved {
+		return nil, errors.New("review not found")
+	}
+	return review, nil
 }
 
 func (s *ReviewService) GetReviewsByProductID(ctx context.Context, productID uuid.UUID, req *GetReviewsRequest) ([]*entity.Review, int64, error) {
@@ -163,7 +229,18 @@ func (s *ReviewService) UpdateReview(ctx context.Context, userID, reviewID uuid.
 		review.Comment = utils.SanitizeString(*req.Comment)
 	}
 
+	// Edited text goes through screening again
+	if req.Title != nil || req.Comment != nil {
+		if err := s.screen(ctx, review); err != nil {
+			return nil, err
+		}
+	}
+
+	previousImages := review.Images
 	if req.Images != nil {
+		if len(req.Images) > maxReviewImages {
+			return nil, fmt.Errorf("a review can have at most %d images", maxReviewImages)
+		}
 		review.Images = req.Images
 	}
 
@@ -172,9 +249,48 @@ func (s *ReviewService) UpdateReview(ctx context.Context, userID, reviewID uuid.
 		return nil, fmt.Errorf("failed to update review: %w", err)
 	}
 
+	if req.Images != nil {
+		s.images.RemoveUnused(ctx, previousImages, review.Images)
+	}
+
 	return s.reviewRepo.GetByID(ctx, review.ID)
 }
 
+// UploadReviewImage stores a photo uploaded by the review's author and
+// appends its zoom rendition to the review's images
+func (s *ReviewService) UploadReviewImage(ctx context.Context, userID, reviewID uuid.UUID, data []byte) (*entity.Review, *UploadedImage, error) {
+	review, err := s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Check if user owns this review
+	if review.UserID != userID {
+		return nil, nil, errors.New("you can only add images to your own reviews")
+	}
+
+	if len(review.Images) >= maxReviewImages {
+		return nil, nil, fmt.Errorf("a review can have at most %d images", maxReviewImages)
+	}
+
+	uploaded, err := s.images.Upload(ctx, fmt.Sprintf("reviews/%s", review.ID), data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	review.Images = append(review.Images, uploaded.ZoomURL)
+	if err := s.reviewRepo.Update(ctx, review); err != nil {
+		s.images.Remove(ctx, uploaded.ZoomURL)
+		return nil, nil, fmt.Errorf("failed to update review: %w", err)
+	}
+
+	review, err = s.reviewRepo.GetByID(ctx, review.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return review, uploaded, nil
+}
+
 func (s *ReviewService) DeleteReview(ctx context.Context, userID, reviewID uuid.UUID) error {
 	// Get existing review
 	review, err := s.reviewRepo.GetByID(ctx, reviewID)
@@ -187,5 +303,35 @@ func (s *ReviewService) DeleteReview(ctx context.Context, userID, reviewID uuid.
 		return errors.New("you can only delete your own reviews")
 	}
 
-	return s.reviewRepo.Delete(ctx, reviewID)
+	if err := s.reviewRepo.Delete(ctx, reviewID); err != nil {
+		return err
+	}
+
+	s.images.Remove(ctx, review.Images...)
+	return nil
+}
+
+func (s *ReviewService) ExportUserData(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	reviews, err := s.reviewRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reviews: %w", err)
+	}
+
+	return map[string]interface{}{
+		"reviews": reviews,
+	}, nil
+}
+
+// EraseUserData pseudonymises the user's reviews: the content and ratings stay
+// on the product page, but are re-owned by a random ID that cannot be traced
+// back to the user.
+func (s *ReviewService) EraseUserData(ctx context.Context, userID uuid.UUID) (*privacy.ErasureReport, error) {
+	count, err := s.reviewRepo.ReassignUser(ctx, userID, uuid.New())
+	if err != nil {
+		return nil, fmt.Errorf("failed to pseudonymize reviews: %w", err)
+	}
+
+	report := privacy.NewErasureReport()
+	report.AddAnonymized("reviews", count)
+	return report, nil
 }
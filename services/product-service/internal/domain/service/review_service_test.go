@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// MockReviewRepository is a mock implementation of repository.ReviewRepository
+type MockReviewRepository struct {
+	mock.Mock
+}
+
+func (m *MockReviewRepository) Create(ctx context.Context, review *entity.Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Review, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetByProductID(ctx context.Context, productID uuid.UUID, filters repository.ReviewFilters) ([]*entity.Review, int64, error) {
+	args := m.Called(ctx, productID, filters)
+	return args.Get(0).([]*entity.Review), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockReviewRepository) Update(ctx context.Context, review *entity.Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) GetUserReviewForProduct(ctx context.Context, userID, productID uuid.UUID) (*entity.Review, error) {
+	args := m.Called(ctx, userID, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Review, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*entity.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) ReassignUser(ctx context.Context, userID, pseudonymID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID, pseudonymID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReviewRepository) ListByStatus(ctx context.Context, status string, limit, offset int) ([]*entity.Review, int64, error) {
+	args := m.Called(ctx, status, limit, offset)
+	return args.Get(0).([]*entity.Review), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockReviewRepository) CountByContentHash(ctx context.Context, hash string, excludeID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, hash, excludeID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReviewRepository) Vote(ctx context.Context, vote *entity.ReviewVote) error {
+	args := m.Called(ctx, vote)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) Report(ctx context.Context, report *entity.ReviewReport) (int64, error) {
+	args := m.Called(ctx, report)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReviewRepository) HoldForModeration(ctx context.Context, id uuid.UUID, flag string) (bool, error) {
+	args := m.Called(ctx, id, flag)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReviewRepository) RefreshProductRatings(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockReviewNotifications is a mock implementation of repository.NotificationRepository
+type MockReviewNotifications struct {
+	mock.Mock
+}
+
+func (m *MockReviewNotifications) SendReviewDecision(ctx context.Context, review *entity.Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+// reviewServiceMocks holds the mocked dependencies of a ReviewService
+type reviewServiceMocks struct {
+	reviews       *MockReviewRepository
+	products      *MockProductRepository
+	notifications *MockReviewNotifications
+	orders        *MockOrderRepository
+}
+
+// newTestReviewService returns a review service that screens in auto mode,
+// bans "scam" and holds a review after its third report
+func newTestReviewService() (*ReviewService, *reviewServiceMocks) {
+	mocks := &reviewServiceMocks{
+		reviews:       new(MockReviewRepository),
+		products:      new(MockProductRepository),
+		notifications: new(MockReviewNotifications),
+		orders:        new(MockOrderRepository),
+	}
+	service := NewReviewService(mocks.reviews, mocks.products, NewImageUploader(externalImageStorage{}),
+		NewReviewScreener(ReviewModerationAuto, []string{"scam"}, 3), mocks.notifications, mocks.orders)
+	return service, mocks
+}
+
+func TestReviewService_CreateReviewVerifiedPurchase(t *testing.T) {
+	ctx := context.Background()
+	userID, productID := uuid.New(), uuid.New()
+	latest, older := uuid.New(), uuid.New()
+	purchases := []repository.PurchasedOrder{{ID: latest, Status: "delivered"}, {ID: older, Status: "completed"}}
+
+	// expectCreate sets up a new review of the product and captures it
+	expectCreate := func(mocks *reviewServiceMocks) *entity.Review {
+		created := &entity.Review{}
+		mocks.products.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+		mocks.reviews.On("GetUserReviewForProduct", ctx, userID, productID).Return(nil, nil)
+		mocks.reviews.On("Create", ctx, mock.AnythingOfType("*entity.Review")).Return(nil).Run(func(args mock.Arguments) {
+			*created = *args.Get(1).(*entity.Review)
+		})
+		mocks.reviews.On("GetByID", ctx, mock.Anything).Return(created, nil)
+		return created
+	}
+	request := func(orderID string) *CreateReviewRequest {
+		return &CreateReviewRequest{ProductID: productID.String(), Rating: 5, Comment: "Comfy", OrderID: orderID}
+	}
+
+	t.Run("without an order the latest purchase is used", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		created := expectCreate(mocks)
+		mocks.orders.On("GetPurchases", ctx, userID, productID).Return(purchases, nil)
+
+		_, err := service.CreateReview(ctx, userID, request(""))
+
+		require.NoError(t, err)
+		require.NotNil(t, created.OrderID)
+		assert.Equal(t, latest, *created.OrderID)
+		assert.True(t, created.IsVerified)
+	})
+
+	t.Run("a named purchase is used", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		created := expectCreate(mocks)
+		mocks.orders.On("GetPurchases", ctx, userID, productID).Return(purchases, nil)
+
+		_, err := service.CreateReview(ctx, userID, request(older.String()))
+
+		require.NoError(t, err)
+		require.NotNil(t, created.OrderID)
+		assert.Equal(t, older, *created.OrderID)
+		assert.True(t, created.IsVerified)
+	})
+
+	t.Run("an order that is not one of the purchases is rejected", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		expectCreate(mocks)
+		mocks.orders.On("GetPurchases", ctx, userID, productID).Return(purchases, nil)
+
+		_, err := service.CreateReview(ctx, userID, request(uuid.New().String()))
+
+		assert.EqualError(t, err, "the order must be a delivered order of yours that contains this product")
+		mocks.reviews.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("without purchases the review is unverified", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		created := expectCreate(mocks)
+		mocks.orders.On("GetPurchases", ctx, userID, productID).Return([]repository.PurchasedOrder{}, nil)
+
+		_, err := service.CreateReview(ctx, userID, request(""))
+
+		require.NoError(t, err)
+		assert.Nil(t, created.OrderID)
+		assert.False(t, created.IsVerified)
+	})
+
+	t.Run("order service failure leaves the review unverified", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		created := expectCreate(mocks)
+		mocks.orders.On("GetPurchases", ctx, userID, productID).Return([]repository.PurchasedOrder(nil), errors.New("order service unavailable"))
+
+		_, err := service.CreateReview(ctx, userID, request(latest.String()))
+
+		require.NoError(t, err)
+		assert.Nil(t, created.OrderID)
+		assert.False(t, created.IsVerified)
+	})
+
+	t.Run("malformed order ID", func(t *testing.T) {
+		service, mocks := newTestReviewService()
+		expectCreate(mocks)
+
+		_, err := service.CreateReview(ctx, userID, request("order-42"))
+
+		assert.EqualError(t, err, "invalid order ID")
+		mocks.orders.AssertNotCalled(t, "GetPurchases", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,153 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"solemate/pkg/utils"
+	"solemate/services/product-service/internal/domain/service"
+)
+
+// ListCategoryAttributes returns the attributes of a category's products,
+// including those inherited from its ancestors
+// GET /api/v1/categories/:id/attributes
+func (h *CategoryHandler) ListCategoryAttributes(c *gin.Context) {
+	categoryID, ok := parseUUIDParam(c, "id", "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	attributes, err := h.categoryService.ListAttributes(c.Request.Context(), categoryID)
+	if err != nil {
+		utils.NotFoundResponse(c, "Category not found")
+		return
+	}
+
+	utils.SuccessResponse(c, "Attributes retrieved successfully", attributes)
+}
+
+// CreateCategoryAttribute defines an attribute for a category and its
+// descendants
+// POST /api/v1/admin/categories/:id/attributes
+func (h *CategoryHandler) CreateCategoryAttribute(c *gin.Context) {
+	categoryID, ok := parseUUIDParam(c, "id", "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	var req service.CreateAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	attribute, err := h.categoryService.CreateAttribute(c.Request.Context(), categoryID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to create attribute", err.Error())
+		return
+	}
+
+	utils.CreatedResponse(c, "Attribute created successfully", attribute)
+}
+
+// UpdateCategoryAttribute changes one of a category's own attributes
+// PUT /api/v1/admin/categories/:id/attributes/:attribute_id
+func (h *CategoryHandler) UpdateCategoryAttribute(c *gin.Context) {
+	categoryID, ok := parseUUIDParam(c, "id", "Invalid category ID")
+	if !ok {
+		return
+	}
+	attributeID, ok := parseUUIDParam(c, "attribute_id", "Invalid attribute ID")
+	if !ok {
+		return
+	}
+
+	var req service.UpdateAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	attribute, err := h.categoryService.UpdateAttribute(c.Request.Context(), categoryID, attributeID, &req)
+	if err != nil {
+		respondAttributeError(c, "Failed to update attribute", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Attribute updated successfully", attribute)
+}
+
+// DeleteCategoryAttribute deletes an attribute and every value stored for it
+// DELETE /api/v1/admin/categories/:id/attributes/:attribute_id
+func (h *CategoryHandler) DeleteCategoryAttribute(c *gin.Context) {
+	categoryID, ok := parseUUIDParam(c, "id", "Invalid category ID")
+	if !ok {
+		return
+	}
+	attributeID, ok := parseUUIDParam(c, "attribute_id", "Invalid attribute ID")
+	if !ok {
+		return
+	}
+
+	if err := h.categoryService.DeleteAttribute(c.Request.Context(), categoryID, attributeID); err != nil {
+		respondAttributeError(c, "Failed to delete attribute", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Attribute deleted successfully", nil)
+}
+
+// SetProductAttributes replaces a product's attribute values
+// PUT /api/v1/admin/products/:id/attributes
+// Body: { "attributes": { "width": "wide", "drop": 6, "waterproof": true } }
+func (h *ProductHandler) SetProductAttributes(c *gin.Context) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	var req service.SetAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	attributes, err := h.productService.SetProductAttributes(c.Request.Context(), productID, &req)
+	if err != nil {
+		respondCatalogError(c, "Failed to set attributes", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Attributes updated successfully", attributes)
+}
+
+// SetVariantAttributes replaces a variant's attribute values
+// PUT /api/v1/admin/products/:id/variants/:variant_id/attributes
+func (h *ProductHandler) SetVariantAttributes(c *gin.Context) {
+	productID, variantID, ok := productChildParams(c, "variant_id", "Invalid variant ID")
+	if !ok {
+		return
+	}
+
+	var req service.SetAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	attributes, err := h.productService.SetVariantAttributes(c.Request.Context(), productID, variantID, &req)
+	if err != nil {
+		respondCatalogError(c, "Failed to set attributes", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Attributes updated successfully", attributes)
+}
+
+func respondAttributeError(c *gin.Context, message string, err error) {
+	if errors.Is(err, service.ErrAttributeNotFound) {
+		utils.NotFoundResponse(c, "Attribute not found")
+		return
+	}
+	utils.BadRequestResponse(c, message, err.Error())
+}
@@ -130,4 +130,46 @@ func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
 	}
 
 	utils.SuccessResponse(c, "Category tree retrieved successfully", categories)
-}
\ No newline at end of file
+}
+// MoveCategory re-parents a category together with its subtree
+// POST /api/v1/admin/categories/:id/move
+func (h *CategoryHandler) MoveCategory(c *gin.Context) {
+	categoryIDParam := c.Param("id")
+	categoryID, err := uuid.Parse(categoryIDParam)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid category ID", err.Error())
+		return
+	}
+
+	var req service.MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	category, err := h.categoryService.MoveCategory(c.Request.Context(), categoryID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to move category", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Category moved successfully", category)
+}
+
+// ReorderCategories sets the display order of sibling categories
+// PUT /api/v1/admin/categories/order
+func (h *CategoryHandler) ReorderCategories(c *gin.Context) {
+	var req service.ReorderCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	categories, err := h.categoryService.ReorderCategories(c.Request.Context(), &req)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to reorder categories", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Categories reordered successfully", categories)
+}
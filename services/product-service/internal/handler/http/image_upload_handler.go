@@ -0,0 +1,132 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"solemate/pkg/auth"
+	"solemate/pkg/imaging"
+	"solemate/pkg/utils"
+	"solemate/services/product-service/internal/domain/service"
+)
+
+// multipartOverhead allows for the form fields and boundaries around the file
+const multipartOverhead = 1 << 20
+
+// UploadImage uploads a product image and generates its renditions
+// POST /api/v1/admin/products/:id/images/upload
+// Multipart form: file, alt_text, is_primary
+func (h *ProductHandler) UploadImage(c *gin.Context) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	data, ok := readImageUpload(c)
+	if !ok {
+		return
+	}
+
+	var req service.UploadImageRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid form data", err.Error())
+		return
+	}
+
+	image, err := h.productService.UploadImage(c.Request.Context(), productID, data, &req)
+	if err != nil {
+		respondCatalogError(c, "Failed to upload image", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Image uploaded successfully", image)
+}
+
+// UploadVariantImage uploads an image for a single variant
+// POST /api/v1/admin/products/:id/variants/:variant_id/images
+// Multipart form: file
+func (h *ProductHandler) UploadVariantImage(c *gin.Context) {
+	productID, variantID, ok := productChildParams(c, "variant_id", "Invalid variant ID")
+	if !ok {
+		return
+	}
+
+	data, ok := readImageUpload(c)
+	if !ok {
+		return
+	}
+
+	variant, uploaded, err := h.productService.UploadVariantImage(c.Request.Context(), productID, variantID, data)
+	if err != nil {
+		respondCatalogError(c, "Failed to upload image", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Image uploaded successfully", gin.H{
+		"variant": variant,
+		"image":   uploaded,
+	})
+}
+
+// UploadReviewImage handles POST /api/v1/reviews/:id/images
+// Multipart form: file
+func (h *ReviewHandler) UploadReviewImage(c *gin.Context) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "Authentication required")
+		return
+	}
+
+	reviewID, ok := parseUUIDParam(c, "id", "Invalid review ID")
+	if !ok {
+		return
+	}
+
+	data, ok := readImageUpload(c)
+	if !ok {
+		return
+	}
+
+	review, uploaded, err := h.reviewService.UploadReviewImage(c.Request.Context(), userID, reviewID, data)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to upload image", err.Error())
+		return
+	}
+
+	utils.CreatedResponse(c, "Image uploaded successfully", gin.H{
+		"review": review,
+		"image":  uploaded,
+	})
+}
+
+// readImageUpload reads the "file" part of a multipart upload, rejecting
+// bodies larger than imaging.MaxUploadSize before they are buffered
+func readImageUpload(c *gin.Context) ([]byte, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, imaging.MaxUploadSize+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequestResponse(c, "Image file is required", err.Error())
+		return nil, false
+	}
+	if header.Size > imaging.MaxUploadSize {
+		utils.BadRequestResponse(c, "Image is too large", imaging.ErrTooLarge.Error())
+		return nil, false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to read image", err.Error())
+		return nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, imaging.MaxUploadSize+1))
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to read image", err.Error())
+		return nil, false
+	}
+
+	return data, true
+}
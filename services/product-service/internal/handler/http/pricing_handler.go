@@ -0,0 +1,103 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"solemate/pkg/utils"
+	"solemate/services/product-service/internal/domain/service"
+)
+
+// ListPriceSchedules returns a product's price schedules
+// GET /api/v1/admin/products/:id/price-schedules
+func (h *ProductHandler) ListPriceSchedules(c *gin.Context) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	schedules, err := h.productService.ListPriceSchedules(c.Request.Context(), productID)
+	if err != nil {
+		respondCatalogError(c, "Failed to get price schedules", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Price schedules retrieved successfully", schedules)
+}
+
+// CreatePriceSchedule schedules a price for a product or variant
+// POST /api/v1/admin/products/:id/price-schedules
+func (h *ProductHandler) CreatePriceSchedule(c *gin.Context) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	var req service.CreatePriceScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	schedule, err := h.productService.CreatePriceSchedule(c.Request.Context(), productID, &req)
+	if err != nil {
+		respondCatalogError(c, "Failed to create price schedule", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Price schedule created successfully", schedule)
+}
+
+// CancelPriceSchedule cancels a price schedule, restoring the previous
+// price if it is active
+// DELETE /api/v1/admin/products/:id/price-schedules/:schedule_id
+func (h *ProductHandler) CancelPriceSchedule(c *gin.Context) {
+	productID, scheduleID, ok := productChildParams(c, "schedule_id", "Invalid price schedule ID")
+	if !ok {
+		return
+	}
+
+	schedule, err := h.productService.CancelPriceSchedule(c.Request.Context(), productID, scheduleID)
+	if err != nil {
+		if errors.Is(err, service.ErrPriceScheduleNotFound) {
+			utils.NotFoundResponse(c, "Price schedule not found")
+			return
+		}
+		respondCatalogError(c, "Failed to cancel price schedule", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Price schedule cancelled successfully", schedule)
+}
+
+// GetPriceHistory returns the price timeline of a product or one of its
+// variants and the lowest price in that window
+// GET /api/v1/admin/products/:id/price-history?variant_id=...&days=30
+func (h *ProductHandler) GetPriceHistory(c *gin.Context) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	var variantID *uuid.UUID
+	if value := c.Query("variant_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid variant ID", err.Error())
+			return
+		}
+		variantID = &id
+	}
+
+	days, _ := strconv.Atoi(c.Query("days"))
+
+	timeline, err := h.productService.GetPriceTimeline(c.Request.Context(), productID, variantID, days)
+	if err != nil {
+		respondCatalogError(c, "Failed to get price history", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Price history retrieved successfully", timeline)
+}
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"errors"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"solemate/pkg/pagination"
 	"solemate/pkg/utils"
 	"solemate/services/product-service/internal/domain/service"
 )
@@ -109,17 +112,25 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 }
 
 func (h *ProductHandler) ListProducts(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	var req service.ProductListRequest
+	req.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	req.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+	req.SortBy = c.Query("sort_by") // e.g. popularity or best_selling; newest first by default
+	req.SortOrder = c.DefaultQuery("sort_order", "desc")
+	req.Cursor = c.Query("cursor") // next_cursor or prev_cursor of a previous page
+	req.SkipCount, _ = strconv.ParseBool(c.Query("skip_count"))
 
-	products, total, err := h.productService.ListProducts(c.Request.Context(), page, limit)
+	products, page, err := h.productService.ListProducts(c.Request.Context(), &req)
 	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			utils.BadRequestResponse(c, "Invalid cursor", err.Error())
+			return
+		}
 		utils.InternalServerErrorResponse(c, "Failed to retrieve products", err.Error())
 		return
 	}
 
-	pagination := utils.CalculatePagination(page, limit, total)
-	utils.PaginatedSuccessResponse(c, "Products retrieved successfully", products, pagination)
+	utils.PaginatedSuccessResponse(c, "Products retrieved successfully", products, utils.CalculateCursorPagination(req.Page, req.Limit, req.Cursor, *page))
 }
 
 func (h *ProductHandler) SearchProducts(c *gin.Context) {
@@ -127,9 +138,13 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 
 	// Parse query parameters
 	req.Query = c.Query("q")
-	req.CategoryID = getStringPtr(c.Query("category_id"))
-	req.BrandID = getStringPtr(c.Query("brand_id"))
-	req.SortBy = c.DefaultQuery("sort_by", "created_at")
+	req.CategoryIDs = queryList(c, "category_id")
+	req.BrandIDs = queryList(c, "brand_id")
+	req.Sizes = queryList(c, "size")
+	req.Colors = queryList(c, "color")
+	// Attribute filters, e.g. ?attr=width=wide,extra-wide&attr=drop<=6
+	req.Attributes = c.QueryArray("attr")
+	req.SortBy = c.Query("sort_by") // defaults to relevance when q is set, newest first otherwise
 	req.SortOrder = c.DefaultQuery("sort_order", "desc")
 
 	// Parse numeric parameters
@@ -154,29 +169,35 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 	// Parse pagination
 	req.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
 	req.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+	req.Cursor = c.Query("cursor")
+	req.SkipCount, _ = strconv.ParseBool(c.Query("skip_count"))
 
 	// Parse tags
 	if tagsParam := c.Query("tags"); tagsParam != "" {
 		req.Tags = []string{tagsParam} // For simplicity, accepting single tag
 	}
 
-	products, total, err := h.productService.SearchProducts(c.Request.Context(), &req)
+	products, page, facets, err := h.productService.SearchProducts(c.Request.Context(), &req)
 	if err != nil {
 		utils.BadRequestResponse(c, "Search failed", err.Error())
 		return
 	}
 
-	pagination := utils.CalculatePagination(req.Page, req.Limit, total)
+	pagination := utils.CalculateCursorPagination(req.Page, req.Limit, req.Cursor, *page)
 	result := map[string]interface{}{
 		"products": products,
 		"query":    req.Query,
+		"facets":   facets,
 		"filters": map[string]interface{}{
-			"category_id": req.CategoryID,
-			"brand_id":    req.BrandID,
+			"category_ids": req.CategoryIDs,
+			"brand_ids":    req.BrandIDs,
+			"sizes":        req.Sizes,
+			"colors":       req.Colors,
 			"min_price":   req.MinPrice,
 			"max_price":   req.MaxPrice,
 			"tags":        req.Tags,
 			"in_stock":    req.InStock,
+			"attributes":  req.Attributes,
 		},
 	}
 
@@ -203,9 +224,16 @@ func (h *ProductHandler) GetRelatedProducts(c *gin.Context) {
 }
 
 // Helper function
-func getStringPtr(s string) *string {
-	if s == "" {
-		return nil
+// queryList reads a multi-valued query parameter, given either repeated
+// (?size=9&size=10) or comma-separated (?size=9,10)
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, param := range c.QueryArray(key) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
 	}
-	return &s
+	return values
 }
\ No newline at end of file
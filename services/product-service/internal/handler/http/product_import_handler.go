@@ -0,0 +1,147 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"solemate/pkg/auth"
+	"solemate/pkg/utils"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/service"
+)
+
+// ImportProducts starts a bulk import of products with their variants and
+// images. The format defaults to the file's extension.
+// POST /api/v1/admin/products/imports
+// Multipart form: file, format (csv or json), dry_run
+func (h *ProductHandler) ImportProducts(c *gin.Context) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "Authentication required")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxImportSize+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequestResponse(c, "Import file is required", err.Error())
+		return
+	}
+	if header.Size > service.MaxImportSize {
+		utils.BadRequestResponse(c, "Import file is too large", "files can be at most 20MB")
+		return
+	}
+
+	var req service.ImportProductsRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid form data", err.Error())
+		return
+	}
+	if req.Format == "" {
+		req.Format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to read import file", err.Error())
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, service.MaxImportSize))
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to read import file", err.Error())
+		return
+	}
+
+	job, err := h.productService.StartImport(c.Request.Context(), userID, data, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrUnsupportedCatalogFormat) {
+			utils.BadRequestResponse(c, "Unsupported import format", err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to start import", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Import started",
+		Data:    job,
+	})
+}
+
+// GetImportJob returns an import's status, progress and validation report
+// GET /api/v1/admin/products/imports/:id
+func (h *ProductHandler) GetImportJob(c *gin.Context) {
+	jobID, ok := parseUUIDParam(c, "id", "Invalid import ID")
+	if !ok {
+		return
+	}
+
+	job, err := h.productService.GetImportJob(c.Request.Context(), jobID)
+	if err != nil {
+		utils.NotFoundResponse(c, "Import not found")
+		return
+	}
+
+	utils.SuccessResponse(c, "Import retrieved successfully", job)
+}
+
+// ConfirmImport imports the products of a validated dry run
+// POST /api/v1/admin/products/imports/:id/confirm
+func (h *ProductHandler) ConfirmImport(c *gin.Context) {
+	jobID, ok := parseUUIDParam(c, "id", "Invalid import ID")
+	if !ok {
+		return
+	}
+
+	job, err := h.productService.ConfirmImport(c.Request.Context(), jobID)
+	if err != nil {
+		if errors.Is(err, service.ErrImportJobNotFound) {
+			utils.NotFoundResponse(c, "Import not found")
+			return
+		}
+		utils.BadRequestResponse(c, "Failed to confirm import", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Import started",
+		Data:    job,
+	})
+}
+
+// ExportProducts streams the whole catalog in the import format
+// GET /api/v1/admin/products/export?format=csv
+func (h *ProductHandler) ExportProducts(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", entity.CatalogFormatCSV))
+
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case entity.CatalogFormatCSV:
+	case entity.CatalogFormatJSON:
+		contentType = "application/json"
+	default:
+		utils.BadRequestResponse(c, "Unsupported export format", service.ErrUnsupportedCatalogFormat.Error())
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="products.`+format+`"`)
+	c.Status(http.StatusOK)
+
+	// The status has been sent by the time a batch fails, so the truncated
+	// download is the only signal the client gets
+	if err := h.productService.ExportCatalog(c.Request.Context(), format, c.Writer); err != nil {
+		log.Printf("product export failed: %v", err)
+		c.Abort()
+	}
+}
@@ -0,0 +1,304 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"solemate/pkg/utils"
+	"solemate/services/product-service/internal/domain/service"
+)
+
+// ListVariants returns all of a product's variants, including inactive ones
+// GET /api/v1/admin/products/:id/variants
+func (h *ProductHandler) ListVariants(c *gin.Context) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	variants, err := h.productService.ListVariants(c.Request.Context(), productID)
+	if err != nil {
+		respondCatalogError(c, "Failed to get variants", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Variants retrieved successfully", variants)
+}
+
+// CreateVariant adds a variant to a product
+// POST /api/v1/admin/products/:id/variants
+func (h *ProductHandler) CreateVariant(c *gin.Context) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	var req service.CreateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	variant, err := h.productService.CreateVariant(c.Request.Context(), productID, &req)
+	if err != nil {
+		respondCatalogError(c, "Failed to create variant", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Variant created successfully", variant)
+}
+
+// UpdateVariant edits a variant
+// PUT /api/v1/admin/products/:id/variants/:variant_id
+func (h *ProductHandler) UpdateVariant(c *gin.Context) {
+	productID, variantID, ok := productChildParams(c, "variant_id", "Invalid variant ID")
+	if !ok {
+		return
+	}
+
+	var req service.UpdateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	variant, err := h.productService.UpdateVariant(c.Request.Context(), productID, variantID, &req)
+	if err != nil {
+		respondCatalogError(c, "Failed to update variant", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Variant updated successfully", variant)
+}
+
+// UpdateVariantStock sets a variant's stock level
+// PUT /api/v1/admin/products/:id/variants/:variant_id/stock
+// Body: { "stock": 12 }
+func (h *ProductHandler) UpdateVariantStock(c *gin.Context) {
+	productID, variantID, ok := productChildParams(c, "variant_id", "Invalid variant ID")
+	if !ok {
+		return
+	}
+
+	var req service.UpdateVariantStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	variant, err := h.productService.UpdateVariantStock(c.Request.Context(), productID, variantID, *req.Stock)
+	if err != nil {
+		respondCatalogError(c, "Failed to update stock", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Stock updated successfully", variant)
+}
+
+// ActivateVariant makes a variant available again
+// POST /api/v1/admin/products/:id/variants/:variant_id/activate
+func (h *ProductHandler) ActivateVariant(c *gin.Context) {
+	h.setVariantActive(c, true, "Variant activated successfully")
+}
+
+// DeactivateVariant hides a variant from sale without deleting it
+// POST /api/v1/admin/products/:id/variants/:variant_id/deactivate
+func (h *ProductHandler) DeactivateVariant(c *gin.Context) {
+	h.setVariantActive(c, false, "Variant deactivated successfully")
+}
+
+func (h *ProductHandler) setVariantActive(c *gin.Context, active bool, message string) {
+	productID, variantID, ok := productChildParams(c, "variant_id", "Invalid variant ID")
+	if !ok {
+		return
+	}
+
+	variant, err := h.productService.SetVariantActive(c.Request.Context(), productID, variantID, active)
+	if err != nil {
+		respondCatalogError(c, "Failed to update variant", err)
+		return
+	}
+
+	utils.SuccessResponse(c, message, variant)
+}
+
+// ReorderVariants sets the display order of a product's variants
+// PUT /api/v1/admin/products/:id/variants/order
+// Body: { "ids": ["uuid", ...] }
+func (h *ProductHandler) ReorderVariants(c *gin.Context) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	var req service.ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	variants, err := h.productService.ReorderVariants(c.Request.Context(), productID, req.IDs)
+	if err != nil {
+		respondCatalogError(c, "Failed to reorder variants", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Variants reordered successfully", variants)
+}
+
+// ListImages returns a product's images in display order
+// GET /api/v1/admin/products/:id/images
+func (h *ProductHandler) ListImages(c *gin.Context) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	images, err := h.productService.ListImages(c.Request.Context(), productID)
+	if err != nil {
+		respondCatalogError(c, "Failed to get images", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Images retrieved successfully", images)
+}
+
+// AddImage adds an image to a product
+// POST /api/v1/admin/products/:id/images
+func (h *ProductHandler) AddImage(c *gin.Context) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	var req service.CreateImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	image, err := h.productService.AddImage(c.Request.Context(), productID, &req)
+	if err != nil {
+		respondCatalogError(c, "Failed to add image", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Image added successfully", image)
+}
+
+// UpdateImage edits an image's URL or alt text
+// PUT /api/v1/admin/products/:id/images/:image_id
+func (h *ProductHandler) UpdateImage(c *gin.Context) {
+	productID, imageID, ok := productChildParams(c, "image_id", "Invalid image ID")
+	if !ok {
+		return
+	}
+
+	var req service.UpdateImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	image, err := h.productService.UpdateImage(c.Request.Context(), productID, imageID, &req)
+	if err != nil {
+		respondCatalogError(c, "Failed to update image", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Image updated successfully", image)
+}
+
+// DeleteImage removes an image from a product
+// DELETE /api/v1/admin/products/:id/images/:image_id
+func (h *ProductHandler) DeleteImage(c *gin.Context) {
+	productID, imageID, ok := productChildParams(c, "image_id", "Invalid image ID")
+	if !ok {
+		return
+	}
+
+	if err := h.productService.DeleteImage(c.Request.Context(), productID, imageID); err != nil {
+		respondCatalogError(c, "Failed to delete image", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Image deleted successfully", nil)
+}
+
+// SetPrimaryImage makes an image the product's primary image
+// PUT /api/v1/admin/products/:id/images/:image_id/primary
+func (h *ProductHandler) SetPrimaryImage(c *gin.Context) {
+	productID, imageID, ok := productChildParams(c, "image_id", "Invalid image ID")
+	if !ok {
+		return
+	}
+
+	image, err := h.productService.SetPrimaryImage(c.Request.Context(), productID, imageID)
+	if err != nil {
+		respondCatalogError(c, "Failed to set primary image", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Primary image updated successfully", image)
+}
+
+// ReorderImages sets the display order of a product's images
+// PUT /api/v1/admin/products/:id/images/order
+// Body: { "ids": ["uuid", ...] }
+func (h *ProductHandler) ReorderImages(c *gin.Context) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	var req service.ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	images, err := h.productService.ReorderImages(c.Request.Context(), productID, req.IDs)
+	if err != nil {
+		respondCatalogError(c, "Failed to reorder images", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Images reordered successfully", images)
+}
+
+func parseUUIDParam(c *gin.Context, name, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		utils.BadRequestResponse(c, message, err.Error())
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func productChildParams(c *gin.Context, name, message string) (uuid.UUID, uuid.UUID, bool) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	childID, ok := parseUUIDParam(c, name, message)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return productID, childID, true
+}
+
+func respondCatalogError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		utils.NotFoundResponse(c, "Product not found")
+	case errors.Is(err, service.ErrVariantNotFound):
+		utils.NotFoundResponse(c, "Variant not found")
+	case errors.Is(err, service.ErrImageNotFound):
+		utils.NotFoundResponse(c, "Image not found")
+	default:
+		utils.BadRequestResponse(c, message, err.Error())
+	}
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"solemate/pkg/auth"
+	"solemate/pkg/utils"
+	"solemate/services/product-service/internal/domain/service"
+)
+
+type ProductViewHandler struct {
+	viewService *service.ProductViewService
+}
+
+func NewProductViewHandler(viewService *service.ProductViewService) *ProductViewHandler {
+	return &ProductViewHandler{
+		viewService: viewService,
+	}
+}
+
+// RecordProductView records a product page view by a signed-in or
+// anonymous shopper
+// POST /api/v1/events/product-view
+func (h *ProductViewHandler) RecordProductView(c *gin.Context) {
+	var req service.RecordViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	var userID *uuid.UUID
+	if id, ok := auth.CurrentUserID(c); ok {
+		userID = &id
+	}
+
+	if err := h.viewService.RecordView(c.Request.Context(), userID, c.ClientIP(), &req); err != nil {
+		respondViewError(c, "Failed to record view", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "View recorded", nil)
+}
+
+// GetRecentlyViewed returns the products the shopper looked at, latest first
+// GET /api/v1/profile/recently-viewed?limit=20
+func (h *ProductViewHandler) GetRecentlyViewed(c *gin.Context) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "Authentication required")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	products, err := h.viewService.GetRecentlyViewed(c.Request.Context(), userID, limit)
+	if err != nil {
+		respondViewError(c, "Failed to retrieve recently viewed products", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Recently viewed products retrieved successfully", products)
+}
+
+// GetTrendingProducts returns the most viewed products of the last day
+// GET /api/v1/products/trending?category=...&limit=20
+func (h *ProductViewHandler) GetTrendingProducts(c *gin.Context) {
+	var categoryID *uuid.UUID
+	if value := c.Query("category"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid category ID", err.Error())
+			return
+		}
+		categoryID = &id
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	products, err := h.viewService.GetTrendingProducts(c.Request.Context(), categoryID, limit)
+	if err != nil {
+		respondViewError(c, "Failed to retrieve trending products", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Trending products retrieved successfully", products)
+}
+
+func respondViewError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		utils.NotFoundResponse(c, "Product not found")
+	case errors.Is(err, service.ErrViewTrackingUnavailable):
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, message, err.Error())
+	default:
+		utils.InternalServerErrorResponse(c, message, err.Error())
+	}
+}
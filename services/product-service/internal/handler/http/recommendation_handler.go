@@ -0,0 +1,47 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"solemate/pkg/utils"
+	"solemate/services/product-service/internal/domain/service"
+)
+
+type RecommendationHandler struct {
+	recommendationService *service.RecommendationService
+}
+
+func NewRecommendationHandler(recommendationService *service.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationService: recommendationService,
+	}
+}
+
+// GetRecommendations returns products frequently bought with, or viewed
+// alongside, a product
+// GET /api/v1/products/:id/recommendations?type=bought_together|similar&limit=10
+func (h *RecommendationHandler) GetRecommendations(c *gin.Context) {
+	productID, ok := parseUUIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	products, err := h.recommendationService.GetRecommendations(c.Request.Context(), productID, c.Query("type"), limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
+			utils.NotFoundResponse(c, "Product not found")
+		case errors.Is(err, service.ErrInvalidRecommendationType):
+			utils.BadRequestResponse(c, "Invalid recommendation type", err.Error())
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to retrieve recommendations", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, "Recommendations retrieved successfully", products)
+}
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"errors"
+	"io"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"solemate/pkg/auth"
 	"solemate/pkg/utils"
 	"solemate/services/product-service/internal/domain/service"
 )
@@ -22,18 +25,12 @@ func NewReviewHandler(reviewService *service.ReviewService) *ReviewHandler {
 // CreateReview handles POST /api/v1/products/:id/reviews
 func (h *ReviewHandler) CreateReview(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
-	userIDStr := c.GetString("user_id")
-	if userIDStr == "" {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
 		utils.UnauthorizedResponse(c, "Authentication required")
 		return
 	}
 
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
-		return
-	}
-
 	// Get product ID from URL
 	productIDParam := c.Param("id")
 	productID, err := uuid.Parse(productIDParam)
@@ -72,9 +69,9 @@ func (h *ReviewHandler) GetReviewsByProductID(c *gin.Context) {
 		return
 	}
 
-	// Parse query parameters
+	// Parse query parameters. Only approved reviews are public; the others
+	// are listed by the moderation queue.
 	var req service.GetReviewsRequest
-	req.Status = c.Query("status")
 	req.SortBy = c.DefaultQuery("sort_by", "newest")
 	req.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
 	req.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "10"))
@@ -110,19 +107,13 @@ func (h *ReviewHandler) GetReview(c *gin.Context) {
 
 // UpdateReview handles PUT /api/v1/reviews/:id
 func (h *ReviewHandler) UpdateReview(c *gin.Context) {
-	// Get user ID from context
-	userIDStr := c.GetString("user_id")
-	if userIDStr == "" {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
 		utils.UnauthorizedResponse(c, "Authentication required")
 		return
 	}
 
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
-		return
-	}
-
 	// Get review ID from URL
 	reviewIDParam := c.Param("id")
 	reviewID, err := uuid.Parse(reviewIDParam)
@@ -150,19 +141,13 @@ func (h *ReviewHandler) UpdateReview(c *gin.Context) {
 
 // DeleteReview handles DELETE /api/v1/reviews/:id
 func (h *ReviewHandler) DeleteReview(c *gin.Context) {
-	// Get user ID from context
-	userIDStr := c.GetString("user_id")
-	if userIDStr == "" {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
 		utils.UnauthorizedResponse(c, "Authentication required")
 		return
 	}
 
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
-		return
-	}
-
 	// Get review ID from URL
 	reviewIDParam := c.Param("id")
 	reviewID, err := uuid.Parse(reviewIDParam)
@@ -180,3 +165,136 @@ func (h *ReviewHandler) DeleteReview(c *gin.Context) {
 
 	utils.SuccessResponse(c, "Review deleted successfully", nil)
 }
+
+// MarkReviewHelpful handles POST /api/v1/reviews/:id/helpful
+func (h *ReviewHandler) MarkReviewHelpful(c *gin.Context) {
+	h.voteReview(c, true)
+}
+
+// MarkReviewNotHelpful handles POST /api/v1/reviews/:id/not-helpful
+func (h *ReviewHandler) MarkReviewNotHelpful(c *gin.Context) {
+	h.voteReview(c, false)
+}
+
+func (h *ReviewHandler) voteReview(c *gin.Context, helpful bool) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "Authentication required")
+		return
+	}
+
+	reviewID, ok := parseUUIDParam(c, "id", "Invalid review ID")
+	if !ok {
+		return
+	}
+
+	review, err := h.reviewService.VoteReview(c.Request.Context(), userID, reviewID, helpful)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to record vote", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Vote recorded successfully", review)
+}
+
+// ReportReview handles POST /api/v1/reviews/:id/report
+// Body: { "reason": "spam|offensive|off_topic|fake|other", "details": "..." }
+func (h *ReviewHandler) ReportReview(c *gin.Context) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "Authentication required")
+		return
+	}
+
+	reviewID, ok := parseUUIDParam(c, "id", "Invalid review ID")
+	if !ok {
+		return
+	}
+
+	var req service.ReportReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	report, err := h.reviewService.ReportReview(c.Request.Context(), userID, reviewID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to report review", err.Error())
+		return
+	}
+
+	utils.CreatedResponse(c, "Review reported successfully", report)
+}
+
+// ListModerationQueue handles GET /api/v1/admin/reviews?status=PENDING
+func (h *ReviewHandler) ListModerationQueue(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	reviews, total, err := h.reviewService.ListReviewsForModeration(c.Request.Context(), c.Query("status"), page, limit)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to retrieve reviews", err.Error())
+		return
+	}
+
+	pagination := utils.CalculatePagination(page, limit, total)
+	utils.PaginatedSuccessResponse(c, "Reviews retrieved successfully", reviews, pagination)
+}
+
+// ApproveReview handles POST /api/v1/admin/reviews/:id/approve
+// Body (optional): { "reason": "note to the author" }
+func (h *ReviewHandler) ApproveReview(c *gin.Context) {
+	moderatorID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "Authentication required")
+		return
+	}
+
+	reviewID, ok := parseUUIDParam(c, "id", "Invalid review ID")
+	if !ok {
+		return
+	}
+
+	var req service.ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	review, err := h.reviewService.ApproveReview(c.Request.Context(), moderatorID, reviewID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to approve review", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Review approved successfully", review)
+}
+
+// RejectReview handles POST /api/v1/admin/reviews/:id/reject
+// Body: { "reason": "shown to the author" }
+func (h *ReviewHandler) RejectReview(c *gin.Context) {
+	moderatorID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "Authentication required")
+		return
+	}
+
+	reviewID, ok := parseUUIDParam(c, "id", "Invalid review ID")
+	if !ok {
+		return
+	}
+
+	var req service.ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	review, err := h.reviewService.RejectReview(c.Request.Context(), moderatorID, reviewID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to reject review", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Review rejected successfully", review)
+}
@@ -3,9 +3,11 @@ package http
 import (
 	"github.com/gin-gonic/gin"
 	"solemate/pkg/auth"
+	"solemate/pkg/authz"
+	"solemate/pkg/privacy"
 )
 
-func SetupRoutes(productHandler *ProductHandler, categoryHandler *CategoryHandler, brandHandler *BrandHandler, reviewHandler *ReviewHandler, jwtManager *auth.JWTManager) *gin.Engine {
+func SetupRoutes(productHandler *ProductHandler, categoryHandler *CategoryHandler, brandHandler *BrandHandler, reviewHandler *ReviewHandler, recommendationHandler *RecommendationHandler, viewHandler *ProductViewHandler, privacyProvider privacy.Provider, jwtManager *auth.JWTManager, internalTokens *auth.InternalTokenManager, revocations *auth.SessionRevocations) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 
@@ -27,9 +29,12 @@ func SetupRoutes(productHandler *ProductHandler, categoryHandler *CategoryHandle
 		{
 			products.GET("", productHandler.ListProducts)
 			products.GET("/search", productHandler.SearchProducts)
+			products.GET("/suggest", productHandler.Suggest)
+			products.GET("/trending", viewHandler.GetTrendingProducts)
 			products.GET("/:id", productHandler.GetProduct)
 			products.GET("/slug/:slug", productHandler.GetProductBySlug)
 			products.GET("/:id/related", productHandler.GetRelatedProducts)
+			products.GET("/:id/recommendations", recommendationHandler.GetRecommendations)
 
 			// Public review routes (no authentication for GET)
 			products.GET("/:id/reviews", reviewHandler.GetReviewsByProductID)
@@ -41,6 +46,7 @@ func SetupRoutes(productHandler *ProductHandler, categoryHandler *CategoryHandle
 			categories.GET("", categoryHandler.ListCategories)
 			categories.GET("/tree", categoryHandler.GetCategoryTree)
 			categories.GET("/:id", categoryHandler.GetCategory)
+			categories.GET("/:id/attributes", categoryHandler.ListCategoryAttributes)
 			categories.GET("/slug/:slug", categoryHandler.GetCategoryBySlug)
 		}
 
@@ -58,9 +64,12 @@ func SetupRoutes(productHandler *ProductHandler, categoryHandler *CategoryHandle
 			reviews.GET("/:id", reviewHandler.GetReview)
 		}
 
+		// Product view tracking, from signed-in or anonymous shoppers
+		v1.POST("/events/product-view", auth.OptionalServiceAuthMiddleware(internalTokens, jwtManager, revocations), viewHandler.RecordProductView)
+
 		// Protected routes (authentication required)
 		protected := v1.Group("/")
-		protected.Use(AuthMiddleware(jwtManager))
+		protected.Use(auth.ServiceAuthMiddleware(internalTokens, jwtManager, revocations))
 		{
 			// Review routes (authentication required for POST, PUT, DELETE)
 			protectedReviews := protected.Group("/products/:id/reviews")
@@ -72,30 +81,87 @@ func SetupRoutes(productHandler *ProductHandler, categoryHandler *CategoryHandle
 			{
 				reviewsAuth.PUT("/:id", reviewHandler.UpdateReview)
 				reviewsAuth.DELETE("/:id", reviewHandler.DeleteReview)
+				reviewsAuth.POST("/:id/images", reviewHandler.UploadReviewImage)
+				reviewsAuth.POST("/:id/helpful", reviewHandler.MarkReviewHelpful)
+				reviewsAuth.POST("/:id/not-helpful", reviewHandler.MarkReviewNotHelpful)
+				reviewsAuth.POST("/:id/report", reviewHandler.ReportReview)
 			}
 
+			// Browsing history
+			protected.GET("/profile/recently-viewed", viewHandler.GetRecentlyViewed)
+
 			// Admin only routes
 			admin := protected.Group("/admin")
-			admin.Use(AdminMiddleware())
 			{
 				// Product management
 				adminProducts := admin.Group("/products")
+				adminProducts.Use(authz.RequirePermission(authz.ProductsWrite))
 				{
 					adminProducts.POST("", productHandler.CreateProduct)
+					adminProducts.GET("/export", productHandler.ExportProducts)
+					adminProducts.POST("/imports", productHandler.ImportProducts)
+					adminProducts.GET("/imports/:id", productHandler.GetImportJob)
+					adminProducts.POST("/imports/:id/confirm", productHandler.ConfirmImport)
 					adminProducts.PUT("/:id", productHandler.UpdateProduct)
 					adminProducts.DELETE("/:id", productHandler.DeleteProduct)
+					adminProducts.PUT("/:id/attributes", productHandler.SetProductAttributes)
+
+					// Variants (sizes and colours)
+					adminProducts.GET("/:id/variants", productHandler.ListVariants)
+					adminProducts.POST("/:id/variants", productHandler.CreateVariant)
+					adminProducts.PUT("/:id/variants/order", productHandler.ReorderVariants)
+					adminProducts.PUT("/:id/variants/:variant_id", productHandler.UpdateVariant)
+					adminProducts.PUT("/:id/variants/:variant_id/stock", productHandler.UpdateVariantStock)
+					adminProducts.PUT("/:id/variants/:variant_id/attributes", productHandler.SetVariantAttributes)
+					adminProducts.POST("/:id/variants/:variant_id/activate", productHandler.ActivateVariant)
+					adminProducts.POST("/:id/variants/:variant_id/deactivate", productHandler.DeactivateVariant)
+					adminProducts.POST("/:id/variants/:variant_id/images", productHandler.UploadVariantImage)
+
+					// Images
+					adminProducts.GET("/:id/images", productHandler.ListImages)
+					adminProducts.POST("/:id/images", productHandler.AddImage)
+					adminProducts.POST("/:id/images/upload", productHandler.UploadImage)
+					adminProducts.PUT("/:id/images/order", productHandler.ReorderImages)
+					adminProducts.PUT("/:id/images/:image_id", productHandler.UpdateImage)
+					adminProducts.PUT("/:id/images/:image_id/primary", productHandler.SetPrimaryImage)
+					adminProducts.DELETE("/:id/images/:image_id", productHandler.DeleteImage)
+
+					// Pricing
+					adminProducts.GET("/:id/price-schedules", productHandler.ListPriceSchedules)
+					adminProducts.POST("/:id/price-schedules", productHandler.CreatePriceSchedule)
+					adminProducts.DELETE("/:id/price-schedules/:schedule_id", productHandler.CancelPriceSchedule)
+					adminProducts.GET("/:id/price-history", productHandler.GetPriceHistory)
+				}
+
+				// Review moderation
+				adminReviews := admin.Group("/reviews")
+				adminReviews.Use(authz.RequirePermission(authz.ReviewsModerate))
+				{
+					adminReviews.GET("", reviewHandler.ListModerationQueue)
+					adminReviews.POST("/:id/approve", reviewHandler.ApproveReview)
+					adminReviews.POST("/:id/reject", reviewHandler.RejectReview)
 				}
 
+				// Search analytics
+				admin.GET("/search/zero-results", authz.RequirePermission(authz.AnalyticsRead), productHandler.ZeroResultSearches)
+
 				// Category management
 				adminCategories := admin.Group("/categories")
+				adminCategories.Use(authz.RequirePermission(authz.CategoriesWrite))
 				{
 					adminCategories.POST("", categoryHandler.CreateCategory)
+					adminCategories.PUT("/order", categoryHandler.ReorderCategories)
 					adminCategories.PUT("/:id", categoryHandler.UpdateCategory)
 					adminCategories.DELETE("/:id", categoryHandler.DeleteCategory)
+					adminCategories.POST("/:id/move", categoryHandler.MoveCategory)
+					adminCategories.POST("/:id/attributes", categoryHandler.CreateCategoryAttribute)
+					adminCategories.PUT("/:id/attributes/:attribute_id", categoryHandler.UpdateCategoryAttribute)
+					adminCategories.DELETE("/:id/attributes/:attribute_id", categoryHandler.DeleteCategoryAttribute)
 				}
 
 				// Brand management
 				adminBrands := admin.Group("/brands")
+				adminBrands.Use(authz.RequirePermission(authz.BrandsWrite))
 				{
 					adminBrands.POST("", brandHandler.CreateBrand)
 					adminBrands.PUT("/:id", brandHandler.UpdateBrand)
@@ -103,44 +169,12 @@ func SetupRoutes(productHandler *ProductHandler, categoryHandler *CategoryHandle
 				}
 			}
 		}
-	}
-
-	return r
-}
-
-func AuthMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Get user info from headers set by API Gateway
-		userID := c.GetHeader("X-User-ID")
-		email := c.GetHeader("X-User-Email")
-		role := c.GetHeader("X-User-Role")
-
-		if userID == "" {
-			c.JSON(401, gin.H{"error": "Authentication required"})
-			c.Abort()
-			return
-		}
-
-		// Set user information in context
-		c.Set("user_id", userID)
-		c.Set("email", email)
-		c.Set("role", role)
 
-		c.Next()
+		// Internal personal data export and erasure, called by user-service
+		privacy.RegisterRoutes(v1, auth.ServiceAuthMiddleware(internalTokens, jwtManager, revocations), privacyProvider)
 	}
-}
-
-func AdminMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role := c.GetString("role")
-		if role != "admin" && role != "manager" {
-			c.JSON(403, gin.H{"error": "Admin access required"})
-			c.Abort()
-			return
-		}
 
-		c.Next()
-	}
+	return r
 }
 
 func CORSMiddleware() gin.HandlerFunc {
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"solemate/pkg/utils"
+)
+
+// Suggest returns type-ahead product, brand and category matches
+// GET /api/v1/products/suggest?q=air&limit=5
+func (h *ProductHandler) Suggest(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	suggestions, err := h.productService.Suggest(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to get suggestions", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Suggestions retrieved successfully", suggestions)
+}
+
+// ZeroResultSearches reports recent searches that found no products
+// GET /api/v1/admin/search/zero-results?days=30&limit=50
+func (h *ProductHandler) ZeroResultSearches(c *gin.Context) {
+	days, _ := strconv.Atoi(c.Query("days"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	queries, err := h.productService.ZeroResultSearches(c.Request.Context(), days, limit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to get zero-result searches", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Zero-result searches retrieved successfully", queries)
+}
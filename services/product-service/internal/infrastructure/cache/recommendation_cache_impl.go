@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/pkg/cache"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// recommendationTTL matches the default affinity refresh, so cached lists
+// never outlive the affinities they were built from by much
+const recommendationTTL = time.Hour
+
+type recommendationCacheImpl struct {
+	redis *cache.RedisClient
+}
+
+func NewRecommendationCache(redis *cache.RedisClient) repository.RecommendationCache {
+	return &recommendationCacheImpl{redis: redis}
+}
+
+// Get returns the cached recommendations. Misses and Redis errors both report
+// false, so the caller recomputes them.
+func (c *recommendationCacheImpl) Get(ctx context.Context, productID uuid.UUID, recommendationType string) ([]uuid.UUID, bool) {
+	value, err := c.redis.Get(ctx, recommendationKey(productID, recommendationType))
+	if err != nil {
+		return nil, false
+	}
+
+	var productIDs []uuid.UUID
+	if err := json.Unmarshal([]byte(value), &productIDs); err != nil {
+		return nil, false
+	}
+	return productIDs, true
+}
+
+func (c *recommendationCacheImpl) Set(ctx context.Context, productID uuid.UUID, recommendationType string, productIDs []uuid.UUID) error {
+	data, err := json.Marshal(productIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal recommendations: %w", err)
+	}
+	return c.redis.Set(ctx, recommendationKey(productID, recommendationType), data, recommendationTTL)
+}
+
+func recommendationKey(productID uuid.UUID, recommendationType string) string {
+	return fmt.Sprintf("product:recommend:%s:%s", recommendationType, productID)
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"solemate/pkg/cache"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// suggestionTTL bounds how stale a suggestion can be after a product, brand
+// or category is renamed or deactivated
+const suggestionTTL = 5 * time.Minute
+
+type suggestionCacheImpl struct {
+	redis *cache.RedisClient
+}
+
+func NewSuggestionCache(redis *cache.RedisClient) repository.SuggestionCache {
+	return &suggestionCacheImpl{redis: redis}
+}
+
+// Get returns the cached suggestions. Misses and Redis errors both report
+// false, so the caller falls back to the database.
+func (c *suggestionCacheImpl) Get(ctx context.Context, query string, limit int) (*repository.SearchSuggestions, bool) {
+	value, err := c.redis.Get(ctx, suggestionKey(query, limit))
+	if err != nil {
+		return nil, false
+	}
+
+	var suggestions repository.SearchSuggestions
+	if err := json.Unmarshal([]byte(value), &suggestions); err != nil {
+		return nil, false
+	}
+	return &suggestions, true
+}
+
+func (c *suggestionCacheImpl) Set(ctx context.Context, query string, limit int, suggestions *repository.SearchSuggestions) error {
+	data, err := json.Marshal(suggestions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal suggestions: %w", err)
+	}
+	return c.redis.Set(ctx, suggestionKey(query, limit), data, suggestionTTL)
+}
+
+func suggestionKey(query string, limit int) string {
+	return fmt.Sprintf("product:suggest:%d:%s", limit, query)
+}
@@ -0,0 +1,259 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"solemate/pkg/cache"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+const (
+	// recentlyViewedSize and recentlyViewedTTL bound each viewer's history
+	recentlyViewedSize = 50
+	recentlyViewedTTL  = 30 * 24 * time.Hour
+
+	// viewSessionGap is how long a viewer's repeated views of a product count
+	// once, and how far apart two views can be to count as viewed together
+	viewSessionGap = 30 * time.Minute
+
+	// maxSessionCoViews is how many of the viewer's previous views a new one
+	// is paired with
+	maxSessionCoViews = 5
+
+	// maxSourceViews is how many anonymous viewers from one IP address are
+	// counted per product and session gap. Shoppers behind a shared address
+	// still count, but a client making up visitor IDs does not inflate views.
+	maxSourceViews = 3
+
+	// trendingHours of hourly counts are combined into the trending list,
+	// each hour weighing half as much as trendingHalfLife hours later
+	trendingHours    = 24
+	trendingHalfLife = 6.0
+
+	// trendingTTL is how long a combined trending list is reused
+	trendingTTL = 5 * time.Minute
+
+	// dailyViewsTTL keeps daily counts around long enough for the compaction
+	// job to copy them to Postgres after the day ends
+	dailyViewsTTL = 3 * 24 * time.Hour
+
+	// trendingScopeAll is the trending scope covering every category
+	trendingScopeAll = "all"
+)
+
+type viewTrackerImpl struct {
+	redis *cache.RedisClient
+}
+
+func NewViewTracker(redis *cache.RedisClient) repository.ViewTracker {
+	return &viewTrackerImpl{redis: redis}
+}
+
+func (t *viewTrackerImpl) RecordView(ctx context.Context, viewer, source string, productID uuid.UUID, categoryIDs []uuid.UUID, at time.Time) error {
+	product := productID.String()
+
+	// Count a viewer's view only once per session, and only while their
+	// address has not used up its views, and pair it with the other products
+	// they looked at in that session
+	var firstView *redis.BoolCmd
+	var sourceViews *redis.IntCmd
+	var previous *redis.StringSliceCmd
+	err := t.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		firstView = pipe.SetNX(ctx, viewSeenKey(viewer, product), 1, viewSessionGap)
+		if source != "" {
+			key := viewSourceKey(source, product)
+			sourceViews = pipe.Incr(ctx, key)
+			pipe.ExpireNX(ctx, key, viewSessionGap)
+		}
+		previous = pipe.ZRevRangeByScore(ctx, recentlyViewedKey(viewer), &redis.ZRangeBy{
+			Min:   strconv.FormatInt(at.Add(-viewSessionGap).UnixMilli(), 10),
+			Max:   "+inf",
+			Count: maxSessionCoViews + 1,
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read viewer session: %w", err)
+	}
+
+	counted := firstView.Val() && (sourceViews == nil || sourceViews.Val() <= maxSourceViews)
+	var sessionViews []string
+	for _, other := range previous.Val() {
+		if other != product && len(sessionViews) < maxSessionCoViews {
+			sessionViews = append(sessionViews, other)
+		}
+	}
+
+	return t.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		key := recentlyViewedKey(viewer)
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(at.UnixMilli()), Member: product})
+		pipe.ZRemRangeByRank(ctx, key, 0, -recentlyViewedSize-1)
+		pipe.Expire(ctx, key, recentlyViewedTTL)
+		if !counted {
+			return nil
+		}
+
+		dayKey := dailyViewsKey(at)
+		pipe.ZIncrBy(ctx, dayKey, 1, product)
+		pipe.Expire(ctx, dayKey, dailyViewsTTL)
+
+		scopes := []string{trendingScopeAll}
+		for _, categoryID := range categoryIDs {
+			scopes = append(scopes, categoryID.String())
+		}
+		for _, scope := range scopes {
+			hourKey := hourlyViewsKey(scope, at)
+			pipe.ZIncrBy(ctx, hourKey, 1, product)
+			pipe.Expire(ctx, hourKey, (trendingHours+1)*time.Hour)
+		}
+
+		if len(sessionViews) > 0 {
+			coViewKey := dailyCoViewsKey(at)
+			for _, other := range sessionViews {
+				pipe.ZIncrBy(ctx, coViewKey, 1, other+":"+product)
+				pipe.ZIncrBy(ctx, coViewKey, 1, product+":"+other)
+			}
+			pipe.Expire(ctx, coViewKey, dailyViewsTTL)
+		}
+		return nil
+	})
+}
+
+func (t *viewTrackerImpl) RecentlyViewed(ctx context.Context, viewer string, limit int) ([]uuid.UUID, error) {
+	members, err := t.redis.ZRevRange(ctx, recentlyViewedKey(viewer), 0, int64(limit)-1)
+	if err != nil {
+		return nil, err
+	}
+	return parseProductIDs(members), nil
+}
+
+// Trending combines the last day's hourly counts into one list, which is
+// reused for a few minutes rather than rebuilt on every request
+func (t *viewTrackerImpl) Trending(ctx context.Context, categoryID *uuid.UUID, limit int) ([]uuid.UUID, error) {
+	scope := trendingScopeAll
+	if categoryID != nil {
+		scope = categoryID.String()
+	}
+	key := "product:trending:" + scope
+
+	exists, err := t.redis.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		now := time.Now()
+		keys := make([]string, 0, trendingHours)
+		weights := make([]float64, 0, trendingHours)
+		for age := 0; age < trendingHours; age++ {
+			keys = append(keys, hourlyViewsKey(scope, now.Add(-time.Duration(age)*time.Hour)))
+			weights = append(weights, math.Pow(0.5, float64(age)/trendingHalfLife))
+		}
+		if err := t.redis.ZUnionStore(ctx, key, keys, weights, trendingTTL); err != nil {
+			return nil, err
+		}
+	}
+
+	members, err := t.redis.ZRevRange(ctx, key, 0, int64(limit)-1)
+	if err != nil {
+		return nil, err
+	}
+	return parseProductIDs(members), nil
+}
+
+func (t *viewTrackerImpl) DailyViews(ctx context.Context, day time.Time) ([]*entity.ProductViewStat, error) {
+	scores, err := t.redis.ZScores(ctx, dailyViewsKey(day))
+	if err != nil {
+		return nil, err
+	}
+
+	date := viewDate(day)
+	stats := make([]*entity.ProductViewStat, 0, len(scores))
+	for member, views := range scores {
+		productID, err := uuid.Parse(member)
+		if err != nil {
+			continue
+		}
+		stats = append(stats, &entity.ProductViewStat{ProductID: productID, Date: date, Views: int(views)})
+	}
+	return stats, nil
+}
+
+func (t *viewTrackerImpl) DailyCoViews(ctx context.Context, day time.Time) ([]*entity.ProductCoView, error) {
+	scores, err := t.redis.ZScores(ctx, dailyCoViewsKey(day))
+	if err != nil {
+		return nil, err
+	}
+
+	date := viewDate(day)
+	coViews := make([]*entity.ProductCoView, 0, len(scores))
+	for member, views := range scores {
+		first, second, ok := strings.Cut(member, ":")
+		if !ok {
+			continue
+		}
+		productID, err := uuid.Parse(first)
+		if err != nil {
+			continue
+		}
+		relatedProductID, err := uuid.Parse(second)
+		if err != nil {
+			continue
+		}
+		coViews = append(coViews, &entity.ProductCoView{
+			ProductID:        productID,
+			RelatedProductID: relatedProductID,
+			Date:             date,
+			Views:            int(views),
+		})
+	}
+	return coViews, nil
+}
+
+// parseProductIDs skips members that are not product IDs
+func parseProductIDs(members []string) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		if id, err := uuid.Parse(member); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// viewDate is the UTC day a view falls on; counts are kept per UTC day
+func viewDate(at time.Time) time.Time {
+	year, month, day := at.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func recentlyViewedKey(viewer string) string {
+	return "product:views:recent:" + viewer
+}
+
+func viewSeenKey(viewer, productID string) string {
+	return fmt.Sprintf("product:views:seen:%s:%s", viewer, productID)
+}
+
+func viewSourceKey(source, productID string) string {
+	return fmt.Sprintf("product:views:source:%s:%s", source, productID)
+}
+
+func hourlyViewsKey(scope string, at time.Time) string {
+	return fmt.Sprintf("product:views:hour:%s:%s", scope, at.UTC().Format("2006010215"))
+}
+
+func dailyViewsKey(at time.Time) string {
+	return "product:views:day:" + at.UTC().Format("2006-01-02")
+}
+
+func dailyCoViewsKey(at time.Time) string {
+	return "product:coviews:day:" + at.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+type attributeRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAttributeRepository(db *gorm.DB) repository.AttributeRepository {
+	return &attributeRepositoryImpl{db: db}
+}
+
+func (r *attributeRepositoryImpl) CreateDefinition(ctx context.Context, definition *entity.AttributeDefinition) error {
+	definition.ID = uuid.New()
+	definition.CreatedAt = time.Now()
+	definition.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Create(definition).Error
+}
+
+func (r *attributeRepositoryImpl) GetDefinition(ctx context.Context, id uuid.UUID) (*entity.AttributeDefinition, error) {
+	var definition entity.AttributeDefinition
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&definition)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attribute not found")
+		}
+		return nil, result.Error
+	}
+	return &definition, nil
+}
+
+func (r *attributeRepositoryImpl) UpdateDefinition(ctx context.Context, definition *entity.AttributeDefinition) error {
+	definition.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Save(definition).Error
+}
+
+func (r *attributeRepositoryImpl) DeleteDefinition(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("attribute_id = ?", id).Delete(&entity.ProductAttributeValue{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entity.AttributeDefinition{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("attribute not found")
+		}
+		return nil
+	})
+}
+
+func (r *attributeRepositoryImpl) GetDefinitionsByCategoryIDs(ctx context.Context, categoryIDs []uuid.UUID) ([]*entity.AttributeDefinition, error) {
+	var definitions []*entity.AttributeDefinition
+	if len(categoryIDs) == 0 {
+		return definitions, nil
+	}
+	result := r.db.WithContext(ctx).
+		Where("category_id IN ?", categoryIDs).
+		Order("sort_order ASC, name ASC").
+		Find(&definitions)
+	return definitions, result.Error
+}
+
+func (r *attributeRepositoryImpl) GetValues(ctx context.Context, productID uuid.UUID) ([]*entity.ProductAttributeValue, error) {
+	var values []*entity.ProductAttributeValue
+	result := r.db.WithContext(ctx).
+		Preload("Attribute").
+		Joins("JOIN attribute_definitions ON attribute_definitions.id = product_attribute_values.attribute_id").
+		Where("product_attribute_values.product_id = ?", productID).
+		Order("attribute_definitions.sort_order ASC, attribute_definitions.name ASC").
+		Find(&values)
+	return values, result.Error
+}
+
+func (r *attributeRepositoryImpl) ReplaceValues(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, values []*entity.ProductAttributeValue) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := whereVariant(tx.Where("product_id = ?", productID), variantID)
+		if err := query.Delete(&entity.ProductAttributeValue{}).Error; err != nil {
+			return err
+		}
+
+		for _, value := range values {
+			value.ID = uuid.New()
+			value.ProductID = productID
+			value.VariantID = variantID
+			value.CreatedAt = time.Now()
+			if err := tx.Omit("Attribute").Create(value).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
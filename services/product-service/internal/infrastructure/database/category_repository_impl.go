@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"solemate/services/product-service/internal/domain/entity"
 	"solemate/services/product-service/internal/domain/repository"
 )
@@ -121,4 +122,132 @@ func (r *categoryRepositoryImpl) GetTree(ctx context.Context) ([]*entity.Categor
 		Find(&categories)
 
 	return categories, result.Error
-}
\ No newline at end of file
+}
+
+// GetAncestors returns the path from the top-level category down to and
+// including the given one
+func (r *categoryRepositoryImpl) GetAncestors(ctx context.Context, id uuid.UUID) ([]*entity.Category, error) {
+	var categories []*entity.Category
+	result := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth FROM categories WHERE id = ?
+			UNION ALL
+			SELECT categories.id, categories.parent_id, ancestors.depth + 1
+			FROM categories JOIN ancestors ON categories.id = ancestors.parent_id
+			WHERE ancestors.depth < ?
+		)
+		SELECT categories.* FROM categories
+		JOIN ancestors ON ancestors.id = categories.id
+		ORDER BY ancestors.depth DESC`, id, maxCategoryDepth).
+		Scan(&categories)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(categories) == 0 {
+		return nil, errors.New("category not found")
+	}
+	return categories, nil
+}
+
+// maxCategoryDepth bounds ancestor lookups in case the tree is corrupt
+const maxCategoryDepth = 100
+
+// GetSiblings returns the children of parentID, or the top-level categories
+// when parentID is nil, in display order
+func (r *categoryRepositoryImpl) GetSiblings(ctx context.Context, parentID *uuid.UUID) ([]*entity.Category, error) {
+	var categories []*entity.Category
+	result := whereParent(r.db.WithContext(ctx), parentID).
+		Order("sort_order ASC, name ASC").
+		Find(&categories)
+	return categories, result.Error
+}
+
+// Move re-parents a category, along with its subtree, and renumbers its new
+// siblings, which siblingIDs lists in display order including the category.
+// The category and the new parent's ancestors stay locked until the move
+// commits, so two concurrent moves cannot together form a cycle.
+func (r *categoryRepositoryImpl) Move(ctx context.Context, id uuid.UUID, parentID *uuid.UUID, siblingIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockCategory(tx, id, nil); err != nil {
+			return err
+		}
+		if err := checkAncestors(tx, id, parentID); err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.Category{}).Where("id = ?", id).Update("parent_id", parentID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("category not found")
+		}
+		return reorderCategories(tx, parentID, siblingIDs)
+	})
+}
+
+// Reorder sets each category's sort order to its position in categoryIDs
+func (r *categoryRepositoryImpl) Reorder(ctx context.Context, parentID *uuid.UUID, categoryIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return reorderCategories(tx, parentID, categoryIDs)
+	})
+}
+
+func reorderCategories(tx *gorm.DB, parentID *uuid.UUID, categoryIDs []uuid.UUID) error {
+	for i, id := range categoryIDs {
+		result := whereParent(tx.Model(&entity.Category{}), parentID).
+			Where("id = ?", id).
+			Update("sort_order", i)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("category not found")
+		}
+	}
+	return nil
+}
+
+// checkAncestors walks up from parentID, locking each category on the way,
+// and fails if it reaches id
+func checkAncestors(tx *gorm.DB, id uuid.UUID, parentID *uuid.UUID) error {
+	next := parentID
+	for depth := 0; next != nil && depth < maxCategoryDepth; depth++ {
+		if *next == id {
+			return repository.ErrCategoryCycle
+		}
+
+		var ancestor entity.Category
+		if err := lockCategory(tx, *next, &ancestor); err != nil {
+			if next == parentID {
+				return errors.New("parent category not found")
+			}
+			return err
+		}
+		next = ancestor.ParentID
+	}
+	return nil
+}
+
+// lockCategory takes a row lock on a category for the rest of the
+// transaction, loading its ID and parent into category when given
+func lockCategory(tx *gorm.DB, id uuid.UUID, category *entity.Category) error {
+	if category == nil {
+		category = &entity.Category{}
+	}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "parent_id").
+		Where("id = ?", id).
+		First(category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("category not found")
+	}
+	return err
+}
+
+func whereParent(query *gorm.DB, parentID *uuid.UUID) *gorm.DB {
+	if parentID == nil {
+		return query.Where("parent_id IS NULL")
+	}
+	return query.Where("parent_id = ?", *parentID)
+}
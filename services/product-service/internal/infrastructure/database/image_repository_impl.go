@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+type imageRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductImageRepository(db *gorm.DB) repository.ProductImageRepository {
+	return &imageRepositoryImpl{db: db}
+}
+
+func (r *imageRepositoryImpl) Create(ctx context.Context, image *entity.ProductImage) error {
+	image.ID = uuid.New()
+	image.CreatedAt = time.Now()
+
+	result := r.db.WithContext(ctx).Create(image)
+	return result.Error
+}
+
+func (r *imageRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.ProductImage, error) {
+	var image entity.ProductImage
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&image)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("image not found")
+		}
+		return nil, result.Error
+	}
+	return &image, nil
+}
+
+func (r *imageRepositoryImpl) GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entity.ProductImage, error) {
+	var images []*entity.ProductImage
+	result := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("sort_order ASC, created_at ASC").
+		Find(&images)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return images, nil
+}
+
+func (r *imageRepositoryImpl) Update(ctx context.Context, image *entity.ProductImage) error {
+	result := r.db.WithContext(ctx).Save(image)
+	return result.Error
+}
+
+func (r *imageRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entity.ProductImage{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("image not found")
+	}
+	return nil
+}
+
+// SetPrimary makes imageID the product's only primary image
+func (r *imageRepositoryImpl) SetPrimary(ctx context.Context, productID, imageID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.ProductImage{}).
+			Where("product_id = ? AND is_primary = ?", productID, true).
+			Update("is_primary", false).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.ProductImage{}).
+			Where("id = ? AND product_id = ?", imageID, productID).
+			Update("is_primary", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("image not found")
+		}
+		return nil
+	})
+}
+
+// Reorder sets each image's sort order to its position in imageIDs
+func (r *imageRepositoryImpl) Reorder(ctx context.Context, productID uuid.UUID, imageIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range imageIDs {
+			result := tx.Model(&entity.ProductImage{}).
+				Where("id = ? AND product_id = ?", id, productID).
+				Update("sort_order", i)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errors.New("image not found")
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+type productImportRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductImportRepository(db *gorm.DB) repository.ProductImportRepository {
+	return &productImportRepositoryImpl{db: db}
+}
+
+func (r *productImportRepositoryImpl) Create(ctx context.Context, job *entity.ProductImportJob) error {
+	job.ID = uuid.New()
+	job.CreatedAt = time.Now()
+	job.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *productImportRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.ProductImportJob, error) {
+	var job entity.ProductImportJob
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&job)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("import job not found")
+		}
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+func (r *productImportRepositoryImpl) Update(ctx context.Context, job *entity.ProductImportJob) error {
+	job.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+func (r *productImportRepositoryImpl) ListByStatus(ctx context.Context, statuses ...string) ([]*entity.ProductImportJob, error) {
+	var jobs []*entity.ProductImportJob
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", statuses).
+		Order("created_at ASC").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *productImportRepositoryImpl) SaveProduct(ctx context.Context, product *entity.Product, variants []*entity.ProductVariant, images []*entity.ProductImage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		product.UpdatedAt = now
+		if product.ID == uuid.Nil {
+			product.ID = uuid.New()
+			product.CreatedAt = now
+			if err := tx.Create(product).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Omit(productStatColumns...).Save(product).Error; err != nil {
+			return err
+		}
+
+		for _, variant := range variants {
+			variant.ProductID = product.ID
+			variant.UpdatedAt = now
+			if variant.ID == uuid.Nil {
+				variant.ID = uuid.New()
+				variant.CreatedAt = now
+				if err := tx.Create(variant).Error; err != nil {
+					return fmt.Errorf("variant %s: %w", variant.SKU, err)
+				}
+			} else if err := tx.Save(variant).Error; err != nil {
+				return fmt.Errorf("variant %s: %w", variant.SKU, err)
+			}
+		}
+
+		for _, image := range images {
+			image.ProductID = product.ID
+			if image.ID == uuid.Nil {
+				image.ID = uuid.New()
+				image.CreatedAt = now
+				if err := tx.Create(image).Error; err != nil {
+					return fmt.Errorf("image %s: %w", image.URL, err)
+				}
+			} else if err := tx.Save(image).Error; err != nil {
+				return fmt.Errorf("image %s: %w", image.URL, err)
+			}
+		}
+
+		return nil
+	})
+}
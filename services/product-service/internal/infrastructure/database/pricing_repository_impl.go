@@ -0,0 +1,190 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+type priceScheduleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPriceScheduleRepository(db *gorm.DB) repository.PriceScheduleRepository {
+	return &priceScheduleRepositoryImpl{db: db}
+}
+
+func (r *priceScheduleRepositoryImpl) Create(ctx context.Context, schedule *entity.PriceSchedule) error {
+	schedule.ID = uuid.New()
+	schedule.CreatedAt = time.Now()
+	schedule.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Create(schedule).Error
+}
+
+func (r *priceScheduleRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.PriceSchedule, error) {
+	var schedule entity.PriceSchedule
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&schedule)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("price schedule not found")
+		}
+		return nil, result.Error
+	}
+	return &schedule, nil
+}
+
+func (r *priceScheduleRepositoryImpl) Update(ctx context.Context, schedule *entity.PriceSchedule) error {
+	schedule.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Save(schedule).Error
+}
+
+func (r *priceScheduleRepositoryImpl) GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entity.PriceSchedule, error) {
+	var schedules []*entity.PriceSchedule
+	result := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("starts_at DESC").
+		Find(&schedules)
+	return schedules, result.Error
+}
+
+func (r *priceScheduleRepositoryImpl) Transition(ctx context.Context, id uuid.UUID, from, to string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.PriceSchedule{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{"status": to, "updated_at": time.Now()})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *priceScheduleRepositoryImpl) DueToStart(ctx context.Context, now time.Time, limit int) ([]*entity.PriceSchedule, error) {
+	var schedules []*entity.PriceSchedule
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND starts_at <= ?", entity.PriceScheduleScheduled, now).
+		Where("ends_at IS NULL OR ends_at > ?", now).
+		Order("starts_at ASC").
+		Limit(limit).
+		Find(&schedules)
+	return schedules, result.Error
+}
+
+func (r *priceScheduleRepositoryImpl) DueToEnd(ctx context.Context, now time.Time, limit int) ([]*entity.PriceSchedule, error) {
+	var schedules []*entity.PriceSchedule
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND ends_at <= ?", entity.PriceScheduleActive, now).
+		Order("ends_at ASC").
+		Limit(limit).
+		Find(&schedules)
+	return schedules, result.Error
+}
+
+func (r *priceScheduleRepositoryImpl) ExpireUnstarted(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.PriceSchedule{}).
+		Where("status = ? AND ends_at <= ?", entity.PriceScheduleScheduled, now).
+		Updates(map[string]interface{}{"status": entity.PriceScheduleEnded, "ended_at": now, "updated_at": now})
+	return result.RowsAffected, result.Error
+}
+
+func (r *priceScheduleRepositoryImpl) Overlapping(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, start time.Time, end *time.Time) ([]*entity.PriceSchedule, error) {
+	query := r.db.WithContext(ctx).
+		Where("product_id = ? AND status IN ?", productID,
+			[]string{entity.PriceScheduleScheduled, entity.PriceScheduleActive}).
+		Where("ends_at IS NULL OR ends_at > ?", start)
+	query = whereVariant(query, variantID)
+	if end != nil {
+		query = query.Where("starts_at < ?", *end)
+	}
+
+	var schedules []*entity.PriceSchedule
+	result := query.Order("starts_at ASC").Find(&schedules)
+	return schedules, result.Error
+}
+
+type priceHistoryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPriceHistoryRepository(db *gorm.DB) repository.PriceHistoryRepository {
+	return &priceHistoryRepositoryImpl{db: db}
+}
+
+func (r *priceHistoryRepositoryImpl) Create(ctx context.Context, entry *entity.PriceHistory) error {
+	entry.ID = uuid.New()
+	entry.CreatedAt = time.Now()
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *priceHistoryRepositoryImpl) GetTimeline(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, since time.Time) ([]*entity.PriceHistory, error) {
+	query := r.db.WithContext(ctx).Where("product_id = ? AND created_at >= ?", productID, since)
+	query = whereVariant(query, variantID)
+
+	var entries []*entity.PriceHistory
+	result := query.Order("created_at ASC").Find(&entries)
+	return entries, result.Error
+}
+
+// LowestPrice looks at a variant's own prices and at the product's prices,
+// since a variant without its own price sells at the product's. Counting the
+// product's prices can only lower the result, so a discount measured against
+// it is never overstated.
+func (r *priceHistoryRepositoryImpl) LowestPrice(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, since time.Time) (*float64, error) {
+	targets := []*uuid.UUID{nil}
+	if variantID != nil {
+		targets = append(targets, variantID)
+	}
+
+	var lowest *float64
+	consider := func(price *float64) {
+		if price != nil && (lowest == nil || *price < *lowest) {
+			value := *price
+			lowest = &value
+		}
+	}
+
+	for _, target := range targets {
+		history := func() *gorm.DB {
+			query := r.db.WithContext(ctx).Model(&entity.PriceHistory{}).Where("product_id = ?", productID)
+			return whereVariant(query, target)
+		}
+
+		var inWindow sql.NullFloat64
+		if err := history().
+			Where("created_at >= ?", since).
+			Select("MIN(price)").
+			Scan(&inWindow).Error; err != nil {
+			return nil, err
+		}
+		if inWindow.Valid {
+			consider(&inWindow.Float64)
+		}
+
+		// The price that was current when the window opened
+		var atStart []*entity.PriceHistory
+		if err := history().
+			Where("created_at < ?", since).
+			Order("created_at DESC").
+			Limit(1).
+			Find(&atStart).Error; err != nil {
+			return nil, err
+		}
+		if len(atStart) > 0 {
+			consider(atStart[0].Price)
+		}
+	}
+
+	return lowest, nil
+}
+
+// whereVariant narrows a query to a variant, or to the product itself when
+// variantID is nil
+func whereVariant(query *gorm.DB, variantID *uuid.UUID) *gorm.DB {
+	if variantID == nil {
+		return query.Where("variant_id IS NULL")
+	}
+	return query.Where("variant_id = ?", *variantID)
+}
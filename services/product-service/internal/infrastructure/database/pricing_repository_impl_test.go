@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestPriceScheduleDueQueries(t *testing.T) {
+	var statements []string
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Discard,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:statement", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+	require.NoError(t, db.Callback().Update().After("gorm:update").Register("test:statement", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+	repo := NewPriceScheduleRepository(db)
+	ctx, now := context.Background(), time.Now()
+
+	_, err = repo.DueToStart(ctx, now, 100)
+	require.NoError(t, err)
+	_, err = repo.ExpireUnstarted(ctx, now)
+	require.NoError(t, err)
+
+	require.Len(t, statements, 2)
+	assert.Contains(t, statements[0], "status = $1 AND starts_at <= $2")
+	assert.Contains(t, statements[0], "(ends_at IS NULL OR ends_at > $3)",
+		"a schedule whose window has passed is never started")
+	assert.Contains(t, statements[1], `"status"=$2`)
+	assert.Contains(t, statements[1], "status = $4 AND ends_at <= $5")
+}
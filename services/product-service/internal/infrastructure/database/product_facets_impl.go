@@ -0,0 +1,274 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// priceBucketBounds are the lower bounds of the price histogram buckets
+var priceBucketBounds = []float64{0, 50, 100, 150, 200, 300}
+
+// SearchFacets counts the products matching a search per brand, category,
+// size, colour and price bucket. searchQuery may be empty to facet the
+// whole catalog.
+func (r *productRepositoryImpl) SearchFacets(ctx context.Context, searchQuery string, filters repository.ProductFilters) (*repository.ProductFacets, error) {
+	facets := &repository.ProductFacets{}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var match *textMatch
+		if searchQuery != "" {
+			chosen, err := r.chooseTextMatch(tx, searchQuery, filters)
+			if err != nil {
+				return err
+			}
+			match = &chosen
+		}
+
+		// scope selects the matching products under the given filters
+		scope := func(filters repository.ProductFilters) *gorm.DB {
+			query := tx.Model(&entity.Product{})
+			if match != nil {
+				query = query.Where(match.where, searchQuery)
+			}
+			return r.applyFilters(query, filters)
+		}
+
+		withoutBrands := filters
+		withoutBrands.BrandIDs = nil
+		if err := scope(withoutBrands).
+			Select("brands.id AS value, brands.name AS label, COUNT(*) AS count").
+			Joins("JOIN brands ON brands.id = products.brand_id").
+			Group("brands.id, brands.name").
+			Order("count DESC, label ASC").
+			Scan(&facets.Brands).Error; err != nil {
+			return fmt.Errorf("failed to count brands: %w", err)
+		}
+
+		withoutCategories := filters
+		withoutCategories.CategoryIDs = nil
+		if err := scope(withoutCategories).
+			Select("categories.id AS value, categories.name AS label, COUNT(*) AS count").
+			Joins("JOIN categories ON categories.id = products.category_id").
+			Group("categories.id, categories.name").
+			Order("count DESC, label ASC").
+			Scan(&facets.Categories).Error; err != nil {
+			return fmt.Errorf("failed to count categories: %w", err)
+		}
+
+		// Each variant facet keeps the other variant filter in its join, so
+		// the size counts only include variants in the selected colours
+		withoutVariants := filters
+		withoutVariants.Sizes = nil
+		withoutVariants.Colors = nil
+
+		sizeJoin, sizeArgs := facetVariantJoin("facet_variants.size <> ''", nil, filters.Colors)
+		if err := scope(withoutVariants).
+			Select("facet_variants.size AS value, COUNT(DISTINCT products.id) AS count").
+			Joins(sizeJoin, sizeArgs...).
+			Group("facet_variants.size").
+			Scan(&facets.Sizes).Error; err != nil {
+			return fmt.Errorf("failed to count sizes: %w", err)
+		}
+		sortSizes(facets.Sizes)
+
+		colorJoin, colorArgs := facetVariantJoin("facet_variants.color <> ''", filters.Sizes, nil)
+		if err := scope(withoutVariants).
+			Select("LOWER(facet_variants.color) AS value, MIN(facet_variants.color) AS label, COUNT(DISTINCT products.id) AS count").
+			Joins(colorJoin, colorArgs...).
+			Group("LOWER(facet_variants.color)").
+			Order("count DESC, value ASC").
+			Scan(&facets.Colors).Error; err != nil {
+			return fmt.Errorf("failed to count colors: %w", err)
+		}
+
+		withoutPrice := filters
+		withoutPrice.MinPrice = nil
+		withoutPrice.MaxPrice = nil
+
+		prices, err := r.countPriceBuckets(scope(withoutPrice))
+		if err != nil {
+			return fmt.Errorf("failed to count prices: %w", err)
+		}
+		facets.Prices = prices
+
+		attributes, err := r.countAttributes(scope, filters)
+		if err != nil {
+			return fmt.Errorf("failed to count attributes: %w", err)
+		}
+		facets.Attributes = attributes
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+// facetVariantJoin joins the active variants that satisfy condition and the
+// given size and colour filters as facet_variants
+func facetVariantJoin(condition string, sizes, colors []string) (string, []interface{}) {
+	join := "JOIN product_variants AS facet_variants ON facet_variants.product_id = products.id AND facet_variants.is_active AND " + condition
+
+	filter, args := variantCondition("facet_variants", sizes, colors)
+	if filter != "" {
+		join += " AND " + filter
+	}
+
+	return join, args
+}
+
+// attributeFacetRow is one attribute value and its product count
+type attributeFacetRow struct {
+	Code  string
+	Name  string
+	Type  string
+	Unit  string
+	Value string
+	Count int64
+}
+
+// countAttributes counts products per value of every filterable attribute.
+// Attributes nobody filters on share one query; each filtered attribute is
+// counted without its own filters, like the other facets.
+func (r *productRepositoryImpl) countAttributes(scope func(repository.ProductFilters) *gorm.DB, filters repository.ProductFilters) ([]repository.AttributeFacet, error) {
+	var filtered []string
+	seen := make(map[string]bool)
+	for _, filter := range filters.Attributes {
+		if !seen[filter.Code] {
+			seen[filter.Code] = true
+			filtered = append(filtered, filter.Code)
+		}
+	}
+
+	count := func(filters repository.ProductFilters, codes []string, exclude bool) ([]attributeFacetRow, error) {
+		query := scope(filters).
+			Select("attribute_definitions.code AS code, MIN(attribute_definitions.name) AS name, MIN(attribute_definitions.type) AS type, " +
+				"MIN(attribute_definitions.unit) AS unit, product_attribute_values.value AS value, COUNT(DISTINCT products.id) AS count").
+			Joins("JOIN product_attribute_values ON product_attribute_values.product_id = products.id AND " + activeAttributeValue).
+			Joins("JOIN attribute_definitions ON attribute_definitions.id = product_attribute_values.attribute_id AND attribute_definitions.filterable")
+		switch {
+		case exclude && len(codes) > 0:
+			query = query.Where("attribute_definitions.code NOT IN ?", codes)
+		case !exclude:
+			query = query.Where("attribute_definitions.code IN ?", codes)
+		}
+
+		var rows []attributeFacetRow
+		err := query.
+			Group("attribute_definitions.code, product_attribute_values.value").
+			Order("code ASC, count DESC, value ASC").
+			Scan(&rows).Error
+		return rows, err
+	}
+
+	rows, err := count(filters, filtered, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, code := range filtered {
+		without := filters
+		without.Attributes = nil
+		for _, filter := range filters.Attributes {
+			if filter.Code != code {
+				without.Attributes = append(without.Attributes, filter)
+			}
+		}
+
+		more, err := count(without, []string{code}, false)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, more...)
+	}
+
+	var facets []repository.AttributeFacet
+	index := make(map[string]int)
+	for _, row := range rows {
+		i, ok := index[row.Code]
+		if !ok {
+			i = len(facets)
+			index[row.Code] = i
+			facets = append(facets, repository.AttributeFacet{
+				Code: row.Code,
+				Name: row.Name,
+				Type: row.Type,
+				Unit: row.Unit,
+			})
+		}
+		facets[i].Values = append(facets[i].Values, repository.FacetCount{Value: row.Value, Count: row.Count})
+	}
+
+	sort.SliceStable(facets, func(i, j int) bool {
+		return facets[i].Code < facets[j].Code
+	})
+	for _, facet := range facets {
+		if facet.Type == entity.AttributeTypeNumber {
+			sortSizes(facet.Values)
+		}
+	}
+
+	return facets, nil
+}
+
+func (r *productRepositoryImpl) countPriceBuckets(query *gorm.DB) ([]repository.PriceBucket, error) {
+	bounds := make([]string, len(priceBucketBounds))
+	for i, bound := range priceBucketBounds {
+		bounds[i] = strconv.FormatFloat(bound, 'f', -1, 64)
+	}
+
+	// width_bucket returns 1 for the first bucket, 2 for the second, ...
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	err := query.
+		Select(fmt.Sprintf("width_bucket(products.price, ARRAY[%s]::numeric[]) AS bucket, COUNT(*) AS count", strings.Join(bounds, ","))).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]repository.PriceBucket, len(priceBucketBounds))
+	for i, bound := range priceBucketBounds {
+		buckets[i].Min = bound
+		if i+1 < len(priceBucketBounds) {
+			upper := priceBucketBounds[i+1]
+			buckets[i].Max = &upper
+		}
+	}
+	for _, row := range rows {
+		if row.Bucket >= 1 && row.Bucket <= len(buckets) {
+			buckets[row.Bucket-1].Count = row.Count
+		}
+	}
+
+	return buckets, nil
+}
+
+// sortSizes orders numeric sizes (8, 8.5, 10) numerically, followed by any
+// others (S, M, L) alphabetically
+func sortSizes(sizes []repository.FacetCount) {
+	sort.SliceStable(sizes, func(i, j int) bool {
+		a, errA := strconv.ParseFloat(sizes[i].Value, 64)
+		b, errB := strconv.ParseFloat(sizes[j].Value, 64)
+		switch {
+		case errA == nil && errB == nil:
+			return a < b
+		case errA == nil || errB == nil:
+			return errA == nil
+		default:
+			return sizes[i].Value < sizes[j].Value
+		}
+	})
+}
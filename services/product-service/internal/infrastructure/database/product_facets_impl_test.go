@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+func TestSortSizes(t *testing.T) {
+	tests := map[string]struct {
+		sizes []string
+		want  []string
+	}{
+		"numeric sizes by value":       {sizes: []string{"10", "8.5", "9", "11.5", "8"}, want: []string{"8", "8.5", "9", "10", "11.5"}},
+		"letter sizes alphabetically":  {sizes: []string{"S", "L", "M", "XL"}, want: []string{"L", "M", "S", "XL"}},
+		"numbers before letters":       {sizes: []string{"M", "42", "S", "38.5"}, want: []string{"38.5", "42", "M", "S"}},
+		"equal sizes keep their order": {sizes: []string{"9", "9.0", "8"}, want: []string{"8", "9", "9.0"}},
+		"nothing to sort":              {sizes: []string{}, want: []string{}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sizes := make([]repository.FacetCount, len(tt.sizes))
+			for i, size := range tt.sizes {
+				sizes[i] = repository.FacetCount{Value: size, Count: int64(i)}
+			}
+
+			sortSizes(sizes)
+
+			got := make([]string, len(sizes))
+			for i, size := range sizes {
+				got[i] = size.Value
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// bucketRowsConn is a database connection that answers every query with the
+// given price bucket counts and records the query it was sent
+type bucketRowsConn struct {
+	rows  [][]driver.Value
+	query *string
+}
+
+func (c bucketRowsConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c bucketRowsConn) Close() error                        { return nil }
+func (c bucketRowsConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (c bucketRowsConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	*c.query = query
+	return &bucketRows{rows: c.rows}, nil
+}
+
+func (c bucketRowsConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c bucketRowsConn) Driver() driver.Driver                        { return nil }
+
+type bucketRows struct {
+	rows [][]driver.Value
+}
+
+func (r *bucketRows) Columns() []string { return []string{"bucket", "count"} }
+func (r *bucketRows) Close() error      { return nil }
+
+func (r *bucketRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestCountPriceBuckets(t *testing.T) {
+	var query string
+	conn := bucketRowsConn{
+		// width_bucket numbers buckets from 1; 0 is below the first bound
+		rows:  [][]driver.Value{{int64(1), int64(4)}, {int64(3), int64(2)}, {int64(6), int64(1)}, {int64(0), int64(9)}},
+		query: &query,
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(conn)}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	require.NoError(t, err)
+	repo := &productRepositoryImpl{db: db}
+
+	buckets, err := repo.countPriceBuckets(db.Model(&entity.Product{}))
+
+	require.NoError(t, err)
+	assert.Contains(t, query, "width_bucket(products.price, ARRAY[0,50,100,150,200,300]::numeric[]) AS bucket")
+	require.Len(t, buckets, len(priceBucketBounds), "every bucket is returned, even without products")
+	for i, bucket := range buckets[:len(buckets)-1] {
+		assert.Equal(t, priceBucketBounds[i], bucket.Min)
+		require.NotNil(t, bucket.Max)
+		assert.Equal(t, priceBucketBounds[i+1], *bucket.Max, "a bucket ends where the next begins")
+	}
+	assert.Nil(t, buckets[len(buckets)-1].Max, "the last bucket is open-ended")
+	assert.Equal(t, []int64{4, 0, 2, 0, 0, 1}, []int64{
+		buckets[0].Count, buckets[1].Count, buckets[2].Count, buckets[3].Count, buckets[4].Count, buckets[5].Count,
+	}, "counts outside the buckets are ignored")
+}
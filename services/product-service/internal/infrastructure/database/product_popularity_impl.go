@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// productPopularityUpdate scores every product from the signals passed in as
+// parallel arrays, its decayed daily views and its damped rating, skipping
+// products whose score and units sold have not changed
+const productPopularityUpdate = `UPDATE products SET
+	units_sold = scores.units_sold,
+	popularity_score = scores.score
+FROM (
+	SELECT p.id,
+		COALESCE(s.units_sold, 0) AS units_sold,
+		@sales_weight * LN(1 + COALESCE(s.sales, 0))
+			+ @wishlist_weight * LN(1 + COALESCE(s.wishlist_adds, 0))
+			+ @views_weight * LN(1 + COALESCE(v.views, 0))
+			+ @rating_weight * p.rating_average::float8 * p.rating_count / (p.rating_count + @rating_prior) AS score
+	FROM products p
+	LEFT JOIN unnest(
+		CAST(@product_ids AS uuid[]),
+		CAST(@units_sold AS integer[]),
+		CAST(@sales AS float8[]),
+		CAST(@wishlist_adds AS float8[])
+	) AS s(product_id, units_sold, sales, wishlist_adds) ON s.product_id = p.id
+	LEFT JOIN (
+		SELECT product_id, SUM(views * POWER(0.5, (CURRENT_DATE - date) / CAST(@view_half_life AS float8))) AS views
+		FROM product_view_stats
+		WHERE date > CURRENT_DATE - CAST(@view_days AS integer)
+		GROUP BY product_id
+	) AS v ON v.product_id = p.id
+) AS scores
+WHERE products.id = scores.id
+	AND (products.units_sold <> scores.units_sold OR products.popularity_score <> scores.score)`
+
+func (r *productRepositoryImpl) UpdatePopularity(ctx context.Context, signals []*repository.PopularitySignals, weights repository.PopularityWeights) (int64, error) {
+	productIDs := make([]string, len(signals))
+	unitsSold := make([]int64, len(signals))
+	sales := make([]float64, len(signals))
+	wishlistAdds := make([]float64, len(signals))
+	for i, signal := range signals {
+		productIDs[i] = signal.ProductID.String()
+		unitsSold[i] = int64(signal.UnitsSold)
+		sales[i] = signal.Sales
+		wishlistAdds[i] = signal.WishlistAdds
+	}
+
+	result := r.db.WithContext(ctx).Exec(productPopularityUpdate, map[string]interface{}{
+		"product_ids":     pq.StringArray(productIDs),
+		"units_sold":      pq.Int64Array(unitsSold),
+		"sales":           pq.Float64Array(sales),
+		"wishlist_adds":   pq.Float64Array(wishlistAdds),
+		"sales_weight":    weights.Sales,
+		"wishlist_weight": weights.WishlistAdds,
+		"views_weight":    weights.Views,
+		"rating_weight":   weights.Rating,
+		"rating_prior":    weights.RatingPrior,
+		"view_days":       weights.ViewDays,
+		"view_half_life":  weights.ViewHalfLifeDays,
+	})
+	return result.RowsAffected, result.Error
+}
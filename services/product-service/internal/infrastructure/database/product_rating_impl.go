@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// productRatingColumns are the product columns derived from its reviews
+var productRatingColumns = []string{"rating_average", "rating_count", "rating_histogram"}
+
+// productStatColumns are the product columns kept up to date by the service
+// itself, which product updates leave alone
+var productStatColumns = append([]string{"recent_views", "units_sold", "popularity_score"}, productRatingColumns...)
+
+// productRatingUpdate recomputes products' rating aggregates from their
+// published reviews. The %s placeholder narrows the products it covers.
+const productRatingUpdate = `UPDATE products SET
+	rating_average = stats.average,
+	rating_count = stats.total,
+	rating_histogram = stats.histogram
+FROM (
+	SELECT p.id,
+		COALESCE(ROUND(AVG(r.rating), 2), 0) AS average,
+		COUNT(r.id) AS total,
+		ARRAY[
+			COUNT(r.id) FILTER (WHERE r.rating = 1),
+			COUNT(r.id) FILTER (WHERE r.rating = 2),
+			COUNT(r.id) FILTER (WHERE r.rating = 3),
+			COUNT(r.id) FILTER (WHERE r.rating = 4),
+			COUNT(r.id) FILTER (WHERE r.rating = 5)
+		]::integer[] AS histogram
+	FROM products p
+	LEFT JOIN reviews r ON r.product_id = p.id AND r.status = 'APPROVED'
+	%s
+	GROUP BY p.id
+) AS stats
+WHERE products.id = stats.id`
+
+// refreshProductRating recomputes a product's rating aggregates inside the
+// transaction that changed its reviews. The product row is locked first, so
+// the reviews are read only after any concurrent change to them has committed
+// and neither transaction's review is left out.
+func refreshProductRating(tx *gorm.DB, productID uuid.UUID) error {
+	if err := tx.Exec("SELECT 1 FROM products WHERE id = ? FOR UPDATE", productID).Error; err != nil {
+		return err
+	}
+	return tx.Exec(fmt.Sprintf(productRatingUpdate, "WHERE p.id = ?"), productID).Error
+}
+
+// RefreshProductRatings recomputes every product's rating aggregates. It is
+// meant for backfills and reads all reviews at once, so a product whose
+// reviews change while it runs may need another run.
+func (r *reviewRepository) RefreshProductRatings(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(fmt.Sprintf(productRatingUpdate, ""))
+	return result.RowsAffected, result.Error
+}
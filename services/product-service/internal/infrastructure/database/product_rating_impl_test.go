@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"solemate/services/product-service/internal/domain/entity"
+)
+
+func TestProductRatingQueries(t *testing.T) {
+	var statements []string
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Discard,
+	})
+	require.NoError(t, err)
+	capture := func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}
+	require.NoError(t, db.Callback().Raw().After("gorm:raw").Register("test:statement", capture))
+	require.NoError(t, db.Callback().Update().After("gorm:update").Register("test:statement", capture))
+	ctx := context.Background()
+
+	t.Run("one product is locked, then recomputed from its published reviews", func(t *testing.T) {
+		statements = nil
+
+		require.NoError(t, refreshProductRating(db, uuid.New()))
+
+		require.Len(t, statements, 2)
+		assert.Equal(t, "SELECT 1 FROM products WHERE id = $1 FOR UPDATE", statements[0])
+		assert.Contains(t, statements[1], "UPDATE products SET")
+		assert.Contains(t, statements[1], "LEFT JOIN reviews r ON r.product_id = p.id AND r.status = 'APPROVED'\n\tWHERE p.id = $1")
+	})
+
+	t.Run("a backfill recomputes every product", func(t *testing.T) {
+		statements = nil
+
+		_, err := (&reviewRepository{db: db}).RefreshProductRatings(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, statements, 1)
+		assert.NotContains(t, statements[0], "WHERE p.id")
+		assert.Contains(t, statements[0], "WHERE products.id = stats.id")
+	})
+
+	t.Run("product updates leave the rating aggregates alone", func(t *testing.T) {
+		statements = nil
+
+		err := (&productRepositoryImpl{db: db}).Update(ctx, &entity.Product{ID: uuid.New(), Name: "Air Max 90", RatingAverage: 1})
+
+		require.NoError(t, err)
+		require.NotEmpty(t, statements)
+		assert.Contains(t, statements[0], `"name"=`)
+		for _, column := range productRatingColumns {
+			assert.NotContains(t, statements[0], `"`+column+`"`)
+		}
+	})
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"solemate/pkg/pagination"
 	"solemate/services/product-service/internal/domain/entity"
 	"solemate/services/product-service/internal/domain/repository"
 )
@@ -35,7 +37,9 @@ func (r *productRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*ent
 	result := r.db.WithContext(ctx).
 		Preload("Category").
 		Preload("Brand").
-		Preload("Variants").
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC, created_at ASC")
+		}).
 		Preload("Images", func(db *gorm.DB) *gorm.DB {
 			return db.Order("sort_order ASC, created_at ASC")
 		}).
@@ -57,7 +61,9 @@ func (r *productRepositoryImpl) GetBySKU(ctx context.Context, sku string) (*enti
 	result := r.db.WithContext(ctx).
 		Preload("Category").
 		Preload("Brand").
-		Preload("Variants").
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC, created_at ASC")
+		}).
 		Preload("Images", func(db *gorm.DB) *gorm.DB {
 			return db.Order("sort_order ASC, created_at ASC")
 		}).
@@ -79,7 +85,9 @@ func (r *productRepositoryImpl) GetBySlug(ctx context.Context, slug string) (*en
 	result := r.db.WithContext(ctx).
 		Preload("Category").
 		Preload("Brand").
-		Preload("Variants").
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC, created_at ASC")
+		}).
 		Preload("Images", func(db *gorm.DB) *gorm.DB {
 			return db.Order("sort_order ASC, created_at ASC")
 		}).
@@ -96,9 +104,12 @@ func (r *productRepositoryImpl) GetBySlug(ctx context.Context, slug string) (*en
 	return &product, nil
 }
 
+// Update leaves the rating aggregates alone: they are only written by the
+// review repository, and a product loaded before a review change would
+// otherwise overwrite them
 func (r *productRepositoryImpl) Update(ctx context.Context, product *entity.Product) error {
 	product.UpdatedAt = time.Now()
-	result := r.db.WithContext(ctx).Save(product)
+	result := r.db.WithContext(ctx).Omit(productStatColumns...).Save(product)
 	return result.Error
 }
 
@@ -110,9 +121,20 @@ func (r *productRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error
 	return result.Error
 }
 
-func (r *productRepositoryImpl) List(ctx context.Context, filters repository.ProductFilters) ([]*entity.Product, int64, error) {
+// List returns a page of products, by cursor when filters has one and by
+// offset otherwise. A row more than the limit is fetched to tell whether
+// there is a next page.
+func (r *productRepositoryImpl) List(ctx context.Context, filters repository.ProductFilters) ([]*entity.Product, *pagination.Page, error) {
 	var products []*entity.Product
-	var total int64
+
+	sort := productSortFor(filters)
+	var cursor *pagination.Cursor
+	if filters.Cursor != "" {
+		var err error
+		if cursor, err = pagination.DecodeCursor(filters.Cursor, sort.name, sort.key(&entity.Product{})); err != nil {
+			return nil, nil, err
+		}
+	}
 
 	query := r.db.WithContext(ctx).Model(&entity.Product{})
 
@@ -120,18 +142,27 @@ func (r *productRepositoryImpl) List(ctx context.Context, filters repository.Pro
 	query = r.applyFilters(query, filters)
 
 	// Count total
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	var total *int64
+	if !filters.SkipCount {
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return nil, nil, err
+		}
+		total = &count
 	}
 
-	// Apply sorting
-	query = r.applySorting(query, filters)
+	// Apply sorting, from the cursor onwards if there is one
+	if cursor != nil {
+		condition, args := cursor.Condition(sort.columns, sort.descending)
+		query = query.Where(condition, args...)
+	}
+	query = sort.apply(query, cursor)
 
 	// Apply pagination
 	if filters.Limit > 0 {
-		query = query.Limit(filters.Limit)
+		query = query.Limit(filters.Limit + 1)
 	}
-	if filters.Offset > 0 {
+	if filters.Offset > 0 && cursor == nil {
 		query = query.Offset(filters.Offset)
 	}
 
@@ -144,30 +175,116 @@ func (r *productRepositoryImpl) List(ctx context.Context, filters repository.Pro
 			return db.Where("is_primary = ?", true)
 		})
 
-	result := query.Find(&products)
+	if err := query.Find(&products).Error; err != nil {
+		return nil, nil, err
+	}
+
+	offset := filters.Offset
+	if cursor != nil {
+		offset = 0
+	}
+	products, page := pagination.Paginate(products, filters.Limit, cursor, sort.name, offset, sort.key)
+	page.Total = total
 
 	// Calculate total stock for each product
 	for _, product := range products {
 		r.calculateTotalStock(product)
 	}
 
-	return products, total, result.Error
+	return products, &page, nil
 }
 
-func (r *productRepositoryImpl) SearchByText(ctx context.Context, searchQuery string, filters repository.ProductFilters) ([]*entity.Product, int64, error) {
+// searchTSQuery parses the shopper's query the way web search engines do:
+// quoted phrases, "or" and -excluded words are supported
+const searchTSQuery = "websearch_to_tsquery('english', ?)"
+
+// searchWordSimilarity is the pg_trgm word similarity a product name needs
+// to match a misspelt query. The extension's default of 0.6 misses most typos.
+const searchWordSimilarity = 0.4
+
+// textMatch is a way of matching products against a search query. Both
+// clauses take the query as their only parameter.
+type textMatch struct {
+	where string
+	rank  string
+}
+
+var (
+	fullTextMatch = textMatch{
+		where: "products.search_vector @@ " + searchTSQuery,
+		rank:  "ts_rank_cd(products.search_vector, " + searchTSQuery + ") DESC",
+	}
+	trigramMatch = textMatch{
+		where: "? <% products.name",
+		rank:  "word_similarity(?, products.name) DESC",
+	}
+)
+
+// SearchByText ranks products by full-text relevance over the weighted
+// search_vector column (see migrations/006_add_product_search). If nothing
+// matches, it falls back to trigram similarity on the name so misspelt
+// queries still find products.
+//
+// Matches are paginated by offset only, since no cursor is issued for
+// relevance.
+func (r *productRepositoryImpl) SearchByText(ctx context.Context, searchQuery string, filters repository.ProductFilters) ([]*entity.Product, *pagination.Page, error) {
+	if searchQuery == "" {
+		return r.List(ctx, filters)
+	}
+	if filters.Cursor != "" {
+		return nil, nil, pagination.ErrInvalidCursor
+	}
+
 	var products []*entity.Product
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&entity.Product{})
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		match, err := r.chooseTextMatch(tx, searchQuery, filters)
+		if err != nil {
+			return err
+		}
+
+		products, total, err = r.findMatches(tx, searchQuery, filters, match)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Calculate total stock for each product
+	for _, product := range products {
+		r.calculateTotalStock(product)
+	}
+
+	return products, &pagination.Page{Total: &total}, nil
+}
+
+// chooseTextMatch uses full-text matching if it finds anything under
+// filters. Otherwise it lowers the trigram threshold for the rest of tx and
+// falls back to trigram matching.
+func (r *productRepositoryImpl) chooseTextMatch(tx *gorm.DB, searchQuery string, filters repository.ProductFilters) (textMatch, error) {
+	var found []uuid.UUID
+	query := r.applyFilters(tx.Model(&entity.Product{}).Where(fullTextMatch.where, searchQuery), filters)
+	if err := query.Limit(1).Pluck("products.id", &found).Error; err != nil {
+		return textMatch{}, err
+	}
+	if len(found) > 0 {
+		return fullTextMatch, nil
+	}
 
-	// Apply text search
-	if searchQuery != "" {
-		searchTerm := "%" + strings.ToLower(searchQuery) + "%"
-		query = query.Where(
-			"LOWER(name) LIKE ? OR LOWER(description) LIKE ? OR ? = ANY(LOWER(tags::text)::text[])",
-			searchTerm, searchTerm, strings.ToLower(searchQuery),
-		)
+	if err := tx.Exec(fmt.Sprintf("SET LOCAL pg_trgm.word_similarity_threshold = %v", searchWordSimilarity)).Error; err != nil {
+		return textMatch{}, err
 	}
+	return trigramMatch, nil
+}
+
+// findMatches returns the page of products matching the search query. They
+// are ordered by relevance unless the caller asked for another sort.
+func (r *productRepositoryImpl) findMatches(tx *gorm.DB, searchQuery string, filters repository.ProductFilters, match textMatch) ([]*entity.Product, int64, error) {
+	var products []*entity.Product
+	var total int64
+
+	query := tx.Model(&entity.Product{}).Where(match.where, searchQuery)
 
 	// Apply filters
 	query = r.applyFilters(query, filters)
@@ -176,9 +293,20 @@ func (r *productRepositoryImpl) SearchByText(ctx context.Context, searchQuery st
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
+	if total == 0 {
+		return nil, 0, nil
+	}
 
-	// Apply sorting
-	query = r.applySorting(query, filters)
+	// Apply sorting, most relevant first by default
+	if filters.SortBy == "" || filters.SortBy == "relevance" {
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                match.rank + ", products.created_at DESC",
+			Vars:               []interface{}{searchQuery},
+			WithoutParentheses: true,
+		}})
+	} else {
+		query = productSortFor(filters).apply(query, nil)
+	}
 
 	// Apply pagination
 	if filters.Limit > 0 {
@@ -192,21 +320,29 @@ func (r *productRepositoryImpl) SearchByText(ctx context.Context, searchQuery st
 	query = query.
 		Preload("Category").
 		Preload("Brand").
-		Preload("Variants"). // Add this line
+		Preload("Variants").
 		Preload("Images", func(db *gorm.DB) *gorm.DB {
 			return db.Where("is_primary = ?", true)
 		})
 
-	result := query.Find(&products)
-
-	// Calculate total stock for each product
-	for _, product := range products {
-		r.calculateTotalStock(product)
+	if err := query.Find(&products).Error; err != nil {
+		return nil, 0, err
 	}
 
-	return products, total, result.Error
+	return products, total, nil
 }
 
+// sharedAttributeValues counts the product-level attribute values a product
+// has in common with @product
+const sharedAttributeValues = `(SELECT COUNT(*) FROM product_attribute_values shared
+	JOIN product_attribute_values own ON own.attribute_id = shared.attribute_id AND own.value = shared.value
+	WHERE shared.product_id = products.id AND shared.variant_id IS NULL
+	AND own.product_id = @product AND own.variant_id IS NULL)`
+
+// GetRelatedProducts ranks other active products by what they have in common
+// with the product: each shared attribute value counts 2, the same category 3
+// and the same brand 1. Products with nothing in common are left out and the
+// closest price breaks ties.
 func (r *productRepositoryImpl) GetRelatedProducts(ctx context.Context, productID uuid.UUID, limit int) ([]*entity.Product, error) {
 	var currentProduct entity.Product
 	if err := r.db.WithContext(ctx).Where("id = ?", productID).First(&currentProduct).Error; err != nil {
@@ -216,98 +352,291 @@ func (r *productRepositoryImpl) GetRelatedProducts(ctx context.Context, productI
 		return nil, err
 	}
 
+	vars := map[string]interface{}{
+		"product": productID,
+		"price":   currentProduct.Price,
+	}
+	terms := []string{"2 * " + sharedAttributeValues}
+	if currentProduct.CategoryID != nil {
+		terms = append(terms, "CASE WHEN products.category_id = @category THEN 3 ELSE 0 END")
+		vars["category"] = *currentProduct.CategoryID
+	}
+	if currentProduct.BrandID != nil {
+		terms = append(terms, "CASE WHEN products.brand_id = @brand THEN 1 ELSE 0 END")
+		vars["brand"] = *currentProduct.BrandID
+	}
+	similarity := strings.Join(terms, " + ")
+
 	var relatedProducts []*entity.Product
-	query := r.db.WithContext(ctx).
-		Where("id != ?", productID). // Exclude the current product
+	err := r.db.WithContext(ctx).
+		Where("products.id <> ? AND products.is_active = ?", productID, true).
+		Where(clause.NamedExpr{SQL: similarity + " > 0", Vars: []interface{}{vars}}).
+		Clauses(clause.OrderBy{Expression: clause.NamedExpr{
+			SQL:  similarity + " DESC, ABS(products.price - @price) ASC",
+			Vars: []interface{}{vars},
+		}}).
 		Limit(limit).
 		Preload("Category").
 		Preload("Brand").
-		Preload("Variants"). // Add this line
+		Preload("Variants").
 		Preload("Images", func(db *gorm.DB) *gorm.DB {
 			return db.Where("is_primary = ?", true)
-		})
+		}).
+		Find(&relatedProducts).Error
+	if err != nil {
+		return nil, err
+	}
 
-	// Try to find products in the same category first
-	if currentProduct.CategoryID != nil {
-		query = query.Or("category_id = ?", *currentProduct.CategoryID)
+	// Calculate total stock for each related product
+	for _, product := range relatedProducts {
+		r.calculateTotalStock(product)
 	}
 
-	// Then try to find products by the same brand
-	if currentProduct.BrandID != nil {
-		query = query.Or("brand_id = ?", *currentProduct.BrandID)
+	return relatedProducts, nil
+}
+
+// GetActiveByIDs loads the active products among ids, in the order given
+func (r *productRepositoryImpl) GetActiveByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Product, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
 
-	if err := query.Find(&relatedProducts).Error; err != nil {
+	var found []*entity.Product
+	err := r.db.WithContext(ctx).
+		Where("id IN ? AND is_active = ?", ids, true).
+		Preload("Category").
+		Preload("Brand").
+		Preload("Variants").
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_primary = ?", true)
+		}).
+		Find(&found).Error
+	if err != nil {
 		return nil, err
 	}
 
-	// Calculate total stock for each related product
-	for _, product := range relatedProducts {
+	byID := make(map[uuid.UUID]*entity.Product, len(found))
+	for _, product := range found {
 		r.calculateTotalStock(product)
+		byID[product.ID] = product
 	}
 
-	return relatedProducts, nil
+	products := make([]*entity.Product, 0, len(found))
+	for _, id := range ids {
+		if product, ok := byID[id]; ok {
+			products = append(products, product)
+		}
+	}
+	return products, nil
 }
 
+// ListAfterSKU returns the next page of all products, active or not, in SKU
+// order with every variant and image. Paging by SKU rather than offset keeps
+// long exports consistent while products are being added.
+func (r *productRepositoryImpl) ListAfterSKU(ctx context.Context, afterSKU string, limit int) ([]*entity.Product, error) {
+	var products []*entity.Product
+	result := r.db.WithContext(ctx).
+		Preload("Category").
+		Preload("Brand").
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC, created_at ASC")
+		}).
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC, created_at ASC")
+		}).
+		Where("sku > ?", afterSKU).
+		Order("sku ASC").
+		Limit(limit).
+		Find(&products)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, product := range products {
+		r.calculateTotalStock(product)
+	}
+	return products, nil
+}
+
+// categorySubtree selects the given categories and all of their
+// descendants, so browsing "Running" includes "Trail Running". UNION rather
+// than UNION ALL stops the recursion should the tree ever contain a cycle.
+const categorySubtree = `WITH RECURSIVE subtree AS (
+	SELECT id FROM categories WHERE id IN ?
+	UNION
+	SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id
+) SELECT id FROM subtree`
+
 func (r *productRepositoryImpl) applyFilters(query *gorm.DB, filters repository.ProductFilters) *gorm.DB {
-	if filters.CategoryID != nil {
-		query = query.Where("category_id = ?", *filters.CategoryID)
+	if len(filters.CategoryIDs) > 0 {
+		query = query.Where("products.category_id IN ("+categorySubtree+")", filters.CategoryIDs)
 	}
 
-	if filters.BrandID != nil {
-		query = query.Where("brand_id = ?", *filters.BrandID)
+	if len(filters.BrandIDs) > 0 {
+		query = query.Where("products.brand_id IN ?", filters.BrandIDs)
 	}
 
 	if filters.MinPrice != nil {
-		query = query.Where("price >= ?", *filters.MinPrice)
+		query = query.Where("products.price >= ?", *filters.MinPrice)
 	}
 
 	if filters.MaxPrice != nil {
-		query = query.Where("price <= ?", *filters.MaxPrice)
+		query = query.Where("products.price <= ?", *filters.MaxPrice)
 	}
 
 	if len(filters.Tags) > 0 {
 		for _, tag := range filters.Tags {
-			query = query.Where("? = ANY(tags)", tag)
+			query = query.Where("? = ANY(products.tags)", tag)
 		}
 	}
 
 	if filters.IsActive != nil {
-		query = query.Where("is_active = ?", *filters.IsActive)
+		query = query.Where("products.is_active = ?", *filters.IsActive)
 	}
 
 	if filters.InStock != nil && *filters.InStock {
-		query = query.Joins("JOIN product_variants ON products.id = product_variants.product_id").
-			Where("product_variants.stock > 0")
+		query = query.Where("EXISTS (SELECT 1 FROM product_variants WHERE product_variants.product_id = products.id AND product_variants.is_active AND product_variants.stock > 0)")
+	}
+
+	// Size and colour must both match the same variant, so "size 10 in red"
+	// doesn't match a product whose only red pair is a size 8
+	if condition, args := variantCondition("product_variants", filters.Sizes, filters.Colors); condition != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM product_variants WHERE product_variants.product_id = products.id AND product_variants.is_active AND "+condition+")", args...)
+	}
+
+	for _, filter := range filters.Attributes {
+		condition, args := attributeCondition(filter)
+		if condition == "" {
+			continue
+		}
+		query = query.Where("EXISTS (SELECT 1 FROM product_attribute_values JOIN attribute_definitions ON attribute_definitions.id = product_attribute_values.attribute_id"+
+			" WHERE product_attribute_values.product_id = products.id AND "+activeAttributeValue+
+			" AND attribute_definitions.code = ? AND "+condition+")", append([]interface{}{filter.Code}, args...)...)
 	}
 
 	return query
 }
 
-func (r *productRepositoryImpl) applySorting(query *gorm.DB, filters repository.ProductFilters) *gorm.DB {
-	sortBy := "created_at"
-	sortOrder := "DESC"
+// activeAttributeValue excludes the values of inactive variants
+const activeAttributeValue = "(product_attribute_values.variant_id IS NULL OR EXISTS (SELECT 1 FROM product_variants WHERE product_variants.id = product_attribute_values.variant_id AND product_variants.is_active))"
 
-	if filters.SortBy != "" {
-		switch filters.SortBy {
-		case "price", "name", "created_at":
-			sortBy = filters.SortBy
+// attributeCondition matches rows of product_attribute_values against a
+// filter. An unknown operator gives condition "".
+func attributeCondition(filter repository.AttributeFilter) (condition string, args []interface{}) {
+	switch filter.Operator {
+	case "=":
+		if len(filter.Values) == 0 {
+			return "", nil
+		}
+		lowered := make([]string, len(filter.Values))
+		for i, value := range filter.Values {
+			lowered[i] = strings.ToLower(value)
 		}
+		return "LOWER(product_attribute_values.value) IN ?", []interface{}{lowered}
+	case "<", "<=", ">", ">=":
+		return "product_attribute_values.number_value " + filter.Operator + " ?", []interface{}{filter.Number}
+	default:
+		return "", nil
 	}
+}
 
-	if filters.SortOrder != "" {
-		if strings.ToUpper(filters.SortOrder) == "ASC" {
-			sortOrder = "ASC"
+// variantCondition matches rows of the variants table whose size is one of
+// sizes and whose colour is one of colors (case-insensitively). An empty list
+// matches anything; if both are empty, condition is "".
+func variantCondition(table string, sizes, colors []string) (condition string, args []interface{}) {
+	var conditions []string
+
+	if len(sizes) > 0 {
+		conditions = append(conditions, table+".size IN ?")
+		args = append(args, sizes)
+	}
+
+	if len(colors) > 0 {
+		lowered := make([]string, len(colors))
+		for i, color := range colors {
+			lowered[i] = strings.ToLower(color)
 		}
+		conditions = append(conditions, "LOWER("+table+".color) IN ?")
+		args = append(args, lowered)
 	}
 
-	return query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
+	return strings.Join(conditions, " AND "), args
+}
+
+// productSort is an ordering of products. Its columns end with the ID so
+// that products with equal values keep the same order from page to page.
+type productSort struct {
+	name       string // tells cursors of different sorts apart
+	columns    []string
+	descending bool
+	key        func(product *entity.Product) []interface{}
+}
+
+func productSortFor(filters repository.ProductFilters) productSort {
+	sortBy := "created_at"
+	switch filters.SortBy {
+	case "price", "name", "created_at", "rating", "popularity", "best_selling":
+		sortBy = filters.SortBy
+	}
+
+	sort := productSort{name: sortBy + ":desc", descending: true}
+	if strings.ToUpper(filters.SortOrder) == "ASC" {
+		sort.name, sort.descending = sortBy+":asc", false
+	}
+
+	switch sortBy {
+	case "rating":
+		// Best rated first; among equal averages, the better reviewed first
+		sort.columns = []string{"products.rating_average", "products.rating_count", "products.id"}
+		sort.key = func(p *entity.Product) []interface{} {
+			return []interface{}{&p.RatingAverage, &p.RatingCount, &p.ID}
+		}
+	case "popularity":
+		sort.columns = []string{"products.popularity_score", "products.created_at", "products.id"}
+		sort.key = func(p *entity.Product) []interface{} {
+			return []interface{}{&p.PopularityScore, &p.CreatedAt, &p.ID}
+		}
+	case "best_selling":
+		// Most units sold over the last 30 days first; unsold products by popularity
+		sort.columns = []string{"products.units_sold", "products.popularity_score", "products.id"}
+		sort.key = func(p *entity.Product) []interface{} {
+			return []interface{}{&p.UnitsSold, &p.PopularityScore, &p.ID}
+		}
+	case "price":
+		sort.columns = []string{"products.price", "products.id"}
+		sort.key = func(p *entity.Product) []interface{} {
+			return []interface{}{&p.Price, &p.ID}
+		}
+	case "name":
+		sort.columns = []string{"products.name", "products.id"}
+		sort.key = func(p *entity.Product) []interface{} {
+			return []interface{}{&p.Name, &p.ID}
+		}
+	default:
+		sort.columns = []string{"products.created_at", "products.id"}
+		sort.key = func(p *entity.Product) []interface{} {
+			return []interface{}{&p.CreatedAt, &p.ID}
+		}
+	}
+	return sort
+}
+
+// apply orders query by the sort, reversed to walk back from a cursor to the
+// previous page
+func (s productSort) apply(query *gorm.DB, cursor *pagination.Cursor) *gorm.DB {
+	direction := " ASC"
+	if cursor.OrderDescending(s.descending) {
+		direction = " DESC"
+	}
+	return query.Order(strings.Join(s.columns, direction+", ") + direction)
 }
 
 func (r *productRepositoryImpl) calculateTotalStock(product *entity.Product) {
 	totalStock := 0
 	for _, variant := range product.Variants {
-		totalStock += variant.Stock
+		if variant.IsActive {
+			totalStock += variant.Stock
+		}
 	}
 	product.StockQuantity = totalStock
 }
\ No newline at end of file
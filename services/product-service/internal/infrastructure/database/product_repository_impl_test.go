@@ -0,0 +1,154 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"solemate/pkg/pagination"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+func TestAttributeCondition(t *testing.T) {
+	tests := map[string]struct {
+		filter    repository.AttributeFilter
+		condition string
+		args      []interface{}
+	}{
+		"values match case-insensitively": {
+			filter:    repository.AttributeFilter{Code: "width", Operator: "=", Values: []string{"Wide", "EXTRA-wide"}},
+			condition: "LOWER(product_attribute_values.value) IN ?",
+			args:      []interface{}{[]string{"wide", "extra-wide"}},
+		},
+		"numeric comparison": {
+			filter:    repository.AttributeFilter{Code: "drop", Operator: "<=", Number: 6},
+			condition: "product_attribute_values.number_value <= ?",
+			args:      []interface{}{6.0},
+		},
+		"greater than": {
+			filter:    repository.AttributeFilter{Code: "drop", Operator: ">", Number: 4.5},
+			condition: "product_attribute_values.number_value > ?",
+			args:      []interface{}{4.5},
+		},
+		"no values": {
+			filter: repository.AttributeFilter{Code: "width", Operator: "="},
+		},
+		"operator outside the whitelist": {
+			filter: repository.AttributeFilter{Code: "drop", Operator: "<> 0 OR 1=1 --", Number: 6},
+		},
+		"no operator": {
+			filter: repository.AttributeFilter{Code: "drop", Number: 6},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			condition, args := attributeCondition(tt.filter)
+			assert.Equal(t, tt.condition, condition)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestProductSortFor(t *testing.T) {
+	tests := map[string]struct {
+		filters    repository.ProductFilters
+		name       string
+		columns    []string
+		descending bool
+	}{
+		"best selling": {
+			filters:    repository.ProductFilters{SortBy: "best_selling"},
+			name:       "best_selling:desc",
+			columns:    []string{"products.units_sold", "products.popularity_score", "products.id"},
+			descending: true,
+		},
+		"popularity": {
+			filters:    repository.ProductFilters{SortBy: "popularity", SortOrder: "desc"},
+			name:       "popularity:desc",
+			columns:    []string{"products.popularity_score", "products.created_at", "products.id"},
+			descending: true,
+		},
+		"least popular first": {
+			filters:    repository.ProductFilters{SortBy: "popularity", SortOrder: "asc"},
+			name:       "popularity:asc",
+			columns:    []string{"products.popularity_score", "products.created_at", "products.id"},
+			descending: false,
+		},
+		"price ascending": {
+			filters:    repository.ProductFilters{SortBy: "price", SortOrder: "ASC"},
+			name:       "price:asc",
+			columns:    []string{"products.price", "products.id"},
+			descending: false,
+		},
+		"rating": {
+			filters:    repository.ProductFilters{SortBy: "rating"},
+			name:       "rating:desc",
+			columns:    []string{"products.rating_average", "products.rating_count", "products.id"},
+			descending: true,
+		},
+		"newest by default": {
+			filters:    repository.ProductFilters{},
+			name:       "created_at:desc",
+			columns:    []string{"products.created_at", "products.id"},
+			descending: true,
+		},
+		"unknown sort falls back to newest": {
+			filters:    repository.ProductFilters{SortBy: "units_sold; DROP TABLE products"},
+			name:       "created_at:desc",
+			columns:    []string{"products.created_at", "products.id"},
+			descending: true,
+		},
+		"relevance outside text search": {
+			filters:    repository.ProductFilters{SortBy: "relevance"},
+			name:       "created_at:desc",
+			columns:    []string{"products.created_at", "products.id"},
+			descending: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sort := productSortFor(tt.filters)
+			assert.Equal(t, tt.name, sort.name)
+			assert.Equal(t, tt.columns, sort.columns)
+			assert.Equal(t, tt.descending, sort.descending)
+			assert.Len(t, sort.key(&entity.Product{}), len(tt.columns), "a cursor holds a value per sort column")
+		})
+	}
+}
+
+func TestProductSortKeys(t *testing.T) {
+	product := &entity.Product{ID: uuid.New(), UnitsSold: 42, PopularityScore: 7.5}
+
+	sort := productSortFor(repository.ProductFilters{SortBy: "best_selling"})
+	encoded := (&pagination.Cursor{Sort: sort.name, Key: sort.key(product)}).Encode()
+
+	var decoded entity.Product
+	_, err := pagination.DecodeCursor(encoded, sort.name, sort.key(&decoded))
+	require.NoError(t, err)
+	assert.Equal(t, 42, decoded.UnitsSold)
+	assert.Equal(t, 7.5, decoded.PopularityScore)
+	assert.Equal(t, product.ID, decoded.ID)
+
+	_, err = pagination.DecodeCursor(encoded, productSortFor(repository.ProductFilters{SortBy: "popularity"}).name, sort.key(&decoded))
+	assert.ErrorIs(t, err, pagination.ErrInvalidCursor, "a best selling cursor is not valid for the popularity sort")
+}
+
+func TestProductSortApply(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	sort := productSortFor(repository.ProductFilters{SortBy: "best_selling"})
+
+	orderBy := func(cursor *pagination.Cursor) string {
+		statement := sort.apply(db.Model(&entity.Product{}), cursor).Find(&[]*entity.Product{}).Statement
+		return statement.SQL.String()
+	}
+
+	assert.Contains(t, orderBy(nil), "ORDER BY products.units_sold DESC, products.popularity_score DESC, products.id DESC")
+	assert.Contains(t, orderBy(&pagination.Cursor{}), "ORDER BY products.units_sold DESC, products.popularity_score DESC, products.id DESC")
+	assert.Contains(t, orderBy(&pagination.Cursor{Before: true}), "ORDER BY products.units_sold ASC, products.popularity_score ASC, products.id ASC",
+		"the page before a cursor is fetched walking back from it")
+}
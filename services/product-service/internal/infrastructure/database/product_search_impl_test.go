@@ -0,0 +1,58 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+func TestProductTextSearchQueries(t *testing.T) {
+	var statements []string
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	require.NoError(t, err)
+	capture := func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:statement", capture))
+	require.NoError(t, db.Callback().Raw().After("gorm:raw").Register("test:statement", capture))
+	repo := &productRepositoryImpl{db: db}
+	filters := repository.ProductFilters{Limit: 20}
+
+	t.Run("without full-text matches the trigram match is used", func(t *testing.T) {
+		statements = nil
+
+		match, err := repo.chooseTextMatch(db, "snekaers", filters)
+
+		require.NoError(t, err)
+		assert.Equal(t, trigramMatch, match)
+		require.Len(t, statements, 2)
+		assert.Contains(t, statements[0], "products.search_vector @@ websearch_to_tsquery('english', $1)")
+		assert.Equal(t, "SET LOCAL pg_trgm.word_similarity_threshold = 0.4", statements[1])
+	})
+
+	t.Run("matches are counted with the chosen match", func(t *testing.T) {
+		for match, where := range map[textMatch]string{
+			fullTextMatch: "products.search_vector @@ websearch_to_tsquery('english', $1)",
+			trigramMatch:  "$1 <% products.name",
+		} {
+			statements = nil
+
+			products, total, err := repo.findMatches(db, "running shoe", filters, match)
+
+			require.NoError(t, err)
+			assert.Empty(t, products)
+			assert.Zero(t, total)
+			require.Len(t, statements, 1, "nothing is fetched once the count finds no matches")
+			assert.Contains(t, statements[0], "SELECT count(*) FROM \"products\" WHERE "+where)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// likeEscaper escapes LIKE wildcards so they match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Suggest returns the active products, brands and categories whose names
+// start with query, or are similar enough to tolerate a typo. Prefix
+// matches come first. See migrations/007_add_search_suggestions for the
+// indexes these lookups use.
+func (r *productRepositoryImpl) Suggest(ctx context.Context, query string, limit int) (*repository.SearchSuggestions, error) {
+	suggestions := &repository.SearchSuggestions{}
+	prefix := likeEscaper.Replace(strings.ToLower(query)) + "%"
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf("SET LOCAL pg_trgm.word_similarity_threshold = %v", searchWordSimilarity)).Error; err != nil {
+			return err
+		}
+
+		lookups := []struct {
+			model interface{}
+			dest  *[]repository.Suggestion
+		}{
+			{&entity.Product{}, &suggestions.Products},
+			{&entity.Brand{}, &suggestions.Brands},
+			{&entity.Category{}, &suggestions.Categories},
+		}
+
+		for _, lookup := range lookups {
+			err := tx.Model(lookup.model).
+				Select("id, name, slug").
+				Where("is_active").
+				Where("(LOWER(name) LIKE ? OR ? <% name)", prefix, query).
+				Order(clause.OrderBy{Expression: clause.Expr{
+					SQL:                "LOWER(name) LIKE ? DESC, word_similarity(?, name) DESC, name ASC",
+					Vars:               []interface{}{prefix, query},
+					WithoutParentheses: true,
+				}}).
+				Limit(limit).
+				Scan(lookup.dest).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLikeEscaper(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  string
+	}{
+		"plain text":          {query: "air max", want: "air max"},
+		"percent sign":        {query: "100% leather", want: `100\% leather`},
+		"underscore":          {query: "air_max", want: `air\_max`},
+		"backslash":           {query: `a\b`, want: `a\\b`},
+		"escapes not doubled": {query: `\%`, want: `\\\%`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, likeEscaper.Replace(tt.query))
+		})
+	}
+}
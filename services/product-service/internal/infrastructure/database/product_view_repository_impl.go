@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// viewStatBatchSize is how many daily counts are upserted per statement
+const viewStatBatchSize = 500
+
+// productRecentViewsUpdate sets each product's recent views to its total
+// since @since, skipping products whose total has not changed
+const productRecentViewsUpdate = `UPDATE products SET recent_views = totals.views
+FROM (
+	SELECT p.id, COALESCE(SUM(s.views), 0) AS views
+	FROM products p
+	LEFT JOIN product_view_stats s ON s.product_id = p.id AND s.date >= @since
+	GROUP BY p.id
+) AS totals
+WHERE products.id = totals.id AND products.recent_views <> totals.views`
+
+type productViewRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductViewRepository(db *gorm.DB) repository.ProductViewRepository {
+	return &productViewRepositoryImpl{db: db}
+}
+
+func (r *productViewRepositoryImpl) SaveDailyViews(ctx context.Context, stats []*entity.ProductViewStat, coViews []*entity.ProductCoView) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(stats) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "product_id"}, {Name: "date"}},
+				DoUpdates: clause.AssignmentColumns([]string{"views"}),
+			}).CreateInBatches(stats, viewStatBatchSize).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(coViews) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "product_id"}, {Name: "related_product_id"}, {Name: "date"}},
+				DoUpdates: clause.AssignmentColumns([]string{"views"}),
+			}).CreateInBatches(coViews, viewStatBatchSize).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *productViewRepositoryImpl) RefreshRecentViews(ctx context.Context, since time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(productRecentViewsUpdate, map[string]interface{}{"since": since})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// affinityBatchSize is how many affinities are inserted per statement
+const affinityBatchSize = 500
+
+// viewAffinityQuery scores each pair of products viewed together by the share
+// of the first product's views that came with a view of the second
+const viewAffinityQuery = `SELECT product_id, related_product_id, score, support FROM (
+	SELECT pairs.product_id, pairs.related_product_id,
+		LEAST(pairs.views::float8 / totals.views, 1) AS score,
+		pairs.views AS support,
+		ROW_NUMBER() OVER (
+			PARTITION BY pairs.product_id
+			ORDER BY pairs.views::float8 / totals.views DESC, pairs.views DESC
+		) AS rank
+	FROM (
+		SELECT product_id, related_product_id, SUM(views) AS views
+		FROM product_co_views
+		WHERE date >= @since
+		GROUP BY product_id, related_product_id
+		HAVING SUM(views) >= @min_views
+	) AS pairs
+	JOIN (
+		SELECT product_id, SUM(views) AS views
+		FROM product_view_stats
+		WHERE date >= @since
+		GROUP BY product_id
+	) AS totals ON totals.product_id = pairs.product_id
+) AS ranked
+WHERE rank <= @per_product`
+
+type recommendationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRecommendationRepository(db *gorm.DB) repository.RecommendationRepository {
+	return &recommendationRepositoryImpl{db: db}
+}
+
+func (r *recommendationRepositoryImpl) ReplaceAffinities(ctx context.Context, source string, affinities []*entity.ProductAffinity) error {
+	now := time.Now()
+	for _, affinity := range affinities {
+		affinity.Source = source
+		affinity.UpdatedAt = now
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("source = ?", source).Delete(&entity.ProductAffinity{}).Error; err != nil {
+			return err
+		}
+		if len(affinities) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(affinities, affinityBatchSize).Error
+	})
+}
+
+// GetRelatedProductIDs skips related products that have since been
+// deactivated or deleted, which is why affinities carry no foreign keys
+func (r *recommendationRepositoryImpl) GetRelatedProductIDs(ctx context.Context, productID uuid.UUID, source string, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&entity.ProductAffinity{}).
+		Joins("JOIN products ON products.id = product_affinities.related_product_id").
+		Where("product_affinities.product_id = ? AND product_affinities.source = ?", productID, source).
+		Where("products.is_active = ?", true).
+		Order("product_affinities.score DESC, product_affinities.support DESC").
+		Limit(limit).
+		Pluck("product_affinities.related_product_id", &ids).Error
+	return ids, err
+}
+
+func (r *recommendationRepositoryImpl) GetViewAffinities(ctx context.Context, since time.Time, minViews, perProduct int) ([]*entity.ProductAffinity, error) {
+	var affinities []*entity.ProductAffinity
+	err := r.db.WithContext(ctx).Raw(viewAffinityQuery, map[string]interface{}{
+		"since":       since,
+		"min_views":   minViews,
+		"per_product": perProduct,
+	}).Scan(&affinities).Error
+	return affinities, err
+}
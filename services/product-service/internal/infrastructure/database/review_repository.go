@@ -3,9 +3,11 @@ package database
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"solemate/services/product-service/internal/domain/entity"
 	"solemate/services/product-service/internal/domain/repository"
 )
@@ -19,7 +21,12 @@ func NewReviewRepository(db *gorm.DB) repository.ReviewRepository {
 }
 
 func (r *reviewRepository) Create(ctx context.Context, review *entity.Review) error {
-	return r.db.WithContext(ctx).Create(review).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+		return refreshProductRating(tx, review.ProductID)
+	})
 }
 
 func (r *reviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Review, error) {
@@ -86,12 +93,31 @@ func (r *reviewRepository) GetByProductID(ctx context.Context, productID uuid.UU
 	return reviews, total, nil
 }
 
+// Update leaves the vote counters alone: they are only moved by Vote, and a
+// review loaded before a vote would otherwise overwrite it
 func (r *reviewRepository) Update(ctx context.Context, review *entity.Review) error {
-	return r.db.WithContext(ctx).Save(review).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("helpful_count", "not_helpful_count").Save(review).Error; err != nil {
+			return err
+		}
+		return refreshProductRating(tx, review.ProductID)
+	})
 }
 
 func (r *reviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&entity.Review{}, "id = ?", id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var review entity.Review
+		if err := tx.Select("id", "product_id").Where("id = ?", id).First(&review).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("review not found")
+			}
+			return err
+		}
+		if err := tx.Delete(&entity.Review{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+		return refreshProductRating(tx, review.ProductID)
+	})
 }
 
 func (r *reviewRepository) GetUserReviewForProduct(ctx context.Context, userID, productID uuid.UUID) (*entity.Review, error) {
@@ -109,3 +135,162 @@ func (r *reviewRepository) GetUserReviewForProduct(ctx context.Context, userID,
 
 	return &review, nil
 }
+
+func (r *reviewRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Review, error) {
+	var reviews []*entity.Review
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// ReassignUser moves all of a user's reviews, votes and reports to
+// pseudonymID. It returns the number of reviews moved.
+func (r *reviewRepository) ReassignUser(ctx context.Context, userID, pseudonymID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Review{}).
+			Where("user_id = ?", userID).
+			Update("user_id", pseudonymID)
+		if result.Error != nil {
+			return result.Error
+		}
+		count = result.RowsAffected
+
+		for _, model := range []interface{}{&entity.ReviewVote{}, &entity.ReviewReport{}} {
+			if err := tx.Model(model).
+				Where("user_id = ?", userID).
+				UpdateColumn("user_id", pseudonymID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (r *reviewRepository) ListByStatus(ctx context.Context, status string, limit, offset int) ([]*entity.Review, int64, error) {
+	var reviews []*entity.Review
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.Review{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Product").
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reviews).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reviews, total, nil
+}
+
+func (r *reviewRepository) CountByContentHash(ctx context.Context, hash string, excludeID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.Review{}).
+		Where("content_hash = ? AND id <> ?", hash, excludeID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *reviewRepository) Vote(ctx context.Context, vote *entity.ReviewVote) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing entity.ReviewVote
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("review_id = ? AND user_id = ?", vote.ReviewID, vote.UserID).
+			First(&existing).Error
+
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			vote.ID = uuid.New()
+			vote.CreatedAt = time.Now()
+			vote.UpdatedAt = time.Now()
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(vote)
+			if result.Error != nil || result.RowsAffected == 0 {
+				// Nothing inserted: the same user's concurrent vote won
+				return result.Error
+			}
+			if vote.Helpful {
+				return shiftVoteCounts(tx, vote.ReviewID, 1, 0)
+			}
+			return shiftVoteCounts(tx, vote.ReviewID, 0, 1)
+		case err != nil:
+			return err
+		case existing.Helpful == vote.Helpful:
+			*vote = existing
+			return nil
+		}
+
+		if err := tx.Model(&existing).Updates(map[string]interface{}{
+			"helpful":    vote.Helpful,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		*vote = existing
+		if vote.Helpful {
+			return shiftVoteCounts(tx, vote.ReviewID, 1, -1)
+		}
+		return shiftVoteCounts(tx, vote.ReviewID, -1, 1)
+	})
+}
+
+// shiftVoteCounts adjusts a review's counters in SQL, so concurrent votes
+// on the same review can't lose each other's updates
+func shiftVoteCounts(tx *gorm.DB, reviewID uuid.UUID, helpful, notHelpful int) error {
+	return tx.Model(&entity.Review{}).
+		Where("id = ?", reviewID).
+		UpdateColumns(map[string]interface{}{
+			"helpful_count":     gorm.Expr("helpful_count + ?", helpful),
+			"not_helpful_count": gorm.Expr("not_helpful_count + ?", notHelpful),
+		}).Error
+}
+
+func (r *reviewRepository) Report(ctx context.Context, report *entity.ReviewReport) (int64, error) {
+	report.ID = uuid.New()
+	report.CreatedAt = time.Now()
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(report)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, errors.New("review already reported")
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.ReviewReport{}).
+		Joins("JOIN reviews ON reviews.id = review_reports.review_id").
+		Where("review_reports.review_id = ?", report.ReviewID).
+		Where("(reviews.moderated_at IS NULL OR review_reports.created_at > reviews.moderated_at)").
+		Count(&count).Error
+	return count, err
+}
+
+func (r *reviewRepository) HoldForModeration(ctx context.Context, id uuid.UUID, flag string) (bool, error) {
+	held := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var review entity.Review
+		result := tx.Model(&review).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "product_id"}}}).
+			Where("id = ? AND status = ?", id, entity.ReviewStatusApproved).
+			UpdateColumns(map[string]interface{}{
+				"status":          entity.ReviewStatusPending,
+				"screening_flags": gorm.Expr("array_append(array_remove(COALESCE(screening_flags, '{}'), ?), ?)", flag, flag),
+				"updated_at":      time.Now(),
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		held = true
+		return refreshProductRating(tx, review.ProductID)
+	})
+	return held, err
+}
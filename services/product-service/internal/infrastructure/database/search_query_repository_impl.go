@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+type searchQueryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSearchQueryRepository(db *gorm.DB) repository.SearchQueryRepository {
+	return &searchQueryRepositoryImpl{db: db}
+}
+
+func (r *searchQueryRepositoryImpl) Create(ctx context.Context, query *entity.SearchQuery) error {
+	query.ID = uuid.New()
+	query.CreatedAt = time.Now()
+	return r.db.WithContext(ctx).Create(query).Error
+}
+
+// ZeroResultQueries returns the searches since the given time that found no
+// products, most frequent first
+func (r *searchQueryRepositoryImpl) ZeroResultQueries(ctx context.Context, since time.Time, limit int) ([]*repository.ZeroResultQuery, error) {
+	var queries []*repository.ZeroResultQuery
+	err := r.db.WithContext(ctx).
+		Model(&entity.SearchQuery{}).
+		Select("query, COUNT(*) AS searches, MAX(created_at) AS last_searched_at").
+		Where("result_count = 0 AND created_at >= ?", since).
+		Group("query").
+		Order("searches DESC, last_searched_at DESC").
+		Limit(limit).
+		Scan(&queries).Error
+	return queries, err
+}
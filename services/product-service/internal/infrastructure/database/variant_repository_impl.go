@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+type variantRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductVariantRepository(db *gorm.DB) repository.ProductVariantRepository {
+	return &variantRepositoryImpl{db: db}
+}
+
+func (r *variantRepositoryImpl) Create(ctx context.Context, variant *entity.ProductVariant) error {
+	variant.ID = uuid.New()
+	variant.CreatedAt = time.Now()
+	variant.UpdatedAt = time.Now()
+
+	result := r.db.WithContext(ctx).Create(variant)
+	return result.Error
+}
+
+func (r *variantRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.ProductVariant, error) {
+	var variant entity.ProductVariant
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&variant)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("variant not found")
+		}
+		return nil, result.Error
+	}
+	return &variant, nil
+}
+
+func (r *variantRepositoryImpl) GetBySKU(ctx context.Context, sku string) (*entity.ProductVariant, error) {
+	var variant entity.ProductVariant
+	result := r.db.WithContext(ctx).Where("sku = ?", sku).First(&variant)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("variant not found")
+		}
+		return nil, result.Error
+	}
+	return &variant, nil
+}
+
+func (r *variantRepositoryImpl) GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entity.ProductVariant, error) {
+	var variants []*entity.ProductVariant
+	result := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("sort_order ASC, created_at ASC").
+		Find(&variants)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return variants, nil
+}
+
+func (r *variantRepositoryImpl) Update(ctx context.Context, variant *entity.ProductVariant) error {
+	variant.UpdatedAt = time.Now()
+	result := r.db.WithContext(ctx).Save(variant)
+	return result.Error
+}
+
+func (r *variantRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entity.ProductVariant{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("variant not found")
+	}
+	return nil
+}
+
+func (r *variantRepositoryImpl) UpdateStock(ctx context.Context, id uuid.UUID, quantity int) error {
+	result := r.db.WithContext(ctx).
+		Model(&entity.ProductVariant{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"stock":      quantity,
+			"updated_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("variant not found")
+	}
+	return nil
+}
+
+// Reorder sets each variant's sort order to its position in variantIDs
+func (r *variantRepositoryImpl) Reorder(ctx context.Context, productID uuid.UUID, variantIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range variantIDs {
+			result := tx.Model(&entity.ProductVariant{}).
+				Where("id = ? AND product_id = ?", id, productID).
+				Update("sort_order", i)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errors.New("variant not found")
+			}
+		}
+		return nil
+	})
+}
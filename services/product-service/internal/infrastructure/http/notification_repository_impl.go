@@ -0,0 +1,81 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"solemate/pkg/auth"
+	"solemate/services/product-service/internal/domain/entity"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+type notificationRepositoryImpl struct {
+	baseURL        string
+	httpClient     *http.Client
+	internalTokens *auth.InternalTokenManager
+}
+
+func NewNotificationRepository(baseURL string, internalTokens *auth.InternalTokenManager) repository.NotificationRepository {
+	return &notificationRepositoryImpl{
+		baseURL:        baseURL,
+		internalTokens: internalTokens,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// SendReviewDecision raises a review.approved or review.rejected event for
+// the author; notification-service picks the channel
+func (r *notificationRepositoryImpl) SendReviewDecision(ctx context.Context, review *entity.Review) error {
+	url := fmt.Sprintf("%s/api/v1/notifications/process-event", r.baseURL)
+
+	payload := map[string]interface{}{
+		"review_id":  review.ID,
+		"product_id": review.ProductID,
+		"status":     review.Status,
+		"reason":     review.ModerationReason,
+	}
+	if review.Product != nil {
+		payload["product_name"] = review.Product.Name
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"event_type":  "review." + strings.ToLower(review.Status),
+		"entity_id":   review.ID,
+		"entity_type": "review",
+		"user_id":     review.UserID,
+		"payload":     payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	token, err := r.internalTokens.MintForService(auth.ServiceNotification)
+	if err != nil {
+		return fmt.Errorf("failed to mint internal token: %w", err)
+	}
+	req.Header.Set(auth.InternalTokenHeader, token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
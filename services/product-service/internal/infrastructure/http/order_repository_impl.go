@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/pkg/auth"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+type orderRepositoryImpl struct {
+	baseURL        string
+	httpClient     *http.Client
+	internalTokens *auth.InternalTokenManager
+}
+
+func NewOrderRepository(baseURL string, internalTokens *auth.InternalTokenManager) repository.OrderRepository {
+	return &orderRepositoryImpl{
+		baseURL:        baseURL,
+		internalTokens: internalTokens,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetPurchases asks order-service on behalf of userID, so order-service only
+// ever looks at that user's orders
+func (r *orderRepositoryImpl) GetPurchases(ctx context.Context, userID, productID uuid.UUID) ([]repository.PurchasedOrder, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/orders/me/purchases?product_id=%s", r.baseURL, url.QueryEscape(productID.String()))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := r.internalTokens.MintForUser(auth.ServiceOrder, &auth.Claims{UserID: userID.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint internal token: %w", err)
+	}
+	req.Header.Set(auth.InternalTokenHeader, token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Data []repository.PurchasedOrder `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return response.Data, nil
+}
+
+// GetCoPurchases reads order-service's analytics as product-service itself
+func (r *orderRepositoryImpl) GetCoPurchases(ctx context.Context, since, until time.Time, minOrders, limit int) ([]repository.ProductPair, error) {
+	query := url.Values{}
+	query.Set("start_date", since.Format("2006-01-02"))
+	query.Set("end_date", until.Format("2006-01-02"))
+	query.Set("min_orders", strconv.Itoa(minOrders))
+	query.Set("limit", strconv.Itoa(limit))
+	endpoint := fmt.Sprintf("%s/api/v1/orders/admin/co-purchases?%s", r.baseURL, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := r.internalTokens.MintForService(auth.ServiceOrder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint internal token: %w", err)
+	}
+	req.Header.Set(auth.InternalTokenHeader, token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Data []repository.ProductPair `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return response.Data, nil
+}
+
+// GetTopProducts reads order-service's analytics as product-service itself
+func (r *orderRepositoryImpl) GetTopProducts(ctx context.Context, since, until time.Time, limit int) ([]repository.ProductSales, error) {
+	query := url.Values{}
+	query.Set("start_date", since.Format("2006-01-02"))
+	query.Set("end_date", until.Format("2006-01-02"))
+	query.Set("limit", strconv.Itoa(limit))
+	endpoint := fmt.Sprintf("%s/api/v1/orders/admin/top-products?%s", r.baseURL, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := r.internalTokens.MintForService(auth.ServiceOrder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint internal token: %w", err)
+	}
+	req.Header.Set(auth.InternalTokenHeader, token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Data []repository.ProductSales `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return response.Data, nil
+}
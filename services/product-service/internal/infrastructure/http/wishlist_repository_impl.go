@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"solemate/pkg/auth"
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+type wishlistRepositoryImpl struct {
+	baseURL        string
+	httpClient     *http.Client
+	internalTokens *auth.InternalTokenManager
+}
+
+func NewWishlistRepository(baseURL string, internalTokens *auth.InternalTokenManager) repository.WishlistRepository {
+	return &wishlistRepositoryImpl{
+		baseURL:        baseURL,
+		internalTokens: internalTokens,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetWishlistAdds reads user-service's analytics as product-service itself.
+// since and until are taken as days; user-service counts the whole end day,
+// so the day before until is sent.
+func (r *wishlistRepositoryImpl) GetWishlistAdds(ctx context.Context, since, until time.Time) ([]repository.ProductWishlistAdds, error) {
+	query := url.Values{}
+	query.Set("start_date", since.Format("2006-01-02"))
+	query.Set("end_date", until.AddDate(0, 0, -1).Format("2006-01-02"))
+	endpoint := fmt.Sprintf("%s/api/v1/admin/analytics/wishlist-adds?%s", r.baseURL, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := r.internalTokens.MintForService(auth.ServiceUser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint internal token: %w", err)
+	}
+	req.Header.Set(auth.InternalTokenHeader, token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Data []repository.ProductWishlistAdds `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return response.Data, nil
+}
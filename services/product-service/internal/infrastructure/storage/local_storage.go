@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"solemate/services/product-service/internal/domain/repository"
+)
+
+// LocalMediaPath is the route the service serves the local storage directory from
+const LocalMediaPath = "/media"
+
+type localStorage struct {
+	root      string
+	publicURL string
+}
+
+// NewLocalStorage stores files under root. publicURL is the address root is
+// served from, e.g. http://localhost:8000/media.
+func NewLocalStorage(root, publicURL string) repository.ImageStorage {
+	return &localStorage{
+		root:      root,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+func (s *localStorage) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return s.publicURL + "/" + key, nil
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	// Drop the upload's directory once its last rendition is gone
+	_ = os.Remove(filepath.Dir(filePath))
+	return nil
+}
+
+func (s *localStorage) KeyForURL(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, s.publicURL+"/")
+	if !ok || key == "" {
+		return "", false
+	}
+	return key, true
+}
+
+// filePath maps key to a path inside root, refusing keys that would escape it
+func (s *localStorage) filePath(key string) (string, error) {
+	if key == "" || path.IsAbs(key) || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -1,17 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/joho/godotenv"
 	"solemate/pkg/auth"
+	"solemate/pkg/cache"
 	"solemate/pkg/database"
+	"solemate/pkg/privacy"
 	"solemate/services/user-service/internal/config"
 	"solemate/services/user-service/internal/domain/entity"
 	"solemate/services/user-service/internal/domain/service"
 	httpHandler "solemate/services/user-service/internal/handler/http"
 	dbImpl "solemate/services/user-service/internal/infrastructure/database"
+	httpImpl "solemate/services/user-service/internal/infrastructure/http"
 )
 
 func main() {
@@ -39,7 +43,7 @@ func main() {
 	}
 
 	// Auto-migrate database schema
-	if err := db.AutoMigrate(&entity.User{}, &entity.Address{}, &entity.WishlistItem{}); err != nil {
+	if err := db.AutoMigrate(&entity.User{}, &entity.Address{}, &entity.Wishlist{}, &entity.WishlistItem{}, &entity.WishlistAlert{}, &entity.RolePermission{}, &entity.PrivacyRequest{}); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
@@ -47,20 +51,63 @@ func main() {
 	userRepo := dbImpl.NewUserRepository(db)
 	addressRepo := dbImpl.NewAddressRepository(db)
 	wishlistRepo := dbImpl.NewWishlistRepository(db)
+	roleRepo := dbImpl.NewRoleRepository(db)
+	privacyRepo := dbImpl.NewPrivacyRequestRepository(db)
 
-	// Initialize JWT manager
+	// Initialize JWT manager and internal token verifier
 	jwtManager := auth.NewJWTManager()
+	internalTokens, err := auth.NewInternalTokenManager(auth.ServiceUser)
+	if err != nil {
+		log.Fatalf("Failed to initialize internal tokens: %v", err)
+	}
+
+	// Session revocations shared with the gateway, which refuses the access
+	// tokens of revoked sessions. Without Redis, revoked access tokens stay
+	// valid at the gateway until they expire and access tokens are only
+	// accepted through the gateway.
+	var revocations *auth.SessionRevocations
+	if redisClient, err := cache.NewRedisClient(cache.GetConfigFromEnv()); err != nil {
+		log.Printf("Redis unavailable, access tokens of revoked sessions stay valid until they expire: %v", err)
+	} else {
+		revocations = auth.NewSessionRevocations(redisClient)
+	}
+
+	// Cart-service client used to move wishlist items to the cart
+	cartRepo := httpImpl.NewCartRepository(cfg.External.CartServiceURL, internalTokens)
+
+	// Notification-service client used for price-drop and restock alerts
+	notificationRepo := httpImpl.NewNotificationRepository(cfg.External.NotificationServiceURL, internalTokens)
 
 	// Initialize services
-	userService := service.NewUserService(userRepo, addressRepo, jwtManager)
-	wishlistService := service.NewWishlistService(wishlistRepo)
+	userService := service.NewUserService(userRepo, addressRepo, roleRepo, jwtManager, revocations)
+	wishlistService := service.NewWishlistService(wishlistRepo, cartRepo, notificationRepo)
+	roleService := service.NewRoleService(roleRepo)
+
+	// Services that hold personal data, reached for export and erasure. A
+	// service that cannot be reached fails the request, which can be retried
+	// once it is back.
+	privacyClients := []*privacy.Client{
+		privacy.NewClient(auth.ServiceProduct, cfg.External.ProductServiceURL, internalTokens),
+		privacy.NewClient(auth.ServiceCart, cfg.External.CartServiceURL, internalTokens),
+		privacy.NewClient(auth.ServiceOrder, cfg.External.OrderServiceURL, internalTokens),
+		privacy.NewClient(auth.ServicePayment, cfg.External.PaymentServiceURL, internalTokens),
+		privacy.NewClient(auth.ServiceNotification, cfg.External.NotificationServiceURL, internalTokens),
+	}
+	privacyService := service.NewPrivacyService(userRepo, addressRepo, wishlistRepo, privacyRepo, privacyClients, revocations)
+
+	// Seed the default role→permission mapping
+	if err := roleService.SeedDefaultRoles(context.Background()); err != nil {
+		log.Fatalf("Failed to seed role permissions: %v", err)
+	}
 
 	// Initialize handlers
 	userHandler := httpHandler.NewUserHandler(userService)
 	wishlistHandler := httpHandler.NewWishlistHandler(wishlistService)
+	roleHandler := httpHandler.NewRoleHandler(roleService)
+	privacyHandler := httpHandler.NewPrivacyHandler(privacyService)
 
 	// Setup routes
-	router := httpHandler.SetupRoutes(userHandler, wishlistHandler, jwtManager)
+	router := httpHandler.SetupRoutes(userHandler, wishlistHandler, roleHandler, privacyHandler, jwtManager, internalTokens, revocations)
 
 	// Start server
 	serverAddr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
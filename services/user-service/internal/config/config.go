@@ -10,6 +10,7 @@ type Config struct {
 	Database DatabaseConfig
 	Redis    RedisConfig
 	JWT      JWTConfig
+	External ExternalConfig
 }
 
 type ServerConfig struct {
@@ -39,6 +40,18 @@ type JWTConfig struct {
 	RefreshSecret string
 }
 
+// ExternalConfig holds the URLs of the services user-service calls. Payment
+// and notification services are optional and have no default: when their URL
+// is empty they are left out of privacy requests, and wishlist alerts cannot
+// be delivered.
+type ExternalConfig struct {
+	ProductServiceURL      string
+	CartServiceURL         string
+	OrderServiceURL        string
+	PaymentServiceURL      string
+	NotificationServiceURL string
+}
+
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
@@ -64,6 +77,13 @@ func Load() *Config {
 			AccessSecret:  getEnv("JWT_ACCESS_SECRET", "default-access-secret"),
 			RefreshSecret: getEnv("JWT_REFRESH_SECRET", "default-refresh-secret"),
 		},
+		External: ExternalConfig{
+			ProductServiceURL:      getEnv("PRODUCT_SERVICE_URL", "http://localhost:8081"),
+			CartServiceURL:         getEnv("CART_SERVICE_URL", "http://localhost:8083"),
+			OrderServiceURL:        getEnv("ORDER_SERVICE_URL", "http://localhost:8084"),
+			PaymentServiceURL:      getEnv("PAYMENT_SERVICE_URL", "http://localhost:8085"),
+			NotificationServiceURL: getEnv("NOTIFICATION_SERVICE_URL", "http://localhost:8086"),
+		},
 	}
 }
 
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/pkg/privacy"
+)
+
+const (
+	PrivacyRequestExport  = "export"
+	PrivacyRequestErasure = "erasure"
+)
+
+const (
+	PrivacyStatusPending    = "pending"
+	PrivacyStatusProcessing = "processing"
+	PrivacyStatusCompleted  = "completed"
+	PrivacyStatusFailed     = "failed"
+)
+
+// PrivacyRequest tracks a data export or erasure across all services. Rows are
+// never deleted and serve as the audit trail for GDPR requests.
+type PrivacyRequest struct {
+	ID          uuid.UUID                        `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID      uuid.UUID                        `json:"user_id" gorm:"type:uuid;not null;index"`
+	RequestedBy uuid.UUID                        `json:"requested_by" gorm:"type:uuid;not null"`
+	Type        string                           `json:"type" gorm:"size:20;not null"`
+	Status      string                           `json:"status" gorm:"size:20;not null;default:pending"`
+	Results     map[string]*PrivacyServiceResult `json:"results,omitempty" gorm:"type:jsonb;serializer:json"`
+	Error       string                           `json:"error,omitempty" gorm:"type:text"`
+	Archive     []byte                           `json:"-" gorm:"type:bytea"`
+	ExpiresAt   *time.Time                       `json:"expires_at,omitempty"`
+	CreatedAt   time.Time                        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time                        `json:"updated_at" gorm:"autoUpdateTime"`
+	CompletedAt *time.Time                       `json:"completed_at,omitempty"`
+}
+
+// PrivacyServiceResult is the outcome of a privacy request in one service
+type PrivacyServiceResult struct {
+	Status string                 `json:"status"`
+	Error  string                 `json:"error,omitempty"`
+	Report *privacy.ErasureReport `json:"report,omitempty"`
+}
+
+func (PrivacyRequest) TableName() string {
+	return "privacy_requests"
+}
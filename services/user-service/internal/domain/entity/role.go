@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+)
+
+// RolePermission grants a single permission to a role
+type RolePermission struct {
+	Role       string    `json:"role" gorm:"primaryKey;size:50"`
+	Permission string    `json:"permission" gorm:"primaryKey;size:100"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
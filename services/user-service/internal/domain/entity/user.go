@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type User struct {
@@ -19,6 +20,10 @@ type User struct {
 	LastLoginAt   *time.Time `json:"last_login_at"`
 	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// SessionsRevokedAt invalidates refresh tokens issued before it
+	SessionsRevokedAt *time.Time     `json:"-"`
+	DeletedAt         gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
 type Address struct {
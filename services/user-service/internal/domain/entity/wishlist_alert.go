@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WishlistAlert records the last price-drop or back-in-stock alert a user got
+// for a product, so repeated changes within the cooldown don't notify again.
+type WishlistAlert struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_wishlist_alert"`
+	ProductID  uuid.UUID `json:"product_id" gorm:"type:uuid;not null;uniqueIndex:idx_wishlist_alert"`
+	Type       string    `json:"type" gorm:"not null;uniqueIndex:idx_wishlist_alert"`
+	Price      *float64  `json:"price,omitempty"` // price announced by the last price-drop alert
+	NotifiedAt time.Time `json:"notified_at"`
+}
+
+func (WishlistAlert) TableName() string {
+	return "wishlist_alerts"
+}
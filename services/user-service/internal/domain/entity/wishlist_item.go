@@ -20,12 +20,34 @@ type Product struct {
 	IsActive    bool      `json:"is_active"`
 }
 
-// WishlistItem represents a product saved to user's wishlist
+const (
+	WishlistPrivate = "private"
+	WishlistShared  = "shared"
+)
+
+// Wishlist is a named list of saved products. Every user has one default list,
+// which backs the single-list /wishlist endpoints.
+type Wishlist struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_wishlists_user_default,where:is_default"`
+	Name       string    `json:"name" gorm:"not null"`
+	Visibility string    `json:"visibility" gorm:"default:private"`
+	ShareSlug  string    `json:"share_slug" gorm:"uniqueIndex;not null"`
+	IsDefault  bool      `json:"is_default" gorm:"default:false"`
+	ItemCount  int64     `json:"item_count" gorm:"-"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Items []*WishlistItem `json:"items,omitempty" gorm:"foreignKey:WishlistID"`
+}
+
+// WishlistItem represents a product saved to one of the user's wishlists
 type WishlistItem struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
-	ProductID uuid.UUID `json:"product_id" gorm:"type:uuid;not null"`
-	AddedAt   time.Time `json:"added_at" gorm:"autoCreateTime"`
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	WishlistID uuid.UUID `json:"wishlist_id" gorm:"type:uuid;index;uniqueIndex:idx_wishlist_items_wishlist_product"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	ProductID  uuid.UUID `json:"product_id" gorm:"type:uuid;not null;uniqueIndex:idx_wishlist_items_wishlist_product"`
+	AddedAt    time.Time `json:"added_at" gorm:"autoCreateTime"`
 
 	// Product relationship - eagerly loaded when fetching wishlist
 	Product *Product `json:"product,omitempty" gorm:"foreignKey:ProductID;references:ID"`
@@ -35,6 +57,10 @@ func (Product) TableName() string {
 	return "products"
 }
 
+func (Wishlist) TableName() string {
+	return "wishlists"
+}
+
 func (WishlistItem) TableName() string {
 	return "wishlist_items"
 }
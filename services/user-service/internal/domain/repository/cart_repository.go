@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCartOutOfStock         = errors.New("insufficient stock for requested quantity")
+	ErrCartProductUnavailable = errors.New("product is not available")
+)
+
+type CartRepository interface {
+	// AddItem adds a product to the user's cart in cart-service, which validates
+	// the product and stock. Rejections are reported as ErrCartOutOfStock or
+	// ErrCartProductUnavailable.
+	AddItem(ctx context.Context, userID, productID uuid.UUID, variantID *uuid.UUID, quantity int) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"solemate/pkg/productalerts"
+)
+
+type NotificationRepository interface {
+	// SendProductAlert asks notification-service to tell the user about a
+	// wishlisted product's price drop or restock. It reports false when the
+	// user's notification preferences ruled the alert out.
+	SendProductAlert(ctx context.Context, userID uuid.UUID, event *productalerts.Event) (bool, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"solemate/services/user-service/internal/domain/entity"
+)
+
+type PrivacyRequestRepository interface {
+	// Create stores a new privacy request
+	Create(ctx context.Context, request *entity.PrivacyRequest) error
+
+	// GetByID retrieves a privacy request including its export archive
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.PrivacyRequest, error)
+
+	// Update saves status, results and archive changes
+	Update(ctx context.Context, request *entity.PrivacyRequest) error
+
+	// ListByUserID returns a user's privacy requests, newest first, without archives
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PrivacyRequest, error)
+
+	// GetActive returns the user's pending or processing request of the given type, if any
+	GetActive(ctx context.Context, userID uuid.UUID, requestType string) (*entity.PrivacyRequest, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+)
+
+type RoleRepository interface {
+	// GetPermissions returns the permissions granted to a role
+	GetPermissions(ctx context.Context, role string) ([]string, error)
+
+	// ListRoles returns every role with its granted permissions
+	ListRoles(ctx context.Context) (map[string][]string, error)
+
+	// SetPermissions replaces the permissions granted to a role
+	SetPermissions(ctx context.Context, role string, permissions []string) error
+
+	// SeedDefaults inserts the default mapping for roles that have no rows yet
+	SeedDefaults(ctx context.Context, defaults map[string][]string) error
+}
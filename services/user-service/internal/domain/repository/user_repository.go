@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"solemate/services/user-service/internal/domain/entity"
@@ -13,8 +14,11 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, limit, offset int) ([]*entity.User, int64, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	EmailExists(ctx context.Context, email string) (bool, error)
+	List(ctx context.Context, filters UserFilters) ([]*entity.User, int64, error)
 	UpdateLastLogin(ctx context.Context, id uuid.UUID) error
+	GetUserStatistics(ctx context.Context, startDate, endDate time.Time) (*UserStatistics, error)
 }
 
 type AddressRepository interface {
@@ -24,4 +28,39 @@ type AddressRepository interface {
 	Update(ctx context.Context, address *entity.Address) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	SetDefault(ctx context.Context, userID, addressID uuid.UUID) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+// UserFilters represents filters for admin user queries
+type UserFilters struct {
+	Search         string     `json:"search"` // matches email, first or last name
+	Email          string     `json:"email"`
+	Name           string     `json:"name"`
+	Role           string     `json:"role"`
+	IsActive       *bool      `json:"is_active"`
+	CreatedFrom    *time.Time `json:"created_from"`
+	CreatedTo      *time.Time `json:"created_to"`
+	IncludeDeleted bool       `json:"include_deleted"`
+	OnlyDeleted    bool       `json:"only_deleted"`
+	SortBy         string     `json:"sort_by"`    // created_at, email, last_name, last_login_at
+	SortOrder      string     `json:"sort_order"` // asc, desc
+	Limit          int        `json:"limit"`
+	Offset         int        `json:"offset"`
+}
+
+type UserStatistics struct {
+	TotalUsers         int64            `json:"total_users"`
+	ActiveUsers        int64            `json:"active_users"`
+	InactiveUsers      int64            `json:"inactive_users"`
+	DeletedUsers       int64            `json:"deleted_users"`
+	VerifiedUsers      int64            `json:"verified_users"`
+	RoleBreakdown      map[string]int64 `json:"role_breakdown"`
+	NewUsers           int64            `json:"new_users"`
+	LoggedInUsers      int64            `json:"logged_in_users"`
+	RegistrationsByDay []DailyUserStats `json:"registrations_by_day"`
+}
+
+type DailyUserStats struct {
+	Date          time.Time `json:"date"`
+	Registrations int64     `json:"registrations"`
 }
@@ -2,27 +2,85 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"solemate/services/user-service/internal/domain/entity"
 )
 
+// ProductWishlistAdds counts the users who saved a product to a wishlist
+type ProductWishlistAdds struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Adds      int       `json:"adds"`
+}
+
+// ErrWishlistNotFound is returned for a wishlist that does not exist
+var ErrWishlistNotFound = errors.New("wishlist not found")
+
 type WishlistRepository interface {
-	// GetByUserID retrieves all wishlist items for a user with product details
+	// CreateList creates a named wishlist
+	CreateList(ctx context.Context, wishlist *entity.Wishlist) error
+
+	// CreateDefaultList creates the user's default wishlist unless they
+	// already have one, and reports whether it was created
+	CreateDefaultList(ctx context.Context, wishlist *entity.Wishlist) (bool, error)
+
+	// GetList retrieves a wishlist by ID
+	GetList(ctx context.Context, id uuid.UUID) (*entity.Wishlist, error)
+
+	// GetListBySlug retrieves a wishlist by its share slug
+	GetListBySlug(ctx context.Context, slug string) (*entity.Wishlist, error)
+
+	// GetDefaultList retrieves the user's default wishlist
+	GetDefaultList(ctx context.Context, userID uuid.UUID) (*entity.Wishlist, error)
+
+	// GetListsByUserID retrieves all of the user's wishlists with item counts
+	GetListsByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Wishlist, error)
+
+	// UpdateList saves a wishlist's name and visibility
+	UpdateList(ctx context.Context, wishlist *entity.Wishlist) error
+
+	// DeleteList removes a wishlist and its items
+	DeleteList(ctx context.Context, id uuid.UUID) error
+
+	// AssignOrphanItems moves items saved before named wishlists existed into wishlistID
+	AssignOrphanItems(ctx context.Context, userID, wishlistID uuid.UUID) error
+
+	// GetByUserID retrieves all wishlist items for a user across all lists
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WishlistItem, error)
 
-	// AddItem adds a product to user's wishlist
-	AddItem(ctx context.Context, userID, productID uuid.UUID) (*entity.WishlistItem, error)
+	// GetItems retrieves the items of a wishlist with product details
+	GetItems(ctx context.Context, wishlistID uuid.UUID) ([]*entity.WishlistItem, error)
 
-	// RemoveItem removes a product from user's wishlist by product ID
-	RemoveItem(ctx context.Context, userID, productID uuid.UUID) error
+	// AddItem adds a product to a wishlist
+	AddItem(ctx context.Context, wishlistID, userID, productID uuid.UUID) (*entity.WishlistItem, error)
 
-	// ClearWishlist removes all items from user's wishlist
-	ClearWishlist(ctx context.Context, userID uuid.UUID) error
+	// RemoveItem removes a product from a wishlist by product ID
+	RemoveItem(ctx context.Context, wishlistID, productID uuid.UUID) error
 
-	// ItemExists checks if a product is already in user's wishlist
-	ItemExists(ctx context.Context, userID, productID uuid.UUID) (bool, error)
+	// ClearWishlist removes all items from a wishlist
+	ClearWishlist(ctx context.Context, wishlistID uuid.UUID) error
+
+	// ItemExists checks if a product is already in a wishlist
+	ItemExists(ctx context.Context, wishlistID, productID uuid.UUID) (bool, error)
 
 	// GetItemByProductID retrieves a specific wishlist item by product ID
-	GetItemByProductID(ctx context.Context, userID, productID uuid.UUID) (*entity.WishlistItem, error)
+	GetItemByProductID(ctx context.Context, wishlistID, productID uuid.UUID) (*entity.WishlistItem, error)
+
+	// GetUserIDsByProductID retrieves the users who have the product in any of their wishlists
+	GetUserIDsByProductID(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error)
+
+	// CountAddsByProduct counts, per product, the users who saved it between
+	// startDate and endDate and still have it in a wishlist
+	CountAddsByProduct(ctx context.Context, startDate, endDate time.Time) ([]*ProductWishlistAdds, error)
+
+	// GetAlert retrieves the last alert of the given type sent to the user for a product
+	GetAlert(ctx context.Context, userID, productID uuid.UUID, alertType string) (*entity.WishlistAlert, error)
+
+	// SaveAlert creates or updates the user's alert record for a product and type
+	SaveAlert(ctx context.Context, alert *entity.WishlistAlert) error
+
+	// DeleteByUserID removes all of the user's wishlists, items and alert records
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) (items int64, lists int64, err error)
 }
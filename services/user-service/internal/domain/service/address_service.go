@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"solemate/pkg/utils"
+	"solemate/services/user-service/internal/domain/entity"
+)
+
+const (
+	AddressTypeShipping = "shipping"
+	AddressTypeBilling  = "billing"
+)
+
+var ErrAddressNotFound = errors.New("address not found")
+
+type AddressRequest struct {
+	Type       string `json:"type"`
+	Name       string `json:"name" binding:"required"`
+	Street1    string `json:"street_1" binding:"required"`
+	Street2    string `json:"street_2"`
+	City       string `json:"city" binding:"required"`
+	State      string `json:"state" binding:"required"`
+	PostalCode string `json:"postal_code" binding:"required"`
+	Country    string `json:"country" binding:"required"`
+	Phone      string `json:"phone"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+func (s *UserService) ListAddresses(ctx context.Context, userID uuid.UUID) ([]*entity.Address, error) {
+	return s.addressRepo.GetByUserID(ctx, userID)
+}
+
+// GetAddress returns the address only if it belongs to userID.
+func (s *UserService) GetAddress(ctx context.Context, userID, addressID uuid.UUID) (*entity.Address, error) {
+	address, err := s.addressRepo.GetByID(ctx, addressID)
+	if err != nil {
+		return nil, ErrAddressNotFound
+	}
+	if address.UserID != userID {
+		return nil, ErrAddressNotFound
+	}
+	return address, nil
+}
+
+func (s *UserService) CreateAddress(ctx context.Context, userID uuid.UUID, req *AddressRequest) (*entity.Address, error) {
+	address := &entity.Address{UserID: userID}
+	if err := applyAddressRequest(address, req); err != nil {
+		return nil, err
+	}
+
+	// The first address of a type becomes the default for that type
+	existing, err := s.addressRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load addresses: %w", err)
+	}
+	makeDefault := req.IsDefault || !hasAddressOfType(existing, address.Type)
+	address.IsDefault = false
+
+	if err := s.addressRepo.Create(ctx, address); err != nil {
+		return nil, fmt.Errorf("failed to create address: %w", err)
+	}
+
+	if makeDefault {
+		if err := s.addressRepo.SetDefault(ctx, userID, address.ID); err != nil {
+			return nil, fmt.Errorf("failed to set default address: %w", err)
+		}
+		address.IsDefault = true
+	}
+
+	return address, nil
+}
+
+func (s *UserService) UpdateAddress(ctx context.Context, userID, addressID uuid.UUID, req *AddressRequest) (*entity.Address, error) {
+	address, err := s.GetAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousType := address.Type
+	wasDefault := address.IsDefault
+	if err := applyAddressRequest(address, req); err != nil {
+		return nil, err
+	}
+
+	// Changing the type drops the default flag; it was the default of the old type
+	if address.Type != previousType {
+		address.IsDefault = false
+	} else {
+		address.IsDefault = wasDefault
+	}
+
+	if err := s.addressRepo.Update(ctx, address); err != nil {
+		return nil, fmt.Errorf("failed to update address: %w", err)
+	}
+
+	if req.IsDefault && !address.IsDefault {
+		if err := s.addressRepo.SetDefault(ctx, userID, address.ID); err != nil {
+			return nil, fmt.Errorf("failed to set default address: %w", err)
+		}
+		address.IsDefault = true
+	}
+
+	return address, nil
+}
+
+// DeleteAddress removes the address. Deleting a default address promotes the
+// most recent remaining address of the same type to default.
+func (s *UserService) DeleteAddress(ctx context.Context, userID, addressID uuid.UUID) error {
+	address, err := s.GetAddress(ctx, userID, addressID)
+	if err != nil {
+		return err
+	}
+	if err := s.addressRepo.Delete(ctx, addressID); err != nil {
+		return err
+	}
+	if !address.IsDefault {
+		return nil
+	}
+
+	remaining, err := s.addressRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load addresses: %w", err)
+	}
+	if next := mostRecentAddressOfType(remaining, address.Type); next != nil {
+		if err := s.addressRepo.SetDefault(ctx, userID, next.ID); err != nil {
+			return fmt.Errorf("failed to set default address: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetDefaultAddress makes the address the default for its type (shipping or billing).
+func (s *UserService) SetDefaultAddress(ctx context.Context, userID, addressID uuid.UUID) (*entity.Address, error) {
+	address, err := s.GetAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.addressRepo.SetDefault(ctx, userID, addressID); err != nil {
+		return nil, fmt.Errorf("failed to set default address: %w", err)
+	}
+	address.IsDefault = true
+
+	return address, nil
+}
+
+func applyAddressRequest(address *entity.Address, req *AddressRequest) error {
+	addressType := strings.ToLower(utils.SanitizeString(req.Type))
+	if addressType == "" {
+		addressType = AddressTypeShipping
+	}
+	if addressType != AddressTypeShipping && addressType != AddressTypeBilling {
+		return errors.New("address type must be shipping or billing")
+	}
+
+	country := strings.ToUpper(utils.SanitizeString(req.Country))
+	if !utils.IsValidCountryCode(country) {
+		return errors.New("country must be a two-letter ISO country code")
+	}
+
+	postalCode := strings.ToUpper(utils.SanitizeString(req.PostalCode))
+	if !utils.IsValidPostalCode(country, postalCode) {
+		return fmt.Errorf("invalid postal code for country %s", country)
+	}
+
+	if req.Phone != "" && !utils.IsValidPhoneNumber(req.Phone) {
+		return errors.New("invalid phone number format")
+	}
+
+	address.Type = addressType
+	address.Name = utils.SanitizeString(req.Name)
+	address.Street1 = utils.SanitizeString(req.Street1)
+	address.Street2 = utils.SanitizeString(req.Street2)
+	address.City = utils.SanitizeString(req.City)
+	address.State = utils.SanitizeString(req.State)
+	address.PostalCode = postalCode
+	address.Country = country
+	address.Phone = utils.SanitizeString(req.Phone)
+
+	return nil
+}
+
+func hasAddressOfType(addresses []*entity.Address, addressType string) bool {
+	for _, address := range addresses {
+		if address.Type == addressType {
+			return true
+		}
+	}
+	return false
+}
+
+func mostRecentAddressOfType(addresses []*entity.Address, addressType string) *entity.Address {
+	var latest *entity.Address
+	for _, address := range addresses {
+		if address.Type != addressType {
+			continue
+		}
+		if latest == nil || address.CreatedAt.After(latest.CreatedAt) {
+			latest = address
+		}
+	}
+	return latest
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"solemate/services/user-service/internal/domain/entity"
+)
+
+func TestUserService_DeleteAddress(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now()
+
+	t.Run("deleting the default promotes the most recent address of the same type", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		deleted := &entity.Address{ID: uuid.New(), UserID: userID, Type: AddressTypeShipping, IsDefault: true}
+		older := &entity.Address{ID: uuid.New(), UserID: userID, Type: AddressTypeShipping, CreatedAt: now.Add(-48 * time.Hour)}
+		newer := &entity.Address{ID: uuid.New(), UserID: userID, Type: AddressTypeShipping, CreatedAt: now.Add(-time.Hour)}
+		billing := &entity.Address{ID: uuid.New(), UserID: userID, Type: AddressTypeBilling, CreatedAt: now}
+
+		mocks.addresses.On("GetByID", ctx, deleted.ID).Return(deleted, nil)
+		mocks.addresses.On("Delete", ctx, deleted.ID).Return(nil)
+		mocks.addresses.On("GetByUserID", ctx, userID).Return([]*entity.Address{billing, older, newer}, nil)
+		mocks.addresses.On("SetDefault", ctx, userID, newer.ID).Return(nil)
+
+		require.NoError(t, service.DeleteAddress(ctx, userID, deleted.ID))
+
+		mocks.addresses.AssertExpectations(t)
+	})
+
+	t.Run("deleting the last default of a type leaves no default", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		deleted := &entity.Address{ID: uuid.New(), UserID: userID, Type: AddressTypeBilling, IsDefault: true}
+		shipping := &entity.Address{ID: uuid.New(), UserID: userID, Type: AddressTypeShipping, CreatedAt: now}
+
+		mocks.addresses.On("GetByID", ctx, deleted.ID).Return(deleted, nil)
+		mocks.addresses.On("Delete", ctx, deleted.ID).Return(nil)
+		mocks.addresses.On("GetByUserID", ctx, userID).Return([]*entity.Address{shipping}, nil)
+
+		require.NoError(t, service.DeleteAddress(ctx, userID, deleted.ID))
+
+		mocks.addresses.AssertNotCalled(t, "SetDefault", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("deleting a non-default address keeps the default", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		deleted := &entity.Address{ID: uuid.New(), UserID: userID, Type: AddressTypeShipping}
+
+		mocks.addresses.On("GetByID", ctx, deleted.ID).Return(deleted, nil)
+		mocks.addresses.On("Delete", ctx, deleted.ID).Return(nil)
+
+		require.NoError(t, service.DeleteAddress(ctx, userID, deleted.ID))
+
+		mocks.addresses.AssertNotCalled(t, "GetByUserID", mock.Anything, mock.Anything)
+		mocks.addresses.AssertNotCalled(t, "SetDefault", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("address of another user", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		addressID := uuid.New()
+		mocks.addresses.On("GetByID", ctx, addressID).Return(&entity.Address{ID: addressID, UserID: uuid.New(), IsDefault: true}, nil)
+
+		err := service.DeleteAddress(ctx, userID, addressID)
+
+		assert.ErrorIs(t, err, ErrAddressNotFound)
+		mocks.addresses.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed delete does not promote", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		deleted := &entity.Address{ID: uuid.New(), UserID: userID, Type: AddressTypeShipping, IsDefault: true}
+
+		mocks.addresses.On("GetByID", ctx, deleted.ID).Return(deleted, nil)
+		mocks.addresses.On("Delete", ctx, deleted.ID).Return(errors.New("database unavailable"))
+
+		err := service.DeleteAddress(ctx, userID, deleted.ID)
+
+		assert.EqualError(t, err, "database unavailable")
+		mocks.addresses.AssertNotCalled(t, "SetDefault", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
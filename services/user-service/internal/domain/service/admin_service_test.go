@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"solemate/pkg/auth"
+	"solemate/pkg/cache"
+	"solemate/services/user-service/internal/domain/entity"
+	"solemate/services/user-service/internal/domain/repository"
+)
+
+// newTestUserServiceWithRevocations returns a user service whose session
+// revocations are stored in an in-memory Redis
+func newTestUserServiceWithRevocations(t *testing.T) (*UserService, *userServiceMocks, *auth.SessionRevocations) {
+	server := miniredis.RunT(t)
+	redisClient, err := cache.NewRedisClient(cache.Config{Host: server.Host(), Port: server.Port()})
+	require.NoError(t, err)
+	revocations := auth.NewSessionRevocations(redisClient)
+
+	_, mocks := newTestUserService()
+	service := NewUserService(mocks.users, mocks.addresses, mocks.roles, auth.NewJWTManager(), revocations)
+	return service, mocks, revocations
+}
+
+// accessTokenIssued returns claims of an access token issued at the given time
+func accessTokenIssued(userID uuid.UUID, at time.Time) *auth.Claims {
+	return &auth.Claims{
+		UserID:           userID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(at)},
+	}
+}
+
+func TestUserService_ListUsers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("search is trimmed and paging is applied", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		active := true
+		users := []*entity.User{{ID: uuid.New(), Email: "jane@example.com"}}
+
+		mocks.users.On("List", ctx, repository.UserFilters{
+			Search:    "jane",
+			Role:      "manager",
+			IsActive:  &active,
+			SortBy:    "email",
+			SortOrder: "asc",
+			Limit:     20,
+			Offset:    40,
+		}).Return(users, int64(41), nil)
+
+		got, total, err := service.ListUsers(ctx, &ListUsersRequest{
+			Search:    "  jane ",
+			Role:      "manager",
+			IsActive:  &active,
+			SortBy:    "email",
+			SortOrder: "asc",
+			Page:      3,
+			Limit:     20,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, users, got)
+		assert.Equal(t, int64(41), total)
+	})
+
+	t.Run("out of range paging falls back to the defaults", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		mocks.users.On("List", ctx, repository.UserFilters{Limit: 10}).Return([]*entity.User{}, int64(0), nil)
+
+		_, _, err := service.ListUsers(ctx, &ListUsersRequest{Page: -1, Limit: 500})
+
+		require.NoError(t, err)
+		mocks.users.AssertExpectations(t)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		service, mocks := newTestUserService()
+
+		_, _, err := service.ListUsers(ctx, &ListUsersRequest{Role: "superuser"})
+
+		assert.ErrorContains(t, err, "invalid role")
+		mocks.users.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_ChangeRole(t *testing.T) {
+	ctx := context.Background()
+	adminID, userID := uuid.New(), uuid.New()
+
+	t.Run("role change revokes sessions", func(t *testing.T) {
+		service, mocks, revocations := newTestUserServiceWithRevocations(t)
+		issuedAt := time.Now().Add(-time.Minute)
+
+		mocks.users.On("GetByID", ctx, userID).Return(&entity.User{ID: userID, Role: "customer"}, nil)
+		mocks.users.On("Update", ctx, mock.MatchedBy(func(u *entity.User) bool {
+			return u.Role == "manager" && u.SessionsRevokedAt != nil
+		})).Return(nil)
+
+		user, err := service.ChangeRole(ctx, adminID, userID, &ChangeRoleRequest{Role: "manager"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "manager", user.Role)
+		revoked, err := revocations.IsRevoked(ctx, accessTokenIssued(userID, issuedAt))
+		require.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("admins cannot demote themselves", func(t *testing.T) {
+		service, mocks := newTestUserService()
+
+		_, err := service.ChangeRole(ctx, adminID, adminID, &ChangeRoleRequest{Role: "customer"})
+
+		assert.ErrorIs(t, err, ErrCannotModifySelf)
+		mocks.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unchanged role keeps sessions", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		mocks.users.On("GetByID", ctx, userID).Return(&entity.User{ID: userID, Role: "manager"}, nil)
+
+		user, err := service.ChangeRole(ctx, adminID, userID, &ChangeRoleRequest{Role: "manager"})
+
+		require.NoError(t, err)
+		assert.Nil(t, user.SessionsRevokedAt)
+		mocks.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		service, _ := newTestUserService()
+
+		_, err := service.ChangeRole(ctx, adminID, userID, &ChangeRoleRequest{Role: "superuser"})
+
+		assert.ErrorContains(t, err, "invalid role")
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		mocks.users.On("GetByID", ctx, userID).Return(nil, errors.New("record not found"))
+
+		_, err := service.ChangeRole(ctx, adminID, userID, &ChangeRoleRequest{Role: "manager"})
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestUserService_SetUserActive(t *testing.T) {
+	ctx := context.Background()
+	adminID, userID := uuid.New(), uuid.New()
+
+	t.Run("deactivation revokes sessions", func(t *testing.T) {
+		service, mocks, revocations := newTestUserServiceWithRevocations(t)
+		issuedAt := time.Now().Add(-time.Minute)
+
+		mocks.users.On("GetByID", ctx, userID).Return(&entity.User{ID: userID, IsActive: true}, nil)
+		mocks.users.On("Update", ctx, mock.MatchedBy(func(u *entity.User) bool {
+			return !u.IsActive && u.SessionsRevokedAt != nil
+		})).Return(nil)
+
+		user, err := service.SetUserActive(ctx, adminID, userID, false)
+
+		require.NoError(t, err)
+		assert.False(t, user.IsActive)
+		revoked, err := revocations.IsRevoked(ctx, accessTokenIssued(userID, issuedAt))
+		require.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("activation keeps sessions", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		mocks.users.On("GetByID", ctx, userID).Return(&entity.User{ID: userID}, nil)
+		mocks.users.On("Update", ctx, mock.MatchedBy(func(u *entity.User) bool {
+			return u.IsActive && u.SessionsRevokedAt == nil
+		})).Return(nil)
+
+		user, err := service.SetUserActive(ctx, adminID, userID, true)
+
+		require.NoError(t, err)
+		assert.True(t, user.IsActive)
+	})
+
+	t.Run("admins cannot deactivate themselves", func(t *testing.T) {
+		service, mocks := newTestUserService()
+
+		_, err := service.SetUserActive(ctx, adminID, adminID, false)
+
+		assert.ErrorIs(t, err, ErrCannotModifySelf)
+		mocks.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_DeleteUser(t *testing.T) {
+	ctx := context.Background()
+	adminID, userID := uuid.New(), uuid.New()
+
+	t.Run("soft delete revokes sessions", func(t *testing.T) {
+		service, mocks, revocations := newTestUserServiceWithRevocations(t)
+		issuedAt := time.Now().Add(-time.Minute)
+
+		mocks.users.On("GetByID", ctx, userID).Return(&entity.User{ID: userID, IsActive: true}, nil)
+		mocks.users.On("Update", ctx, mock.MatchedBy(func(u *entity.User) bool {
+			return u.SessionsRevokedAt != nil
+		})).Return(nil).Once()
+		mocks.users.On("Delete", ctx, userID).Return(nil)
+
+		require.NoError(t, service.DeleteUser(ctx, adminID, userID))
+
+		mocks.users.AssertExpectations(t)
+		revoked, err := revocations.IsRevoked(ctx, accessTokenIssued(userID, issuedAt))
+		require.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("sessions are revoked before the user is deleted", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		mocks.users.On("GetByID", ctx, userID).Return(&entity.User{ID: userID}, nil)
+		mocks.users.On("Update", ctx, mock.Anything).Return(errors.New("database unavailable"))
+
+		err := service.DeleteUser(ctx, adminID, userID)
+
+		assert.ErrorContains(t, err, "failed to revoke sessions")
+		mocks.users.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("admins cannot delete themselves", func(t *testing.T) {
+		service, mocks := newTestUserService()
+
+		err := service.DeleteUser(ctx, adminID, adminID)
+
+		assert.ErrorIs(t, err, ErrCannotModifySelf)
+		mocks.users.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+		mocks.users.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		mocks.users.On("GetByID", ctx, userID).Return(nil, errors.New("record not found"))
+
+		err := service.DeleteUser(ctx, adminID, userID)
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestUserService_RestoreUser(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("restore clears the deletion", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		deleted := &entity.User{ID: userID, IsActive: true, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
+		mocks.users.On("Restore", ctx, userID).Return(nil).Run(func(mock.Arguments) {
+			deleted.DeletedAt = gorm.DeletedAt{}
+		})
+		mocks.users.On("GetByID", ctx, userID).Return(deleted, nil)
+
+		user, err := service.RestoreUser(ctx, userID)
+
+		require.NoError(t, err)
+		assert.False(t, user.DeletedAt.Valid)
+		mocks.users.AssertExpectations(t)
+	})
+
+	t.Run("user that is not deleted", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		mocks.users.On("Restore", ctx, userID).Return(errors.New("deleted user not found"))
+
+		_, err := service.RestoreUser(ctx, userID)
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		mocks.users.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/services/user-service/internal/domain/entity"
+	"solemate/services/user-service/internal/domain/repository"
+)
+
+const (
+	defaultWishlistName = "My Wishlist"
+	maxWishlistsPerUser = 50
+)
+
+var (
+	ErrWishlistNotFound      = errors.New("wishlist not found")
+	ErrDefaultWishlistDelete = errors.New("the default wishlist cannot be deleted")
+)
+
+type CreateWishlistRequest struct {
+	Name       string `json:"name" binding:"required,max=100"`
+	Visibility string `json:"visibility"`
+}
+
+type UpdateWishlistRequest struct {
+	Name       *string `json:"name" binding:"omitempty,max=100"`
+	Visibility *string `json:"visibility"`
+}
+
+type CopyWishlistRequest struct {
+	WishlistID *uuid.UUID `json:"wishlist_id"` // defaults to the user's default wishlist
+}
+
+// WishlistDetail is a wishlist together with its items
+type WishlistDetail struct {
+	*entity.Wishlist
+	Items      []*entity.WishlistItem `json:"items"`
+	TotalItems int                    `json:"totalItems"`
+}
+
+// SharedWishlistItem is a wishlist item as shown to anyone with the share link
+type SharedWishlistItem struct {
+	ProductID uuid.UUID       `json:"product_id"`
+	AddedAt   time.Time       `json:"added_at"`
+	Product   *entity.Product `json:"product,omitempty"`
+}
+
+// SharedWishlistResponse leaves out the owner's identity
+type SharedWishlistResponse struct {
+	Name       string                `json:"name"`
+	Items      []*SharedWishlistItem `json:"items"`
+	TotalItems int                   `json:"totalItems"`
+}
+
+type CopyWishlistResponse struct {
+	WishlistID uuid.UUID `json:"wishlist_id"`
+	Copied     int       `json:"copied"`
+	Skipped    int       `json:"skipped"`
+}
+
+// ListWishlists returns the user's wishlists, default first
+func (s *WishlistService) ListWishlists(ctx context.Context, userID uuid.UUID) ([]*entity.Wishlist, error) {
+	// Make sure the default list exists so it is always part of the result
+	if _, err := s.defaultList(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	wishlists, err := s.wishlistRepo.GetListsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishlists: %w", err)
+	}
+	return wishlists, nil
+}
+
+// CreateWishlist creates a named wishlist
+func (s *WishlistService) CreateWishlist(ctx context.Context, userID uuid.UUID, req *CreateWishlistRequest) (*entity.Wishlist, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	visibility, err := validVisibility(req.Visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	wishlists, err := s.ListWishlists(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(wishlists) >= maxWishlistsPerUser {
+		return nil, fmt.Errorf("a user can have at most %d wishlists", maxWishlistsPerUser)
+	}
+
+	return s.createList(ctx, userID, name, visibility)
+}
+
+// GetUserWishlist returns one of the user's wishlists with its items
+func (s *WishlistService) GetUserWishlist(ctx context.Context, userID, wishlistID uuid.UUID) (*WishlistDetail, error) {
+	wishlist, err := s.ownedList(ctx, userID, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.wishlistRepo.GetItems(ctx, wishlist.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishlist: %w", err)
+	}
+
+	wishlist.ItemCount = int64(len(items))
+	return &WishlistDetail{
+		Wishlist:   wishlist,
+		Items:      items,
+		TotalItems: len(items),
+	}, nil
+}
+
+// UpdateWishlist renames a wishlist or changes who can see it
+func (s *WishlistService) UpdateWishlist(ctx context.Context, userID, wishlistID uuid.UUID, req *UpdateWishlistRequest) (*entity.Wishlist, error) {
+	wishlist, err := s.ownedList(ctx, userID, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+		wishlist.Name = name
+	}
+
+	if req.Visibility != nil {
+		visibility, err := validVisibility(*req.Visibility)
+		if err != nil {
+			return nil, err
+		}
+		wishlist.Visibility = visibility
+	}
+
+	if err := s.wishlistRepo.UpdateList(ctx, wishlist); err != nil {
+		return nil, fmt.Errorf("failed to update wishlist: %w", err)
+	}
+	return wishlist, nil
+}
+
+// DeleteWishlist removes a named wishlist and its items
+func (s *WishlistService) DeleteWishlist(ctx context.Context, userID, wishlistID uuid.UUID) error {
+	wishlist, err := s.ownedList(ctx, userID, wishlistID)
+	if err != nil {
+		return err
+	}
+
+	if wishlist.IsDefault {
+		return ErrDefaultWishlistDelete
+	}
+
+	if err := s.wishlistRepo.DeleteList(ctx, wishlist.ID); err != nil {
+		return fmt.Errorf("failed to delete wishlist: %w", err)
+	}
+	return nil
+}
+
+// AddToList adds a product to one of the user's wishlists
+func (s *WishlistService) AddToList(ctx context.Context, userID, wishlistID, productID uuid.UUID) (*entity.WishlistItem, error) {
+	wishlist, err := s.ownedList(ctx, userID, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.addItem(ctx, wishlist, productID)
+}
+
+// RemoveFromList removes a product from one of the user's wishlists
+func (s *WishlistService) RemoveFromList(ctx context.Context, userID, wishlistID, productID uuid.UUID) error {
+	wishlist, err := s.ownedList(ctx, userID, wishlistID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.wishlistRepo.RemoveItem(ctx, wishlist.ID, productID); err != nil {
+		return fmt.Errorf("failed to remove from wishlist: %w", err)
+	}
+	return nil
+}
+
+// GetSharedWishlist returns a shared wishlist by its share slug. Private lists
+// are reported as not found so their slugs cannot be probed.
+func (s *WishlistService) GetSharedWishlist(ctx context.Context, slug string) (*SharedWishlistResponse, error) {
+	wishlist, err := s.sharedList(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.wishlistRepo.GetItems(ctx, wishlist.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishlist: %w", err)
+	}
+
+	shared := make([]*SharedWishlistItem, len(items))
+	for i, item := range items {
+		shared[i] = &SharedWishlistItem{
+			ProductID: item.ProductID,
+			AddedAt:   item.AddedAt,
+			Product:   item.Product,
+		}
+	}
+
+	return &SharedWishlistResponse{
+		Name:       wishlist.Name,
+		Items:      shared,
+		TotalItems: len(shared),
+	}, nil
+}
+
+// CopySharedWishlist copies the items of a shared wishlist into one of the
+// user's own wishlists. Products already in the target list are skipped.
+func (s *WishlistService) CopySharedWishlist(ctx context.Context, userID uuid.UUID, slug string, req *CopyWishlistRequest) (*CopyWishlistResponse, error) {
+	source, err := s.sharedList(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.resolveList(ctx, userID, req.WishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.wishlistRepo.GetItems(ctx, source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishlist: %w", err)
+	}
+
+	response := &CopyWishlistResponse{WishlistID: target.ID}
+	for _, item := range items {
+		exists, err := s.wishlistRepo.ItemExists(ctx, target.ID, item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check wishlist item: %w", err)
+		}
+		if exists {
+			response.Skipped++
+			continue
+		}
+
+		if _, err := s.wishlistRepo.AddItem(ctx, target.ID, userID, item.ProductID); err != nil {
+			return nil, fmt.Errorf("failed to copy wishlist item: %w", err)
+		}
+		response.Copied++
+	}
+
+	return response, nil
+}
+
+// defaultList returns the user's default wishlist, creating it on first use.
+// Items saved before named wishlists existed are moved into it.
+func (s *WishlistService) defaultList(ctx context.Context, userID uuid.UUID) (*entity.Wishlist, error) {
+	wishlist, err := s.wishlistRepo.GetDefaultList(ctx, userID)
+	if err == nil {
+		return wishlist, nil
+	}
+	if !errors.Is(err, repository.ErrWishlistNotFound) {
+		return nil, fmt.Errorf("failed to get default wishlist: %w", err)
+	}
+
+	wishlist, err = newWishlist(userID, defaultWishlistName, entity.WishlistPrivate, true)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.wishlistRepo.CreateDefaultList(ctx, wishlist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wishlist: %w", err)
+	}
+	if !created {
+		// Created by a concurrent request
+		return s.wishlistRepo.GetDefaultList(ctx, userID)
+	}
+
+	if err := s.wishlistRepo.AssignOrphanItems(ctx, userID, wishlist.ID); err != nil {
+		return nil, fmt.Errorf("failed to migrate wishlist items: %w", err)
+	}
+	return wishlist, nil
+}
+
+// resolveList returns the given wishlist if the user owns it, or the default list when wishlistID is nil
+func (s *WishlistService) resolveList(ctx context.Context, userID uuid.UUID, wishlistID *uuid.UUID) (*entity.Wishlist, error) {
+	if wishlistID == nil {
+		return s.defaultList(ctx, userID)
+	}
+	return s.ownedList(ctx, userID, *wishlistID)
+}
+
+func (s *WishlistService) ownedList(ctx context.Context, userID, wishlistID uuid.UUID) (*entity.Wishlist, error) {
+	wishlist, err := s.wishlistRepo.GetList(ctx, wishlistID)
+	if err != nil || wishlist.UserID != userID {
+		return nil, ErrWishlistNotFound
+	}
+	return wishlist, nil
+}
+
+func (s *WishlistService) sharedList(ctx context.Context, slug string) (*entity.Wishlist, error) {
+	wishlist, err := s.wishlistRepo.GetListBySlug(ctx, slug)
+	if err != nil || wishlist.Visibility != entity.WishlistShared {
+		return nil, ErrWishlistNotFound
+	}
+	return wishlist, nil
+}
+
+func (s *WishlistService) createList(ctx context.Context, userID uuid.UUID, name, visibility string) (*entity.Wishlist, error) {
+	wishlist, err := newWishlist(userID, name, visibility, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.wishlistRepo.CreateList(ctx, wishlist); err != nil {
+		return nil, fmt.Errorf("failed to create wishlist: %w", err)
+	}
+	return wishlist, nil
+}
+
+func newWishlist(userID uuid.UUID, name, visibility string, isDefault bool) (*entity.Wishlist, error) {
+	slug, err := newShareSlug()
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.Wishlist{
+		UserID:     userID,
+		Name:       name,
+		Visibility: visibility,
+		ShareSlug:  slug,
+		IsDefault:  isDefault,
+	}, nil
+}
+
+func (s *WishlistService) addItem(ctx context.Context, wishlist *entity.Wishlist, productID uuid.UUID) (*entity.WishlistItem, error) {
+	// Check if product already exists in wishlist
+	exists, err := s.wishlistRepo.ItemExists(ctx, wishlist.ID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check wishlist item: %w", err)
+	}
+
+	if exists {
+		// Return existing item if already in wishlist (graceful handling)
+		item, err := s.wishlistRepo.GetItemByProductID(ctx, wishlist.ID, productID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get existing wishlist item: %w", err)
+		}
+		return item, nil
+	}
+
+	// Add new item to wishlist
+	item, err := s.wishlistRepo.AddItem(ctx, wishlist.ID, wishlist.UserID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add to wishlist: %w", err)
+	}
+
+	return item, nil
+}
+
+func validVisibility(visibility string) (string, error) {
+	switch visibility {
+	case "":
+		return entity.WishlistPrivate, nil
+	case entity.WishlistPrivate, entity.WishlistShared:
+		return visibility, nil
+	}
+	return "", fmt.Errorf("invalid visibility: %s", visibility)
+}
+
+// newShareSlug returns an unguessable, URL-safe share slug
+func newShareSlug() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate share slug: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"solemate/pkg/auth"
+	"solemate/pkg/privacy"
+	"solemate/services/user-service/internal/domain/entity"
+	"solemate/services/user-service/internal/domain/repository"
+)
+
+const (
+	// exportRetention is how long a finished export archive can be downloaded
+	exportRetention = 7 * 24 * time.Hour
+
+	// privacyJobTimeout bounds a single export or erasure run across all services
+	privacyJobTimeout = 5 * time.Minute
+)
+
+var (
+	ErrPrivacyRequestNotFound = errors.New("privacy request not found")
+	ErrExportNotReady         = errors.New("export is not ready yet")
+	ErrExportExpired          = errors.New("export has expired")
+)
+
+// PrivacyService coordinates GDPR data export and erasure. User-service data is
+// handled locally; every other service is reached through its privacy endpoints.
+type PrivacyService struct {
+	userRepo     repository.UserRepository
+	addressRepo  repository.AddressRepository
+	wishlistRepo repository.WishlistRepository
+	privacyRepo  repository.PrivacyRequestRepository
+	clients      []*privacy.Client
+	revocations  *auth.SessionRevocations
+}
+
+func NewPrivacyService(
+	userRepo repository.UserRepository,
+	addressRepo repository.AddressRepository,
+	wishlistRepo repository.WishlistRepository,
+	privacyRepo repository.PrivacyRequestRepository,
+	clients []*privacy.Client,
+	revocations *auth.SessionRevocations,
+) *PrivacyService {
+	return &PrivacyService{
+		userRepo:     userRepo,
+		addressRepo:  addressRepo,
+		wishlistRepo: wishlistRepo,
+		privacyRepo:  privacyRepo,
+		clients:      clients,
+		revocations:  revocations,
+	}
+}
+
+type EraseAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// RequestExport starts building an export archive in the background. If an
+// export is already running, that request is returned instead.
+func (s *PrivacyService) RequestExport(ctx context.Context, userID uuid.UUID) (*entity.PrivacyRequest, error) {
+	active, err := s.privacyRepo.GetActive(ctx, userID, entity.PrivacyRequestExport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing exports: %w", err)
+	}
+	if active != nil {
+		return active, nil
+	}
+
+	return s.startRequest(ctx, userID, userID, entity.PrivacyRequestExport)
+}
+
+// RequestErasure verifies the user's password, deactivates the account and
+// revokes its sessions right away, and erases the user's data across all
+// services in the background.
+func (s *PrivacyService) RequestErasure(ctx context.Context, userID uuid.UUID, req *EraseAccountRequest) (*entity.PrivacyRequest, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, errors.New("invalid password")
+	}
+
+	user.IsActive = false
+	revokeSessions(user)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to deactivate account: %w", err)
+	}
+	revokeAccessTokens(ctx, s.revocations, user)
+
+	return s.startRequest(ctx, userID, userID, entity.PrivacyRequestErasure)
+}
+
+// RetryRequest re-runs a failed request, e.g. after a service was unavailable.
+func (s *PrivacyService) RetryRequest(ctx context.Context, requestID uuid.UUID) (*entity.PrivacyRequest, error) {
+	request, err := s.privacyRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, ErrPrivacyRequestNotFound
+	}
+
+	if request.Status != entity.PrivacyStatusFailed {
+		return nil, fmt.Errorf("only failed requests can be retried, request is %s", request.Status)
+	}
+
+	request.Status = entity.PrivacyStatusPending
+	request.Error = ""
+	if err := s.privacyRepo.Update(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to update privacy request: %w", err)
+	}
+
+	go s.process(request.ID)
+
+	request.Archive = nil
+	return request, nil
+}
+
+// GetRequest returns a privacy request owned by userID.
+func (s *PrivacyService) GetRequest(ctx context.Context, userID, requestID uuid.UUID) (*entity.PrivacyRequest, error) {
+	request, err := s.privacyRepo.GetByID(ctx, requestID)
+	if err != nil || request.UserID != userID {
+		return nil, ErrPrivacyRequestNotFound
+	}
+	return request, nil
+}
+
+func (s *PrivacyService) ListRequests(ctx context.Context, userID uuid.UUID) ([]*entity.PrivacyRequest, error) {
+	return s.privacyRepo.ListByUserID(ctx, userID)
+}
+
+// GetExportArchive returns a finished export as a ZIP archive with one JSON file
+// per service, or as a single JSON document when format is "json".
+func (s *PrivacyService) GetExportArchive(ctx context.Context, userID, requestID uuid.UUID, format string) ([]byte, string, error) {
+	request, err := s.GetRequest(ctx, userID, requestID)
+	if err != nil || request.Type != entity.PrivacyRequestExport {
+		return nil, "", ErrPrivacyRequestNotFound
+	}
+
+	if request.Status != entity.PrivacyStatusCompleted {
+		return nil, "", ErrExportNotReady
+	}
+
+	if request.ExpiresAt != nil && time.Now().After(*request.ExpiresAt) {
+		return nil, "", ErrExportExpired
+	}
+
+	if format == "json" {
+		return request.Archive, "application/json", nil
+	}
+
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(request.Archive, &sections); err != nil {
+		return nil, "", fmt.Errorf("failed to read export: %w", err)
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, data := range sections {
+		file, err := archive.Create(name + ".json")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to build archive: %w", err)
+		}
+		if _, err := file.Write(data); err != nil {
+			return nil, "", fmt.Errorf("failed to build archive: %w", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to build archive: %w", err)
+	}
+
+	return buf.Bytes(), "application/zip", nil
+}
+
+func (s *PrivacyService) startRequest(ctx context.Context, userID, requestedBy uuid.UUID, requestType string) (*entity.PrivacyRequest, error) {
+	request := &entity.PrivacyRequest{
+		UserID:      userID,
+		RequestedBy: requestedBy,
+		Type:        requestType,
+		Status:      entity.PrivacyStatusPending,
+		Results:     map[string]*entity.PrivacyServiceResult{},
+	}
+
+	if err := s.privacyRepo.Create(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to create privacy request: %w", err)
+	}
+
+	go s.process(request.ID)
+
+	return request, nil
+}
+
+// process runs a privacy request outside the HTTP request that created it
+func (s *PrivacyService) process(requestID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), privacyJobTimeout)
+	defer cancel()
+
+	request, err := s.privacyRepo.GetByID(ctx, requestID)
+	if err != nil {
+		log.Printf("privacy request %s: %v", requestID, err)
+		return
+	}
+
+	request.Status = entity.PrivacyStatusProcessing
+	if request.Results == nil {
+		request.Results = map[string]*entity.PrivacyServiceResult{}
+	}
+	if err := s.privacyRepo.Update(ctx, request); err != nil {
+		log.Printf("privacy request %s: failed to mark as processing: %v", requestID, err)
+		return
+	}
+
+	switch request.Type {
+	case entity.PrivacyRequestExport:
+		s.runExport(ctx, request)
+	case entity.PrivacyRequestErasure:
+		s.runErasure(ctx, request)
+	}
+
+	var failed []string
+	for service, result := range request.Results {
+		if result.Status == entity.PrivacyStatusFailed {
+			failed = append(failed, service)
+		}
+	}
+
+	now := time.Now()
+	if len(failed) > 0 {
+		request.Status = entity.PrivacyStatusFailed
+		request.Error = "failed services: " + strings.Join(failed, ", ")
+	} else {
+		request.Status = entity.PrivacyStatusCompleted
+		request.Error = ""
+		request.CompletedAt = &now
+		if request.Type == entity.PrivacyRequestExport {
+			expiresAt := now.Add(exportRetention)
+			request.ExpiresAt = &expiresAt
+		}
+	}
+
+	if err := s.privacyRepo.Update(ctx, request); err != nil {
+		log.Printf("privacy request %s: failed to save result: %v", requestID, err)
+	}
+}
+
+func (s *PrivacyService) runExport(ctx context.Context, request *entity.PrivacyRequest) {
+	sections := map[string]interface{}{}
+
+	local, err := s.exportLocal(ctx, request.UserID)
+	request.Results[auth.ServiceUser] = serviceResult(nil, err)
+	if err == nil {
+		sections[auth.ServiceUser] = local
+	}
+
+	for _, client := range s.clients {
+		data, err := client.Export(ctx, request.UserID)
+		request.Results[client.Service()] = serviceResult(nil, err)
+		if err == nil {
+			sections[client.Service()] = data
+		}
+	}
+
+	archive, err := json.Marshal(sections)
+	if err != nil {
+		request.Results[auth.ServiceUser] = serviceResult(nil, err)
+		return
+	}
+	request.Archive = archive
+}
+
+func (s *PrivacyService) runErasure(ctx context.Context, request *entity.PrivacyRequest) {
+	for _, client := range s.clients {
+		// Services already erased on a previous attempt are skipped
+		if result, ok := request.Results[client.Service()]; ok && result.Status == entity.PrivacyStatusCompleted {
+			continue
+		}
+		report, err := client.Erase(ctx, request.UserID)
+		request.Results[client.Service()] = serviceResult(report, err)
+	}
+
+	report, err := s.eraseLocal(ctx, request.UserID)
+	request.Results[auth.ServiceUser] = serviceResult(report, err)
+}
+
+func (s *PrivacyService) exportLocal(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := s.addressRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load addresses: %w", err)
+	}
+
+	wishlists, err := s.wishlistRepo.GetListsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wishlists: %w", err)
+	}
+
+	wishlistItems, err := s.wishlistRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wishlist: %w", err)
+	}
+
+	return map[string]interface{}{
+		"profile":        user,
+		"addresses":      addresses,
+		"wishlists":      wishlists,
+		"wishlist_items": wishlistItems,
+	}, nil
+}
+
+// eraseLocal removes addresses and wishlists and anonymises the user row.
+// The row itself is kept so orders, payments and audit records still resolve.
+func (s *PrivacyService) eraseLocal(ctx context.Context, userID uuid.UUID) (*privacy.ErasureReport, error) {
+	report := privacy.NewErasureReport()
+
+	count, err := s.addressRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete addresses: %w", err)
+	}
+	report.AddDeleted("addresses", count)
+
+	items, lists, err := s.wishlistRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete wishlists: %w", err)
+	}
+	report.AddDeleted("wishlist_items", items)
+	report.AddDeleted("wishlists", lists)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Email = privacy.ErasedEmail(userID)
+	user.PasswordHash = ""
+	user.FirstName = ""
+	user.LastName = ""
+	user.PhoneNumber = ""
+	user.IsActive = false
+	user.EmailVerified = false
+	user.LastLoginAt = nil
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to anonymize user: %w", err)
+	}
+	report.AddAnonymized("users", 1)
+
+	return report, nil
+}
+
+func serviceResult(report *privacy.ErasureReport, err error) *entity.PrivacyServiceResult {
+	if err != nil {
+		return &entity.PrivacyServiceResult{Status: entity.PrivacyStatusFailed, Error: err.Error()}
+	}
+	return &entity.PrivacyServiceResult{Status: entity.PrivacyStatusCompleted, Report: report}
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"solemate/pkg/auth"
+	"solemate/pkg/cache"
+	"solemate/pkg/privacy"
+	"solemate/services/user-service/internal/domain/entity"
+)
+
+// MockPrivacyRequestRepository is a mock implementation of repository.PrivacyRequestRepository
+type MockPrivacyRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockPrivacyRequestRepository) Create(ctx context.Context, request *entity.PrivacyRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *MockPrivacyRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.PrivacyRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PrivacyRequest), args.Error(1)
+}
+
+func (m *MockPrivacyRequestRepository) Update(ctx context.Context, request *entity.PrivacyRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *MockPrivacyRequestRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PrivacyRequest, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.PrivacyRequest), args.Error(1)
+}
+
+func (m *MockPrivacyRequestRepository) GetActive(ctx context.Context, userID uuid.UUID, requestType string) (*entity.PrivacyRequest, error) {
+	args := m.Called(ctx, userID, requestType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PrivacyRequest), args.Error(1)
+}
+
+// fakePrivacyProvider stands in for the domain layer of a downstream service
+type fakePrivacyProvider struct {
+	data   interface{}
+	report *privacy.ErasureReport
+	err    error
+	calls  atomic.Int32
+}
+
+func (p *fakePrivacyProvider) ExportUserData(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	p.calls.Add(1)
+	return p.data, p.err
+}
+
+func (p *fakePrivacyProvider) EraseUserData(ctx context.Context, userID uuid.UUID) (*privacy.ErasureReport, error) {
+	p.calls.Add(1)
+	return p.report, p.err
+}
+
+// newPrivacyClient serves provider on the internal privacy endpoints of a test
+// server and returns the client user-service uses to reach it
+func newPrivacyClient(t *testing.T, service string, provider privacy.Provider) *privacy.Client {
+	gin.SetMode(gin.TestMode)
+
+	serviceTokens, err := auth.NewInternalTokenManager(service)
+	require.NoError(t, err)
+	userTokens, err := auth.NewInternalTokenManager(auth.ServiceUser)
+	require.NoError(t, err)
+
+	router := gin.New()
+	privacy.RegisterRoutes(router.Group("/api/v1"), auth.ServiceAuthMiddleware(serviceTokens, nil, nil), provider)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return privacy.NewClient(service, server.URL, userTokens)
+}
+
+type privacyServiceMocks struct {
+	users     *MockUserRepository
+	addresses *MockAddressRepository
+	wishlists *MockWishlistRepository
+	requests  *MockPrivacyRequestRepository
+}
+
+func newTestPrivacyService(clients []*privacy.Client, revocations *auth.SessionRevocations) (*PrivacyService, *privacyServiceMocks) {
+	mocks := &privacyServiceMocks{
+		users:     new(MockUserRepository),
+		addresses: new(MockAddressRepository),
+		wishlists: new(MockWishlistRepository),
+		requests:  new(MockPrivacyRequestRepository),
+	}
+	return NewPrivacyService(mocks.users, mocks.addresses, mocks.wishlists, mocks.requests, clients, revocations), mocks
+}
+
+func TestPrivacyService_Export(t *testing.T) {
+	userID := uuid.New()
+	user := &entity.User{ID: userID, Email: "jane@example.com"}
+
+	expectLocalExport := func(mocks *privacyServiceMocks) {
+		mocks.users.On("GetByID", mock.Anything, userID).Return(user, nil)
+		mocks.addresses.On("GetByUserID", mock.Anything, userID).Return([]*entity.Address{{UserID: userID, City: "Berlin"}}, nil)
+		mocks.wishlists.On("GetListsByUserID", mock.Anything, userID).Return([]*entity.Wishlist{}, nil)
+		mocks.wishlists.On("GetByUserID", mock.Anything, userID).Return([]*entity.WishlistItem{}, nil)
+	}
+
+	t.Run("every service is included and the archive expires", func(t *testing.T) {
+		orders := newPrivacyClient(t, auth.ServiceOrder, &fakePrivacyProvider{data: map[string]int{"orders": 2}})
+		service, mocks := newTestPrivacyService([]*privacy.Client{orders}, nil)
+		request := &entity.PrivacyRequest{ID: uuid.New(), UserID: userID, Type: entity.PrivacyRequestExport, Status: entity.PrivacyStatusPending}
+
+		expectLocalExport(mocks)
+		mocks.requests.On("GetByID", mock.Anything, request.ID).Return(request, nil)
+		mocks.requests.On("Update", mock.Anything, request).Return(nil)
+
+		service.process(request.ID)
+
+		assert.Equal(t, entity.PrivacyStatusCompleted, request.Status)
+		require.NotNil(t, request.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(exportRetention), *request.ExpiresAt, time.Minute)
+
+		var sections map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(request.Archive, &sections))
+		assert.Contains(t, sections, auth.ServiceUser)
+		assert.JSONEq(t, `{"orders": 2}`, string(sections[auth.ServiceOrder]))
+
+		mocks.requests.On("GetByID", mock.Anything, request.ID).Return(request, nil)
+		archive, contentType, err := service.GetExportArchive(context.Background(), userID, request.ID, "zip")
+		require.NoError(t, err)
+		assert.Equal(t, "application/zip", contentType)
+
+		files, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		require.NoError(t, err)
+		var names []string
+		for _, file := range files.File {
+			names = append(names, file.Name)
+		}
+		assert.ElementsMatch(t, []string{"user-service.json", "order-service.json"}, names)
+	})
+
+	t.Run("an unreachable service fails the export", func(t *testing.T) {
+		orders := newPrivacyClient(t, auth.ServiceOrder, &fakePrivacyProvider{err: errors.New("database unavailable")})
+		service, mocks := newTestPrivacyService([]*privacy.Client{orders}, nil)
+		request := &entity.PrivacyRequest{ID: uuid.New(), UserID: userID, Type: entity.PrivacyRequestExport, Status: entity.PrivacyStatusPending}
+
+		expectLocalExport(mocks)
+		mocks.requests.On("GetByID", mock.Anything, request.ID).Return(request, nil)
+		mocks.requests.On("Update", mock.Anything, request).Return(nil)
+
+		service.process(request.ID)
+
+		assert.Equal(t, entity.PrivacyStatusFailed, request.Status)
+		assert.Equal(t, "failed services: order-service", request.Error)
+		assert.Nil(t, request.ExpiresAt)
+		assert.Equal(t, entity.PrivacyStatusCompleted, request.Results[auth.ServiceUser].Status)
+	})
+
+	t.Run("a running export is reused", func(t *testing.T) {
+		service, mocks := newTestPrivacyService(nil, nil)
+		active := &entity.PrivacyRequest{ID: uuid.New(), UserID: userID, Status: entity.PrivacyStatusProcessing}
+		mocks.requests.On("GetActive", mock.Anything, userID, entity.PrivacyRequestExport).Return(active, nil)
+
+		request, err := service.RequestExport(context.Background(), userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, active, request)
+		mocks.requests.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPrivacyService_GetExportArchive(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+
+	tests := map[string]struct {
+		request *entity.PrivacyRequest
+		userID  uuid.UUID
+		wantErr error
+	}{
+		"finished export": {
+			request: &entity.PrivacyRequest{UserID: userID, Type: entity.PrivacyRequestExport, Status: entity.PrivacyStatusCompleted, ExpiresAt: &future, Archive: []byte(`{}`)},
+			userID:  userID,
+		},
+		"expired export": {
+			request: &entity.PrivacyRequest{UserID: userID, Type: entity.PrivacyRequestExport, Status: entity.PrivacyStatusCompleted, ExpiresAt: &past, Archive: []byte(`{}`)},
+			userID:  userID,
+			wantErr: ErrExportExpired,
+		},
+		"export still running": {
+			request: &entity.PrivacyRequest{UserID: userID, Type: entity.PrivacyRequestExport, Status: entity.PrivacyStatusProcessing},
+			userID:  userID,
+			wantErr: ErrExportNotReady,
+		},
+		"export of another user": {
+			request: &entity.PrivacyRequest{UserID: uuid.New(), Type: entity.PrivacyRequestExport, Status: entity.PrivacyStatusCompleted, ExpiresAt: &future},
+			userID:  userID,
+			wantErr: ErrPrivacyRequestNotFound,
+		},
+		"erasure request": {
+			request: &entity.PrivacyRequest{UserID: userID, Type: entity.PrivacyRequestErasure, Status: entity.PrivacyStatusCompleted},
+			userID:  userID,
+			wantErr: ErrPrivacyRequestNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, mocks := newTestPrivacyService(nil, nil)
+			tt.request.ID = uuid.New()
+			mocks.requests.On("GetByID", ctx, tt.request.ID).Return(tt.request, nil)
+
+			archive, contentType, err := service.GetExportArchive(ctx, tt.userID, tt.request.ID, "json")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, archive)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "application/json", contentType)
+			assert.Equal(t, tt.request.Archive, archive)
+		})
+	}
+}
+
+func TestPrivacyService_RequestErasure(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	t.Run("account is deactivated and its sessions revoked", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		redisClient, err := cache.NewRedisClient(cache.Config{Host: server.Host(), Port: server.Port()})
+		require.NoError(t, err)
+		revocations := auth.NewSessionRevocations(redisClient)
+		service, mocks := newTestPrivacyService(nil, revocations)
+		issuedAt := time.Now().Add(-time.Minute)
+
+		mocks.users.On("GetByID", ctx, userID).Return(&entity.User{ID: userID, PasswordHash: string(hashedPassword), IsActive: true}, nil)
+		mocks.users.On("Update", ctx, mock.MatchedBy(func(u *entity.User) bool {
+			return !u.IsActive && u.SessionsRevokedAt != nil
+		})).Return(nil)
+		mocks.requests.On("Create", ctx, mock.MatchedBy(func(r *entity.PrivacyRequest) bool {
+			return r.UserID == userID && r.Type == entity.PrivacyRequestErasure && r.Status == entity.PrivacyStatusPending
+		})).Return(nil)
+
+		started := make(chan struct{})
+		mocks.requests.On("GetByID", mock.Anything, mock.Anything).
+			Return(nil, errors.New("stop")).
+			Run(func(mock.Arguments) { close(started) })
+
+		request, err := service.RequestErasure(ctx, userID, &EraseAccountRequest{Password: "password123"})
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.PrivacyRequestErasure, request.Type)
+		mocks.users.AssertExpectations(t)
+
+		revoked, err := revocations.IsRevoked(ctx, accessTokenIssued(userID, issuedAt))
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("erasure was not started")
+		}
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		service, mocks := newTestPrivacyService(nil, nil)
+		mocks.users.On("GetByID", ctx, userID).Return(&entity.User{ID: userID, PasswordHash: string(hashedPassword), IsActive: true}, nil)
+
+		_, err := service.RequestErasure(ctx, userID, &EraseAccountRequest{Password: "wrong_password"})
+
+		assert.EqualError(t, err, "invalid password")
+		mocks.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		mocks.requests.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPrivacyService_Erasure(t *testing.T) {
+	userID := uuid.New()
+
+	expectLocalErasure := func(mocks *privacyServiceMocks) {
+		mocks.addresses.On("DeleteByUserID", mock.Anything, userID).Return(int64(2), nil)
+		mocks.wishlists.On("DeleteByUserID", mock.Anything, userID).Return(int64(3), int64(1), nil)
+		mocks.users.On("GetByID", mock.Anything, userID).Return(&entity.User{ID: userID, Email: "jane@example.com", FirstName: "Jane"}, nil)
+		mocks.users.On("Update", mock.Anything, mock.MatchedBy(func(u *entity.User) bool {
+			return u.Email == privacy.ErasedEmail(userID) && u.FirstName == "" && u.PasswordHash == "" && !u.IsActive
+		})).Return(nil)
+	}
+
+	t.Run("local data is anonymized and failed services are reported", func(t *testing.T) {
+		orders := newPrivacyClient(t, auth.ServiceOrder, &fakePrivacyProvider{report: &privacy.ErasureReport{Anonymized: map[string]int64{"orders": 4}}})
+		payments := newPrivacyClient(t, auth.ServicePayment, &fakePrivacyProvider{err: errors.New("database unavailable")})
+		service, mocks := newTestPrivacyService([]*privacy.Client{orders, payments}, nil)
+		request := &entity.PrivacyRequest{ID: uuid.New(), UserID: userID, Type: entity.PrivacyRequestErasure, Status: entity.PrivacyStatusPending}
+
+		expectLocalErasure(mocks)
+		mocks.requests.On("GetByID", mock.Anything, request.ID).Return(request, nil)
+		mocks.requests.On("Update", mock.Anything, request).Return(nil)
+
+		service.process(request.ID)
+
+		assert.Equal(t, entity.PrivacyStatusFailed, request.Status)
+		assert.Equal(t, "failed services: payment-service", request.Error)
+		assert.Equal(t, int64(4), request.Results[auth.ServiceOrder].Report.Anonymized["orders"])
+		assert.Equal(t, int64(1), request.Results[auth.ServiceUser].Report.Anonymized["users"])
+		assert.Equal(t, int64(2), request.Results[auth.ServiceUser].Report.Deleted["addresses"])
+		assert.Nil(t, request.CompletedAt)
+		mocks.users.AssertExpectations(t)
+	})
+
+	t.Run("services erased on a previous attempt are skipped", func(t *testing.T) {
+		orderProvider := &fakePrivacyProvider{report: privacy.NewErasureReport()}
+		paymentProvider := &fakePrivacyProvider{report: privacy.NewErasureReport()}
+		orders := newPrivacyClient(t, auth.ServiceOrder, orderProvider)
+		payments := newPrivacyClient(t, auth.ServicePayment, paymentProvider)
+		service, mocks := newTestPrivacyService([]*privacy.Client{orders, payments}, nil)
+		request := &entity.PrivacyRequest{
+			ID:     uuid.New(),
+			UserID: userID,
+			Type:   entity.PrivacyRequestErasure,
+			Status: entity.PrivacyStatusPending,
+			Results: map[string]*entity.PrivacyServiceResult{
+				auth.ServiceOrder:   {Status: entity.PrivacyStatusCompleted},
+				auth.ServicePayment: {Status: entity.PrivacyStatusFailed, Error: "payment-service returned status code 500"},
+			},
+		}
+
+		expectLocalErasure(mocks)
+		mocks.requests.On("GetByID", mock.Anything, request.ID).Return(request, nil)
+		mocks.requests.On("Update", mock.Anything, request).Return(nil)
+
+		service.process(request.ID)
+
+		assert.Equal(t, entity.PrivacyStatusCompleted, request.Status)
+		assert.NotNil(t, request.CompletedAt)
+		assert.Equal(t, int32(0), orderProvider.calls.Load())
+		assert.Equal(t, int32(1), paymentProvider.calls.Load())
+	})
+}
+
+func TestPrivacyService_RetryRequest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("failed request is processed again", func(t *testing.T) {
+		service, mocks := newTestPrivacyService(nil, nil)
+		request := &entity.PrivacyRequest{ID: uuid.New(), Type: entity.PrivacyRequestErasure, Status: entity.PrivacyStatusFailed, Error: "failed services: payment-service"}
+
+		mocks.requests.On("GetByID", ctx, request.ID).Return(request, nil).Once()
+		mocks.requests.On("Update", ctx, mock.MatchedBy(func(r *entity.PrivacyRequest) bool {
+			return r.Status == entity.PrivacyStatusPending && r.Error == ""
+		})).Return(nil).Once()
+
+		restarted := make(chan struct{})
+		mocks.requests.On("GetByID", mock.Anything, request.ID).
+			Return(nil, errors.New("stop")).
+			Run(func(mock.Arguments) { close(restarted) })
+
+		retried, err := service.RetryRequest(ctx, request.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.PrivacyStatusPending, retried.Status)
+
+		select {
+		case <-restarted:
+		case <-time.After(time.Second):
+			t.Fatal("request was not processed again")
+		}
+	})
+
+	t.Run("only failed requests can be retried", func(t *testing.T) {
+		service, mocks := newTestPrivacyService(nil, nil)
+		request := &entity.PrivacyRequest{ID: uuid.New(), Status: entity.PrivacyStatusCompleted}
+		mocks.requests.On("GetByID", ctx, request.ID).Return(request, nil)
+
+		_, err := service.RetryRequest(ctx, request.ID)
+
+		assert.ErrorContains(t, err, "only failed requests can be retried")
+		mocks.requests.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown request", func(t *testing.T) {
+		service, mocks := newTestPrivacyService(nil, nil)
+		requestID := uuid.New()
+		mocks.requests.On("GetByID", ctx, requestID).Return(nil, errors.New("record not found"))
+
+		_, err := service.RetryRequest(ctx, requestID)
+
+		assert.ErrorIs(t, err, ErrPrivacyRequestNotFound)
+	})
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"solemate/pkg/authz"
+	"solemate/services/user-service/internal/domain/repository"
+)
+
+type RoleService struct {
+	roleRepo repository.RoleRepository
+}
+
+func NewRoleService(roleRepo repository.RoleRepository) *RoleService {
+	return &RoleService{
+		roleRepo: roleRepo,
+	}
+}
+
+type UpdateRolePermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
+
+// SeedDefaultRoles stores the built-in role mapping for roles not yet present
+func (s *RoleService) SeedDefaultRoles(ctx context.Context) error {
+	defaults := make(map[string][]string, len(authz.DefaultRolePermissions))
+	for role, permissions := range authz.DefaultRolePermissions {
+		defaults[role] = authz.Strings(permissions)
+	}
+	return s.roleRepo.SeedDefaults(ctx, defaults)
+}
+
+// ListRoles returns every role with its granted permissions
+func (s *RoleService) ListRoles(ctx context.Context) (map[string][]string, error) {
+	roles, err := s.roleRepo.ListRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	// Include built-in roles that currently have no permissions
+	for role := range authz.DefaultRolePermissions {
+		if _, ok := roles[role]; !ok {
+			roles[role] = []string{}
+		}
+	}
+	return roles, nil
+}
+
+// UpdateRolePermissions replaces the permissions granted to a role
+func (s *RoleService) UpdateRolePermissions(ctx context.Context, role string, req *UpdateRolePermissionsRequest) ([]string, error) {
+	if !authz.IsValidRole(role) {
+		return nil, fmt.Errorf("invalid role: %s", role)
+	}
+
+	seen := make(map[string]bool, len(req.Permissions))
+	permissions := make([]string, 0, len(req.Permissions))
+	for _, permission := range req.Permissions {
+		if !authz.IsValidPermission(permission) {
+			return nil, fmt.Errorf("invalid permission: %s", permission)
+		}
+		if seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		permissions = append(permissions, permission)
+	}
+
+	if err := s.roleRepo.SetPermissions(ctx, role, permissions); err != nil {
+		return nil, fmt.Errorf("failed to update role permissions: %w", err)
+	}
+
+	return s.roleRepo.GetPermissions(ctx, role)
+}
@@ -16,14 +16,20 @@ import (
 type UserService struct {
 	userRepo    repository.UserRepository
 	addressRepo repository.AddressRepository
+	roleRepo    repository.RoleRepository
 	jwtManager  *auth.JWTManager
+	revocations *auth.SessionRevocations
 }
 
-func NewUserService(userRepo repository.UserRepository, addressRepo repository.AddressRepository, jwtManager *auth.JWTManager) *UserService {
+// NewUserService creates the user service. revocations may be nil, in which
+// case revoked sessions keep their access tokens until they expire.
+func NewUserService(userRepo repository.UserRepository, addressRepo repository.AddressRepository, roleRepo repository.RoleRepository, jwtManager *auth.JWTManager, revocations *auth.SessionRevocations) *UserService {
 	return &UserService{
 		userRepo:    userRepo,
 		addressRepo: addressRepo,
+		roleRepo:    roleRepo,
 		jwtManager:  jwtManager,
+		revocations: revocations,
 	}
 }
 
@@ -67,8 +73,11 @@ func (s *UserService) Register(ctx context.Context, req *RegisterRequest) (*enti
 	}
 
 	// Check if user already exists
-	existingUser, _ := s.userRepo.GetByEmail(ctx, req.Email)
-	if existingUser != nil {
+	exists, err := s.userRepo.EmailExists(ctx, req.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check email: %w", err)
+	}
+	if exists {
 		return nil, errors.New("user with this email already exists")
 	}
 
@@ -115,11 +124,18 @@ func (s *UserService) Login(ctx context.Context, req *LoginRequest) (*LoginRespo
 		return nil, errors.New("account is deactivated")
 	}
 
+	// Resolve role permissions for the token
+	permissions, err := s.roleRepo.GetPermissions(ctx, user.Role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permissions: %w", err)
+	}
+
 	// Generate tokens
 	accessToken, refreshToken, err := s.jwtManager.GenerateTokenPair(
 		user.ID.String(),
 		user.Email,
 		user.Role,
+		permissions,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
@@ -168,22 +184,6 @@ func (s *UserService) UpdateUser(ctx context.Context, id uuid.UUID, req *UpdateU
 	return user, nil
 }
 
-func (s *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	return s.userRepo.Delete(ctx, id)
-}
-
-func (s *UserService) ListUsers(ctx context.Context, page, limit int) ([]*entity.User, int64, error) {
-	if page <= 0 {
-		page = 1
-	}
-	if limit <= 0 {
-		limit = 10
-	}
-
-	offset := (page - 1) * limit
-	return s.userRepo.List(ctx, limit, offset)
-}
-
 func (s *UserService) ValidateToken(ctx context.Context, token string) (*auth.Claims, error) {
 	return s.jwtManager.ValidateAccessToken(token)
 }
@@ -194,5 +194,25 @@ func (s *UserService) RefreshToken(ctx context.Context, refreshToken string) (st
 		return "", "", errors.New("invalid refresh token")
 	}
 
-	return s.jwtManager.GenerateTokenPair(claims.UserID, claims.Email, claims.Role)
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return "", "", errors.New("invalid refresh token")
+	}
+
+	// Deleted and deactivated users, and sessions revoked by an admin, cannot refresh
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || !user.IsActive {
+		return "", "", errors.New("invalid refresh token")
+	}
+	if user.SessionsRevokedAt != nil && claims.IssuedBefore(*user.SessionsRevokedAt) {
+		return "", "", errors.New("invalid refresh token")
+	}
+
+	// Re-resolve role and permissions so role changes take effect on refresh
+	permissions, err := s.roleRepo.GetPermissions(ctx, user.Role)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load permissions: %w", err)
+	}
+
+	return s.jwtManager.GenerateTokenPair(user.ID.String(), user.Email, user.Role, permissions)
 }
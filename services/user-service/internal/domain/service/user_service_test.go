@@ -2,32 +2,39 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
+	"solemate/pkg/auth"
 	"solemate/services/user-service/internal/domain/entity"
+	"solemate/services/user-service/internal/domain/repository"
 )
 
-// MockUserRepository for testing
+// MockUserRepository is a mock implementation of repository.UserRepository
 type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) CreateUser(ctx context.Context, user *entity.User) error {
+func (m *MockUserRepository) Create(ctx context.Context, user *entity.User) error {
 	args := m.Called(ctx, user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) GetUserByID(ctx context.Context, userID uuid.UUID) (*entity.User, error) {
-	args := m.Called(ctx, userID)
+func (m *MockUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	args := m.Called(ctx, email)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -35,373 +42,324 @@ func (m *MockUserRepository) GetUserByEmail(ctx context.Context, email string) (
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
-func (m *MockUserRepository) UpdateUser(ctx context.Context, user *entity.User) error {
+func (m *MockUserRepository) Update(ctx context.Context, user *entity.User) error {
 	args := m.Called(ctx, user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) DeleteUser(ctx context.Context, userID uuid.UUID) error {
-	args := m.Called(ctx, userID)
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) GetUsersByRole(ctx context.Context, role entity.Role) ([]*entity.User, error) {
-	args := m.Called(ctx, role)
-	return args.Get(0).([]*entity.User), args.Error(1)
+func (m *MockUserRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
 }
 
-func (m *MockUserRepository) SearchUsers(ctx context.Context, query string, limit, offset int) ([]*entity.User, int64, error) {
-	args := m.Called(ctx, query, limit, offset)
+func (m *MockUserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	args := m.Called(ctx, email)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, filters repository.UserFilters) ([]*entity.User, int64, error) {
+	args := m.Called(ctx, filters)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
 	return args.Get(0).([]*entity.User), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockUserRepository) UpdateLastLogin(ctx context.Context, userID uuid.UUID) error {
+func (m *MockUserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetUserStatistics(ctx context.Context, startDate, endDate time.Time) (*repository.UserStatistics, error) {
+	args := m.Called(ctx, startDate, endDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.UserStatistics), args.Error(1)
+}
+
+// MockAddressRepository is a mock implementation of repository.AddressRepository
+type MockAddressRepository struct {
+	mock.Mock
+}
+
+func (m *MockAddressRepository) Create(ctx context.Context, address *entity.Address) error {
+	args := m.Called(ctx, address)
+	return args.Error(0)
+}
+
+func (m *MockAddressRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Address, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Address), args.Error(1)
+}
+
+func (m *MockAddressRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Address, error) {
 	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Address), args.Error(1)
+}
+
+func (m *MockAddressRepository) Update(ctx context.Context, address *entity.Address) error {
+	args := m.Called(ctx, address)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
-	args := m.Called(ctx, email)
-	return args.Bool(0), args.Error(1)
+func (m *MockAddressRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
 }
 
-// MockJWTService for testing
-type MockJWTService struct {
+func (m *MockAddressRepository) SetDefault(ctx context.Context, userID, addressID uuid.UUID) error {
+	args := m.Called(ctx, userID, addressID)
+	return args.Error(0)
+}
+
+func (m *MockAddressRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockRoleRepository is a mock implementation of repository.RoleRepository
+type MockRoleRepository struct {
 	mock.Mock
 }
 
-func (m *MockJWTService) GenerateTokens(userID uuid.UUID, email string, role entity.Role) (*TokenPair, error) {
-	args := m.Called(userID, email, role)
-	return args.Get(0).(*TokenPair), args.Error(1)
+func (m *MockRoleRepository) GetPermissions(ctx context.Context, role string) ([]string, error) {
+	args := m.Called(ctx, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRoleRepository) ListRoles(ctx context.Context) (map[string][]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]string), args.Error(1)
 }
 
-func (m *MockJWTService) ValidateAccessToken(tokenString string) (*Claims, error) {
-	args := m.Called(tokenString)
-	return args.Get(0).(*Claims), args.Error(1)
+func (m *MockRoleRepository) SetPermissions(ctx context.Context, role string, permissions []string) error {
+	args := m.Called(ctx, role, permissions)
+	return args.Error(0)
 }
 
-func (m *MockJWTService) ValidateRefreshToken(tokenString string) (*Claims, error) {
-	args := m.Called(tokenString)
-	return args.Get(0).(*Claims), args.Error(1)
+func (m *MockRoleRepository) SeedDefaults(ctx context.Context, defaults map[string][]string) error {
+	args := m.Called(ctx, defaults)
+	return args.Error(0)
 }
 
-func (m *MockJWTService) RefreshTokens(refreshToken string) (*TokenPair, error) {
-	args := m.Called(refreshToken)
-	return args.Get(0).(*TokenPair), args.Error(1)
+type userServiceMocks struct {
+	users     *MockUserRepository
+	addresses *MockAddressRepository
+	roles     *MockRoleRepository
 }
 
-func TestUserService_RegisterUser(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	mockJWT := new(MockJWTService)
-	userService := NewUserService(mockRepo, mockJWT)
+func newTestUserService() (*UserService, *userServiceMocks) {
+	mocks := &userServiceMocks{
+		users:     new(MockUserRepository),
+		addresses: new(MockAddressRepository),
+		roles:     new(MockRoleRepository),
+	}
+	return NewUserService(mocks.users, mocks.addresses, mocks.roles, auth.NewJWTManager(), nil), mocks
+}
 
+func TestUserService_Register(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("successful registration", func(t *testing.T) {
-		request := &RegisterUserRequest{
+		service, mocks := newTestUserService()
+		request := &RegisterRequest{
 			Email:     "test@example.com",
 			Password:  "password123",
 			FirstName: "John",
 			LastName:  "Doe",
-			Phone:     "+1234567890",
 		}
 
-		// Mock email doesn't exist
-		mockRepo.On("EmailExists", ctx, request.Email).Return(false, nil)
+		mocks.users.On("EmailExists", ctx, request.Email).Return(false, nil)
+		mocks.users.On("Create", ctx, mock.MatchedBy(func(u *entity.User) bool {
+			return u.Email == request.Email && u.Role == "customer" && u.IsActive &&
+				bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(request.Password)) == nil
+		})).Return(nil)
 
-		// Mock successful user creation
-		mockRepo.On("CreateUser", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+		user, err := service.Register(ctx, request)
 
-		// Mock token generation
-		tokens := &TokenPair{
-			AccessToken:  "access_token",
-			RefreshToken: "refresh_token",
-		}
-		mockRepo.On("GetUserByEmail", ctx, request.Email).Return(&entity.User{
-			ID:        uuid.New(),
-			Email:     request.Email,
-			FirstName: request.FirstName,
-			LastName:  request.LastName,
-			Role:      entity.RoleCustomer,
-		}, nil)
-		mockJWT.On("GenerateTokens", mock.AnythingOfType("uuid.UUID"), request.Email, entity.RoleCustomer).Return(tokens, nil)
-
-		response, err := userService.RegisterUser(ctx, request)
-
-		assert.NoError(t, err)
-		assert.NotNil(t, response)
-		assert.Equal(t, request.Email, response.User.Email)
-		assert.Equal(t, "access_token", response.AccessToken)
-		assert.Equal(t, "refresh_token", response.RefreshToken)
-
-		mockRepo.AssertExpectations(t)
-		mockJWT.AssertExpectations(t)
+		require.NoError(t, err)
+		assert.Equal(t, "John", user.FirstName)
+		mocks.users.AssertExpectations(t)
 	})
 
 	t.Run("email already exists", func(t *testing.T) {
-		request := &RegisterUserRequest{
+		service, mocks := newTestUserService()
+		request := &RegisterRequest{
 			Email:     "existing@example.com",
 			Password:  "password123",
 			FirstName: "John",
 			LastName:  "Doe",
 		}
 
-		mockRepo.On("EmailExists", ctx, request.Email).Return(true, nil)
+		mocks.users.On("EmailExists", ctx, request.Email).Return(true, nil)
 
-		response, err := userService.RegisterUser(ctx, request)
+		user, err := service.Register(ctx, request)
 
-		assert.Error(t, err)
-		assert.Nil(t, response)
-		assert.Contains(t, err.Error(), "email already exists")
-
-		mockRepo.AssertExpectations(t)
+		assert.Nil(t, user)
+		assert.ErrorContains(t, err, "already exists")
+		mocks.users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 	})
 }
 
-func TestUserService_LoginUser(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	mockJWT := new(MockJWTService)
-	userService := NewUserService(mockRepo, mockJWT)
-
+func TestUserService_Login(t *testing.T) {
 	ctx := context.Background()
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
 
 	t.Run("successful login", func(t *testing.T) {
-		email := "test@example.com"
-		password := "password123"
-		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-
-		user := &entity.User{
-			ID:           uuid.New(),
-			Email:        email,
-			PasswordHash: string(hashedPassword),
-			FirstName:    "John",
-			LastName:     "Doe",
-			Role:         entity.RoleCustomer,
-			IsActive:     true,
-		}
+		service, mocks := newTestUserService()
+		user := &entity.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: string(hashedPassword), Role: "customer", IsActive: true}
 
-		request := &LoginUserRequest{
-			Email:    email,
-			Password: password,
-		}
-
-		mockRepo.On("GetUserByEmail", ctx, email).Return(user, nil)
-		mockRepo.On("UpdateLastLogin", ctx, user.ID).Return(nil)
-
-		tokens := &TokenPair{
-			AccessToken:  "access_token",
-			RefreshToken: "refresh_token",
-		}
-		mockJWT.On("GenerateTokens", user.ID, user.Email, user.Role).Return(tokens, nil)
+		mocks.users.On("GetByEmail", ctx, user.Email).Return(user, nil)
+		mocks.roles.On("GetPermissions", ctx, "customer").Return([]string{"orders:read"}, nil)
+		mocks.users.On("UpdateLastLogin", ctx, user.ID).Return(nil)
 
-		response, err := userService.LoginUser(ctx, request)
+		response, err := service.Login(ctx, &LoginRequest{Email: user.Email, Password: "password123"})
 
-		assert.NoError(t, err)
-		assert.NotNil(t, response)
-		assert.Equal(t, user.Email, response.User.Email)
-		assert.Equal(t, "access_token", response.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, user, response.User)
 
-		mockRepo.AssertExpectations(t)
-		mockJWT.AssertExpectations(t)
+		claims, err := service.ValidateToken(ctx, response.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID.String(), claims.UserID)
+		assert.Equal(t, []string{"orders:read"}, claims.Permissions)
+		mocks.users.AssertExpectations(t)
 	})
 
-	t.Run("invalid email", func(t *testing.T) {
-		request := &LoginUserRequest{
-			Email:    "nonexistent@example.com",
-			Password: "password123",
-		}
-
-		mockRepo.On("GetUserByEmail", ctx, request.Email).Return((*entity.User)(nil), entity.ErrUserNotFound)
+	t.Run("unknown email", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		mocks.users.On("GetByEmail", ctx, "nobody@example.com").Return(nil, errors.New("record not found"))
 
-		response, err := userService.LoginUser(ctx, request)
+		response, err := service.Login(ctx, &LoginRequest{Email: "nobody@example.com", Password: "password123"})
 
-		assert.Error(t, err)
 		assert.Nil(t, response)
-		assert.Equal(t, entity.ErrInvalidCredentials, err)
-
-		mockRepo.AssertExpectations(t)
+		assert.EqualError(t, err, "invalid email or password")
 	})
 
-	t.Run("invalid password", func(t *testing.T) {
-		email := "test@example.com"
-		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correct_password"), bcrypt.DefaultCost)
+	t.Run("wrong password", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		user := &entity.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: string(hashedPassword), IsActive: true}
+		mocks.users.On("GetByEmail", ctx, user.Email).Return(user, nil)
 
-		user := &entity.User{
-			ID:           uuid.New(),
-			Email:        email,
-			PasswordHash: string(hashedPassword),
-			IsActive:     true,
-		}
-
-		request := &LoginUserRequest{
-			Email:    email,
-			Password: "wrong_password",
-		}
-
-		mockRepo.On("GetUserByEmail", ctx, email).Return(user, nil)
+		response, err := service.Login(ctx, &LoginRequest{Email: user.Email, Password: "wrong_password"})
 
-		response, err := userService.LoginUser(ctx, request)
-
-		assert.Error(t, err)
 		assert.Nil(t, response)
-		assert.Equal(t, entity.ErrInvalidCredentials, err)
-
-		mockRepo.AssertExpectations(t)
+		assert.EqualError(t, err, "invalid email or password")
 	})
 
-	t.Run("inactive user", func(t *testing.T) {
-		email := "test@example.com"
-		password := "password123"
-		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-
-		user := &entity.User{
-			ID:           uuid.New(),
-			Email:        email,
-			PasswordHash: string(hashedPassword),
-			IsActive:     false,
-		}
-
-		request := &LoginUserRequest{
-			Email:    email,
-			Password: password,
-		}
-
-		mockRepo.On("GetUserByEmail", ctx, email).Return(user, nil)
+	t.Run("deactivated account", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		user := &entity.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: string(hashedPassword)}
+		mocks.users.On("GetByEmail", ctx, user.Email).Return(user, nil)
 
-		response, err := userService.LoginUser(ctx, request)
+		response, err := service.Login(ctx, &LoginRequest{Email: user.Email, Password: "password123"})
 
-		assert.Error(t, err)
 		assert.Nil(t, response)
-		assert.Contains(t, err.Error(), "account is deactivated")
-
-		mockRepo.AssertExpectations(t)
+		assert.EqualError(t, err, "account is deactivated")
 	})
 }
 
-func TestUserService_GetUserProfile(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	mockJWT := new(MockJWTService)
-	userService := NewUserService(mockRepo, mockJWT)
-
+func TestUserService_UpdateUser(t *testing.T) {
 	ctx := context.Background()
 	userID := uuid.New()
 
-	t.Run("successful profile retrieval", func(t *testing.T) {
-		user := &entity.User{
-			ID:        userID,
-			Email:     "test@example.com",
-			FirstName: "John",
-			LastName:  "Doe",
-			Role:      entity.RoleCustomer,
-			IsActive:  true,
-			CreatedAt: time.Now(),
-		}
-
-		mockRepo.On("GetUserByID", ctx, userID).Return(user, nil)
-
-		response, err := userService.GetUserProfile(ctx, userID)
-
-		assert.NoError(t, err)
-		assert.NotNil(t, response)
-		assert.Equal(t, user.Email, response.Email)
-		assert.Equal(t, user.FirstName, response.FirstName)
-
-		mockRepo.AssertExpectations(t)
+	t.Run("successful profile update", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		mocks.users.On("GetByID", ctx, userID).Return(&entity.User{ID: userID, FirstName: "John", LastName: "Doe"}, nil)
+		mocks.users.On("Update", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+
+		user, err := service.UpdateUser(ctx, userID, &UpdateUserRequest{
+			FirstName:   stringPtr("Jane"),
+			PhoneNumber: stringPtr("+14155552671"),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Jane", user.FirstName)
+		assert.Equal(t, "Doe", user.LastName)
+		assert.Equal(t, "+14155552671", user.PhoneNumber)
 	})
 
-	t.Run("user not found", func(t *testing.T) {
-		mockRepo.On("GetUserByID", ctx, userID).Return((*entity.User)(nil), entity.ErrUserNotFound)
+	t.Run("invalid phone number", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		mocks.users.On("GetByID", ctx, userID).Return(&entity.User{ID: userID}, nil)
 
-		response, err := userService.GetUserProfile(ctx, userID)
+		_, err := service.UpdateUser(ctx, userID, &UpdateUserRequest{PhoneNumber: stringPtr("call me")})
 
-		assert.Error(t, err)
-		assert.Nil(t, response)
-		assert.Equal(t, entity.ErrUserNotFound, err)
-
-		mockRepo.AssertExpectations(t)
+		assert.EqualError(t, err, "invalid phone number format")
+		mocks.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
 	})
 }
 
-func TestUserService_UpdateUserProfile(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	mockJWT := new(MockJWTService)
-	userService := NewUserService(mockRepo, mockJWT)
-
+func TestUserService_RefreshToken(t *testing.T) {
 	ctx := context.Background()
-	userID := uuid.New()
+	user := &entity.User{ID: uuid.New(), Email: "test@example.com", Role: "customer", IsActive: true}
 
-	t.Run("successful profile update", func(t *testing.T) {
-		existingUser := &entity.User{
-			ID:        userID,
-			Email:     "test@example.com",
-			FirstName: "John",
-			LastName:  "Doe",
-			Phone:     "+1234567890",
-		}
-
-		request := &UpdateUserProfileRequest{
-			FirstName: stringPtr("Jane"),
-			LastName:  stringPtr("Smith"),
-			Phone:     stringPtr("+0987654321"),
-		}
+	_, refreshToken, err := auth.NewJWTManager().GenerateTokenPair(user.ID.String(), user.Email, user.Role, nil)
+	require.NoError(t, err)
 
-		mockRepo.On("GetUserByID", ctx, userID).Return(existingUser, nil)
-		mockRepo.On("UpdateUser", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+	t.Run("active session", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		mocks.users.On("GetByID", ctx, user.ID).Return(user, nil)
+		mocks.roles.On("GetPermissions", ctx, "customer").Return([]string{}, nil)
 
-		response, err := userService.UpdateUserProfile(ctx, userID, request)
+		accessToken, newRefreshToken, err := service.RefreshToken(ctx, refreshToken)
 
-		assert.NoError(t, err)
-		assert.NotNil(t, response)
-		assert.Equal(t, "Jane", response.FirstName)
-		assert.Equal(t, "Smith", response.LastName)
-		assert.Equal(t, "+0987654321", response.Phone)
-
-		mockRepo.AssertExpectations(t)
+		require.NoError(t, err)
+		assert.NotEmpty(t, accessToken)
+		assert.NotEmpty(t, newRefreshToken)
 	})
-}
-
-func TestUserService_RefreshTokens(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	mockJWT := new(MockJWTService)
-	userService := NewUserService(mockRepo, mockJWT)
 
-	ctx := context.Background()
+	t.Run("revoked session", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		revokedAt := time.Now().Add(time.Second)
+		revoked := *user
+		revoked.SessionsRevokedAt = &revokedAt
+		mocks.users.On("GetByID", ctx, user.ID).Return(&revoked, nil)
 
-	t.Run("successful token refresh", func(t *testing.T) {
-		refreshToken := "valid_refresh_token"
-		newTokens := &TokenPair{
-			AccessToken:  "new_access_token",
-			RefreshToken: "new_refresh_token",
-		}
+		_, _, err := service.RefreshToken(ctx, refreshToken)
 
-		mockJWT.On("RefreshTokens", refreshToken).Return(newTokens, nil)
+		assert.EqualError(t, err, "invalid refresh token")
+	})
 
-		response, err := userService.RefreshTokens(ctx, refreshToken)
+	t.Run("deactivated user", func(t *testing.T) {
+		service, mocks := newTestUserService()
+		inactive := *user
+		inactive.IsActive = false
+		mocks.users.On("GetByID", ctx, user.ID).Return(&inactive, nil)
 
-		assert.NoError(t, err)
-		assert.NotNil(t, response)
-		assert.Equal(t, "new_access_token", response.AccessToken)
-		assert.Equal(t, "new_refresh_token", response.RefreshToken)
+		_, _, err := service.RefreshToken(ctx, refreshToken)
 
-		mockJWT.AssertExpectations(t)
+		assert.EqualError(t, err, "invalid refresh token")
 	})
 
-	t.Run("invalid refresh token", func(t *testing.T) {
-		refreshToken := "invalid_refresh_token"
-
-		mockJWT.On("RefreshTokens", refreshToken).Return((*TokenPair)(nil), entity.ErrInvalidToken)
+	t.Run("malformed token", func(t *testing.T) {
+		service, _ := newTestUserService()
 
-		response, err := userService.RefreshTokens(ctx, refreshToken)
+		_, _, err := service.RefreshToken(ctx, "not-a-token")
 
-		assert.Error(t, err)
-		assert.Nil(t, response)
-		assert.Equal(t, entity.ErrInvalidToken, err)
-
-		mockJWT.AssertExpectations(t)
+		assert.EqualError(t, err, "invalid refresh token")
 	})
 }
 
 // Helper function
 func stringPtr(s string) *string {
 	return &s
-}
\ No newline at end of file
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/pkg/productalerts"
+	"solemate/services/user-service/internal/domain/entity"
+)
+
+// wishlistAlertCooldown is how long a user who was alerted about a product
+// is left alone before another alert of the same type for it goes out.
+const wishlistAlertCooldown = 24 * time.Hour
+
+// ProductAlertResult summarises how a product event was fanned out to the
+// users watching the product
+type ProductAlertResult struct {
+	Watchers int `json:"watchers"`
+	Notified int `json:"notified"`
+	Skipped  int `json:"skipped"` // alerted recently, or opted out of this kind of alert
+	Failed   int `json:"failed"`
+}
+
+// AcceptProductEvent validates a product event and alerts the users watching
+// the product in the background, so the service reporting the change doesn't
+// wait for one notification per watcher
+func (s *WishlistService) AcceptProductEvent(event *productalerts.Event) error {
+	if err := validateProductEvent(event); err != nil {
+		return err
+	}
+
+	go func() {
+		result, err := s.HandleProductEvent(context.Background(), event)
+		if err != nil {
+			log.Printf("wishlist alert %s for product %s: %v", event.Type, event.ProductID, err)
+			return
+		}
+		log.Printf("wishlist alert %s for product %s: %d watchers, %d notified, %d skipped, %d failed",
+			event.Type, event.ProductID, result.Watchers, result.Notified, result.Skipped, result.Failed)
+	}()
+	return nil
+}
+
+// HandleProductEvent alerts every user who has the product in a wishlist
+// about a price drop or restock. A user gets at most one alert of each type
+// per product within wishlistAlertCooldown.
+func (s *WishlistService) HandleProductEvent(ctx context.Context, event *productalerts.Event) (*ProductAlertResult, error) {
+	if err := validateProductEvent(event); err != nil {
+		return nil, err
+	}
+
+	userIDs, err := s.wishlistRepo.GetUserIDsByProductID(ctx, event.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find wishlists with product: %w", err)
+	}
+
+	result := &ProductAlertResult{Watchers: len(userIDs)}
+	now := time.Now()
+
+	for _, userID := range userIDs {
+		alert, err := s.wishlistRepo.GetAlert(ctx, userID, event.ProductID, event.Type)
+		if err == nil && now.Sub(alert.NotifiedAt) < wishlistAlertCooldown {
+			result.Skipped++
+			continue
+		}
+
+		sent, err := s.notificationRepo.SendProductAlert(ctx, userID, event)
+		if err != nil {
+			log.Printf("wishlist alert %s for product %s to user %s: %v", event.Type, event.ProductID, userID, err)
+			result.Failed++
+			continue
+		}
+		if !sent {
+			result.Skipped++
+			continue
+		}
+		result.Notified++
+
+		if err := s.recordAlert(ctx, alert, userID, event, now); err != nil {
+			log.Printf("wishlist alert %s for product %s to user %s: failed to record: %v", event.Type, event.ProductID, userID, err)
+		}
+	}
+
+	return result, nil
+}
+
+func validateProductEvent(event *productalerts.Event) error {
+	if event.Type == productalerts.TypePriceDrop {
+		if event.OldPrice == nil || event.NewPrice == nil || *event.NewPrice >= *event.OldPrice {
+			return errors.New("price drop requires a new price below the old price")
+		}
+	}
+	return nil
+}
+
+func (s *WishlistService) recordAlert(ctx context.Context, alert *entity.WishlistAlert, userID uuid.UUID, event *productalerts.Event, now time.Time) error {
+	if alert == nil {
+		alert = &entity.WishlistAlert{
+			UserID:    userID,
+			ProductID: event.ProductID,
+			Type:      event.Type,
+		}
+	}
+	alert.Price = event.NewPrice
+	alert.NotifiedAt = now
+
+	return s.wishlistRepo.SaveAlert(ctx, alert)
+}
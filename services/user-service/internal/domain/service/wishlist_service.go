@@ -2,20 +2,34 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"solemate/services/user-service/internal/domain/entity"
 	"solemate/services/user-service/internal/domain/repository"
 )
 
+const (
+	MoveStatusMoved              = "moved"
+	MoveStatusOutOfStock         = "out_of_stock"
+	MoveStatusProductUnavailable = "product_unavailable"
+	MoveStatusNotInWishlist      = "not_in_wishlist"
+	MoveStatusFailed             = "failed"
+)
+
 type WishlistService struct {
-	wishlistRepo repository.WishlistRepository
+	wishlistRepo     repository.WishlistRepository
+	cartRepo         repository.CartRepository
+	notificationRepo repository.NotificationRepository
 }
 
-func NewWishlistService(wishlistRepo repository.WishlistRepository) *WishlistService {
+func NewWishlistService(wishlistRepo repository.WishlistRepository, cartRepo repository.CartRepository, notificationRepo repository.NotificationRepository) *WishlistService {
 	return &WishlistService{
-		wishlistRepo: wishlistRepo,
+		wishlistRepo:     wishlistRepo,
+		cartRepo:         cartRepo,
+		notificationRepo: notificationRepo,
 	}
 }
 
@@ -25,9 +39,40 @@ type WishlistResponse struct {
 	TotalItems int                    `json:"totalItems"`
 }
 
-// GetWishlist retrieves all wishlist items for a user
+type MoveToCartItem struct {
+	ProductID uuid.UUID  `json:"product_id" binding:"required"`
+	VariantID *uuid.UUID `json:"variant_id"`
+	Quantity  int        `json:"quantity"`
+}
+
+type MoveToCartRequest struct {
+	WishlistID *uuid.UUID       `json:"wishlist_id"` // defaults to the user's default wishlist
+	Items      []MoveToCartItem `json:"items" binding:"required,min=1,dive"`
+}
+
+// MoveToCartResult reports what happened to a single requested item
+type MoveToCartResult struct {
+	ProductID uuid.UUID  `json:"product_id"`
+	VariantID *uuid.UUID `json:"variant_id,omitempty"`
+	Quantity  int        `json:"quantity"`
+	Status    string     `json:"status"`
+	Error     string     `json:"error,omitempty"`
+}
+
+type MoveToCartResponse struct {
+	Results []*MoveToCartResult `json:"results"`
+	Moved   int                 `json:"moved"`
+	Failed  int                 `json:"failed"`
+}
+
+// GetWishlist retrieves all items in the user's default wishlist
 func (s *WishlistService) GetWishlist(ctx context.Context, userID uuid.UUID) (*WishlistResponse, error) {
-	items, err := s.wishlistRepo.GetByUserID(ctx, userID)
+	wishlist, err := s.defaultList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.wishlistRepo.GetItems(ctx, wishlist.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wishlist: %w", err)
 	}
@@ -38,35 +83,24 @@ func (s *WishlistService) GetWishlist(ctx context.Context, userID uuid.UUID) (*W
 	}, nil
 }
 
-// AddToWishlist adds a product to user's wishlist
+// AddToWishlist adds a product to user's default wishlist
 func (s *WishlistService) AddToWishlist(ctx context.Context, userID, productID uuid.UUID) (*entity.WishlistItem, error) {
-	// Check if product already exists in wishlist
-	exists, err := s.wishlistRepo.ItemExists(ctx, userID, productID)
+	wishlist, err := s.defaultList(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check wishlist item: %w", err)
+		return nil, err
 	}
 
-	if exists {
-		// Return existing item if already in wishlist (graceful handling)
-		item, err := s.wishlistRepo.GetItemByProductID(ctx, userID, productID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get existing wishlist item: %w", err)
-		}
-		return item, nil
-	}
+	return s.addItem(ctx, wishlist, productID)
+}
 
-	// Add new item to wishlist
-	item, err := s.wishlistRepo.AddItem(ctx, userID, productID)
+// RemoveFromWishlist removes a product from user's default wishlist by product ID
+func (s *WishlistService) RemoveFromWishlist(ctx context.Context, userID, productID uuid.UUID) error {
+	wishlist, err := s.defaultList(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add to wishlist: %w", err)
+		return err
 	}
 
-	return item, nil
-}
-
-// RemoveFromWishlist removes a product from user's wishlist by product ID
-func (s *WishlistService) RemoveFromWishlist(ctx context.Context, userID, productID uuid.UUID) error {
-	err := s.wishlistRepo.RemoveItem(ctx, userID, productID)
+	err = s.wishlistRepo.RemoveItem(ctx, wishlist.ID, productID)
 	if err != nil {
 		return fmt.Errorf("failed to remove from wishlist: %w", err)
 	}
@@ -74,9 +108,14 @@ func (s *WishlistService) RemoveFromWishlist(ctx context.Context, userID, produc
 	return nil
 }
 
-// ClearWishlist removes all items from user's wishlist
+// ClearWishlist removes all items from user's default wishlist
 func (s *WishlistService) ClearWishlist(ctx context.Context, userID uuid.UUID) error {
-	err := s.wishlistRepo.ClearWishlist(ctx, userID)
+	wishlist, err := s.defaultList(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.wishlistRepo.ClearWishlist(ctx, wishlist.ID)
 	if err != nil {
 		return fmt.Errorf("failed to clear wishlist: %w", err)
 	}
@@ -84,7 +123,92 @@ func (s *WishlistService) ClearWishlist(ctx context.Context, userID uuid.UUID) e
 	return nil
 }
 
-// IsInWishlist checks if a product is in user's wishlist
+// IsInWishlist checks if a product is in user's default wishlist
 func (s *WishlistService) IsInWishlist(ctx context.Context, userID, productID uuid.UUID) (bool, error) {
-	return s.wishlistRepo.ItemExists(ctx, userID, productID)
+	wishlist, err := s.defaultList(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return s.wishlistRepo.ItemExists(ctx, wishlist.ID, productID)
+}
+
+// GetWishlistAdds counts, per product, the users who saved it between
+// startDate and endDate. Product-service uses it to rank products by
+// popularity.
+func (s *WishlistService) GetWishlistAdds(ctx context.Context, startDate, endDate time.Time) ([]*repository.ProductWishlistAdds, error) {
+	return s.wishlistRepo.CountAddsByProduct(ctx, startDate, endDate)
+}
+
+// MoveToCart adds items from one of the user's wishlists to their cart. A product is removed from
+// the wishlist only if every requested line for it was added to the cart.
+func (s *WishlistService) MoveToCart(ctx context.Context, userID uuid.UUID, req *MoveToCartRequest) (*MoveToCartResponse, error) {
+	wishlist, err := s.resolveList(ctx, userID, req.WishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MoveToCartResponse{
+		Results: make([]*MoveToCartResult, 0, len(req.Items)),
+	}
+	added := make(map[uuid.UUID]bool)
+	rejected := make(map[uuid.UUID]bool)
+
+	for _, item := range req.Items {
+		quantity := item.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+
+		result := &MoveToCartResult{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  quantity,
+		}
+		response.Results = append(response.Results, result)
+
+		exists, err := s.wishlistRepo.ItemExists(ctx, wishlist.ID, item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check wishlist item: %w", err)
+		}
+		if !exists {
+			result.Status = MoveStatusNotInWishlist
+			continue
+		}
+
+		err = s.cartRepo.AddItem(ctx, userID, item.ProductID, item.VariantID, quantity)
+		switch {
+		case err == nil:
+			result.Status = MoveStatusMoved
+			added[item.ProductID] = true
+			continue
+		case errors.Is(err, repository.ErrCartOutOfStock):
+			result.Status = MoveStatusOutOfStock
+		case errors.Is(err, repository.ErrCartProductUnavailable):
+			result.Status = MoveStatusProductUnavailable
+		default:
+			result.Status = MoveStatusFailed
+			result.Error = err.Error()
+		}
+		rejected[item.ProductID] = true
+	}
+
+	for productID := range added {
+		if rejected[productID] {
+			continue
+		}
+		if err := s.wishlistRepo.RemoveItem(ctx, wishlist.ID, productID); err != nil {
+			return nil, fmt.Errorf("items were added to cart but removing them from wishlist failed: %w", err)
+		}
+	}
+
+	for _, result := range response.Results {
+		if result.Status == MoveStatusMoved {
+			response.Moved++
+		} else {
+			response.Failed++
+		}
+	}
+
+	return response, nil
 }
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"solemate/pkg/productalerts"
 	"solemate/services/user-service/internal/domain/entity"
+	"solemate/services/user-service/internal/domain/repository"
 )
 
 // MockWishlistRepository is a mock implementation of repository.WishlistRepository
@@ -16,6 +19,69 @@ type MockWishlistRepository struct {
 	mock.Mock
 }
 
+func (m *MockWishlistRepository) CreateList(ctx context.Context, wishlist *entity.Wishlist) error {
+	args := m.Called(ctx, wishlist)
+	if wishlist.ID == uuid.Nil {
+		wishlist.ID = uuid.New()
+	}
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepository) CreateDefaultList(ctx context.Context, wishlist *entity.Wishlist) (bool, error) {
+	args := m.Called(ctx, wishlist)
+	if wishlist.ID == uuid.Nil {
+		wishlist.ID = uuid.New()
+	}
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWishlistRepository) GetList(ctx context.Context, id uuid.UUID) (*entity.Wishlist, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Wishlist), args.Error(1)
+}
+
+func (m *MockWishlistRepository) GetListBySlug(ctx context.Context, slug string) (*entity.Wishlist, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Wishlist), args.Error(1)
+}
+
+func (m *MockWishlistRepository) GetDefaultList(ctx context.Context, userID uuid.UUID) (*entity.Wishlist, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Wishlist), args.Error(1)
+}
+
+func (m *MockWishlistRepository) GetListsByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Wishlist, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Wishlist), args.Error(1)
+}
+
+func (m *MockWishlistRepository) UpdateList(ctx context.Context, wishlist *entity.Wishlist) error {
+	args := m.Called(ctx, wishlist)
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepository) DeleteList(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepository) AssignOrphanItems(ctx context.Context, userID, wishlistID uuid.UUID) error {
+	args := m.Called(ctx, userID, wishlistID)
+	return args.Error(0)
+}
+
 func (m *MockWishlistRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WishlistItem, error) {
 	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
@@ -24,57 +90,135 @@ func (m *MockWishlistRepository) GetByUserID(ctx context.Context, userID uuid.UU
 	return args.Get(0).([]*entity.WishlistItem), args.Error(1)
 }
 
-func (m *MockWishlistRepository) AddItem(ctx context.Context, userID, productID uuid.UUID) (*entity.WishlistItem, error) {
-	args := m.Called(ctx, userID, productID)
+func (m *MockWishlistRepository) GetItems(ctx context.Context, wishlistID uuid.UUID) ([]*entity.WishlistItem, error) {
+	args := m.Called(ctx, wishlistID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.WishlistItem), args.Error(1)
+}
+
+func (m *MockWishlistRepository) AddItem(ctx context.Context, wishlistID, userID, productID uuid.UUID) (*entity.WishlistItem, error) {
+	args := m.Called(ctx, wishlistID, userID, productID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.WishlistItem), args.Error(1)
 }
 
-func (m *MockWishlistRepository) RemoveItem(ctx context.Context, userID, productID uuid.UUID) error {
-	args := m.Called(ctx, userID, productID)
+func (m *MockWishlistRepository) RemoveItem(ctx context.Context, wishlistID, productID uuid.UUID) error {
+	args := m.Called(ctx, wishlistID, productID)
 	return args.Error(0)
 }
 
-func (m *MockWishlistRepository) ClearWishlist(ctx context.Context, userID uuid.UUID) error {
-	args := m.Called(ctx, userID)
+func (m *MockWishlistRepository) ClearWishlist(ctx context.Context, wishlistID uuid.UUID) error {
+	args := m.Called(ctx, wishlistID)
 	return args.Error(0)
 }
 
-func (m *MockWishlistRepository) ItemExists(ctx context.Context, userID, productID uuid.UUID) (bool, error) {
-	args := m.Called(ctx, userID, productID)
+func (m *MockWishlistRepository) ItemExists(ctx context.Context, wishlistID, productID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, wishlistID, productID)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockWishlistRepository) GetItemByProductID(ctx context.Context, userID, productID uuid.UUID) (*entity.WishlistItem, error) {
-	args := m.Called(ctx, userID, productID)
+func (m *MockWishlistRepository) GetItemByProductID(ctx context.Context, wishlistID, productID uuid.UUID) (*entity.WishlistItem, error) {
+	args := m.Called(ctx, wishlistID, productID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.WishlistItem), args.Error(1)
 }
 
+func (m *MockWishlistRepository) GetUserIDsByProductID(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockWishlistRepository) CountAddsByProduct(ctx context.Context, startDate, endDate time.Time) ([]*repository.ProductWishlistAdds, error) {
+	args := m.Called(ctx, startDate, endDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repository.ProductWishlistAdds), args.Error(1)
+}
+
+func (m *MockWishlistRepository) GetAlert(ctx context.Context, userID, productID uuid.UUID, alertType string) (*entity.WishlistAlert, error) {
+	args := m.Called(ctx, userID, productID, alertType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WishlistAlert), args.Error(1)
+}
+
+func (m *MockWishlistRepository) SaveAlert(ctx context.Context, alert *entity.WishlistAlert) error {
+	args := m.Called(ctx, alert)
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
+
+// MockCartRepository is a mock implementation of repository.CartRepository
+type MockCartRepository struct {
+	mock.Mock
+}
+
+func (m *MockCartRepository) AddItem(ctx context.Context, userID, productID uuid.UUID, variantID *uuid.UUID, quantity int) error {
+	args := m.Called(ctx, userID, productID, variantID, quantity)
+	return args.Error(0)
+}
+
+// MockNotificationRepository is a mock implementation of repository.NotificationRepository
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) SendProductAlert(ctx context.Context, userID uuid.UUID, event *productalerts.Event) (bool, error) {
+	args := m.Called(ctx, userID, event)
+	return args.Bool(0), args.Error(1)
+}
+
+// expectDefaultList makes the mock return an existing default wishlist for userID
+func expectDefaultList(mockRepo *MockWishlistRepository, ctx context.Context, userID uuid.UUID) *entity.Wishlist {
+	wishlist := &entity.Wishlist{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Name:       "My Wishlist",
+		Visibility: entity.WishlistPrivate,
+		IsDefault:  true,
+	}
+	mockRepo.On("GetDefaultList", ctx, userID).Return(wishlist, nil)
+	return wishlist
+}
+
 func TestWishlistService_GetWishlist(t *testing.T) {
 	mockRepo := new(MockWishlistRepository)
-	service := NewWishlistService(mockRepo)
+	service := NewWishlistService(mockRepo, nil, nil)
 	ctx := context.Background()
 	userID := uuid.New()
+	wishlist := expectDefaultList(mockRepo, ctx, userID)
 
 	items := []*entity.WishlistItem{
 		{
-			ID:        uuid.New(),
-			UserID:    userID,
-			ProductID: uuid.New(),
+			ID:         uuid.New(),
+			WishlistID: wishlist.ID,
+			UserID:     userID,
+			ProductID:  uuid.New(),
 		},
 		{
-			ID:        uuid.New(),
-			UserID:    userID,
-			ProductID: uuid.New(),
+			ID:         uuid.New(),
+			WishlistID: wishlist.ID,
+			UserID:     userID,
+			ProductID:  uuid.New(),
 		},
 	}
 
-	mockRepo.On("GetByUserID", ctx, userID).Return(items, nil)
+	mockRepo.On("GetItems", ctx, wishlist.ID).Return(items, nil)
 
 	result, err := service.GetWishlist(ctx, userID)
 
@@ -85,21 +229,76 @@ func TestWishlistService_GetWishlist(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestWishlistService_GetWishlist_CreatesDefaultList(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	service := NewWishlistService(mockRepo, nil, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	mockRepo.On("GetDefaultList", ctx, userID).Return(nil, repository.ErrWishlistNotFound)
+	mockRepo.On("CreateDefaultList", ctx, mock.MatchedBy(func(w *entity.Wishlist) bool {
+		return w.UserID == userID && w.IsDefault && w.Visibility == entity.WishlistPrivate && len(w.ShareSlug) >= 22
+	})).Return(true, nil)
+	mockRepo.On("AssignOrphanItems", ctx, userID, mock.AnythingOfType("uuid.UUID")).Return(nil)
+	mockRepo.On("GetItems", ctx, mock.AnythingOfType("uuid.UUID")).Return([]*entity.WishlistItem{}, nil)
+
+	result, err := service.GetWishlist(ctx, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.TotalItems)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWishlistService_GetWishlist_DefaultListCreatedConcurrently(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	service := NewWishlistService(mockRepo, nil, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+	existing := &entity.Wishlist{ID: uuid.New(), UserID: userID, IsDefault: true}
+
+	mockRepo.On("GetDefaultList", ctx, userID).Return(nil, repository.ErrWishlistNotFound).Once()
+	mockRepo.On("CreateDefaultList", ctx, mock.AnythingOfType("*entity.Wishlist")).Return(false, nil)
+	mockRepo.On("GetDefaultList", ctx, userID).Return(existing, nil).Once()
+	mockRepo.On("GetItems", ctx, existing.ID).Return([]*entity.WishlistItem{}, nil)
+
+	_, err := service.GetWishlist(ctx, userID)
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "AssignOrphanItems", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWishlistService_GetWishlist_DefaultListLookupFails(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	service := NewWishlistService(mockRepo, nil, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	mockRepo.On("GetDefaultList", ctx, userID).Return(nil, errors.New("connection refused"))
+
+	_, err := service.GetWishlist(ctx, userID)
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "CreateDefaultList", mock.Anything, mock.Anything)
+}
+
 func TestWishlistService_AddToWishlist_NewItem(t *testing.T) {
 	mockRepo := new(MockWishlistRepository)
-	service := NewWishlistService(mockRepo)
+	service := NewWishlistService(mockRepo, nil, nil)
 	ctx := context.Background()
 	userID := uuid.New()
 	productID := uuid.New()
+	wishlist := expectDefaultList(mockRepo, ctx, userID)
 
 	expectedItem := &entity.WishlistItem{
-		ID:        uuid.New(),
-		UserID:    userID,
-		ProductID: productID,
+		ID:         uuid.New(),
+		WishlistID: wishlist.ID,
+		UserID:     userID,
+		ProductID:  productID,
 	}
 
-	mockRepo.On("ItemExists", ctx, userID, productID).Return(false, nil)
-	mockRepo.On("AddItem", ctx, userID, productID).Return(expectedItem, nil)
+	mockRepo.On("ItemExists", ctx, wishlist.ID, productID).Return(false, nil)
+	mockRepo.On("AddItem", ctx, wishlist.ID, userID, productID).Return(expectedItem, nil)
 
 	result, err := service.AddToWishlist(ctx, userID, productID)
 
@@ -111,19 +310,21 @@ func TestWishlistService_AddToWishlist_NewItem(t *testing.T) {
 
 func TestWishlistService_AddToWishlist_ExistingItem(t *testing.T) {
 	mockRepo := new(MockWishlistRepository)
-	service := NewWishlistService(mockRepo)
+	service := NewWishlistService(mockRepo, nil, nil)
 	ctx := context.Background()
 	userID := uuid.New()
 	productID := uuid.New()
+	wishlist := expectDefaultList(mockRepo, ctx, userID)
 
 	existingItem := &entity.WishlistItem{
-		ID:        uuid.New(),
-		UserID:    userID,
-		ProductID: productID,
+		ID:         uuid.New(),
+		WishlistID: wishlist.ID,
+		UserID:     userID,
+		ProductID:  productID,
 	}
 
-	mockRepo.On("ItemExists", ctx, userID, productID).Return(true, nil)
-	mockRepo.On("GetItemByProductID", ctx, userID, productID).Return(existingItem, nil)
+	mockRepo.On("ItemExists", ctx, wishlist.ID, productID).Return(true, nil)
+	mockRepo.On("GetItemByProductID", ctx, wishlist.ID, productID).Return(existingItem, nil)
 
 	result, err := service.AddToWishlist(ctx, userID, productID)
 
@@ -135,12 +336,13 @@ func TestWishlistService_AddToWishlist_ExistingItem(t *testing.T) {
 
 func TestWishlistService_RemoveFromWishlist(t *testing.T) {
 	mockRepo := new(MockWishlistRepository)
-	service := NewWishlistService(mockRepo)
+	service := NewWishlistService(mockRepo, nil, nil)
 	ctx := context.Background()
 	userID := uuid.New()
 	productID := uuid.New()
+	wishlist := expectDefaultList(mockRepo, ctx, userID)
 
-	mockRepo.On("RemoveItem", ctx, userID, productID).Return(nil)
+	mockRepo.On("RemoveItem", ctx, wishlist.ID, productID).Return(nil)
 
 	err := service.RemoveFromWishlist(ctx, userID, productID)
 
@@ -150,12 +352,13 @@ func TestWishlistService_RemoveFromWishlist(t *testing.T) {
 
 func TestWishlistService_RemoveFromWishlist_Error(t *testing.T) {
 	mockRepo := new(MockWishlistRepository)
-	service := NewWishlistService(mockRepo)
+	service := NewWishlistService(mockRepo, nil, nil)
 	ctx := context.Background()
 	userID := uuid.New()
 	productID := uuid.New()
+	wishlist := expectDefaultList(mockRepo, ctx, userID)
 
-	mockRepo.On("RemoveItem", ctx, userID, productID).Return(errors.New("item not found"))
+	mockRepo.On("RemoveItem", ctx, wishlist.ID, productID).Return(errors.New("item not found"))
 
 	err := service.RemoveFromWishlist(ctx, userID, productID)
 
@@ -165,11 +368,12 @@ func TestWishlistService_RemoveFromWishlist_Error(t *testing.T) {
 
 func TestWishlistService_ClearWishlist(t *testing.T) {
 	mockRepo := new(MockWishlistRepository)
-	service := NewWishlistService(mockRepo)
+	service := NewWishlistService(mockRepo, nil, nil)
 	ctx := context.Background()
 	userID := uuid.New()
+	wishlist := expectDefaultList(mockRepo, ctx, userID)
 
-	mockRepo.On("ClearWishlist", ctx, userID).Return(nil)
+	mockRepo.On("ClearWishlist", ctx, wishlist.ID).Return(nil)
 
 	err := service.ClearWishlist(ctx, userID)
 
@@ -179,12 +383,13 @@ func TestWishlistService_ClearWishlist(t *testing.T) {
 
 func TestWishlistService_IsInWishlist(t *testing.T) {
 	mockRepo := new(MockWishlistRepository)
-	service := NewWishlistService(mockRepo)
+	service := NewWishlistService(mockRepo, nil, nil)
 	ctx := context.Background()
 	userID := uuid.New()
 	productID := uuid.New()
+	wishlist := expectDefaultList(mockRepo, ctx, userID)
 
-	mockRepo.On("ItemExists", ctx, userID, productID).Return(true, nil)
+	mockRepo.On("ItemExists", ctx, wishlist.ID, productID).Return(true, nil)
 
 	result, err := service.IsInWishlist(ctx, userID, productID)
 
@@ -192,3 +397,227 @@ func TestWishlistService_IsInWishlist(t *testing.T) {
 	assert.True(t, result)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestWishlistService_DeleteWishlist_Default(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	service := NewWishlistService(mockRepo, nil, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+	wishlist := &entity.Wishlist{ID: uuid.New(), UserID: userID, IsDefault: true}
+
+	mockRepo.On("GetList", ctx, wishlist.ID).Return(wishlist, nil)
+
+	err := service.DeleteWishlist(ctx, userID, wishlist.ID)
+
+	assert.ErrorIs(t, err, ErrDefaultWishlistDelete)
+	mockRepo.AssertNotCalled(t, "DeleteList", ctx, wishlist.ID)
+}
+
+func TestWishlistService_GetUserWishlist_OtherUser(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	service := NewWishlistService(mockRepo, nil, nil)
+	ctx := context.Background()
+	wishlist := &entity.Wishlist{ID: uuid.New(), UserID: uuid.New()}
+
+	mockRepo.On("GetList", ctx, wishlist.ID).Return(wishlist, nil)
+
+	_, err := service.GetUserWishlist(ctx, uuid.New(), wishlist.ID)
+
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+}
+
+func TestWishlistService_GetSharedWishlist_Private(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	service := NewWishlistService(mockRepo, nil, nil)
+	ctx := context.Background()
+	wishlist := &entity.Wishlist{ID: uuid.New(), UserID: uuid.New(), ShareSlug: "slug", Visibility: entity.WishlistPrivate}
+
+	mockRepo.On("GetListBySlug", ctx, "slug").Return(wishlist, nil)
+
+	_, err := service.GetSharedWishlist(ctx, "slug")
+
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+	mockRepo.AssertNotCalled(t, "GetItems", ctx, wishlist.ID)
+}
+
+func TestWishlistService_CopySharedWishlist(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	service := NewWishlistService(mockRepo, nil, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+	target := expectDefaultList(mockRepo, ctx, userID)
+	source := &entity.Wishlist{ID: uuid.New(), UserID: uuid.New(), ShareSlug: "slug", Visibility: entity.WishlistShared}
+	owned := uuid.New()
+	fresh := uuid.New()
+
+	mockRepo.On("GetListBySlug", ctx, "slug").Return(source, nil)
+	mockRepo.On("GetItems", ctx, source.ID).Return([]*entity.WishlistItem{
+		{ID: uuid.New(), WishlistID: source.ID, ProductID: owned},
+		{ID: uuid.New(), WishlistID: source.ID, ProductID: fresh},
+	}, nil)
+	mockRepo.On("ItemExists", ctx, target.ID, owned).Return(true, nil)
+	mockRepo.On("ItemExists", ctx, target.ID, fresh).Return(false, nil)
+	mockRepo.On("AddItem", ctx, target.ID, userID, fresh).Return(&entity.WishlistItem{ID: uuid.New()}, nil)
+
+	result, err := service.CopySharedWishlist(ctx, userID, "slug", &CopyWishlistRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, target.ID, result.WishlistID)
+	assert.Equal(t, 1, result.Copied)
+	assert.Equal(t, 1, result.Skipped)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWishlistService_MoveToCart(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	mockCart := new(MockCartRepository)
+	service := NewWishlistService(mockRepo, mockCart, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+	productID := uuid.New()
+	variantID := uuid.New()
+	wishlist := expectDefaultList(mockRepo, ctx, userID)
+
+	mockRepo.On("ItemExists", ctx, wishlist.ID, productID).Return(true, nil)
+	mockCart.On("AddItem", ctx, userID, productID, &variantID, 2).Return(nil)
+	mockRepo.On("RemoveItem", ctx, wishlist.ID, productID).Return(nil)
+
+	result, err := service.MoveToCart(ctx, userID, &MoveToCartRequest{
+		Items: []MoveToCartItem{{ProductID: productID, VariantID: &variantID, Quantity: 2}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Moved)
+	assert.Equal(t, 0, result.Failed)
+	assert.Equal(t, MoveStatusMoved, result.Results[0].Status)
+	mockRepo.AssertExpectations(t)
+	mockCart.AssertExpectations(t)
+}
+
+func TestWishlistService_MoveToCart_PartialFailure(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	mockCart := new(MockCartRepository)
+	service := NewWishlistService(mockRepo, mockCart, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+	inStock := uuid.New()
+	outOfStock := uuid.New()
+	inactive := uuid.New()
+	missing := uuid.New()
+	wishlist := expectDefaultList(mockRepo, ctx, userID)
+
+	mockRepo.On("ItemExists", ctx, wishlist.ID, inStock).Return(true, nil)
+	mockRepo.On("ItemExists", ctx, wishlist.ID, outOfStock).Return(true, nil)
+	mockRepo.On("ItemExists", ctx, wishlist.ID, inactive).Return(true, nil)
+	mockRepo.On("ItemExists", ctx, wishlist.ID, missing).Return(false, nil)
+	mockCart.On("AddItem", ctx, userID, inStock, (*uuid.UUID)(nil), 1).Return(nil)
+	mockCart.On("AddItem", ctx, userID, outOfStock, (*uuid.UUID)(nil), 1).Return(repository.ErrCartOutOfStock)
+	mockCart.On("AddItem", ctx, userID, inactive, (*uuid.UUID)(nil), 1).Return(repository.ErrCartProductUnavailable)
+	mockRepo.On("RemoveItem", ctx, wishlist.ID, inStock).Return(nil)
+
+	result, err := service.MoveToCart(ctx, userID, &MoveToCartRequest{
+		Items: []MoveToCartItem{
+			{ProductID: inStock},
+			{ProductID: outOfStock, Quantity: 1},
+			{ProductID: inactive, Quantity: 1},
+			{ProductID: missing, Quantity: 1},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Moved)
+	assert.Equal(t, 3, result.Failed)
+	assert.Equal(t, MoveStatusMoved, result.Results[0].Status)
+	assert.Equal(t, MoveStatusOutOfStock, result.Results[1].Status)
+	assert.Equal(t, MoveStatusProductUnavailable, result.Results[2].Status)
+	assert.Equal(t, MoveStatusNotInWishlist, result.Results[3].Status)
+	mockRepo.AssertNotCalled(t, "RemoveItem", ctx, wishlist.ID, outOfStock)
+	mockRepo.AssertNotCalled(t, "RemoveItem", ctx, wishlist.ID, inactive)
+	mockRepo.AssertExpectations(t)
+	mockCart.AssertExpectations(t)
+}
+
+func TestWishlistService_MoveToCart_KeepsProductWhenAnyLineFails(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	mockCart := new(MockCartRepository)
+	service := NewWishlistService(mockRepo, mockCart, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+	productID := uuid.New()
+	small := uuid.New()
+	large := uuid.New()
+	wishlist := expectDefaultList(mockRepo, ctx, userID)
+
+	mockRepo.On("ItemExists", ctx, wishlist.ID, productID).Return(true, nil)
+	mockCart.On("AddItem", ctx, userID, productID, &small, 1).Return(nil)
+	mockCart.On("AddItem", ctx, userID, productID, &large, 1).Return(repository.ErrCartOutOfStock)
+
+	result, err := service.MoveToCart(ctx, userID, &MoveToCartRequest{
+		Items: []MoveToCartItem{
+			{ProductID: productID, VariantID: &small, Quantity: 1},
+			{ProductID: productID, VariantID: &large, Quantity: 1},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Moved)
+	mockRepo.AssertNotCalled(t, "RemoveItem", ctx, wishlist.ID, productID)
+	mockCart.AssertExpectations(t)
+}
+
+func TestWishlistService_HandleProductEvent_PriceDrop(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	mockNotifications := new(MockNotificationRepository)
+	service := NewWishlistService(mockRepo, nil, mockNotifications)
+	ctx := context.Background()
+	productID := uuid.New()
+	fresh := uuid.New()
+	recent := uuid.New()
+	optedOut := uuid.New()
+	event := productalerts.PriceDrop(productID, "Air Max", 150, 120)
+
+	mockRepo.On("GetUserIDsByProductID", ctx, productID).Return([]uuid.UUID{fresh, recent, optedOut}, nil)
+	mockRepo.On("GetAlert", ctx, fresh, productID, productalerts.TypePriceDrop).Return(nil, errors.New("wishlist alert not found"))
+	mockRepo.On("GetAlert", ctx, recent, productID, productalerts.TypePriceDrop).Return(&entity.WishlistAlert{
+		UserID:     recent,
+		ProductID:  productID,
+		Type:       productalerts.TypePriceDrop,
+		NotifiedAt: time.Now().Add(-time.Hour),
+	}, nil)
+	mockRepo.On("GetAlert", ctx, optedOut, productID, productalerts.TypePriceDrop).Return(nil, errors.New("wishlist alert not found"))
+	mockNotifications.On("SendProductAlert", ctx, fresh, event).Return(true, nil)
+	mockNotifications.On("SendProductAlert", ctx, optedOut, event).Return(false, nil)
+	mockRepo.On("SaveAlert", ctx, mock.MatchedBy(func(alert *entity.WishlistAlert) bool {
+		return alert.UserID == fresh && alert.Price != nil && *alert.Price == 120
+	})).Return(nil)
+
+	result, err := service.HandleProductEvent(ctx, event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Watchers)
+	assert.Equal(t, 1, result.Notified)
+	assert.Equal(t, 2, result.Skipped)
+	mockNotifications.AssertNotCalled(t, "SendProductAlert", ctx, recent, event)
+	mockRepo.AssertExpectations(t)
+	mockNotifications.AssertExpectations(t)
+}
+
+func TestWishlistService_HandleProductEvent_RejectsPriceIncrease(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	service := NewWishlistService(mockRepo, nil, nil)
+
+	_, err := service.HandleProductEvent(context.Background(), productalerts.PriceDrop(uuid.New(), "Air Max", 120, 150))
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "GetUserIDsByProductID", mock.Anything, mock.Anything)
+}
+
+func TestWishlistService_AcceptProductEvent_RejectsPriceIncrease(t *testing.T) {
+	mockRepo := new(MockWishlistRepository)
+	service := NewWishlistService(mockRepo, nil, nil)
+
+	err := service.AcceptProductEvent(productalerts.PriceDrop(uuid.New(), "Air Max", 120, 150))
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "GetUserIDsByProductID", mock.Anything, mock.Anything)
+}
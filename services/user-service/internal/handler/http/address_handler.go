@@ -0,0 +1,155 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"solemate/pkg/auth"
+	"solemate/pkg/utils"
+	"solemate/services/user-service/internal/domain/service"
+)
+
+// ListAddresses returns the user's address book
+// GET /api/v1/profile/addresses
+func (h *UserHandler) ListAddresses(c *gin.Context) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	addresses, err := h.userService.ListAddresses(c.Request.Context(), userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve addresses", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Addresses retrieved successfully", addresses)
+}
+
+// GetAddress returns a single address from the user's address book
+// GET /api/v1/profile/addresses/:id
+func (h *UserHandler) GetAddress(c *gin.Context) {
+	userID, addressID, ok := addressParams(c)
+	if !ok {
+		return
+	}
+
+	address, err := h.userService.GetAddress(c.Request.Context(), userID, addressID)
+	if err != nil {
+		utils.NotFoundResponse(c, "Address not found")
+		return
+	}
+
+	utils.SuccessResponse(c, "Address retrieved successfully", address)
+}
+
+// CreateAddress adds an address to the user's address book
+// POST /api/v1/profile/addresses
+func (h *UserHandler) CreateAddress(c *gin.Context) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req service.AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	address, err := h.userService.CreateAddress(c.Request.Context(), userID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to create address", err.Error())
+		return
+	}
+
+	utils.CreatedResponse(c, "Address created successfully", address)
+}
+
+// UpdateAddress replaces an address in the user's address book
+// PUT /api/v1/profile/addresses/:id
+func (h *UserHandler) UpdateAddress(c *gin.Context) {
+	userID, addressID, ok := addressParams(c)
+	if !ok {
+		return
+	}
+
+	var req service.AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	address, err := h.userService.UpdateAddress(c.Request.Context(), userID, addressID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrAddressNotFound) {
+			utils.NotFoundResponse(c, "Address not found")
+			return
+		}
+		utils.BadRequestResponse(c, "Failed to update address", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Address updated successfully", address)
+}
+
+// DeleteAddress removes an address from the user's address book
+// DELETE /api/v1/profile/addresses/:id
+func (h *UserHandler) DeleteAddress(c *gin.Context) {
+	userID, addressID, ok := addressParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.userService.DeleteAddress(c.Request.Context(), userID, addressID)
+	if err != nil {
+		if errors.Is(err, service.ErrAddressNotFound) {
+			utils.NotFoundResponse(c, "Address not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to delete address", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Address deleted successfully", nil)
+}
+
+// SetDefaultAddress makes an address the default for its type
+// PUT /api/v1/profile/addresses/:id/default
+func (h *UserHandler) SetDefaultAddress(c *gin.Context) {
+	userID, addressID, ok := addressParams(c)
+	if !ok {
+		return
+	}
+
+	address, err := h.userService.SetDefaultAddress(c.Request.Context(), userID, addressID)
+	if err != nil {
+		if errors.Is(err, service.ErrAddressNotFound) {
+			utils.NotFoundResponse(c, "Address not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to set default address", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Default address updated", address)
+}
+
+func addressParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	addressID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid address ID", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, addressID, true
+}
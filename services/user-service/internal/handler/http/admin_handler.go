@@ -0,0 +1,151 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"solemate/pkg/auth"
+	"solemate/pkg/utils"
+	"solemate/services/user-service/internal/domain/service"
+)
+
+// ChangeUserRole assigns a new role to a user
+// PUT /api/v1/admin/users/:id/role
+func (h *UserHandler) ChangeUserRole(c *gin.Context) {
+	actorID, userID, ok := adminUserParams(c)
+	if !ok {
+		return
+	}
+
+	var req service.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	user, err := h.userService.ChangeRole(c.Request.Context(), actorID, userID, &req)
+	if err != nil {
+		respondAdminUserError(c, "Failed to change role", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "User role updated successfully", user)
+}
+
+// ActivateUser re-enables a deactivated account
+// POST /api/v1/admin/users/:id/activate
+func (h *UserHandler) ActivateUser(c *gin.Context) {
+	h.setUserActive(c, true)
+}
+
+// DeactivateUser disables an account and revokes its sessions
+// POST /api/v1/admin/users/:id/deactivate
+func (h *UserHandler) DeactivateUser(c *gin.Context) {
+	h.setUserActive(c, false)
+}
+
+func (h *UserHandler) setUserActive(c *gin.Context, active bool) {
+	actorID, userID, ok := adminUserParams(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.SetUserActive(c.Request.Context(), actorID, userID, active)
+	if err != nil {
+		respondAdminUserError(c, "Failed to update user", err)
+		return
+	}
+
+	message := "User deactivated successfully"
+	if active {
+		message = "User activated successfully"
+	}
+	utils.SuccessResponse(c, message, user)
+}
+
+// RestoreUser undoes a soft delete
+// POST /api/v1/admin/users/:id/restore
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	user, err := h.userService.RestoreUser(c.Request.Context(), userID)
+	if err != nil {
+		respondAdminUserError(c, "Failed to restore user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "User restored successfully", user)
+}
+
+// GetUserStatistics returns registration and activity statistics
+// GET /api/v1/admin/analytics/users
+func (h *UserHandler) GetUserStatistics(c *gin.Context) {
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+
+	var startDate, endDate time.Time
+	var err error
+
+	if startDateStr != "" {
+		startDate, err = time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid start date format", err.Error())
+			return
+		}
+	} else {
+		startDate = time.Now().AddDate(0, -1, 0) // Default to last month
+	}
+
+	if endDateStr != "" {
+		endDate, err = time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid end date format", err.Error())
+			return
+		}
+		endDate = endDate.AddDate(0, 0, 1) // Include the whole end day
+	} else {
+		endDate = time.Now()
+	}
+
+	statistics, err := h.userService.GetUserStatistics(c.Request.Context(), startDate, endDate)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to get user statistics", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "User statistics retrieved successfully", statistics)
+}
+
+func adminUserParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	actorID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return actorID, userID, true
+}
+
+func respondAdminUserError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		utils.NotFoundResponse(c, "User not found")
+	case errors.Is(err, service.ErrCannotModifySelf):
+		utils.ErrorResponse(c, http.StatusForbidden, message, err.Error())
+	default:
+		utils.BadRequestResponse(c, message, err.Error())
+	}
+}
@@ -1,59 +1,9 @@
 package http
 
 import (
-	"strings"
-
 	"github.com/gin-gonic/gin"
-	"solemate/pkg/auth"
-	"solemate/pkg/utils"
 )
 
-func AuthMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			utils.UnauthorizedResponse(c, "Authorization header required")
-			c.Abort()
-			return
-		}
-
-		bearerToken := strings.Split(authHeader, " ")
-		if len(bearerToken) != 2 || bearerToken[0] != "Bearer" {
-			utils.UnauthorizedResponse(c, "Invalid authorization header format")
-			c.Abort()
-			return
-		}
-
-		token := bearerToken[1]
-		claims, err := jwtManager.ValidateAccessToken(token)
-		if err != nil {
-			utils.UnauthorizedResponse(c, "Invalid or expired token")
-			c.Abort()
-			return
-		}
-
-		// Set user information in context
-		c.Set("user_id", claims.UserID)
-		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
-
-		c.Next()
-	}
-}
-
-func AdminMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role := c.GetString("role")
-		if role != "admin" && role != "manager" {
-			utils.ForbiddenResponse(c, "Admin access required")
-			c.Abort()
-			return
-		}
-
-		c.Next()
-	}
-}
-
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
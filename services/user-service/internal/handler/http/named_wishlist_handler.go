@@ -0,0 +1,230 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"solemate/pkg/auth"
+	"solemate/pkg/utils"
+	"solemate/services/user-service/internal/domain/service"
+)
+
+// ListWishlists returns all of the user's wishlists
+// GET /api/v1/wishlists
+func (h *WishlistHandler) ListWishlists(c *gin.Context) {
+	id, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	wishlists, err := h.wishlistService.ListWishlists(c.Request.Context(), id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to get wishlists", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Wishlists retrieved successfully", wishlists)
+}
+
+// CreateWishlist creates a named wishlist
+// POST /api/v1/wishlists
+// Body: { "name": "Running", "visibility": "private|shared" }
+func (h *WishlistHandler) CreateWishlist(c *gin.Context) {
+	id, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req service.CreateWishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	wishlist, err := h.wishlistService.CreateWishlist(c.Request.Context(), id, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to create wishlist", err.Error())
+		return
+	}
+
+	utils.CreatedResponse(c, "Wishlist created successfully", wishlist)
+}
+
+// GetWishlistByID returns one of the user's wishlists with its items
+// GET /api/v1/wishlists/:id
+func (h *WishlistHandler) GetWishlistByID(c *gin.Context) {
+	userID, wishlistID, ok := wishlistParams(c)
+	if !ok {
+		return
+	}
+
+	wishlist, err := h.wishlistService.GetUserWishlist(c.Request.Context(), userID, wishlistID)
+	if err != nil {
+		respondWishlistError(c, "Failed to get wishlist", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Wishlist retrieved successfully", wishlist)
+}
+
+// UpdateWishlist renames a wishlist or changes its visibility
+// PUT /api/v1/wishlists/:id
+func (h *WishlistHandler) UpdateWishlist(c *gin.Context) {
+	userID, wishlistID, ok := wishlistParams(c)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateWishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	wishlist, err := h.wishlistService.UpdateWishlist(c.Request.Context(), userID, wishlistID, &req)
+	if err != nil {
+		respondWishlistError(c, "Failed to update wishlist", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Wishlist updated successfully", wishlist)
+}
+
+// DeleteWishlist removes a named wishlist
+// DELETE /api/v1/wishlists/:id
+func (h *WishlistHandler) DeleteWishlist(c *gin.Context) {
+	userID, wishlistID, ok := wishlistParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.wishlistService.DeleteWishlist(c.Request.Context(), userID, wishlistID)
+	if err != nil {
+		respondWishlistError(c, "Failed to delete wishlist", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Wishlist deleted successfully", nil)
+}
+
+// AddListItem adds a product to a named wishlist
+// POST /api/v1/wishlists/:id/items
+// Body: { "product_id": "uuid" }
+func (h *WishlistHandler) AddListItem(c *gin.Context) {
+	userID, wishlistID, ok := wishlistParams(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ProductID string `json:"product_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	productID, err := uuid.Parse(req.ProductID)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid product ID", err.Error())
+		return
+	}
+
+	item, err := h.wishlistService.AddToList(c.Request.Context(), userID, wishlistID, productID)
+	if err != nil {
+		respondWishlistError(c, "Failed to add to wishlist", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Product added to wishlist", item)
+}
+
+// RemoveListItem removes a product from a named wishlist
+// DELETE /api/v1/wishlists/:id/items/:product_id
+func (h *WishlistHandler) RemoveListItem(c *gin.Context) {
+	userID, wishlistID, ok := wishlistParams(c)
+	if !ok {
+		return
+	}
+
+	productID, err := uuid.Parse(c.Param("product_id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid product ID", err.Error())
+		return
+	}
+
+	err = h.wishlistService.RemoveFromList(c.Request.Context(), userID, wishlistID, productID)
+	if err != nil {
+		respondWishlistError(c, "Failed to remove from wishlist", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Product removed from wishlist", nil)
+}
+
+// GetSharedWishlist shows a shared wishlist to anyone with the link
+// GET /api/v1/wishlists/shared/:slug
+func (h *WishlistHandler) GetSharedWishlist(c *gin.Context) {
+	wishlist, err := h.wishlistService.GetSharedWishlist(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		respondWishlistError(c, "Failed to get wishlist", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Wishlist retrieved successfully", wishlist)
+}
+
+// CopySharedWishlist copies a shared wishlist's items into one of the user's wishlists
+// POST /api/v1/wishlists/shared/:slug/copy
+// Body: { "wishlist_id": "uuid" } (optional, defaults to the default wishlist)
+func (h *WishlistHandler) CopySharedWishlist(c *gin.Context) {
+	id, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req service.CopyWishlistRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BadRequestResponse(c, "Invalid request body", err.Error())
+			return
+		}
+	}
+
+	result, err := h.wishlistService.CopySharedWishlist(c.Request.Context(), id, c.Param("slug"), &req)
+	if err != nil {
+		respondWishlistError(c, "Failed to copy wishlist", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Wishlist copied successfully", result)
+}
+
+func wishlistParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	wishlistID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid wishlist ID", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, wishlistID, true
+}
+
+func respondWishlistError(c *gin.Context, message string, err error) {
+	if errors.Is(err, service.ErrWishlistNotFound) {
+		utils.NotFoundResponse(c, "Wishlist not found")
+		return
+	}
+	utils.BadRequestResponse(c, message, err.Error())
+}
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"solemate/pkg/auth"
+	"solemate/pkg/utils"
+	"solemate/services/user-service/internal/domain/service"
+)
+
+type PrivacyHandler struct {
+	privacyService *service.PrivacyService
+}
+
+func NewPrivacyHandler(privacyService *service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
+
+// RequestExport starts building an archive of all the user's data
+// GET /api/v1/profile/export
+func (h *PrivacyHandler) RequestExport(c *gin.Context) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	request, err := h.privacyService.RequestExport(c.Request.Context(), userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to start export", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Export started",
+		Data:    request,
+	})
+}
+
+// GetExport returns the status of an export
+// GET /api/v1/profile/export/:id
+func (h *PrivacyHandler) GetExport(c *gin.Context) {
+	userID, requestID, ok := privacyParams(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.privacyService.GetRequest(c.Request.Context(), userID, requestID)
+	if err != nil {
+		utils.NotFoundResponse(c, "Export not found")
+		return
+	}
+
+	utils.SuccessResponse(c, "Export retrieved successfully", request)
+}
+
+// DownloadExport returns a finished export as a ZIP archive, or as JSON with ?format=json
+// GET /api/v1/profile/export/:id/download
+func (h *PrivacyHandler) DownloadExport(c *gin.Context) {
+	userID, requestID, ok := privacyParams(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "zip")
+	data, contentType, err := h.privacyService.GetExportArchive(c.Request.Context(), userID, requestID, format)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPrivacyRequestNotFound):
+			utils.NotFoundResponse(c, "Export not found")
+		case errors.Is(err, service.ErrExportNotReady):
+			utils.ErrorResponse(c, http.StatusConflict, "Export is not ready yet", err.Error())
+		case errors.Is(err, service.ErrExportExpired):
+			utils.ErrorResponse(c, http.StatusGone, "Export has expired", err.Error())
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to download export", err.Error())
+		}
+		return
+	}
+
+	filename := "solemate-export-" + requestID.String()
+	if contentType == "application/zip" {
+		filename += ".zip"
+	} else {
+		filename += ".json"
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// EraseAccount deactivates the account and erases the user's data across all services
+// DELETE /api/v1/profile
+func (h *PrivacyHandler) EraseAccount(c *gin.Context) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req service.EraseAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	request, err := h.privacyService.RequestErasure(c.Request.Context(), userID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to erase account", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Account erasure started",
+		Data:    request,
+	})
+}
+
+// ListPrivacyRequests returns the user's export and erasure history
+// GET /api/v1/profile/privacy-requests
+func (h *PrivacyHandler) ListPrivacyRequests(c *gin.Context) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	requests, err := h.privacyService.ListRequests(c.Request.Context(), userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve privacy requests", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Privacy requests retrieved successfully", requests)
+}
+
+// ListUserPrivacyRequests returns the privacy request audit trail for a user
+// GET /api/v1/admin/users/:id/privacy-requests
+func (h *PrivacyHandler) ListUserPrivacyRequests(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	requests, err := h.privacyService.ListRequests(c.Request.Context(), userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve privacy requests", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Privacy requests retrieved successfully", requests)
+}
+
+// RetryPrivacyRequest re-runs a failed export or erasure
+// POST /api/v1/admin/privacy-requests/:id/retry
+func (h *PrivacyHandler) RetryPrivacyRequest(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid request ID", err.Error())
+		return
+	}
+
+	request, err := h.privacyService.RetryRequest(c.Request.Context(), requestID)
+	if err != nil {
+		if errors.Is(err, service.ErrPrivacyRequestNotFound) {
+			utils.NotFoundResponse(c, "Privacy request not found")
+			return
+		}
+		utils.BadRequestResponse(c, "Failed to retry privacy request", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Privacy request restarted",
+		Data:    request,
+	})
+}
+
+func privacyParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid request ID", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, requestID, true
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"solemate/pkg/utils"
+	"solemate/services/user-service/internal/domain/service"
+)
+
+type RoleHandler struct {
+	roleService *service.RoleService
+}
+
+func NewRoleHandler(roleService *service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+// ListRoles returns the role→permission mapping
+// GET /api/v1/admin/roles
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles(c.Request.Context())
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve roles", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Roles retrieved successfully", roles)
+}
+
+// UpdateRolePermissions replaces the permissions granted to a role
+// PUT /api/v1/admin/roles/:role/permissions
+// Body: { "permissions": ["orders:ship", ...] }
+func (h *RoleHandler) UpdateRolePermissions(c *gin.Context) {
+	role := c.Param("role")
+
+	var req service.UpdateRolePermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	permissions, err := h.roleService.UpdateRolePermissions(c.Request.Context(), role, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to update role permissions", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Role permissions updated successfully", gin.H{
+		"role":        role,
+		"permissions": permissions,
+	})
+}
@@ -3,9 +3,11 @@ package http
 import (
 	"github.com/gin-gonic/gin"
 	"solemate/pkg/auth"
+	"solemate/pkg/authz"
+	"solemate/pkg/productalerts"
 )
 
-func SetupRoutes(userHandler *UserHandler, wishlistHandler *WishlistHandler, jwtManager *auth.JWTManager) *gin.Engine {
+func SetupRoutes(userHandler *UserHandler, wishlistHandler *WishlistHandler, roleHandler *RoleHandler, privacyHandler *PrivacyHandler, jwtManager *auth.JWTManager, internalTokens *auth.InternalTokenManager, revocations *auth.SessionRevocations) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 
@@ -23,21 +25,42 @@ func SetupRoutes(userHandler *UserHandler, wishlistHandler *WishlistHandler, jwt
 	v1 := r.Group("/api/v1")
 	{
 		// Public routes (no authentication required)
-		auth := v1.Group("/auth")
+		authRoutes := v1.Group("/auth")
 		{
-			auth.POST("/register", userHandler.Register)
-			auth.POST("/login", userHandler.Login)
-			auth.POST("/refresh", userHandler.RefreshToken)
+			authRoutes.POST("/register", userHandler.Register)
+			authRoutes.POST("/login", userHandler.Login)
+			authRoutes.POST("/refresh", userHandler.RefreshToken)
 		}
 
+		// Shared wishlists can be viewed by anyone with the link
+		v1.GET("/wishlists/shared/:slug", wishlistHandler.GetSharedWishlist)
+
 		// Protected routes (authentication required)
 		protected := v1.Group("/")
-		protected.Use(AuthMiddleware(jwtManager))
+		protected.Use(auth.ServiceAuthMiddleware(internalTokens, jwtManager, revocations))
 		{
 			// User profile routes
 			protected.GET("/profile", userHandler.GetProfile)
 			protected.PUT("/profile", userHandler.UpdateProfile)
 
+			// Personal data export and account erasure
+			protected.DELETE("/profile", privacyHandler.EraseAccount)
+			protected.GET("/profile/export", privacyHandler.RequestExport)
+			protected.GET("/profile/export/:id", privacyHandler.GetExport)
+			protected.GET("/profile/export/:id/download", privacyHandler.DownloadExport)
+			protected.GET("/profile/privacy-requests", privacyHandler.ListPrivacyRequests)
+
+			// Address book routes
+			addresses := protected.Group("/profile/addresses")
+			{
+				addresses.GET("", userHandler.ListAddresses)
+				addresses.POST("", userHandler.CreateAddress)
+				addresses.GET("/:id", userHandler.GetAddress)
+				addresses.PUT("/:id", userHandler.UpdateAddress)
+				addresses.DELETE("/:id", userHandler.DeleteAddress)
+				addresses.PUT("/:id/default", userHandler.SetDefaultAddress)
+			}
+
 			// Wishlist routes
 			wishlist := protected.Group("/wishlist")
 			{
@@ -45,16 +68,48 @@ func SetupRoutes(userHandler *UserHandler, wishlistHandler *WishlistHandler, jwt
 				wishlist.POST("/items", wishlistHandler.AddItem)
 				wishlist.DELETE("/items/:product_id", wishlistHandler.RemoveItem)
 				wishlist.DELETE("", wishlistHandler.ClearWishlist)
-				wishlist.POST("/move-to-cart", wishlistHandler.MoveToCart) // Not implemented, frontend handles
+				wishlist.POST("/move-to-cart", wishlistHandler.MoveToCart)
+			}
+
+			// Named wishlist routes
+			wishlists := protected.Group("/wishlists")
+			{
+				wishlists.GET("", wishlistHandler.ListWishlists)
+				wishlists.POST("", wishlistHandler.CreateWishlist)
+				wishlists.GET("/:id", wishlistHandler.GetWishlistByID)
+				wishlists.PUT("/:id", wishlistHandler.UpdateWishlist)
+				wishlists.DELETE("/:id", wishlistHandler.DeleteWishlist)
+				wishlists.POST("/:id/items", wishlistHandler.AddListItem)
+				wishlists.DELETE("/:id/items/:product_id", wishlistHandler.RemoveListItem)
+				wishlists.POST("/shared/:slug/copy", wishlistHandler.CopySharedWishlist)
 			}
 
+			// Price-drop and restock events from product-service and inventory-service
+			protected.POST(productalerts.Path, authz.RequirePermission(authz.NotificationsSend), wishlistHandler.HandleProductAlert)
+
 			// Admin only routes
 			admin := protected.Group("/")
-			admin.Use(AdminMiddleware())
 			{
-				admin.GET("/users", userHandler.ListUsers)
-				admin.GET("/users/:id", userHandler.GetUser)
-				admin.DELETE("/users/:id", userHandler.DeleteUser)
+				// User management
+				admin.GET("/admin/users", authz.RequirePermission(authz.UsersRead), userHandler.ListUsers)
+				admin.GET("/admin/users/:id", authz.RequirePermission(authz.UsersRead), userHandler.GetUser)
+				admin.DELETE("/admin/users/:id", authz.RequirePermission(authz.UsersWrite), userHandler.DeleteUser)
+				admin.POST("/admin/users/:id/restore", authz.RequirePermission(authz.UsersWrite), userHandler.RestoreUser)
+				admin.POST("/admin/users/:id/activate", authz.RequirePermission(authz.UsersWrite), userHandler.ActivateUser)
+				admin.POST("/admin/users/:id/deactivate", authz.RequirePermission(authz.UsersWrite), userHandler.DeactivateUser)
+				admin.PUT("/admin/users/:id/role", authz.RequirePermission(authz.RolesManage), userHandler.ChangeUserRole)
+
+				// Registration and activity statistics
+				admin.GET("/admin/analytics/users", authz.RequirePermission(authz.AnalyticsRead), userHandler.GetUserStatistics)
+				admin.GET("/admin/analytics/wishlist-adds", authz.RequirePermission(authz.AnalyticsRead), wishlistHandler.GetWishlistAdds)
+
+				// Role and permission management
+				admin.GET("/admin/roles", authz.RequirePermission(authz.RolesManage), roleHandler.ListRoles)
+				admin.PUT("/admin/roles/:role/permissions", authz.RequirePermission(authz.RolesManage), roleHandler.UpdateRolePermissions)
+
+				// Privacy request audit trail
+				admin.GET("/admin/users/:id/privacy-requests", authz.RequirePermission(authz.UsersRead), privacyHandler.ListUserPrivacyRequests)
+				admin.POST("/admin/privacy-requests/:id/retry", authz.RequirePermission(authz.UsersWrite), privacyHandler.RetryPrivacyRequest)
 			}
 		}
 	}
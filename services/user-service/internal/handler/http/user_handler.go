@@ -2,9 +2,11 @@ package http
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"solemate/pkg/auth"
 	"solemate/pkg/utils"
 	"solemate/services/user-service/internal/domain/service"
 )
@@ -52,18 +54,12 @@ func (h *UserHandler) Login(c *gin.Context) {
 }
 
 func (h *UserHandler) GetProfile(c *gin.Context) {
-	userID := c.GetString("user_id")
-	if userID == "" {
+	id, ok := auth.CurrentUserID(c)
+	if !ok {
 		utils.UnauthorizedResponse(c, "User not authenticated")
 		return
 	}
 
-	id, err := uuid.Parse(userID)
-	if err != nil {
-		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
-		return
-	}
-
 	user, err := h.userService.GetUserByID(c.Request.Context(), id)
 	if err != nil {
 		utils.NotFoundResponse(c, "User not found")
@@ -74,18 +70,12 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 }
 
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
-	userID := c.GetString("user_id")
-	if userID == "" {
+	id, ok := auth.CurrentUserID(c)
+	if !ok {
 		utils.UnauthorizedResponse(c, "User not authenticated")
 		return
 	}
 
-	id, err := uuid.Parse(userID)
-	if err != nil {
-		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
-		return
-	}
-
 	var req service.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequestResponse(c, "Invalid request body", err.Error())
@@ -119,30 +109,67 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 }
 
 func (h *UserHandler) ListUsers(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-
-	users, total, err := h.userService.ListUsers(c.Request.Context(), page, limit)
+	var req service.ListUsersRequest
+
+	// Parse query parameters
+	req.Search = c.Query("search")
+	req.Email = c.Query("email")
+	req.Name = c.Query("name")
+	req.Role = c.Query("role")
+	req.SortBy = c.DefaultQuery("sort_by", "created_at")
+	req.SortOrder = c.DefaultQuery("sort_order", "desc")
+	req.IncludeDeleted, _ = strconv.ParseBool(c.Query("include_deleted"))
+	req.OnlyDeleted, _ = strconv.ParseBool(c.Query("only_deleted"))
+
+	if isActive := c.Query("is_active"); isActive != "" {
+		if active, err := strconv.ParseBool(isActive); err == nil {
+			req.IsActive = &active
+		}
+	}
+
+	// Parse signup date range, both ends inclusive
+	if createdFrom := c.Query("created_from"); createdFrom != "" {
+		date, err := time.Parse("2006-01-02", createdFrom)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid created_from date format", err.Error())
+			return
+		}
+		req.CreatedFrom = &date
+	}
+
+	if createdTo := c.Query("created_to"); createdTo != "" {
+		date, err := time.Parse("2006-01-02", createdTo)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid created_to date format", err.Error())
+			return
+		}
+		date = date.AddDate(0, 0, 1)
+		req.CreatedTo = &date
+	}
+
+	// Parse pagination
+	req.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	req.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	users, total, err := h.userService.ListUsers(c.Request.Context(), &req)
 	if err != nil {
-		utils.InternalServerErrorResponse(c, "Failed to retrieve users", err.Error())
+		utils.BadRequestResponse(c, "Failed to retrieve users", err.Error())
 		return
 	}
 
-	pagination := utils.CalculatePagination(page, limit, total)
+	pagination := utils.CalculatePagination(req.Page, req.Limit, total)
 	utils.PaginatedSuccessResponse(c, "Users retrieved successfully", users, pagination)
 }
 
 func (h *UserHandler) DeleteUser(c *gin.Context) {
-	userIDParam := c.Param("id")
-	id, err := uuid.Parse(userIDParam)
-	if err != nil {
-		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
+	actorID, id, ok := adminUserParams(c)
+	if !ok {
 		return
 	}
 
-	err = h.userService.DeleteUser(c.Request.Context(), id)
+	err := h.userService.DeleteUser(c.Request.Context(), actorID, id)
 	if err != nil {
-		utils.BadRequestResponse(c, "Delete failed", err.Error())
+		respondAdminUserError(c, "Delete failed", err)
 		return
 	}
 
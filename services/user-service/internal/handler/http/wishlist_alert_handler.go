@@ -0,0 +1,31 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"solemate/pkg/productalerts"
+	"solemate/pkg/utils"
+)
+
+// HandleProductAlert alerts the users who wishlisted a product about a price drop or restock.
+// The users are alerted in the background once the event is accepted.
+// POST /api/v1/internal/product-alerts
+// Called by product-service and inventory-service
+func (h *WishlistHandler) HandleProductAlert(c *gin.Context) {
+	var event productalerts.Event
+	if err := c.ShouldBindJSON(&event); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := h.wishlistService.AcceptProductEvent(&event); err != nil {
+		utils.BadRequestResponse(c, "Failed to process product alert", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Product alert accepted",
+	})
+}
@@ -1,8 +1,13 @@
 package http
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"solemate/pkg/auth"
 	"solemate/pkg/utils"
 	"solemate/services/user-service/internal/domain/service"
 )
@@ -20,18 +25,12 @@ func NewWishlistHandler(wishlistService *service.WishlistService) *WishlistHandl
 // GetWishlist retrieves user's wishlist
 // GET /api/v1/wishlist
 func (h *WishlistHandler) GetWishlist(c *gin.Context) {
-	userID := c.GetString("user_id")
-	if userID == "" {
+	id, ok := auth.CurrentUserID(c)
+	if !ok {
 		utils.UnauthorizedResponse(c, "User not authenticated")
 		return
 	}
 
-	id, err := uuid.Parse(userID)
-	if err != nil {
-		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
-		return
-	}
-
 	wishlist, err := h.wishlistService.GetWishlist(c.Request.Context(), id)
 	if err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to get wishlist", err.Error())
@@ -45,18 +44,12 @@ func (h *WishlistHandler) GetWishlist(c *gin.Context) {
 // POST /api/v1/wishlist/items
 // Body: { "product_id": "uuid" }
 func (h *WishlistHandler) AddItem(c *gin.Context) {
-	userID := c.GetString("user_id")
-	if userID == "" {
+	id, ok := auth.CurrentUserID(c)
+	if !ok {
 		utils.UnauthorizedResponse(c, "User not authenticated")
 		return
 	}
 
-	id, err := uuid.Parse(userID)
-	if err != nil {
-		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
-		return
-	}
-
 	var req struct {
 		ProductID string `json:"product_id" binding:"required"`
 	}
@@ -84,18 +77,12 @@ func (h *WishlistHandler) AddItem(c *gin.Context) {
 // RemoveItem removes a product from wishlist
 // DELETE /api/v1/wishlist/items/:product_id
 func (h *WishlistHandler) RemoveItem(c *gin.Context) {
-	userID := c.GetString("user_id")
-	if userID == "" {
+	id, ok := auth.CurrentUserID(c)
+	if !ok {
 		utils.UnauthorizedResponse(c, "User not authenticated")
 		return
 	}
 
-	id, err := uuid.Parse(userID)
-	if err != nil {
-		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
-		return
-	}
-
 	productIDParam := c.Param("product_id")
 	productID, err := uuid.Parse(productIDParam)
 	if err != nil {
@@ -115,34 +102,85 @@ func (h *WishlistHandler) RemoveItem(c *gin.Context) {
 // ClearWishlist removes all items from wishlist
 // DELETE /api/v1/wishlist
 func (h *WishlistHandler) ClearWishlist(c *gin.Context) {
-	userID := c.GetString("user_id")
-	if userID == "" {
+	id, ok := auth.CurrentUserID(c)
+	if !ok {
 		utils.UnauthorizedResponse(c, "User not authenticated")
 		return
 	}
 
-	id, err := uuid.Parse(userID)
+	err := h.wishlistService.ClearWishlist(c.Request.Context(), id)
 	if err != nil {
-		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
+		utils.InternalServerErrorResponse(c, "Failed to clear wishlist", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Wishlist cleared successfully", nil)
+}
+
+// MoveToCart adds wishlist items to the cart and removes the ones that were added
+// POST /api/v1/wishlist/move-to-cart
+// Body: { "items": [{ "product_id": "uuid", "variant_id": "uuid", "quantity": 1 }] }
+func (h *WishlistHandler) MoveToCart(c *gin.Context) {
+	id, ok := auth.CurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req service.MoveToCartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
 		return
 	}
 
-	err = h.wishlistService.ClearWishlist(c.Request.Context(), id)
+	result, err := h.wishlistService.MoveToCart(c.Request.Context(), id, &req)
+	if errors.Is(err, service.ErrWishlistNotFound) {
+		utils.NotFoundResponse(c, "Wishlist not found")
+		return
+	}
 	if err != nil {
-		utils.InternalServerErrorResponse(c, "Failed to clear wishlist", err.Error())
+		utils.InternalServerErrorResponse(c, "Failed to move items to cart", err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, "Wishlist cleared successfully", nil)
+	utils.SuccessResponse(c, fmt.Sprintf("Moved %d of %d items to cart", result.Moved, len(result.Results)), result)
 }
 
-// MoveToCart endpoint is handled by frontend calling cart API directly
-// This handler is included for completeness but not implemented here
-// Frontend should:
-// 1. Get product from wishlist
-// 2. Call cart service to add product
-// 3. Call this service to remove from wishlist
-func (h *WishlistHandler) MoveToCart(c *gin.Context) {
-	utils.BadRequestResponse(c, "Move to cart should be handled by frontend",
-		"Call cart service to add item, then remove from wishlist")
+// GetWishlistAdds counts, per product, the users who wishlisted it in a period
+// GET /api/v1/admin/analytics/wishlist-adds?start_date=...&end_date=...
+func (h *WishlistHandler) GetWishlistAdds(c *gin.Context) {
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+
+	var startDate, endDate time.Time
+	var err error
+
+	if startDateStr != "" {
+		startDate, err = time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid start date format", err.Error())
+			return
+		}
+	} else {
+		startDate = time.Now().AddDate(0, -1, 0) // Default to last month
+	}
+
+	if endDateStr != "" {
+		endDate, err = time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid end date format", err.Error())
+			return
+		}
+		endDate = endDate.AddDate(0, 0, 1) // Include the whole end day
+	} else {
+		endDate = time.Now()
+	}
+
+	adds, err := h.wishlistService.GetWishlistAdds(c.Request.Context(), startDate, endDate)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to get wishlist adds", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Wishlist adds retrieved successfully", adds)
 }
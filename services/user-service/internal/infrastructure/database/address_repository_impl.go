@@ -41,7 +41,10 @@ func (r *addressRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*ent
 
 func (r *addressRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Address, error) {
 	var addresses []*entity.Address
-	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&addresses)
+	result := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default DESC, created_at DESC").
+		Find(&addresses)
 	return addresses, result.Error
 }
 
@@ -58,6 +61,8 @@ func (r *addressRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error
 	return result.Error
 }
 
+// SetDefault marks addressID as the user's default address for its type, so a
+// user can have one default shipping and one default billing address.
 func (r *addressRepositoryImpl) SetDefault(ctx context.Context, userID, addressID uuid.UUID) error {
 	tx := r.db.WithContext(ctx).Begin()
 	defer func() {
@@ -66,17 +71,31 @@ func (r *addressRepositoryImpl) SetDefault(ctx context.Context, userID, addressI
 		}
 	}()
 
-	// Set all addresses to non-default
-	if err := tx.Model(&entity.Address{}).Where("user_id = ?", userID).Update("is_default", false).Error; err != nil {
+	var address entity.Address
+	if err := tx.Where("id = ? AND user_id = ?", addressID, userID).First(&address).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("address not found")
+		}
+		return err
+	}
+
+	// Set all addresses of the same type to non-default
+	if err := tx.Model(&entity.Address{}).Where("user_id = ? AND type = ?", userID, address.Type).Update("is_default", false).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
 	// Set the specified address as default
-	if err := tx.Model(&entity.Address{}).Where("id = ? AND user_id = ?", addressID, userID).Update("is_default", true).Error; err != nil {
+	if err := tx.Model(&entity.Address{}).Where("id = ?", addressID).Update("is_default", true).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
 	return tx.Commit().Error
 }
+
+func (r *addressRepositoryImpl) DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.Address{})
+	return result.RowsAffected, result.Error
+}
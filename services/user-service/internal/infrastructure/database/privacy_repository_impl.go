@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"solemate/services/user-service/internal/domain/entity"
+	"solemate/services/user-service/internal/domain/repository"
+)
+
+type privacyRequestRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPrivacyRequestRepository(db *gorm.DB) repository.PrivacyRequestRepository {
+	return &privacyRequestRepositoryImpl{db: db}
+}
+
+func (r *privacyRequestRepositoryImpl) Create(ctx context.Context, request *entity.PrivacyRequest) error {
+	request.ID = uuid.New()
+	return r.db.WithContext(ctx).Create(request).Error
+}
+
+func (r *privacyRequestRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.PrivacyRequest, error) {
+	var request entity.PrivacyRequest
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&request)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("privacy request not found")
+		}
+		return nil, result.Error
+	}
+	return &request, nil
+}
+
+func (r *privacyRequestRepositoryImpl) Update(ctx context.Context, request *entity.PrivacyRequest) error {
+	return r.db.WithContext(ctx).Save(request).Error
+}
+
+func (r *privacyRequestRepositoryImpl) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PrivacyRequest, error) {
+	var requests []*entity.PrivacyRequest
+	result := r.db.WithContext(ctx).
+		Omit("archive").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&requests)
+	return requests, result.Error
+}
+
+func (r *privacyRequestRepositoryImpl) GetActive(ctx context.Context, userID uuid.UUID, requestType string) (*entity.PrivacyRequest, error) {
+	var request entity.PrivacyRequest
+	result := r.db.WithContext(ctx).
+		Omit("archive").
+		Where("user_id = ? AND type = ? AND status IN ?", userID, requestType,
+			[]string{entity.PrivacyStatusPending, entity.PrivacyStatusProcessing}).
+		Order("created_at DESC").
+		First(&request)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &request, nil
+}
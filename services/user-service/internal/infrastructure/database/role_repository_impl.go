@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"solemate/services/user-service/internal/domain/entity"
+	"solemate/services/user-service/internal/domain/repository"
+)
+
+type roleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) repository.RoleRepository {
+	return &roleRepositoryImpl{db: db}
+}
+
+func (r *roleRepositoryImpl) GetPermissions(ctx context.Context, role string) ([]string, error) {
+	var permissions []string
+	result := r.db.WithContext(ctx).
+		Model(&entity.RolePermission{}).
+		Where("role = ?", role).
+		Order("permission ASC").
+		Pluck("permission", &permissions)
+
+	return permissions, result.Error
+}
+
+func (r *roleRepositoryImpl) ListRoles(ctx context.Context) (map[string][]string, error) {
+	var rows []*entity.RolePermission
+	result := r.db.WithContext(ctx).Order("role ASC, permission ASC").Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	roles := make(map[string][]string)
+	for _, row := range rows {
+		roles[row.Role] = append(roles[row.Role], row.Permission)
+	}
+	return roles, nil
+}
+
+func (r *roleRepositoryImpl) SetPermissions(ctx context.Context, role string, permissions []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role = ?", role).Delete(&entity.RolePermission{}).Error; err != nil {
+			return err
+		}
+
+		if len(permissions) == 0 {
+			return nil
+		}
+
+		rows := make([]entity.RolePermission, len(permissions))
+		for i, permission := range permissions {
+			rows[i] = entity.RolePermission{Role: role, Permission: permission}
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (r *roleRepositoryImpl) SeedDefaults(ctx context.Context, defaults map[string][]string) error {
+	for role, permissions := range defaults {
+		var count int64
+		if err := r.db.WithContext(ctx).Model(&entity.RolePermission{}).Where("role = ?", role).Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count > 0 {
+			continue
+		}
+
+		if err := r.SetPermissions(ctx, role, permissions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
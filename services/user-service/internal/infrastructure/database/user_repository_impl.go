@@ -3,6 +3,8 @@ package database
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -66,15 +68,40 @@ func (r *userRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	return result.Error
 }
 
-func (r *userRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*entity.User, int64, error) {
+// Restore undoes a soft delete
+func (r *userRepositoryImpl) Restore(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Unscoped().Model(&entity.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("deleted user not found")
+	}
+	return nil
+}
+
+// EmailExists also checks deleted users, whose email stays reserved until they are purged
+func (r *userRepositoryImpl) EmailExists(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&entity.User{}).
+		Where("LOWER(email) = LOWER(?)", email).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *userRepositoryImpl) List(ctx context.Context, filters repository.UserFilters) ([]*entity.User, int64, error) {
 	var users []*entity.User
 	var total int64
 
-	if err := r.db.WithContext(ctx).Model(&entity.User{}).Count(&total).Error; err != nil {
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&entity.User{}), filters)
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	result := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&users)
+	query = r.applySorting(query, filters)
+	result := query.Limit(filters.Limit).Offset(filters.Offset).Find(&users)
 	return users, total, result.Error
 }
 
@@ -83,3 +110,138 @@ func (r *userRepositoryImpl) UpdateLastLogin(ctx context.Context, id uuid.UUID)
 	result := r.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Update("last_login_at", now)
 	return result.Error
 }
+
+func (r *userRepositoryImpl) GetUserStatistics(ctx context.Context, startDate, endDate time.Time) (*repository.UserStatistics, error) {
+	var stats repository.UserStatistics
+	users := func() *gorm.DB {
+		return r.db.WithContext(ctx).Model(&entity.User{})
+	}
+
+	if err := users().Count(&stats.TotalUsers).Error; err != nil {
+		return nil, err
+	}
+
+	if err := users().Where("is_active = ?", true).Count(&stats.ActiveUsers).Error; err != nil {
+		return nil, err
+	}
+	stats.InactiveUsers = stats.TotalUsers - stats.ActiveUsers
+
+	if err := users().Where("email_verified = ?", true).Count(&stats.VerifiedUsers).Error; err != nil {
+		return nil, err
+	}
+
+	if err := users().Unscoped().Where("deleted_at IS NOT NULL").Count(&stats.DeletedUsers).Error; err != nil {
+		return nil, err
+	}
+
+	// Get role breakdown
+	var roleResults []struct {
+		Role  string
+		Count int64
+	}
+
+	if err := users().
+		Select("role, COUNT(*) as count").
+		Group("role").
+		Scan(&roleResults).Error; err != nil {
+		return nil, err
+	}
+
+	stats.RoleBreakdown = make(map[string]int64)
+	for _, result := range roleResults {
+		stats.RoleBreakdown[result.Role] = result.Count
+	}
+
+	// Activity within the requested period
+	if err := users().
+		Where("created_at BETWEEN ? AND ?", startDate, endDate).
+		Count(&stats.NewUsers).Error; err != nil {
+		return nil, err
+	}
+
+	if err := users().
+		Where("last_login_at BETWEEN ? AND ?", startDate, endDate).
+		Count(&stats.LoggedInUsers).Error; err != nil {
+		return nil, err
+	}
+
+	// Get daily registrations
+	var dailyResults []struct {
+		Date          time.Time
+		Registrations int64
+	}
+
+	if err := users().
+		Select("DATE(created_at) as date, COUNT(*) as registrations").
+		Where("created_at BETWEEN ? AND ?", startDate, endDate).
+		Group("DATE(created_at)").
+		Order("date ASC").
+		Scan(&dailyResults).Error; err != nil {
+		return nil, err
+	}
+
+	stats.RegistrationsByDay = make([]repository.DailyUserStats, len(dailyResults))
+	for i, result := range dailyResults {
+		stats.RegistrationsByDay[i] = repository.DailyUserStats{
+			Date:          result.Date,
+			Registrations: result.Registrations,
+		}
+	}
+
+	return &stats, nil
+}
+
+func (r *userRepositoryImpl) applyFilters(query *gorm.DB, filters repository.UserFilters) *gorm.DB {
+	if filters.OnlyDeleted {
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	} else if filters.IncludeDeleted {
+		query = query.Unscoped()
+	}
+
+	if filters.Search != "" {
+		pattern := "%" + filters.Search + "%"
+		query = query.Where("email ILIKE ? OR first_name ILIKE ? OR last_name ILIKE ?", pattern, pattern, pattern)
+	}
+
+	if filters.Email != "" {
+		query = query.Where("email ILIKE ?", "%"+filters.Email+"%")
+	}
+
+	if filters.Name != "" {
+		query = query.Where("CONCAT_WS(' ', first_name, last_name) ILIKE ?", "%"+filters.Name+"%")
+	}
+
+	if filters.Role != "" {
+		query = query.Where("role = ?", filters.Role)
+	}
+
+	if filters.IsActive != nil {
+		query = query.Where("is_active = ?", *filters.IsActive)
+	}
+
+	if filters.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filters.CreatedFrom)
+	}
+
+	if filters.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filters.CreatedTo)
+	}
+
+	return query
+}
+
+func (r *userRepositoryImpl) applySorting(query *gorm.DB, filters repository.UserFilters) *gorm.DB {
+	sortBy := "created_at"
+	sortOrder := "DESC"
+
+	switch filters.SortBy {
+	case "created_at", "email", "first_name", "last_name", "last_login_at":
+		sortBy = filters.SortBy
+	}
+
+	if strings.ToUpper(filters.SortOrder) == "ASC" {
+		sortOrder = "ASC"
+	}
+
+	return query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
+}
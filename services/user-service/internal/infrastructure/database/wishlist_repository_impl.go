@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"solemate/services/user-service/internal/domain/entity"
 	"solemate/services/user-service/internal/domain/repository"
 )
@@ -19,6 +20,128 @@ func NewWishlistRepository(db *gorm.DB) repository.WishlistRepository {
 	return &wishlistRepositoryImpl{db: db}
 }
 
+func (r *wishlistRepositoryImpl) CreateList(ctx context.Context, wishlist *entity.Wishlist) error {
+	wishlist.ID = uuid.New()
+	wishlist.CreatedAt = time.Now()
+	wishlist.UpdatedAt = time.Now()
+
+	result := r.db.WithContext(ctx).Create(wishlist)
+	return result.Error
+}
+
+// CreateDefaultList relies on the partial unique index on the user's default
+// list, so concurrent first requests create a single one
+func (r *wishlistRepositoryImpl) CreateDefaultList(ctx context.Context, wishlist *entity.Wishlist) (bool, error) {
+	wishlist.ID = uuid.New()
+	wishlist.IsDefault = true
+	wishlist.CreatedAt = time.Now()
+	wishlist.UpdatedAt = time.Now()
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "is_default"}}},
+		DoNothing:   true,
+	}).Create(wishlist)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *wishlistRepositoryImpl) GetList(ctx context.Context, id uuid.UUID) (*entity.Wishlist, error) {
+	return r.getList(ctx, "id = ?", id)
+}
+
+func (r *wishlistRepositoryImpl) GetListBySlug(ctx context.Context, slug string) (*entity.Wishlist, error) {
+	return r.getList(ctx, "share_slug = ?", slug)
+}
+
+func (r *wishlistRepositoryImpl) GetDefaultList(ctx context.Context, userID uuid.UUID) (*entity.Wishlist, error) {
+	return r.getList(ctx, "user_id = ? AND is_default = ?", userID, true)
+}
+
+func (r *wishlistRepositoryImpl) getList(ctx context.Context, query string, args ...interface{}) (*entity.Wishlist, error) {
+	var wishlist entity.Wishlist
+	result := r.db.WithContext(ctx).Where(query, args...).First(&wishlist)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrWishlistNotFound
+		}
+		return nil, result.Error
+	}
+	return &wishlist, nil
+}
+
+func (r *wishlistRepositoryImpl) GetListsByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Wishlist, error) {
+	var wishlists []*entity.Wishlist
+
+	// Default list first, then in creation order
+	result := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default DESC, created_at ASC").
+		Find(&wishlists)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var counts []struct {
+		WishlistID uuid.UUID
+		Count      int64
+	}
+
+	if err := r.db.WithContext(ctx).Model(&entity.WishlistItem{}).
+		Select("wishlist_id, COUNT(*) as count").
+		Where("user_id = ?", userID).
+		Group("wishlist_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	itemCounts := make(map[uuid.UUID]int64, len(counts))
+	for _, count := range counts {
+		itemCounts[count.WishlistID] = count.Count
+	}
+	for _, wishlist := range wishlists {
+		wishlist.ItemCount = itemCounts[wishlist.ID]
+	}
+
+	return wishlists, nil
+}
+
+func (r *wishlistRepositoryImpl) UpdateList(ctx context.Context, wishlist *entity.Wishlist) error {
+	wishlist.UpdatedAt = time.Now()
+	result := r.db.WithContext(ctx).Model(wishlist).Updates(map[string]interface{}{
+		"name":       wishlist.Name,
+		"visibility": wishlist.Visibility,
+		"updated_at": wishlist.UpdatedAt,
+	})
+	return result.Error
+}
+
+func (r *wishlistRepositoryImpl) DeleteList(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("wishlist_id = ?", id).Delete(&entity.WishlistItem{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ?", id).Delete(&entity.Wishlist{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrWishlistNotFound
+		}
+		return nil
+	})
+}
+
+func (r *wishlistRepositoryImpl) AssignOrphanItems(ctx context.Context, userID, wishlistID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&entity.WishlistItem{}).
+		Where("user_id = ? AND wishlist_id IS NULL", userID).
+		Update("wishlist_id", wishlistID)
+	return result.Error
+}
+
 func (r *wishlistRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WishlistItem, error) {
 	var items []*entity.WishlistItem
 
@@ -36,24 +159,42 @@ func (r *wishlistRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UU
 	return items, nil
 }
 
-func (r *wishlistRepositoryImpl) AddItem(ctx context.Context, userID, productID uuid.UUID) (*entity.WishlistItem, error) {
+func (r *wishlistRepositoryImpl) GetItems(ctx context.Context, wishlistID uuid.UUID) ([]*entity.WishlistItem, error) {
+	var items []*entity.WishlistItem
+
+	// Preload product details and order by most recently added
+	result := r.db.WithContext(ctx).
+		Preload("Product").
+		Where("wishlist_id = ?", wishlistID).
+		Order("added_at DESC").
+		Find(&items)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return items, nil
+}
+
+func (r *wishlistRepositoryImpl) AddItem(ctx context.Context, wishlistID, userID, productID uuid.UUID) (*entity.WishlistItem, error) {
 	// Check if item already exists
-	exists, err := r.ItemExists(ctx, userID, productID)
+	exists, err := r.ItemExists(ctx, wishlistID, productID)
 	if err != nil {
 		return nil, err
 	}
 
 	if exists {
 		// If already exists, return the existing item
-		return r.GetItemByProductID(ctx, userID, productID)
+		return r.GetItemByProductID(ctx, wishlistID, productID)
 	}
 
 	// Create new wishlist item
 	item := &entity.WishlistItem{
-		ID:        uuid.New(),
-		UserID:    userID,
-		ProductID: productID,
-		AddedAt:   time.Now(),
+		ID:         uuid.New(),
+		WishlistID: wishlistID,
+		UserID:     userID,
+		ProductID:  productID,
+		AddedAt:    time.Now(),
 	}
 
 	result := r.db.WithContext(ctx).Create(item)
@@ -67,9 +208,9 @@ func (r *wishlistRepositoryImpl) AddItem(ctx context.Context, userID, productID
 	return item, nil
 }
 
-func (r *wishlistRepositoryImpl) RemoveItem(ctx context.Context, userID, productID uuid.UUID) error {
+func (r *wishlistRepositoryImpl) RemoveItem(ctx context.Context, wishlistID, productID uuid.UUID) error {
 	result := r.db.WithContext(ctx).
-		Where("user_id = ? AND product_id = ?", userID, productID).
+		Where("wishlist_id = ? AND product_id = ?", wishlistID, productID).
 		Delete(&entity.WishlistItem{})
 
 	if result.Error != nil {
@@ -83,19 +224,19 @@ func (r *wishlistRepositoryImpl) RemoveItem(ctx context.Context, userID, product
 	return nil
 }
 
-func (r *wishlistRepositoryImpl) ClearWishlist(ctx context.Context, userID uuid.UUID) error {
+func (r *wishlistRepositoryImpl) ClearWishlist(ctx context.Context, wishlistID uuid.UUID) error {
 	result := r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
+		Where("wishlist_id = ?", wishlistID).
 		Delete(&entity.WishlistItem{})
 
 	return result.Error
 }
 
-func (r *wishlistRepositoryImpl) ItemExists(ctx context.Context, userID, productID uuid.UUID) (bool, error) {
+func (r *wishlistRepositoryImpl) ItemExists(ctx context.Context, wishlistID, productID uuid.UUID) (bool, error) {
 	var count int64
 	result := r.db.WithContext(ctx).
 		Model(&entity.WishlistItem{}).
-		Where("user_id = ? AND product_id = ?", userID, productID).
+		Where("wishlist_id = ? AND product_id = ?", wishlistID, productID).
 		Count(&count)
 
 	if result.Error != nil {
@@ -105,11 +246,11 @@ func (r *wishlistRepositoryImpl) ItemExists(ctx context.Context, userID, product
 	return count > 0, nil
 }
 
-func (r *wishlistRepositoryImpl) GetItemByProductID(ctx context.Context, userID, productID uuid.UUID) (*entity.WishlistItem, error) {
+func (r *wishlistRepositoryImpl) GetItemByProductID(ctx context.Context, wishlistID, productID uuid.UUID) (*entity.WishlistItem, error) {
 	var item entity.WishlistItem
 	result := r.db.WithContext(ctx).
 		Preload("Product").
-		Where("user_id = ? AND product_id = ?", userID, productID).
+		Where("wishlist_id = ? AND product_id = ?", wishlistID, productID).
 		First(&item)
 
 	if result.Error != nil {
@@ -121,3 +262,81 @@ func (r *wishlistRepositoryImpl) GetItemByProductID(ctx context.Context, userID,
 
 	return &item, nil
 }
+
+func (r *wishlistRepositoryImpl) GetUserIDsByProductID(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	result := r.db.WithContext(ctx).
+		Model(&entity.WishlistItem{}).
+		Distinct("user_id").
+		Where("product_id = ?", productID).
+		Pluck("user_id", &userIDs)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return userIDs, nil
+}
+
+func (r *wishlistRepositoryImpl) CountAddsByProduct(ctx context.Context, startDate, endDate time.Time) ([]*repository.ProductWishlistAdds, error) {
+	var adds []*repository.ProductWishlistAdds
+	result := r.db.WithContext(ctx).
+		Model(&entity.WishlistItem{}).
+		Select("product_id, COUNT(DISTINCT user_id) AS adds").
+		Where("added_at BETWEEN ? AND ?", startDate, endDate).
+		Group("product_id").
+		Scan(&adds)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return adds, nil
+}
+
+func (r *wishlistRepositoryImpl) GetAlert(ctx context.Context, userID, productID uuid.UUID, alertType string) (*entity.WishlistAlert, error) {
+	var alert entity.WishlistAlert
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND product_id = ? AND type = ?", userID, productID, alertType).
+		First(&alert)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("wishlist alert not found")
+		}
+		return nil, result.Error
+	}
+
+	return &alert, nil
+}
+
+func (r *wishlistRepositoryImpl) SaveAlert(ctx context.Context, alert *entity.WishlistAlert) error {
+	if alert.ID == uuid.Nil {
+		alert.ID = uuid.New()
+	}
+
+	result := r.db.WithContext(ctx).Save(alert)
+	return result.Error
+}
+
+func (r *wishlistRepositoryImpl) DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, int64, error) {
+	var items, lists int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ?", userID).Delete(&entity.WishlistItem{})
+		if result.Error != nil {
+			return result.Error
+		}
+		items = result.RowsAffected
+
+		result = tx.Where("user_id = ?", userID).Delete(&entity.Wishlist{})
+		if result.Error != nil {
+			return result.Error
+		}
+		lists = result.RowsAffected
+
+		return tx.Where("user_id = ?", userID).Delete(&entity.WishlistAlert{}).Error
+	})
+
+	return items, lists, err
+}
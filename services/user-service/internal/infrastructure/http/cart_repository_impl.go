@@ -0,0 +1,91 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/pkg/auth"
+	"solemate/services/user-service/internal/domain/repository"
+)
+
+type cartRepositoryImpl struct {
+	baseURL        string
+	httpClient     *http.Client
+	internalTokens *auth.InternalTokenManager
+}
+
+func NewCartRepository(baseURL string, internalTokens *auth.InternalTokenManager) repository.CartRepository {
+	return &cartRepositoryImpl{
+		baseURL:        baseURL,
+		internalTokens: internalTokens,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// AddItem calls cart-service on behalf of userID, so the item lands in that user's cart
+func (r *cartRepositoryImpl) AddItem(ctx context.Context, userID, productID uuid.UUID, variantID *uuid.UUID, quantity int) error {
+	url := fmt.Sprintf("%s/api/v1/cart/items", r.baseURL)
+
+	payload := map[string]interface{}{
+		"productId": productID,
+		"quantity":  quantity,
+	}
+	if variantID != nil {
+		payload["variantId"] = variantID
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	token, err := r.internalTokens.MintForUser(auth.ServiceCart, &auth.Claims{UserID: userID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to mint internal token: %w", err)
+	}
+	req.Header.Set(auth.InternalTokenHeader, token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var response struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	switch response.Error {
+	case "out_of_stock":
+		return repository.ErrCartOutOfStock
+	case "product_unavailable":
+		return repository.ErrCartProductUnavailable
+	}
+
+	if response.Error != "" {
+		return errors.New(response.Error)
+	}
+	return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"solemate/pkg/auth"
+	"solemate/pkg/productalerts"
+	"solemate/services/user-service/internal/domain/repository"
+)
+
+type notificationRepositoryImpl struct {
+	baseURL        string
+	httpClient     *http.Client
+	internalTokens *auth.InternalTokenManager
+}
+
+func NewNotificationRepository(baseURL string, internalTokens *auth.InternalTokenManager) repository.NotificationRepository {
+	return &notificationRepositoryImpl{
+		baseURL:        baseURL,
+		internalTokens: internalTokens,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// SendProductAlert raises a wishlist.<type> event for the user; notification-service
+// picks the channel and checks the user's preferences
+func (r *notificationRepositoryImpl) SendProductAlert(ctx context.Context, userID uuid.UUID, event *productalerts.Event) (bool, error) {
+	url := fmt.Sprintf("%s/api/v1/notifications/process-event", r.baseURL)
+
+	payload := map[string]interface{}{
+		"product_id":   event.ProductID,
+		"product_name": event.ProductName,
+	}
+	if event.VariantID != nil {
+		payload["variant_id"] = event.VariantID
+	}
+	if event.OldPrice != nil {
+		payload["old_price"] = *event.OldPrice
+	}
+	if event.NewPrice != nil {
+		payload["new_price"] = *event.NewPrice
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"event_type":  "wishlist." + event.Type,
+		"entity_id":   event.ProductID,
+		"entity_type": "product",
+		"user_id":     userID,
+		"payload":     payload,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	token, err := r.internalTokens.MintForService(auth.ServiceNotification)
+	if err != nil {
+		return false, fmt.Errorf("failed to mint internal token: %w", err)
+	}
+	req.Header.Set(auth.InternalTokenHeader, token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		NotificationsCreated int `json:"notifications_created"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return response.NotificationsCreated > 0, nil
+}